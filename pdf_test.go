@@ -1,6 +1,9 @@
 package pdfplumber
 
 import (
+	"bytes"
+	"encoding/json"
+	"math"
 	"strings"
 	"testing"
 )
@@ -19,6 +22,81 @@ func TestOpenPDF(t *testing.T) {
 	}
 }
 
+func TestOpenFallsBackForPdfcpuOptions(t *testing.T) {
+	// The libraries cannot repair the byte-swapped CMap, so Open falls back
+	// to the pdfcpu backend
+	doc, err := Open("testdata/swapped_cmap.pdf", WithRepairUnicode(true))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	if text := page.ExtractText(); text != "Hi!" {
+		t.Errorf("Expected the repaired text %q, got %q", "Hi!", text)
+	}
+
+	// Nor can they drop the content of hidden layers
+	layers, err := Open("testdata/layers.pdf", WithVisibleLayersOnly(true))
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer layers.Close()
+
+	page, _ = layers.GetPage(0)
+	if text := page.ExtractText(); strings.Contains(text, "Secret") || !strings.Contains(text, "Shown") {
+		t.Errorf("Expected only the visible layers' text, got %q", text)
+	}
+}
+
+func TestOpenObjectStreamCycle(t *testing.T) {
+	// Object stream 6 is stored inside itself; the libraries would recurse
+	// until the stack overflows, so Open must reject the file before them
+	_, err := Open("testdata/objstm_cycle.pdf")
+	if err == nil {
+		t.Fatal("Expected an error for an object stream stored in itself")
+	}
+}
+
+func TestOpenStandardFontWidths(t *testing.T) {
+	// Non-embedded Helvetica, Times-Bold and Arial at 10pt without Widths
+	// arrays: the library backends read them as zero-width
+	doc, err := Open("testdata/standard_fonts.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Advances from the AFM files, in 1/1000 em
+	expected := map[string][]float64{
+		"Helvetica":  {722, 556, 222, 222, 556},
+		"Times-Bold": {778, 444, 278, 278, 500},
+		"Arial":      {944, 222},
+	}
+
+	page, _ := doc.GetPage(0)
+	got := map[string][]CharObject{}
+	for _, char := range page.GetObjects().Chars {
+		got[char.Font] = append(got[char.Font], char)
+	}
+
+	for font, widths := range expected {
+		chars := got[font]
+		if len(chars) != len(widths) {
+			t.Fatalf("Font %s: expected %d chars, got %d", font, len(widths), len(chars))
+		}
+		x := 72.0
+		for i, width := range widths {
+			want := width / 1000 * 10
+			if math.Abs(chars[i].Width-want) > 0.001 || math.Abs(chars[i].X0-x) > 0.001 {
+				t.Errorf("Font %s char %q: expected width %.2f at x %.2f, got %.2f at %.2f",
+					font, chars[i].Text, want, x, chars[i].Width, chars[i].X0)
+			}
+			x += want
+		}
+	}
+}
+
 func TestExtractText(t *testing.T) {
 	// Open PDF
 	doc, err := Open("testdata/sample.pdf")
@@ -104,4 +182,233 @@ func TestGetObjects(t *testing.T) {
 			t.Errorf("Expected first character to be 'D', got '%s'", firstChar.Text)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestExtractTextWithColumnDetection(t *testing.T) {
+	doc, err := Open("testdata/two_column.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("Failed to get page: %v", err)
+	}
+
+	text := page.ExtractText(WithColumnDetection(true))
+	lines := strings.Split(text, "\n")
+
+	expected := []string{
+		"A Study of Two Columns",
+		"Column layouts are common in",
+		"academic papers and journals.",
+		"Readers finish the left column",
+		"before moving to the right one.",
+		"Each column is read from the",
+		"top of the page to the bottom.",
+		"The right column continues the",
+		"text from the bottom of the",
+		"left column and ends the page.",
+		"It keeps going for a while.",
+		"Headings may span both of the",
+		"columns. This is the final line.",
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %d:\n%s", len(expected), len(lines), text)
+	}
+	for i, line := range lines {
+		if line != expected[i] {
+			t.Errorf("Line %d: expected %q, got %q", i, expected[i], line)
+		}
+	}
+}
+
+func TestExtractTextWithColumnSeparator(t *testing.T) {
+	doc, err := Open("testdata/three_column_report.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("Failed to get page: %v", err)
+	}
+
+	text := page.ExtractText(WithColumnDetection(true), WithColumnSeparator("\t"))
+	lines := strings.Split(text, "\n")
+	if len(lines) != 7 {
+		t.Fatalf("Expected 7 lines, got %d:\n%s", len(lines), text)
+	}
+	for i, line := range lines {
+		if fields := strings.Split(line, "\t"); len(fields) != 3 {
+			t.Errorf("Line %d: expected 3 fields, got %q", i, line)
+		}
+	}
+	if lines[4] != "Cherries\t100\t9.99" {
+		t.Errorf("Expected %q, got %q", "Cherries\t100\t9.99", lines[4])
+	}
+}
+
+func TestExtractTextWithParagraphBreaks(t *testing.T) {
+	doc, err := Open("testdata/paragraphs.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("Failed to get page: %v", err)
+	}
+
+	expected := "The first paragraph starts here\nand continues on this line\nbefore it ends.\n\n" +
+		"A second paragraph follows\nafter a larger gap."
+	if text := page.ExtractText(WithParagraphBreaks(true)); text != expected {
+		t.Errorf("Expected paragraphs separated by a blank line:\n%q\ngot:\n%q", expected, text)
+	}
+}
+
+func TestRotatePage(t *testing.T) {
+	doc, err := Open("testdata/sample.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("Failed to get page: %v", err)
+	}
+
+	original := page.GetObjects().Chars[0]
+	rotated := page.Rotate(90)
+
+	// Width and height swap for a quarter-turn
+	if rotated.GetWidth() != page.GetHeight() || rotated.GetHeight() != page.GetWidth() {
+		t.Errorf("Expected rotated size %.1fx%.1f, got %.1fx%.1f",
+			page.GetHeight(), page.GetWidth(), rotated.GetWidth(), rotated.GetHeight())
+	}
+
+	if rotated.GetRotation() != (page.GetRotation()+90)%360 {
+		t.Errorf("Expected rotation %d, got %d", (page.GetRotation()+90)%360, rotated.GetRotation())
+	}
+
+	// The character's top edge becomes its right edge after turning clockwise
+	char := rotated.GetObjects().Chars[0]
+	if char.Text != original.Text {
+		t.Fatalf("Expected first character '%s', got '%s'", original.Text, char.Text)
+	}
+	expectedX0 := page.GetHeight() - original.Y1
+	if math.Abs(char.X0-expectedX0) > 0.01 || math.Abs(char.Y0-original.X0) > 0.01 {
+		t.Errorf("Expected rotated char at (%.2f, %.2f), got (%.2f, %.2f)",
+			expectedX0, original.X0, char.X0, char.Y0)
+	}
+
+	// Four quarter-turns bring the page back
+	full := page.Rotate(90).Rotate(90).Rotate(90).Rotate(90)
+	if back := full.GetObjects().Chars[0]; math.Abs(back.X0-original.X0) > 0.01 || math.Abs(back.Y0-original.Y0) > 0.01 {
+		t.Errorf("Expected char back at (%.2f, %.2f), got (%.2f, %.2f)",
+			original.X0, original.Y0, back.X0, back.Y0)
+	}
+}
+
+func TestWriteCharsJSONL(t *testing.T) {
+	doc, err := Open("testdata/sample.pdf")
+	if err != nil {
+		t.Fatalf("Failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("Failed to get page: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := page.WriteCharsJSONL(&buf); err != nil {
+		t.Fatalf("Failed to write chars: %v", err)
+	}
+
+	chars := page.GetObjects().Chars
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(chars) {
+		t.Fatalf("Expected %d lines, got %d", len(chars), len(lines))
+	}
+
+	var first struct {
+		Text     string   `json:"text"`
+		FontName string   `json:"fontname"`
+		Size     float64  `json:"size"`
+		X0       float64  `json:"x0"`
+		Top      float64  `json:"top"`
+		X1       float64  `json:"x1"`
+		Bottom   float64  `json:"bottom"`
+		Upright  bool     `json:"upright"`
+		Color    [3]uint8 `json:"color"`
+		Page     int      `json:"page"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to parse line: %v", err)
+	}
+
+	if first.Text != chars[0].Text || first.X0 != chars[0].X0 || first.Size != chars[0].FontSize {
+		t.Errorf("First record does not match first char: %+v", first)
+	}
+	if first.Page != 1 {
+		t.Errorf("Expected page 1, got %d", first.Page)
+	}
+	if first.Bottom <= first.Top {
+		t.Errorf("Expected bottom below top, got top=%.2f bottom=%.2f", first.Top, first.Bottom)
+	}
+}
+
+// Benchmark opening a document, where page objects are extracted lazily
+func BenchmarkOpenLazy(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		doc, err := Open("testdata/two_column.pdf")
+		if err != nil {
+			b.Fatalf("Failed to open PDF: %v", err)
+		}
+		doc.Close()
+	}
+}
+
+// Benchmark opening a document and extracting every page up front,
+// which is what Open used to do
+func BenchmarkOpenEager(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		doc, err := Open("testdata/two_column.pdf")
+		if err != nil {
+			b.Fatalf("Failed to open PDF: %v", err)
+		}
+		for _, page := range doc.GetPages() {
+			_ = page.GetObjects()
+		}
+		doc.Close()
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc, err := Open("testdata/problems.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	var got []string
+	for _, issue := range doc.Validate() {
+		got = append(got, string(issue.Severity)+" "+issue.String())
+	}
+
+	expected := []string{
+		"warning page 1: font F2 has no ToUnicode and non-standard encoding",
+		"warning page 2: 0 characters, 1 image(s) - likely scanned",
+		"warning page 3: no MediaBox, assuming 612x792",
+		"info page 3: no characters",
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("unexpected issues:\n got: %q\nwant: %q", got, expected)
+	}
+}
@@ -2,6 +2,8 @@
 package pdfplumber
 
 import (
+	"io"
+
 	"github.com/pyhub-apps/pdfplumber-golang/pkg/pdf"
 )
 
@@ -10,6 +12,7 @@ type (
 	Document               = pdf.Document
 	Page                  = pdf.Page
 	Table                 = pdf.Table
+	TableRule             = pdf.TableRule
 	TableExtractionOption = pdf.TableExtractionOption
 	TextExtractionOption  = pdf.TextExtractionOption
 	WordExtractionOption  = pdf.WordExtractionOption
@@ -20,48 +23,176 @@ type (
 	RectObject            = pdf.RectObject
 	CurveObject           = pdf.CurveObject
 	BoundingBox           = pdf.BoundingBox
+	Color                 = pdf.Color
+	ShadingObject         = pdf.ShadingObject
+	TextSpan              = pdf.TextSpan
+	ValidationIssue       = pdf.ValidationIssue
+	Severity              = pdf.Severity
+	Attachment            = pdf.Attachment
+	FormField             = pdf.FormField
+	OpenOption            = pdf.OpenOption
+	ToUnicodeCMap         = pdf.ToUnicodeCMap
+	SearchOption          = pdf.SearchOption
+	DocSearchMatch        = pdf.DocSearchMatch
+	ColoredRun            = pdf.ColoredRun
+	DedupeOption          = pdf.DedupeOption
+	ReadingOrder          = pdf.ReadingOrder
+	ExtractedImage        = pdf.ExtractedImage
+	PageDim               = pdf.PageDim
+	RedactionWarning      = pdf.RedactionWarning
+	Comment               = pdf.Comment
+	DocumentInfo          = pdf.DocumentInfo
+	PageInfo              = pdf.PageInfo
+	ImageOption           = pdf.ImageOption
+	DebugOptions          = pdf.DebugOptions
+	CropOption            = pdf.CropOption
 )
 
 // Re-export option functions
 var (
-	WithTableStrategy = pdf.WithTableStrategy
-	WithMinTableSize  = pdf.WithMinTableSize
-	WithTextTolerance = pdf.WithTextTolerance
-	WithLayout        = pdf.WithLayout
-	WithXTolerance    = pdf.WithXTolerance
-	WithYTolerance    = pdf.WithYTolerance
+	WithTableStrategy           = pdf.WithTableStrategy
+	WithMinTableSize            = pdf.WithMinTableSize
+	WithTextTolerance           = pdf.WithTextTolerance
+	WithExplicitVerticalLines   = pdf.WithExplicitVerticalLines
+	WithExplicitHorizontalLines = pdf.WithExplicitHorizontalLines
+	WithRemoveRepeatedHeaders   = pdf.WithRemoveRepeatedHeaders
+	WithConsistentColumns       = pdf.WithConsistentColumns
+	WithMinColumnGap            = pdf.WithMinColumnGap
+	WithExcludeDashedLines      = pdf.WithExcludeDashedLines
+	WithLayout                  = pdf.WithLayout
+	WithXTolerance              = pdf.WithXTolerance
+	WithYTolerance              = pdf.WithYTolerance
+	WithColumnDetection         = pdf.WithColumnDetection
+	WithOCRFunc                 = pdf.WithOCRFunc
+	WithScale                   = pdf.WithScale
+	WithTextAngleThreshold      = pdf.WithTextAngleThreshold
+	WithMergeAdjacentChars      = pdf.WithMergeAdjacentChars
+	WithSeparateSuperscripts    = pdf.WithSeparateSuperscripts
+	WithTrimLines               = pdf.WithTrimLines
+	WithCollapseWhitespace      = pdf.WithCollapseWhitespace
+	WithLineEnding              = pdf.WithLineEnding
+	WithFontUnicodeOverride     = pdf.WithFontUnicodeOverride
+	NewToUnicodeCMap            = pdf.NewToUnicodeCMap
+	WithMaxPages                = pdf.WithMaxPages
+	WithTruncatePages           = pdf.WithTruncatePages
+	WithIgnoreRotatedText       = pdf.WithIgnoreRotatedText
+	WithIncludeOutlined         = pdf.WithIncludeOutlined
+	WithRotatedTextTolerance    = pdf.WithRotatedTextTolerance
+	WithColumnSeparator         = pdf.WithColumnSeparator
+	WithParagraphBreaks         = pdf.WithParagraphBreaks
+	WithSpaceGlyphDetection     = pdf.WithSpaceGlyphDetection
+	WithRepairUnicode           = pdf.WithRepairUnicode
+	WithMaxObjectsPerPage       = pdf.WithMaxObjectsPerPage
+	WithMaxStreamSize           = pdf.WithMaxStreamSize
+	WithPageBBox                = pdf.WithPageBBox
+	WithExcludeRegions          = pdf.WithExcludeRegions
+	WithVisibleLayersOnly       = pdf.WithVisibleLayersOnly
+	WithFallbackFont            = pdf.WithFallbackFont
+	WithPhysicalDimensions      = pdf.WithPhysicalDimensions
+	WithSnapGrid                = pdf.WithSnapGrid
+	WithEmitSpaces              = pdf.WithEmitSpaces
+	WithSearchContext           = pdf.WithSearchContext
+	WithSearchRegex             = pdf.WithSearchRegex
+	WithSearchCaseSensitive     = pdf.WithSearchCaseSensitive
+	WithDedupeCharTolerance     = pdf.WithDedupeCharTolerance
+	WithDedupeShapeTolerance    = pdf.WithDedupeShapeTolerance
+	WithDedupeMergeLines        = pdf.WithDedupeMergeLines
+	WithReadingOrder            = pdf.WithReadingOrder
+	WithResolution              = pdf.WithResolution
+	DefaultDebugOptions         = pdf.DefaultDebugOptions
+	WithRelativeCoordinates     = pdf.WithRelativeCoordinates
+)
+
+// Re-export object filters
+var (
+	StrokeColorMatches = pdf.StrokeColorMatches
+	FillColorMatches   = pdf.FillColorMatches
+)
+
+// Re-export reading orders
+const (
+	ReadingOrderGeometric  = pdf.ReadingOrderGeometric
+	ReadingOrderAuto       = pdf.ReadingOrderAuto
+	ReadingOrderStructTree = pdf.ReadingOrderStructTree
+)
+
+// Re-export validation severities
+const (
+	SeverityInfo    = pdf.SeverityInfo
+	SeverityWarning = pdf.SeverityWarning
+	SeverityError   = pdf.SeverityError
+)
+
+// Re-export document-level extraction
+var (
+	ExtractTextSpans         = pdf.ExtractTextSpans
+	ExtractTablesAcrossPages = pdf.ExtractTablesAcrossPages
+	MergeTables              = pdf.MergeTables
+	ConcatText               = pdf.ConcatText
 )
 
-// Open opens a PDF file and returns a Document
-func Open(filepath string) (pdf.Document, error) {
+// Re-export PDF date parsing
+var (
+	ParsePDFDate = pdf.ParsePDFDate
+)
+
+// Re-export errors
+var (
+	ErrNotPDF            = pdf.ErrNotPDF
+	ErrEncrypted         = pdf.ErrEncrypted
+	ErrCorruptXRef       = pdf.ErrCorruptXRef
+	ErrPageOutOfRange    = pdf.ErrPageOutOfRange
+	ErrUnsupportedFilter = pdf.ErrUnsupportedFilter
+	ErrStreamTooLarge    = pdf.ErrStreamTooLarge
+	ErrTooManyPages      = pdf.ErrTooManyPages
+	ErrPageTreeCycle     = pdf.ErrPageTreeCycle
+	ErrImageNotFound     = pdf.ErrImageNotFound
+	ErrNotImplemented    = pdf.ErrNotImplemented
+)
+
+// Open opens a PDF file and returns a Document. It tries the ledongthuc,
+// dslipak and pdfcpu backends in turn, so options the libraries do not
+// apply, such as WithRepairUnicode, open the document with pdfcpu.
+func Open(filepath string, opts ...OpenOption) (pdf.Document, error) {
 	// Try ledongthuc implementation first as it has the most accurate text extraction
-	doc, err := pdf.OpenWithLedongthuc(filepath)
+	doc, err := pdf.OpenWithLedongthuc(filepath, opts...)
 	if err == nil {
 		return doc, nil
 	}
 	
 	// Fallback to dslipak implementation
-	doc, err = pdf.OpenWithDslipak(filepath)
+	doc, err = pdf.OpenWithDslipak(filepath, opts...)
 	if err == nil {
 		return doc, nil
 	}
 	
 	// Final fallback to pdfcpu implementation
-	return pdf.Open(filepath)
+	return pdf.Open(filepath, opts...)
 }
 
 // OpenWithPassword opens a password-protected PDF file
-func OpenWithPassword(filepath string, password string) (pdf.Document, error) {
-	return pdf.OpenWithPassword(filepath, password)
+func OpenWithPassword(filepath string, password string, opts ...OpenOption) (pdf.Document, error) {
+	return pdf.OpenWithPassword(filepath, password, opts...)
+}
+
+// OpenReader opens a PDF from the first size bytes of r, without a file
+// on disk
+func OpenReader(r io.ReaderAt, size int64, opts ...OpenOption) (pdf.Document, error) {
+	return pdf.OpenReader(r, size, opts...)
+}
+
+// OpenBytes opens a PDF held in memory
+func OpenBytes(data []byte, opts ...OpenOption) (pdf.Document, error) {
+	return pdf.OpenBytes(data, opts...)
 }
 
 // OpenWithDslipak opens a PDF file using the dslipak/pdf library
-func OpenWithDslipak(filepath string) (pdf.Document, error) {
-	return pdf.OpenWithDslipak(filepath)
+func OpenWithDslipak(filepath string, opts ...OpenOption) (pdf.Document, error) {
+	return pdf.OpenWithDslipak(filepath, opts...)
 }
 
 // OpenWithLedongthuc opens a PDF file using the ledongthuc/pdf library
 // This provides the most accurate text extraction with proper coordinates
-func OpenWithLedongthuc(filepath string) (pdf.Document, error) {
-	return pdf.OpenWithLedongthuc(filepath)
+func OpenWithLedongthuc(filepath string, opts ...OpenOption) (pdf.Document, error) {
+	return pdf.OpenWithLedongthuc(filepath, opts...)
 }
\ No newline at end of file
@@ -41,7 +41,9 @@ func NewContentExtractor(page *parser.PDFPage) *ContentExtractor {
 	}
 }
 
-// Extract extracts all content from the page
+// Extract extracts all content from the page. Content streams the parser
+// could not read are reported by the page's ContentErr, returned once the
+// rest is extracted.
 func (e *ContentExtractor) Extract() error {
 	// Load fonts from resources
 	if e.page.Resources != nil {
@@ -50,14 +52,15 @@ func (e *ContentExtractor) Extract() error {
 		}
 	}
 
-	// Process each content stream
-	for i := range e.page.Contents {
-		if err := e.processContentStream(e.page.Contents[i].Data); err != nil {
-			return fmt.Errorf("error processing content stream: %v", err)
-		}
+	// Process the content streams as one, since operands may be split across them
+	if err := e.processContentStream(e.page.ContentData()); err != nil {
+		return fmt.Errorf("error processing content stream: %w", err)
 	}
 
-	return nil
+	// Pick up rectangles whose edges were drawn as separate paths
+	e.rects = append(e.rects, pdf.DetectRectanglesFromLines(e.lines, pdf.LineJoinTolerance)...)
+
+	return e.page.ContentErr
 }
 
 // loadFonts loads font information from resources
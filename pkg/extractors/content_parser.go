@@ -30,7 +30,7 @@ func NewContentParser(filepath string) (*ContentParser, error) {
 // ExtractPageObjects extracts all objects from a specific page
 func (cp *ContentParser) ExtractPageObjects(pageNum int) (pdf.Objects, error) {
 	if pageNum < 1 || pageNum > cp.reader.NumPage() {
-		return pdf.Objects{}, fmt.Errorf("invalid page number: %d", pageNum)
+		return pdf.Objects{}, fmt.Errorf("%w: number %d", pdf.ErrPageOutOfRange, pageNum)
 	}
 	
 	page := cp.reader.Page(pageNum)
@@ -141,7 +141,7 @@ func (cp *ContentParser) extractGraphicsFromContent(content gopdf.Content, objec
 // ExtractText extracts all text from a page
 func (cp *ContentParser) ExtractText(pageNum int) (string, error) {
 	if pageNum < 1 || pageNum > cp.reader.NumPage() {
-		return "", fmt.Errorf("invalid page number: %d", pageNum)
+		return "", fmt.Errorf("%w: number %d", pdf.ErrPageOutOfRange, pageNum)
 	}
 	
 	page := cp.reader.Page(pageNum)
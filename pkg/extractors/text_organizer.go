@@ -114,16 +114,14 @@ func (to *TextOrganizer) extractLineText(lineChars []pdf.CharObject) string {
 	
 	var result strings.Builder
 	var lastX float64
+	threshold := to.spaceThreshold(lineChars)
 	
 	for i, char := range lineChars {
 		if i > 0 {
 			// Check if there's a significant gap (indicating a space)
 			gap := char.X0 - lastX
-			if gap > to.xTolerance {
-				// Add space if gap is large enough
-				if gap > char.Width*0.5 { // If gap is more than half character width
-					result.WriteString(" ")
-				}
+			if gap > to.xTolerance && gap > threshold {
+				result.WriteString(" ")
 			}
 		}
 		result.WriteString(char.Text)
@@ -133,6 +131,19 @@ func (to *TextOrganizer) extractLineText(lineChars []pdf.CharObject) string {
 	return result.String()
 }
 
+// spaceThreshold returns the smallest gap in a line that counts as a space:
+// half the width of the space glyph of the line's largest font. It is the same
+// for every gap of the line, whichever characters surround it.
+func (to *TextOrganizer) spaceThreshold(lineChars []pdf.CharObject) float64 {
+	var largest pdf.CharObject
+	for _, char := range lineChars {
+		if char.FontSize > largest.FontSize {
+			largest = char
+		}
+	}
+	return largest.FontSize * largest.SpaceGlyphWidth() * 0.5
+}
+
 // ExtractWords extracts individual words from character objects
 func (to *TextOrganizer) ExtractWords(chars []pdf.CharObject) []Word {
 	if len(chars) == 0 {
@@ -196,25 +207,16 @@ func (to *TextOrganizer) extractWordsFromLine(lineChars []pdf.CharObject) []Word
 // createWord creates a Word from a group of characters
 func (to *TextOrganizer) createWord(chars []pdf.CharObject) Word {
 	var text strings.Builder
-	minX, minY := chars[0].X0, chars[0].Y0
-	maxX, maxY := chars[0].X1, chars[0].Y1
+	bbox := chars[0].GetBBox().Normalize()
 	
 	for _, char := range chars {
 		text.WriteString(char.Text)
-		minX = min(minX, char.X0)
-		minY = min(minY, char.Y0)
-		maxX = max(maxX, char.X1)
-		maxY = max(maxY, char.Y1)
+		bbox = bbox.Union(char.GetBBox())
 	}
 	
 	return Word{
-		Text: text.String(),
-		BBox: pdf.BoundingBox{
-			X0: minX,
-			Y0: minY,
-			X1: maxX,
-			Y1: maxY,
-		},
+		Text:       text.String(),
+		BBox:       bbox,
 		Characters: chars,
 	}
 }
@@ -0,0 +1,27 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/pyhub-apps/pdfplumber-golang/pkg/pdf"
+)
+
+func TestExtractLineTextStableSpacing(t *testing.T) {
+	to := NewTextOrganizer()
+	to.SetTolerances(0, 3)
+
+	// The same 2.5pt gap is followed by a wide and by a narrow character.
+	// Half a 12pt Helvetica space is about 1.7pt, so both gaps are spaces.
+	char := func(text string, x0, width float64) pdf.CharObject {
+		return pdf.CharObject{Text: text, Font: "Helvetica", FontSize: 12, X0: x0, X1: x0 + width, Width: width}
+	}
+	lines := map[string][]pdf.CharObject{
+		"a W": {char("a", 0, 6.7), char("W", 9.2, 11.3)},
+		"a i": {char("a", 0, 6.7), char("i", 9.2, 2.7)},
+	}
+	for expected, chars := range lines {
+		if got := to.extractLineText(chars); got != expected {
+			t.Errorf("expected %q, got %q", expected, got)
+		}
+	}
+}
@@ -15,6 +15,7 @@ type PDFPage struct {
 	width      float64
 	height     float64
 	rotation   int
+	userUnit   float64 // Size of a default user space unit in 1/72 inch
 	bbox       pdf.BoundingBox
 	objects    pdf.Objects
 }
@@ -22,7 +23,7 @@ type PDFPage struct {
 // NewPDFPage creates a new PDFPage instance
 func NewPDFPage(ctx *model.Context, pageNumber int) (pdf.Page, error) {
 	if pageNumber < 1 || pageNumber > ctx.PageCount {
-		return nil, fmt.Errorf("invalid page number: %d", pageNumber)
+		return nil, fmt.Errorf("%w: number %d not in [1, %d]", pdf.ErrPageOutOfRange, pageNumber, ctx.PageCount)
 	}
 
 	// Get page dimensions
@@ -33,12 +34,21 @@ func NewPDFPage(ctx *model.Context, pageNumber int) (pdf.Page, error) {
 
 	dim := pageDims[pageNumber-1]
 	
+	// A missing or non-positive /UserUnit leaves the default of 1/72 inch
+	userUnit := 1.0
+	if pageDict, _, _, err := ctx.PageDict(pageNumber, false); err == nil && pageDict != nil {
+		if unit, err := ctx.DereferenceNumber(pageDict["UserUnit"]); err == nil && unit > 0 {
+			userUnit = unit
+		}
+	}
+	
 	page := &PDFPage{
 		ctx:        ctx,
 		pageNumber: pageNumber,
 		width:      dim.Width,
 		height:     dim.Height,
 		rotation:   0, // TODO: Extract actual rotation
+		userUnit:   userUnit,
 		bbox: pdf.BoundingBox{
 			X0: 0,
 			Y0: 0,
@@ -98,6 +108,11 @@ func (p *PDFPage) GetRotation() int {
 	return p.rotation
 }
 
+// UserUnit returns the size of a default user space unit in 1/72 inch
+func (p *PDFPage) UserUnit() float64 {
+	return p.userUnit
+}
+
 // GetBBox returns the page bounding box
 func (p *PDFPage) GetBBox() pdf.BoundingBox {
 	return p.bbox
@@ -131,6 +146,58 @@ func (p *PDFPage) ExtractWords(opts ...pdf.WordExtractionOption) []pdf.Word {
 	return []pdf.Word{}
 }
 
+// ColorRuns returns the page's text split into runs of one fill color
+func (p *PDFPage) ColorRuns() []pdf.ColoredRun {
+	// TODO: Implement color runs
+	return []pdf.ColoredRun{}
+}
+
+// LayerNames returns the names of the layers the page's content is marked with
+func (p *PDFPage) LayerNames() []string {
+	// TODO: Implement layer extraction
+	return []string{}
+}
+
+// Comments returns the page's comment and text markup annotations
+func (p *PDFPage) Comments() []pdf.Comment {
+	// TODO: Implement comment extraction
+	return []pdf.Comment{}
+}
+
+// ExtractBetween returns the text between two anchors in reading order
+func (p *PDFPage) ExtractBetween(start, end string, opts ...pdf.SearchOption) (string, bool) {
+	// TODO: Implement anchored text extraction
+	return "", false
+}
+
+// RecognizeText runs OCR on a page of scanned images
+func (p *PDFPage) RecognizeText(opts ...pdf.TextExtractionOption) (string, error) {
+	return "", fmt.Errorf("OCR: %w", pdf.ErrNotImplemented)
+}
+
+// PotentialRedactions returns the filled boxes drawn over extractable text
+func (p *PDFPage) PotentialRedactions() ([]pdf.RedactionWarning, error) {
+	return nil, fmt.Errorf("potential redactions: %w", pdf.ErrNotImplemented)
+}
+
+// Image returns the image XObject with the given resource name
+func (p *PDFPage) Image(name string) (pdf.ExtractedImage, error) {
+	// TODO: Implement image extraction
+	return pdf.ExtractedImage{}, fmt.Errorf("image extraction: %w", pdf.ErrNotImplemented)
+}
+
+// ExtractTextSpans extracts text lines along with their page and position
+func (p *PDFPage) ExtractTextSpans(opts ...pdf.TextExtractionOption) []pdf.TextSpan {
+	// TODO: Implement text span extraction
+	return []pdf.TextSpan{}
+}
+
+// CharGrid returns the characters placed on the grid of layout-mode text
+func (p *PDFPage) CharGrid(opts ...pdf.TextExtractionOption) [][]rune {
+	// TODO: Implement char grid
+	return [][]rune{}
+}
+
 // ExtractTables extracts tables from the page
 func (p *PDFPage) ExtractTables(opts ...pdf.TableExtractionOption) []pdf.Table {
 	// TODO: Implement table extraction
@@ -144,7 +211,9 @@ func (p *PDFPage) ExtractTables(opts ...pdf.TableExtractionOption) []pdf.Table {
 }
 
 // Crop returns a new page cropped to the specified bounding box
-func (p *PDFPage) Crop(bbox pdf.BoundingBox) pdf.Page {
+func (p *PDFPage) Crop(bbox pdf.BoundingBox, opts ...pdf.CropOption) pdf.Page {
+	objects, bbox := pdf.CropObjects(p.GetObjects(), bbox, opts...)
+	
 	// Create a new page with cropped dimensions
 	croppedPage := &PDFPage{
 		ctx:        p.ctx,
@@ -153,12 +222,29 @@ func (p *PDFPage) Crop(bbox pdf.BoundingBox) pdf.Page {
 		height:     bbox.Height(),
 		rotation:   p.rotation,
 		bbox:       bbox,
-		objects:    p.filterObjectsInBBox(bbox),
+		objects:    objects,
 	}
 	
 	return croppedPage
 }
 
+// Rotate returns a new page rotated clockwise by a multiple of 90 degrees
+func (p *PDFPage) Rotate(degrees int) pdf.Page {
+	objects, bbox := pdf.RotateObjectsInBox(p.GetObjects(), degrees, p.bbox, false)
+	
+	rotatedPage := &PDFPage{
+		ctx:        p.ctx,
+		pageNumber: p.pageNumber,
+		width:      bbox.Width(),
+		height:     bbox.Height(),
+		rotation:   ((p.rotation+degrees)%360 + 360) % 360,
+		bbox:       bbox,
+		objects:    objects,
+	}
+	
+	return rotatedPage
+}
+
 // WithinBBox filters objects within a bounding box
 func (p *PDFPage) WithinBBox(bbox pdf.BoundingBox) pdf.Objects {
 	return p.filterObjectsInBBox(bbox)
@@ -220,6 +306,11 @@ func (p *PDFPage) Filter(predicate func(pdf.Object) bool) pdf.Objects {
 	return filtered
 }
 
+// WriteCharsJSONL writes each character as a line of JSON
+func (p *PDFPage) WriteCharsJSONL(w io.Writer) error {
+	return pdf.WriteCharsJSONL(w, p.objects.Chars, p.pageNumber, p.height, false)
+}
+
 // ToImage renders the page to an image (for visual debugging)
 func (p *PDFPage) ToImage(opts ...pdf.ImageOption) (io.Reader, error) {
 	// TODO: Implement page rendering to image
@@ -228,7 +319,13 @@ func (p *PDFPage) ToImage(opts ...pdf.ImageOption) (io.Reader, error) {
 	// 2. Applying rendering options
 	// 3. Returning the image data as io.Reader
 	
-	return nil, fmt.Errorf("image rendering not yet implemented")
+	return nil, fmt.Errorf("image rendering: %w", pdf.ErrNotImplemented)
+}
+
+// DrawDebug renders the page with its objects outlined, which needs
+// ToImage's rendering
+func (p *PDFPage) DrawDebug(opts pdf.DebugOptions) (io.Reader, error) {
+	return nil, fmt.Errorf("debug rendering: %w", pdf.ErrNotImplemented)
 }
 
 // filterObjectsInBBox filters objects that are within the given bounding box
@@ -0,0 +1,120 @@
+package parser
+
+import "unicode/utf16"
+
+// Attachment is a file embedded in the document's /EmbeddedFiles name tree
+type Attachment struct {
+	Name     string // File name, from /UF or /F of the file specification
+	MIMEType string // From the embedded file's /Subtype, empty if not given
+	Data     []byte
+}
+
+// maxNameTreeDepth bounds the recursion into /Kids of a name tree
+const maxNameTreeDepth = 32
+
+// Attachments returns the files embedded in the document
+func (d *PDFDocument) Attachments() []Attachment {
+	names, ok := d.resolve(d.Catalog.Get(PDFName("Names"))).(PDFDict)
+	if !ok {
+		return nil
+	}
+
+	var attachments []Attachment
+	d.walkNameTree(names.Get(PDFName("EmbeddedFiles")), 0, map[ObjectRef]bool{}, func(name string, value PDFObject) {
+		if attachment, ok := d.attachment(name, value); ok {
+			attachments = append(attachments, attachment)
+		}
+	})
+	return attachments
+}
+
+// attachment reads the embedded file of a file specification
+func (d *PDFDocument) attachment(name string, value PDFObject) (Attachment, bool) {
+	spec, ok := d.resolve(value).(PDFDict)
+	if !ok {
+		return Attachment{}, false
+	}
+	ef, ok := d.resolve(spec.Get(PDFName("EF"))).(PDFDict)
+	if !ok {
+		return Attachment{}, false
+	}
+	streamObj := ef.Get(PDFName("UF"))
+	if streamObj == nil {
+		streamObj = ef.Get(PDFName("F"))
+	}
+	stream, ok := d.resolve(streamObj).(*PDFStream)
+	if !ok {
+		return Attachment{}, false
+	}
+
+	if fileName := d.textString(spec.Get(PDFName("UF"))); fileName != "" {
+		name = fileName
+	} else if fileName := d.textString(spec.Get(PDFName("F"))); fileName != "" {
+		name = fileName
+	}
+
+	attachment := Attachment{Name: name, Data: stream.Data}
+	if subtype, ok := stream.Dict.GetName(PDFName("Subtype")); ok {
+		attachment.MIMEType = string(subtype)
+	}
+	return attachment, true
+}
+
+// walkNameTree calls fn for each key and value of a name tree. visited
+// holds the nodes walked so far, so that a node listed among several /Kids
+// is walked once.
+func (d *PDFDocument) walkNameTree(obj PDFObject, depth int, visited map[ObjectRef]bool, fn func(name string, value PDFObject)) {
+	if ref, ok := obj.(ObjectRef); ok {
+		if visited[ref] {
+			return
+		}
+		visited[ref] = true
+	}
+	node, ok := d.resolve(obj).(PDFDict)
+	if !ok || depth > maxNameTreeDepth {
+		return
+	}
+
+	if names, ok := d.resolve(node.Get(PDFName("Names"))).(PDFArray); ok {
+		for i := 0; i+1 < len(names); i += 2 {
+			fn(d.textString(names[i]), names[i+1])
+		}
+	}
+
+	if kids, ok := d.resolve(node.Get(PDFName("Kids"))).(PDFArray); ok {
+		for _, kid := range kids {
+			d.walkNameTree(kid, depth+1, visited, fn)
+		}
+	}
+}
+
+// resolve follows an indirect reference, returning other objects as they are
+func (d *PDFDocument) resolve(obj PDFObject) PDFObject {
+	ref, ok := obj.(ObjectRef)
+	if !ok {
+		return obj
+	}
+	resolved, err := d.GetObject(ref)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
+
+// textString decodes a PDF text string, which is UTF-16BE when it starts
+// with a byte order mark
+func (d *PDFDocument) textString(obj PDFObject) string {
+	s, ok := d.resolve(obj).(PDFString)
+	if !ok {
+		return ""
+	}
+	if len(s) < 2 || s[0] != 0xFE || s[1] != 0xFF {
+		return string(s)
+	}
+
+	units := make([]uint16, 0, len(s)/2-1)
+	for i := 2; i+1 < len(s); i += 2 {
+		units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,35 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttachments(t *testing.T) {
+	doc := parseFile(t, "../../testdata/attachment.pdf")
+
+	attachments := doc.Attachments()
+	if len(attachments) != 1 {
+		t.Fatalf("expected 1 attachment, got %d", len(attachments))
+	}
+
+	got := attachments[0]
+	expected := []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Invoice><ID>INV-001</ID><Total>42.00</Total></Invoice>\n")
+	if got.Name != "factur-x.xml" || got.MIMEType != "text/xml" {
+		t.Errorf("expected factur-x.xml of type text/xml, got %q of type %q", got.Name, got.MIMEType)
+	}
+	if !bytes.Equal(got.Data, expected) {
+		t.Errorf("expected data %q, got %q", expected, got.Data)
+	}
+}
+
+func TestAttachmentsSharedKids(t *testing.T) {
+	// Every node of the tree lists the next one twice, giving 2^25 paths
+	// down to the one leaf
+	doc := parseFile(t, "../../testdata/shared_name_tree.pdf")
+
+	attachments := doc.Attachments()
+	if len(attachments) != 1 || attachments[0].Name != "shared.txt" {
+		t.Errorf("expected shared.txt once, got %+v", attachments)
+	}
+}
@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"errors"
+)
+
+// Errors returned for the kinds of failure callers may want to tell apart,
+// wrapped with details; test for them with errors.Is
+var (
+	// ErrNotPDF is returned for files without a PDF header
+	ErrNotPDF = errors.New("not a PDF file")
+	// ErrEncrypted is returned for encrypted documents that cannot be read,
+	// for a wrong password or an unsupported security handler
+	ErrEncrypted = errors.New("document is encrypted")
+	// ErrCorruptXRef is returned when the cross-reference table or trailer
+	// cannot be read
+	ErrCorruptXRef = errors.New("corrupt cross-reference table")
+	// ErrPageOutOfRange is returned for page indexes or numbers past the
+	// document's pages
+	ErrPageOutOfRange = errors.New("page out of range")
+	// ErrUnsupportedFilter is returned for streams encoded with a filter
+	// that cannot be decoded
+	ErrUnsupportedFilter = errors.New("unsupported stream filter")
+	// ErrStreamTooLarge is returned when a decoded stream exceeds the size
+	// set with WithMaxStreamSize
+	ErrStreamTooLarge = errors.New("decoded stream too large")
+	// ErrTooManyPages is returned for documents with more pages than the
+	// maximum set with WithMaxPages
+	ErrTooManyPages = errors.New("too many pages")
+	// ErrPageTreeCycle is returned for page trees whose kids refer back to
+	// a node already walked
+	ErrPageTreeCycle = errors.New("page tree cycle")
+)
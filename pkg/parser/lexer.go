@@ -320,7 +320,8 @@ func (l *Lexer) readString() (*Token, error) {
 		}
 	}
 
-	return &Token{Type: TokenString, Value: PDFString(l.buffer)}, nil
+	// Copy the bytes, the buffer is reused for the next token
+	return &Token{Type: TokenString, Value: PDFString(append([]byte{}, l.buffer...))}, nil
 }
 
 // readHexString reads a hexadecimal string token
@@ -0,0 +1,73 @@
+package parser
+
+import "fmt"
+
+// lzwDecode decodes LZWDecode data: codes of 9 to 12 bits, most significant
+// bit first, with 256 clearing the table and 257 ending the data. With
+// EarlyChange, the default, codes widen one entry before the table needs it.
+func (p *PDFParser) lzwDecode(data []byte, parms PDFDict) ([]byte, error) {
+	early := 1
+	if n, ok := parms.GetInt("EarlyChange"); ok {
+		early = int(n)
+	}
+
+	const clearCode, endCode = 256, 257
+	var (
+		out    []byte
+		table  [][]byte
+		prev   []byte
+		width  = 9
+		buffer uint32
+		bits   int
+	)
+	reset := func() {
+		table = table[:0]
+		for i := 0; i < 256; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+		table = append(table, nil, nil)
+		width, prev = 9, nil
+	}
+	reset()
+
+	for _, b := range data {
+		buffer = buffer<<8 | uint32(b)
+		bits += 8
+		for bits >= width {
+			code := int(buffer>>(bits-width)) & (1<<width - 1)
+			bits -= width
+			switch {
+			case code == clearCode:
+				reset()
+				continue
+			case code == endCode:
+				return out, nil
+			}
+
+			var entry []byte
+			switch {
+			case code < len(table) && table[code] != nil:
+				entry = table[code]
+			case code == len(table) && prev != nil:
+				// The code being defined: the previous entry and its first byte
+				entry = append(append([]byte{}, prev...), prev[0])
+			default:
+				return nil, fmt.Errorf("LZWDecode: invalid code %d", code)
+			}
+			out = append(out, entry...)
+			if p.maxStreamSize > 0 && int64(len(out)) > p.maxStreamSize {
+				return nil, fmt.Errorf("%w: more than %d bytes", ErrStreamTooLarge, p.maxStreamSize)
+			}
+
+			if prev != nil && len(table) < 4096 {
+				table = append(table, append(append([]byte{}, prev...), entry[0]))
+			}
+			prev = entry
+			if len(table)+early >= 1<<width && width < 12 {
+				width++
+			}
+		}
+	}
+	// Data may end without an end code
+	return out, nil
+}
@@ -0,0 +1,49 @@
+package parser
+
+import (
+	"bytes"
+	"compress/lzw"
+	"errors"
+	"testing"
+)
+
+func TestLZWDecode(t *testing.T) {
+	p := NewPDFParser(bytes.NewReader(nil), 0)
+
+	// The example of the PDF specification, 7.4.4.2
+	example := []byte{0x80, 0x0b, 0x60, 0x50, 0x22, 0x0c, 0x0c, 0x85, 0x01}
+	if data, err := p.decodeStream(example, PDFName("LZWDecode"), nil); err != nil || string(data) != "-----A---B" {
+		t.Errorf("expected -----A---B, got %q, %v", data, err)
+	}
+
+	// Go's encoder widens codes without the early change, over several
+	// widths of code
+	raw := make([]byte, 20000)
+	for i := range raw {
+		raw[i] = byte(i * i % 251)
+	}
+	var encoded bytes.Buffer
+	w := lzw.NewWriter(&encoded, lzw.MSB, 8)
+	w.Write(raw)
+	w.Close()
+	parms := PDFDict{"EarlyChange": PDFInt(0)}
+	if data, err := p.decodeStream(encoded.Bytes(), PDFName("LZWDecode"), parms); err != nil || !bytes.Equal(data, raw) {
+		t.Errorf("expected %d bytes decoded, got %d, %v", len(raw), len(data), err)
+	}
+
+	p = NewPDFParser(bytes.NewReader(nil), 0, WithMaxStreamSize(1000))
+	if _, err := p.decodeStream(encoded.Bytes(), PDFName("LZWDecode"), parms); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("expected ErrStreamTooLarge, got %v", err)
+	}
+
+	// Predictors apply after LZW as after Flate
+	rows := [][]byte{{1, 2, 3}, {4, 5, 6}}
+	encoded.Reset()
+	w = lzw.NewWriter(&encoded, lzw.MSB, 8)
+	w.Write(pngPredict(rows, []byte{1, 2}, 1))
+	w.Close()
+	parms = PDFDict{"EarlyChange": PDFInt(0), "Predictor": PDFInt(12), "Columns": PDFInt(3)}
+	if data, err := p.decodeStream(encoded.Bytes(), PDFName("LZWDecode"), parms); err != nil || !bytes.Equal(data, []byte{1, 2, 3, 4, 5, 6}) {
+		t.Errorf("expected predicted rows decoded, got %v, %v", data, err)
+	}
+}
@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+)
+
+// defaultObjectStreamCacheSize is how many decoded object streams are kept
+// when not configured otherwise
+const defaultObjectStreamCacheSize = 32
+
+// WithObjectStreamCacheSize bounds how many decoded object streams (/Type
+// /ObjStm) are kept in memory, evicting the least recently used ones. An
+// evicted stream is decoded again when one of its objects is needed; 0
+// disables the cache.
+func WithObjectStreamCacheSize(n int) Option {
+	return func(p *PDFParser) {
+		p.objStmCacheSize = n
+	}
+}
+
+// objectStream is the decoded content of an object stream
+type objectStream struct {
+	number  int
+	dict    PDFDict
+	data    []byte
+	objects []int   // Object numbers, in the order of the header
+	offsets []int64 // Offsets of the objects in data
+}
+
+// objectStreamCache is an LRU cache of decoded object streams keyed by
+// object number
+type objectStreamCache struct {
+	size    int
+	order   *list.List // Most recently used at the front
+	entries map[int]*list.Element
+}
+
+// newObjectStreamCache creates a cache holding up to size streams
+func newObjectStreamCache(size int) *objectStreamCache {
+	return &objectStreamCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[int]*list.Element),
+	}
+}
+
+// get returns a cached stream, marking it most recently used
+func (c *objectStreamCache) get(number int) (*objectStream, bool) {
+	elem, ok := c.entries[number]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*objectStream), true
+}
+
+// add caches a stream, evicting the least recently used ones over the size
+func (c *objectStreamCache) add(stream *objectStream) {
+	if c.size <= 0 {
+		return
+	}
+	if elem, ok := c.entries[stream.number]; ok {
+		elem.Value = stream
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[stream.number] = c.order.PushFront(stream)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*objectStream).number)
+	}
+}
+
+// len returns how many streams are cached
+func (c *objectStreamCache) len() int {
+	return c.order.Len()
+}
+
+// objectStream returns the decoded object stream with the given object
+// number. Object streams bypass the object cache so that their memory is
+// bounded by the object stream cache; a stream that is not cached is read
+// and decoded again.
+func (p *PDFParser) objectStream(number int) (*objectStream, error) {
+	if stream, ok := p.objStmCache.get(number); ok {
+		return stream, nil
+	}
+
+	ref := ObjectRef{Number: number}
+	if p.resolving[ref] {
+		return nil, fmt.Errorf("object stream %d refers to itself while being read", number)
+	}
+	p.resolving[ref] = true
+	defer delete(p.resolving, ref)
+
+	entry, ok := p.xref.Get(ref)
+	if !ok || !entry.InUse {
+		return nil, fmt.Errorf("object stream %d not found", number)
+	}
+	obj, err := p.readObject(ref, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object stream %d: %v", number, err)
+	}
+	stream, ok := obj.(*PDFStream)
+	if !ok {
+		return nil, fmt.Errorf("object %d is not a stream", number)
+	}
+	if name, _ := stream.Dict.GetName("Type"); name != "ObjStm" {
+		return nil, fmt.Errorf("object %d is not an object stream", number)
+	}
+
+	decoded := &objectStream{number: number, dict: stream.Dict, data: stream.Data}
+	if err := decoded.parseHeader(); err != nil {
+		return nil, fmt.Errorf("object stream %d: %v", number, err)
+	}
+	p.objStmCache.add(decoded)
+	return decoded, nil
+}
+
+// parseHeader reads the /N pairs of object number and offset, relative to
+// /First, that begin the stream data
+func (s *objectStream) parseHeader() error {
+	n, ok := s.dict.GetInt("N")
+	if !ok || n < 0 {
+		return fmt.Errorf("invalid N")
+	}
+	first, ok := s.dict.GetInt("First")
+	if !ok || first < 0 || first > int64(len(s.data)) {
+		return fmt.Errorf("invalid First")
+	}
+
+	lexer := NewLexer(bytes.NewReader(s.data[:first]))
+	for i := int64(0); i < n; i++ {
+		var pair [2]int64
+		for j := range pair {
+			token, err := lexer.NextToken()
+			if err != nil {
+				return fmt.Errorf("header ends after %d objects", i)
+			}
+			v, ok := token.Value.(PDFInt)
+			if !ok || v < 0 {
+				return fmt.Errorf("invalid header entry %v", token.Value)
+			}
+			pair[j] = int64(v)
+		}
+		s.objects = append(s.objects, int(pair[0]))
+		s.offsets = append(s.offsets, first+pair[1])
+	}
+	return nil
+}
+
+// compressedObject reads an object stored in an object stream, as listed
+// by a type 2 cross-reference stream entry
+func (p *PDFParser) compressedObject(ref ObjectRef, entry *XRefEntry) (PDFObject, error) {
+	stream, err := p.objectStream(entry.Stream)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trust the header over a stale index
+	index := entry.Index
+	if index < 0 || index >= len(stream.objects) || stream.objects[index] != ref.Number {
+		index = -1
+		for i, num := range stream.objects {
+			if num == ref.Number {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return nil, fmt.Errorf("object %s not in object stream %d", ref.String(), entry.Stream)
+		}
+	}
+
+	offset := stream.offsets[index]
+	if offset > int64(len(stream.data)) {
+		return nil, fmt.Errorf("object %s past the end of object stream %d", ref.String(), entry.Stream)
+	}
+	return p.parseObject(NewLexer(bytes.NewReader(stream.data[offset:])))
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestObjectStreamCacheEviction(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/object_streams.pdf")
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+	p := NewPDFParser(bytes.NewReader(data), int64(len(data)), WithObjectStreamCacheSize(1))
+	if _, err := p.Parse(); err != nil {
+		t.Fatalf("failed to parse PDF: %v", err)
+	}
+
+	// Objects 6 (compressed) and 7 are object streams
+	first, err := p.objectStream(6)
+	if err != nil {
+		t.Fatalf("failed to load object stream 6: %v", err)
+	}
+	if !strings.Contains(string(first.data), "(first)") {
+		t.Errorf("expected decoded data of object stream 6, got %q", first.data)
+	}
+	if _, err := p.objectStream(7); err != nil {
+		t.Fatalf("failed to load object stream 7: %v", err)
+	}
+	if _, ok := p.objStmCache.get(6); ok || p.objStmCache.len() != 1 {
+		t.Errorf("expected object stream 6 evicted, %d streams cached", p.objStmCache.len())
+	}
+
+	// An evicted stream is decoded again
+	again, err := p.objectStream(6)
+	if err != nil {
+		t.Fatalf("failed to reload object stream 6: %v", err)
+	}
+	if !bytes.Equal(again.data, first.data) {
+		t.Errorf("expected the same data after reloading, got %q", again.data)
+	}
+	if _, ok := p.objStmCache.get(7); ok {
+		t.Error("expected object stream 7 evicted by the reload")
+	}
+
+	if _, err := p.objectStream(4); err == nil {
+		t.Error("expected an error for a stream that is not an object stream")
+	}
+}
+
+func TestObjectStreamCacheDisabled(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/object_streams.pdf")
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+	p := NewPDFParser(bytes.NewReader(data), int64(len(data)), WithObjectStreamCacheSize(0))
+	if _, err := p.Parse(); err != nil {
+		t.Fatalf("failed to parse PDF: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		stream, err := p.objectStream(7)
+		if err != nil || !strings.Contains(string(stream.data), "(second)") {
+			t.Fatalf("expected object stream 7 decoded on every use, got %v", err)
+		}
+	}
+	if p.objStmCache.len() != 0 {
+		t.Errorf("expected nothing cached, got %d streams", p.objStmCache.len())
+	}
+}
+
+func TestCompressedObjects(t *testing.T) {
+	// Catalog, page tree, page and font stored in an object stream listed
+	// by a cross-reference stream
+	doc := parseFile(t, "../../testdata/compressed_objects.pdf")
+
+	if len(doc.Pages) != 1 {
+		t.Fatalf("expected 1 page, got %d", len(doc.Pages))
+	}
+	page := doc.Pages[0]
+	if got := string(page.ContentData()); !strings.Contains(got, "(Compressed objects)") {
+		t.Errorf("expected the page contents, got %q", got)
+	}
+	if len(page.MediaBox) != 4 || page.MediaBox[2] != 612 {
+		t.Errorf("expected the page's MediaBox, got %v", page.MediaBox)
+	}
+
+	fonts, _ := page.Resources.GetDict("Font")
+	font, err := doc.GetObject(fonts.Get("F1").(ObjectRef))
+	if err != nil {
+		t.Fatalf("failed to get the font: %v", err)
+	}
+	if name, _ := font.(PDFDict).GetName("BaseFont"); name != "Courier" {
+		t.Errorf("expected the Courier font, got %v", font)
+	}
+
+	// A stale index in the entry is corrected from the stream's header
+	p := doc.parser.(*PDFParser)
+	obj, err := p.compressedObject(ObjectRef{Number: 2}, &XRefEntry{InUse: true, Compressed: true, Stream: 6, Index: 3})
+	if dict, ok := obj.(PDFDict); err != nil || !ok || dict.Get("Count") != PDFInt(1) {
+		t.Errorf("expected the page tree root, got %v, %v", obj, err)
+	}
+	if _, err := p.compressedObject(ObjectRef{Number: 9}, &XRefEntry{InUse: true, Compressed: true, Stream: 6}); err == nil {
+		t.Error("expected an error for an object missing from the stream")
+	}
+}
+
+func TestObjectStreamLengthCycle(t *testing.T) {
+	// The object stream's /Length is object 8, stored in that same stream
+	_, err := parseFileErr(t, "../../testdata/objstm_cycle.pdf")
+	if err == nil || !strings.Contains(err.Error(), "refers to itself") {
+		t.Errorf("expected a reference cycle error, got %v", err)
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"bytes"
 	"compress/flate"
 	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
-	"strings"
 )
 
 // PDFParser is the main PDF parser
@@ -18,32 +19,94 @@ type PDFParser struct {
 	trailer  PDFDict
 	catalog  PDFDict
 	objects  map[ObjectRef]PDFObject
+	
+	xrefSection int         // Cross-reference sections read so far, newest first
+	xrefListed  map[int]int // Section that first listed each object number
+	
+	resolving map[ObjectRef]bool // Objects and object streams being read, to catch references back into them
+	
+	maxPages      int  // 0 for no limit
+	truncatePages bool // Drop pages past maxPages instead of failing
+	
+	objStmCacheSize int // Decoded object streams kept, 0 to decode on every use
+	objStmCache     *objectStreamCache
+	
+	maxStreamSize int64 // Largest decoded stream in bytes, 0 for no limit
+	
+	password string           // User or owner password of encrypted documents
+	security *securityHandler // Decrypts strings and streams, nil if not encrypted
+}
+
+// Option is a function that modifies parser behavior
+type Option func(*PDFParser)
+
+// WithMaxPages limits how many pages are read from the page tree. By default
+// a document with more pages fails to parse.
+func WithMaxPages(n int) Option {
+	return func(p *PDFParser) {
+		p.maxPages = n
+	}
+}
+
+// WithTruncatePages keeps the first pages of a document exceeding the
+// maximum page count instead of failing
+func WithTruncatePages(enabled bool) Option {
+	return func(p *PDFParser) {
+		p.truncatePages = enabled
+	}
+}
+
+// WithMaxStreamSize bounds the size of decoded streams, guarding against
+// small compressed streams that inflate to gigabytes. Decoding a stream
+// past n bytes fails with ErrStreamTooLarge; 0 means no limit.
+func WithMaxStreamSize(n int64) Option {
+	return func(p *PDFParser) {
+		p.maxStreamSize = n
+	}
 }
 
+// errPageLimit stops the page tree walk once enough pages were read
+var errPageLimit = errors.New("page limit reached")
+
 // NewPDFParser creates a new PDF parser
-func NewPDFParser(reader io.ReaderAt, size int64) *PDFParser {
-	return &PDFParser{
-		reader:  reader,
-		size:    size,
-		objects: make(map[ObjectRef]PDFObject),
+func NewPDFParser(reader io.ReaderAt, size int64, opts ...Option) *PDFParser {
+	p := &PDFParser{
+		reader:    reader,
+		size:      size,
+		objects:   make(map[ObjectRef]PDFObject),
+		resolving: make(map[ObjectRef]bool),
+		
+		objStmCacheSize: defaultObjectStreamCacheSize,
+	}
+	for _, opt := range opts {
+		opt(p)
 	}
+	p.objStmCache = newObjectStreamCache(p.objStmCacheSize)
+	return p
 }
 
 // Parse parses the PDF document
 func (p *PDFParser) Parse() (*PDFDocument, error) {
 	// Verify PDF header
 	if err := p.verifyHeader(); err != nil {
-		return nil, fmt.Errorf("invalid PDF header: %v", err)
+		return nil, fmt.Errorf("invalid PDF header: %w", err)
 	}
 
 	// Find and parse xref table
 	xrefOffset, err := p.findXRefOffset()
 	if err != nil {
-		return nil, fmt.Errorf("failed to find xref offset: %v", err)
+		return nil, fmt.Errorf("%w: failed to find xref offset: %v", ErrCorruptXRef, err)
 	}
 
 	if err := p.parseXRef(xrefOffset); err != nil {
-		return nil, fmt.Errorf("failed to parse xref: %v", err)
+		return nil, fmt.Errorf("%w: failed to parse xref: %v", ErrCorruptXRef, err)
+	}
+
+	// Authenticate the password before reading encrypted objects
+	if p.trailer.Get(PDFName("Encrypt")) != nil {
+		if err := p.setupSecurity(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Get catalog
@@ -51,7 +114,7 @@ func (p *PDFParser) Parse() (*PDFDocument, error) {
 		if ref, ok := root.(ObjectRef); ok {
 			cat, err := p.GetObject(ref)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get catalog: %v", err)
+				return nil, fmt.Errorf("failed to get catalog: %w", err)
 			}
 			if dict, ok := cat.(PDFDict); ok {
 				p.catalog = dict
@@ -60,13 +123,13 @@ func (p *PDFParser) Parse() (*PDFDocument, error) {
 			}
 		}
 	} else {
-		return nil, fmt.Errorf("no Root in trailer")
+		return nil, fmt.Errorf("%w: no Root in trailer", ErrCorruptXRef)
 	}
 
 	// Parse pages
 	pages, err := p.parsePages()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse pages: %v", err)
+		return nil, fmt.Errorf("failed to parse pages: %w", err)
 	}
 
 	// Get version
@@ -95,11 +158,11 @@ func (p *PDFParser) verifyHeader() error {
 	header := make([]byte, 8)
 	n, err := p.reader.ReadAt(header, 0)
 	if err != nil || n < 8 {
-		return fmt.Errorf("failed to read header")
+		return fmt.Errorf("%w: failed to read header", ErrNotPDF)
 	}
 
 	if !bytes.HasPrefix(header, []byte("%PDF-")) {
-		return fmt.Errorf("not a PDF file")
+		return ErrNotPDF
 	}
 
 	return nil
@@ -164,35 +227,90 @@ func (p *PDFParser) findXRefOffset() (int64, error) {
 	}
 }
 
-// parseXRef parses the cross-reference table
+// parseXRef parses the cross-reference section at offset and the older
+// sections its /Prev entries chain to, as left by incremental updates.
+// Entries of newer sections take precedence, and the trailer is that of the
+// newest section.
 func (p *PDFParser) parseXRef(offset int64) error {
 	p.xref = NewXRefTable()
+	p.xrefListed = make(map[int]int)
+	p.xrefSection = 0
+	p.trailer = nil
+
+	visited := make(map[int64]bool)
+	for {
+		visited[offset] = true
+		p.xrefSection++
+		trailer, err := p.parseXRefSection(offset)
+		if err != nil {
+			// A damaged older section only loses the objects it alone lists
+			if p.trailer != nil {
+				return nil
+			}
+			return err
+		}
+		if p.trailer == nil {
+			p.trailer = trailer
+		}
+
+		prev, ok := trailer.GetInt("Prev")
+		if !ok || prev < 0 || prev >= p.size || visited[prev] {
+			return nil
+		}
+		offset = prev
+	}
+}
 
+// parseXRefSection parses a cross-reference table or stream at offset,
+// returning its trailer dictionary
+func (p *PDFParser) parseXRefSection(offset int64) (PDFDict, error) {
 	// Read xref section
 	buf := make([]byte, 65536) // Start with 64KB buffer
 	n, err := p.reader.ReadAt(buf, offset)
 	if err != nil && err != io.EOF {
-		return err
+		return nil, err
 	}
 	buf = buf[:n]
 
 	lexer := NewLexer(bytes.NewReader(buf))
 	
-	// Read "xref" keyword
+	// Read "xref" keyword, or the object number of a cross-reference stream
 	token, err := lexer.NextToken()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if _, ok := token.Value.(PDFInt); ok {
+		return p.parseXRefStream(offset)
 	}
 	if kw, ok := token.Value.(string); !ok || kw != "xref" {
-		return fmt.Errorf("expected 'xref', got %v", token.Value)
+		return nil, fmt.Errorf("expected 'xref', got %v", token.Value)
+	}
+
+	trailer, err := p.parseXRefTable(lexer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hybrid files list the objects in object streams in a
+	// cross-reference stream that older readers do not know about
+	if stm, ok := trailer.GetInt("XRefStm"); ok {
+		if _, err := p.parseXRefStream(stm); err != nil {
+			return nil, fmt.Errorf("failed to parse XRefStm: %v", err)
+		}
 	}
 
+	return trailer, nil
+}
+
+// parseXRefTable parses the subsections of a classic cross-reference table
+// after the xref keyword, and the trailer following them
+func (p *PDFParser) parseXRefTable(lexer *Lexer) (PDFDict, error) {
 	// Parse xref subsections
 	for {
 		// Read first object number or "trailer" keyword
-		token, err = lexer.NextToken()
+		token, err := lexer.NextToken()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// Check if we've reached the trailer
@@ -208,13 +326,13 @@ func (p *PDFParser) parseXRef(offset int64) error {
 		case PDFFloat:
 			firstObj = int64(v)
 		default:
-			return fmt.Errorf("expected object number or 'trailer', got %T: %v", token.Value, token.Value)
+			return nil, fmt.Errorf("expected object number or 'trailer', got %T: %v", token.Value, token.Value)
 		}
 
 		// Read count
 		token, err = lexer.NextToken()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		
 		var count int64
@@ -224,7 +342,7 @@ func (p *PDFParser) parseXRef(offset int64) error {
 		case PDFFloat:
 			count = int64(v)
 		default:
-			return fmt.Errorf("expected count, got %T: %v", token.Value, token.Value)
+			return nil, fmt.Errorf("expected count, got %T: %v", token.Value, token.Value)
 		}
 
 		// Read entries
@@ -232,7 +350,7 @@ func (p *PDFParser) parseXRef(offset int64) error {
 			// Read offset
 			token, err = lexer.NextToken()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			
 			var offsetVal int64
@@ -242,13 +360,13 @@ func (p *PDFParser) parseXRef(offset int64) error {
 			case PDFFloat:
 				offsetVal = int64(v)
 			default:
-				return fmt.Errorf("expected offset for entry %d, got %T: %v", i, token.Value, token.Value)
+				return nil, fmt.Errorf("expected offset for entry %d, got %T: %v", i, token.Value, token.Value)
 			}
 
 			// Read generation
 			token, err = lexer.NextToken()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			
 			var gen int64
@@ -258,17 +376,17 @@ func (p *PDFParser) parseXRef(offset int64) error {
 			case PDFFloat:
 				gen = int64(v)
 			default:
-				return fmt.Errorf("expected generation for entry %d, got %T: %v", i, token.Value, token.Value)
+				return nil, fmt.Errorf("expected generation for entry %d, got %T: %v", i, token.Value, token.Value)
 			}
 
 			// Read flag (n or f)
 			token, err = lexer.NextToken()
 			if err != nil {
-				return err
+				return nil, err
 			}
 			flag, ok := token.Value.(string)
 			if !ok {
-				return fmt.Errorf("expected flag for entry %d, got %T: %v", i, token.Value, token.Value)
+				return nil, fmt.Errorf("expected flag for entry %d, got %T: %v", i, token.Value, token.Value)
 			}
 
 			ref := ObjectRef{
@@ -282,23 +400,39 @@ func (p *PDFParser) parseXRef(offset int64) error {
 				InUse:      flag == "n",
 			}
 
-			p.xref.Add(ref, entry)
+			p.addXRefEntry(ref, entry)
 		}
 	}
 
 	// Parse trailer dictionary
 	trailer, err := p.parseObject(lexer)
 	if err != nil {
-		return fmt.Errorf("failed to parse trailer: %v", err)
+		return nil, fmt.Errorf("failed to parse trailer: %v", err)
 	}
 
 	dict, ok := trailer.(PDFDict)
 	if !ok {
-		return fmt.Errorf("trailer is not a dictionary")
+		return nil, fmt.Errorf("trailer is not a dictionary")
 	}
-	p.trailer = dict
+	return dict, nil
+}
 
-	return nil
+// addXRefEntry adds an entry unless a newer section already listed the
+// object number, under any generation: an object an update freed or
+// renumbered keeps none of its older entries. Within one section, an entry
+// in use replaces a free one, as the cross-reference stream of a hybrid
+// file does for the objects its table lists as free.
+func (p *PDFParser) addXRefEntry(ref ObjectRef, entry *XRefEntry) {
+	if section, ok := p.xrefListed[ref.Number]; ok {
+		if section < p.xrefSection {
+			return
+		}
+		if old, ok := p.xref.Get(ref); ok && old.InUse {
+			return
+		}
+	}
+	p.xrefListed[ref.Number] = p.xrefSection
+	p.xref.Add(ref, entry)
 }
 
 // GetObject retrieves an object by reference
@@ -314,11 +448,53 @@ func (p *PDFParser) GetObject(ref ObjectRef) (PDFObject, error) {
 		return PDFNull{}, nil
 	}
 
+	// A stream's /Length may refer to an object that needs the stream
+	if p.resolving[ref] {
+		return nil, fmt.Errorf("object %s refers to itself while being read", ref.String())
+	}
+	p.resolving[ref] = true
+	defer delete(p.resolving, ref)
+
+	var obj PDFObject
+	var err error
+	if entry.Compressed {
+		obj, err = p.compressedObject(ref, entry)
+	} else {
+		obj, err = p.readObject(ref, entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Cache the object
+	p.objects[ref] = obj
+
+	return obj, nil
+}
+
+// readObject reads and parses the object an xref entry points to
+func (p *PDFParser) readObject(ref ObjectRef, entry *XRefEntry) (PDFObject, error) {
+	found, obj, err := p.readIndirectObject(entry.Offset)
+	if err != nil {
+		return nil, err
+	}
+	if found.Number != ref.Number {
+		return nil, fmt.Errorf("object number mismatch")
+	}
+	if found.Generation != ref.Generation {
+		return nil, fmt.Errorf("generation number mismatch")
+	}
+	return obj, nil
+}
+
+// readIndirectObject reads and parses the indirect object starting at
+// offset, returning its object number and generation with it
+func (p *PDFParser) readIndirectObject(offset int64) (ObjectRef, PDFObject, error) {
 	// Read object from file - use a larger buffer to ensure we get the full object
 	buf := make([]byte, 131072) // 128KB buffer
-	n, err := p.reader.ReadAt(buf, entry.Offset)
+	n, err := p.reader.ReadAt(buf, offset)
 	if err != nil && err != io.EOF {
-		return nil, err
+		return ObjectRef{}, nil, err
 	}
 	buf = buf[:n]
 
@@ -327,36 +503,42 @@ func (p *PDFParser) GetObject(ref ObjectRef) (PDFObject, error) {
 	// Read object number
 	token, err := lexer.NextToken()
 	if err != nil {
-		return nil, err
+		return ObjectRef{}, nil, err
 	}
 	objNum, ok := token.Value.(PDFInt)
-	if !ok || int(objNum) != ref.Number {
-		return nil, fmt.Errorf("object number mismatch")
+	if !ok {
+		return ObjectRef{}, nil, fmt.Errorf("expected object number, got %v", token.Value)
 	}
 
 	// Read generation number
 	token, err = lexer.NextToken()
 	if err != nil {
-		return nil, err
+		return ObjectRef{}, nil, err
 	}
 	genNum, ok := token.Value.(PDFInt)
-	if !ok || int(genNum) != ref.Generation {
-		return nil, fmt.Errorf("generation number mismatch")
+	if !ok {
+		return ObjectRef{}, nil, fmt.Errorf("expected generation number, got %v", token.Value)
 	}
+	ref := ObjectRef{Number: int(objNum), Generation: int(genNum)}
 
 	// Read "obj" keyword
 	token, err = lexer.NextToken()
 	if err != nil {
-		return nil, err
+		return ref, nil, err
 	}
 	if kw, ok := token.Value.(string); !ok || kw != "obj" {
-		return nil, fmt.Errorf("expected 'obj', got %v", token.Value)
+		return ref, nil, fmt.Errorf("expected 'obj', got %v", token.Value)
 	}
 
 	// Parse the object
 	obj, err := p.parseObject(lexer)
 	if err != nil {
-		return nil, err
+		return ref, nil, err
+	}
+	if p.security != nil && ref != p.security.encryptRef {
+		if obj, err = p.security.decryptStrings(ref, obj); err != nil {
+			return ref, nil, fmt.Errorf("failed to decrypt object %s: %v", ref.String(), err)
+		}
 	}
 
 	// Check for stream
@@ -364,18 +546,15 @@ func (p *PDFParser) GetObject(ref ObjectRef) (PDFObject, error) {
 		token, _ = lexer.NextToken()
 		if kw, ok := token.Value.(string); ok && kw == "stream" {
 			// Read stream data
-			stream, err := p.readStream(lexer, dict, entry.Offset+lexer.Position())
+			stream, err := p.readStream(lexer, dict, offset+lexer.Position(), ref)
 			if err != nil {
-				return nil, err
+				return ref, nil, err
 			}
 			obj = stream
 		}
 	}
 
-	// Cache the object
-	p.objects[ref] = obj
-
-	return obj, nil
+	return ref, obj, nil
 }
 
 // parseObject parses a PDF object
@@ -537,16 +716,14 @@ func (p *PDFParser) handleToken(token *Token, lexer *Lexer) (PDFObject, error) {
 	}
 }
 
-// readStream reads stream data
-func (p *PDFParser) readStream(lexer *Lexer, dict PDFDict, offset int64) (*PDFStream, error) {
-	// Get stream length
-	lengthObj := dict.Get(PDFName("Length"))
-	if lengthObj == nil {
-		return nil, fmt.Errorf("stream missing Length")
-	}
-
-	var length int64
-	switch v := lengthObj.(type) {
+// readStream reads the data of object ref's stream, decrypting and decoding
+// it
+func (p *PDFParser) readStream(lexer *Lexer, dict PDFDict, offset int64, ref ObjectRef) (*PDFStream, error) {
+	// Get stream length. A missing length is recovered from the position
+	// of the endstream keyword below.
+	var length int64 = -1
+	switch v := dict.Get(PDFName("Length")).(type) {
+	case nil:
 	case PDFInt:
 		length = int64(v)
 	case ObjectRef:
@@ -577,17 +754,36 @@ func (p *PDFParser) readStream(lexer *Lexer, dict PDFDict, offset int64) (*PDFSt
 		skipBytes = 1
 	}
 	
+	// Many PDFs declare a wrong length; trust the endstream keyword instead
+	start := offset + int64(skipBytes)
+	length = p.verifyStreamLength(start, length)
+	if length < 0 {
+		return nil, fmt.Errorf("stream missing Length and endstream")
+	}
+
+	// The declared length is untrusted; no stream runs past the file
+	if length > p.size-start {
+		length = max(p.size-start, 0)
+	}
+
 	// Read stream data
 	data := make([]byte, length)
-	n, err := p.reader.ReadAt(data, offset+int64(skipBytes))
+	n, err := p.reader.ReadAt(data, start)
 	if err != nil && err != io.EOF {
 		return nil, err
 	}
 	data = data[:n]
 
+	if p.security != nil && p.security.encryptsStream(dict) {
+		data, err = p.security.decrypt(ref, p.security.streamMethod, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt stream %s: %v", ref.String(), err)
+		}
+	}
+
 	// Decode if necessary
 	if filter := dict.Get(PDFName("Filter")); filter != nil {
-		data, err = p.decodeStream(data, filter)
+		data, err = p.decodeStream(data, filter, dict.Get(PDFName("DecodeParms")))
 		if err != nil {
 			return nil, err
 		}
@@ -599,8 +795,56 @@ func (p *PDFParser) readStream(lexer *Lexer, dict PDFDict, offset int64) (*PDFSt
 	}, nil
 }
 
-// decodeStream decodes stream data based on filter
-func (p *PDFParser) decodeStream(data []byte, filter PDFObject) ([]byte, error) {
+// streamScanChunk is how many bytes are read at a time when searching for
+// the endstream keyword
+const streamScanChunk = 64 * 1024
+
+// verifyStreamLength checks that the stream data starting at start is
+// followed by the endstream keyword after length bytes. If not, the length
+// is corrected to end where the next endstream keyword begins, less the
+// end-of-line marker before it. The declared length, or -1 if it is unknown,
+// is kept when no endstream keyword follows.
+func (p *PDFParser) verifyStreamLength(start, length int64) int64 {
+	if length >= 0 {
+		buf := make([]byte, 32)
+		n, _ := p.reader.ReadAt(buf, start+length)
+		if bytes.HasPrefix(bytes.TrimLeft(buf[:n], " \t\r\n\f\x00"), []byte("endstream")) {
+			return length
+		}
+	}
+
+	keyword := []byte("endstream")
+	for pos := start; pos < p.size; pos += streamScanChunk {
+		// Overlap the chunks so a keyword across their boundary is found
+		buf := make([]byte, streamScanChunk+len(keyword))
+		n, _ := p.reader.ReadAt(buf, pos)
+		i := bytes.Index(buf[:n], keyword)
+		if i < 0 {
+			continue
+		}
+
+		end := pos + int64(i)
+		eol := make([]byte, 2)
+		if end-start >= 2 {
+			p.reader.ReadAt(eol, end-2)
+		} else if end-start == 1 {
+			p.reader.ReadAt(eol[1:], end-1)
+		}
+		switch {
+		case eol[0] == '\r' && eol[1] == '\n':
+			end -= 2
+		case eol[1] == '\n' || eol[1] == '\r':
+			end--
+		}
+		return end - start
+	}
+	return length
+}
+
+// decodeStream decodes stream data based on filter and its decode
+// parameters, a dictionary for a single filter or an array with one entry
+// per filter
+func (p *PDFParser) decodeStream(data []byte, filter, decodeParms PDFObject) ([]byte, error) {
 	var filters []PDFName
 
 	switch f := filter.(type) {
@@ -616,19 +860,45 @@ func (p *PDFParser) decodeStream(data []byte, filter PDFObject) ([]byte, error)
 		return data, nil
 	}
 
+	parms := make([]PDFDict, len(filters))
+	switch v := p.resolve(decodeParms).(type) {
+	case PDFDict:
+		if len(parms) > 0 {
+			parms[0] = v
+		}
+	case PDFArray:
+		for i := 0; i < len(v) && i < len(parms); i++ {
+			parms[i], _ = p.resolve(v[i]).(PDFDict)
+		}
+	}
+
 	// Apply filters in order
-	for _, f := range filters {
+	for i, f := range filters {
 		var err error
 		switch string(f) {
 		case "FlateDecode":
 			data, err = p.flateDecode(data)
+			if err == nil {
+				data, err = unpredict(data, parms[i], p.maxStreamSize)
+			}
+		case "LZWDecode":
+			data, err = p.lzwDecode(data, parms[i])
+			if err == nil {
+				data, err = unpredict(data, parms[i], p.maxStreamSize)
+			}
 		case "ASCIIHexDecode":
 			data, err = p.asciiHexDecode(data)
 		case "ASCII85Decode":
 			data, err = p.ascii85Decode(data)
-		default:
-			// Unknown filter, return data as-is
+		case "RunLengthDecode":
+			data, err = p.runLengthDecode(data)
+		case "Crypt":
+			data, err = cryptFilter(data, parms[i])
+		case "DCTDecode", "JPXDecode", "JBIG2Decode", "CCITTFaxDecode":
+			// Image codecs are left to the caller, data stays encoded
 			return data, nil
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFilter, f)
 		}
 		if err != nil {
 			return nil, err
@@ -638,20 +908,71 @@ func (p *PDFParser) decodeStream(data []byte, filter PDFObject) ([]byte, error)
 	return data, nil
 }
 
+// Filter names a stream filter and its integer decode parameters, such as
+// Predictor and Columns
+type Filter struct {
+	Name  string
+	Parms map[string]int
+}
+
+// DecodeFilters applies filters to data in order, as the parser decodes its
+// own streams, for streams read by other parsers. Decoding past maxSize bytes
+// fails with ErrStreamTooLarge; 0 means no limit. Image codecs leave the data
+// encoded.
+func DecodeFilters(data []byte, filters []Filter, maxSize int64) ([]byte, error) {
+	names := make(PDFArray, len(filters))
+	parms := make(PDFArray, len(filters))
+	for i, f := range filters {
+		names[i] = PDFName(f.Name)
+		dict := PDFDict{}
+		for key, value := range f.Parms {
+			dict[PDFName(key)] = PDFInt(value)
+		}
+		parms[i] = dict
+	}
+	p := &PDFParser{maxStreamSize: maxSize}
+	return p.decodeStream(data, names, parms)
+}
+
+// resolve follows an indirect reference, nil if it cannot be read
+func (p *PDFParser) resolve(obj PDFObject) PDFObject {
+	if ref, ok := obj.(ObjectRef); ok {
+		resolved, err := p.GetObject(ref)
+		if err != nil {
+			return nil
+		}
+		return resolved
+	}
+	return obj
+}
+
 // flateDecode decodes FlateDecode (zlib) compressed data
 func (p *PDFParser) flateDecode(data []byte) ([]byte, error) {
 	// Try zlib first (with header)
 	reader, err := zlib.NewReader(bytes.NewReader(data))
 	if err == nil {
 		defer reader.Close()
-		return io.ReadAll(reader)
+		return p.readDecoded(reader)
 	}
 	
 	// If zlib fails, try raw DEFLATE (without header)
 	// This is common in PDF files
 	reader2 := flate.NewReader(bytes.NewReader(data))
 	defer reader2.Close()
-	return io.ReadAll(reader2)
+	return p.readDecoded(reader2)
+}
+
+// readDecoded reads the output of a decoding filter, failing once it
+// exceeds the maximum stream size
+func (p *PDFParser) readDecoded(r io.Reader) ([]byte, error) {
+	if p.maxStreamSize <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, p.maxStreamSize+1))
+	if int64(len(data)) > p.maxStreamSize {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrStreamTooLarge, p.maxStreamSize)
+	}
+	return data, err
 }
 
 // asciiHexDecode decodes ASCIIHexDecode data
@@ -682,11 +1003,18 @@ func (p *PDFParser) asciiHexDecode(data []byte) ([]byte, error) {
 	return result, nil
 }
 
-// ascii85Decode decodes ASCII85Decode data
+// ascii85Decode decodes ASCII85Decode data, which ends at the ~> marker.
+// The <~ prefix of Adobe's format is skipped if present.
 func (p *PDFParser) ascii85Decode(data []byte) ([]byte, error) {
-	// Simple implementation - can be improved
-	// For now, return data as-is
-	return data, nil
+	data = bytes.TrimPrefix(bytes.TrimLeft(data, " \t\r\n\f\x00"), []byte("<~"))
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	decoded, err := p.readDecoded(ascii85.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("ASCII85Decode: %w", err)
+	}
+	return decoded, nil
 }
 
 // parsePages parses all pages from the document
@@ -710,7 +1038,11 @@ func (p *PDFParser) parsePages() ([]*PDFPage, error) {
 	// Parse page tree recursively
 	var pages []*PDFPage
 	pageNum := 1
-	err = p.parsePageTree(pagesDict, &pages, &pageNum)
+	visited := map[ObjectRef]bool{pagesRef: true}
+	err = p.parsePageTree(pagesDict, &pages, &pageNum, visited)
+	if errors.Is(err, errPageLimit) {
+		return pages, nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -719,12 +1051,14 @@ func (p *PDFParser) parsePages() ([]*PDFPage, error) {
 }
 
 // parsePageTree recursively parses the page tree
-func (p *PDFParser) parsePageTree(node PDFDict, pages *[]*PDFPage, pageNum *int) error {
-	return p.parsePageTreeWithInheritance(node, pages, pageNum, nil)
+func (p *PDFParser) parsePageTree(node PDFDict, pages *[]*PDFPage, pageNum *int, visited map[ObjectRef]bool) error {
+	return p.parsePageTreeWithInheritance(node, pages, pageNum, nil, visited)
 }
 
-// parsePageTreeWithInheritance recursively parses the page tree with inheritance
-func (p *PDFParser) parsePageTreeWithInheritance(node PDFDict, pages *[]*PDFPage, pageNum *int, inherited PDFDict) error {
+// parsePageTreeWithInheritance recursively parses the page tree with inheritance.
+// visited holds the nodes already seen, so that kids referring back up the
+// tree are reported instead of recursing forever.
+func (p *PDFParser) parsePageTreeWithInheritance(node PDFDict, pages *[]*PDFPage, pageNum *int, inherited PDFDict, visited map[ObjectRef]bool) error {
 	nodeType, ok := node.GetName(PDFName("Type"))
 	if !ok {
 		return fmt.Errorf("missing Type in page tree node")
@@ -763,12 +1097,17 @@ func (p *PDFParser) parsePageTreeWithInheritance(node PDFDict, pages *[]*PDFPage
 
 		for _, kidRef := range kids {
 			if ref, ok := kidRef.(ObjectRef); ok {
+				if visited[ref] {
+					return fmt.Errorf("%w at %s", ErrPageTreeCycle, ref.String())
+				}
+				visited[ref] = true
+				
 				kidObj, err := p.GetObject(ref)
 				if err != nil {
 					return err
 				}
 				if kidDict, ok := kidObj.(PDFDict); ok {
-					err = p.parsePageTreeWithInheritance(kidDict, pages, pageNum, childInherited)
+					err = p.parsePageTreeWithInheritance(kidDict, pages, pageNum, childInherited, visited)
 					if err != nil {
 						return err
 					}
@@ -777,6 +1116,13 @@ func (p *PDFParser) parsePageTreeWithInheritance(node PDFDict, pages *[]*PDFPage
 		}
 
 	case "Page":
+		if p.maxPages > 0 && len(*pages) >= p.maxPages {
+			if p.truncatePages {
+				return errPageLimit
+			}
+			return fmt.Errorf("%w: document has more than %d pages", ErrTooManyPages, p.maxPages)
+		}
+		
 		// Leaf node - create page with inherited properties
 		// Merge inherited properties with page properties (page properties override)
 		mergedDict := make(PDFDict)
@@ -812,32 +1158,26 @@ func (p *PDFParser) parsePageTreeWithInheritance(node PDFDict, pages *[]*PDFPage
 
 		// Get content streams from merged dictionary
 		if contents := mergedDict.Get(PDFName("Contents")); contents != nil {
-			switch c := contents.(type) {
-			case ObjectRef:
-				obj, err := p.GetObject(c)
+			refs, ok := contents.(PDFArray)
+			if !ok {
+				refs = PDFArray{contents}
+			}
+			for _, item := range refs {
+				ref, ok := item.(ObjectRef)
+				if !ok {
+					continue
+				}
+				obj, err := p.GetObject(ref)
 				if err != nil {
-					fmt.Printf("Error getting content stream %s: %v\n", c.String(), err)
-				} else {
-					if stream, ok := obj.(*PDFStream); ok {
-						page.Contents = []PDFStream{*stream}
-					} else {
-						fmt.Printf("Content object %s is not a stream: %T\n", c.String(), obj)
+					if page.ContentErr == nil {
+						page.ContentErr = fmt.Errorf("content stream %s: %w", ref.String(), err)
 					}
+					continue
 				}
-			case PDFArray:
-				for _, item := range c {
-					if ref, ok := item.(ObjectRef); ok {
-						obj, err := p.GetObject(ref)
-						if err != nil {
-							fmt.Printf("Error getting content stream %s: %v\n", ref.String(), err)
-						} else {
-							if stream, ok := obj.(*PDFStream); ok {
-								page.Contents = append(page.Contents, *stream)
-							} else {
-								fmt.Printf("Content object %s is not a stream: %T\n", ref.String(), obj)
-							}
-						}
-					}
+				if stream, ok := obj.(*PDFStream); ok {
+					page.Contents = append(page.Contents, *stream)
+				} else if page.ContentErr == nil {
+					page.ContentErr = fmt.Errorf("content object %s is not a stream: %T", ref.String(), obj)
 				}
 			}
 		}
@@ -906,7 +1246,7 @@ func (d *PDFDocument) GetPageCount() int {
 // GetPage returns a specific page by index (0-based)
 func (d *PDFDocument) GetPage(index int) (*PDFPage, error) {
 	if index < 0 || index >= len(d.Pages) {
-		return nil, fmt.Errorf("page index out of range")
+		return nil, fmt.Errorf("%w: index %d not in [0, %d)", ErrPageOutOfRange, index, len(d.Pages))
 	}
 	return d.Pages[index], nil
 }
@@ -935,12 +1275,46 @@ func (d *PDFDocument) GetObject(ref ObjectRef) (PDFObject, error) {
 	return nil, fmt.Errorf("object %s not found in document", ref.String())
 }
 
+// GetObjectBytes returns an object by its number and generation, along with
+// the decoded data when it is a stream. The data is nil for other objects,
+// and a copy the caller may change for streams, whose objects are cached.
+// It is meant for inspecting a document's objects when diagnosing problems.
+func (d *PDFDocument) GetObjectBytes(num, gen int) ([]byte, PDFObject, error) {
+	obj, err := d.GetObject(ObjectRef{Number: num, Generation: gen})
+	if err != nil {
+		return nil, nil, err
+	}
+	if stream, ok := obj.(*PDFStream); ok {
+		return bytes.Clone(stream.Data), obj, nil
+	}
+	return nil, obj, nil
+}
+
 // GetContentString returns the content stream as a string (for debugging)
 func (p *PDFPage) GetContentString() string {
-	var result strings.Builder
-	for _, stream := range p.Contents {
-		result.Write(stream.Data)
-		result.WriteString("\n")
+	return string(p.ContentData())
+}
+
+// ContentData returns the page's content streams joined into one, to be
+// tokenized as a single stream
+func (p *PDFPage) ContentData() []byte {
+	streams := make([][]byte, len(p.Contents))
+	for i, stream := range p.Contents {
+		streams[i] = stream.Data
+	}
+	return JoinContentStreams(streams)
+}
+
+// JoinContentStreams joins a page's content streams into one, separated by
+// newlines. Streams divide only at token boundaries, so a number ending
+// one stream and one starting the next stay two numbers.
+func JoinContentStreams(streams [][]byte) []byte {
+	var data []byte
+	for _, stream := range streams {
+		if len(data) > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, stream...)
 	}
-	return result.String()
+	return data
 }
\ No newline at end of file
@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// parseFile parses a PDF file with the native parser
+func parseFile(t *testing.T, path string, opts ...Option) *PDFDocument {
+	t.Helper()
+	doc, err := parseFileErr(t, path, opts...)
+	if err != nil {
+		t.Fatalf("failed to parse %s: %v", path, err)
+	}
+	return doc
+}
+
+// parseFileErr parses a PDF file with the native parser, returning the error
+func parseFileErr(t *testing.T, path string, opts ...Option) (*PDFDocument, error) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return NewPDFParser(bytes.NewReader(data), int64(len(data)), opts...).Parse()
+}
+
+func TestParseMaxPages(t *testing.T) {
+	if doc := parseFile(t, "../../testdata/two_pages.pdf", WithMaxPages(2)); len(doc.Pages) != 2 {
+		t.Errorf("expected 2 pages within the limit, got %d", len(doc.Pages))
+	}
+
+	if _, err := parseFileErr(t, "../../testdata/two_pages.pdf", WithMaxPages(1)); err == nil {
+		t.Error("expected an error for a document exceeding the page limit")
+	}
+
+	doc := parseFile(t, "../../testdata/two_pages.pdf", WithMaxPages(1), WithTruncatePages(true))
+	if len(doc.Pages) != 1 || doc.Pages[0].Number != 1 {
+		t.Errorf("expected only the first page when truncating, got %d pages", len(doc.Pages))
+	}
+}
+
+func TestParsePageTreeCycle(t *testing.T) {
+	// A Pages node lists the root Pages node among its kids
+	_, err := parseFileErr(t, "../../testdata/page_tree_cycle.pdf")
+	if !errors.Is(err, ErrPageTreeCycle) {
+		t.Errorf("expected a page tree cycle error, got %v", err)
+	}
+}
+
+func TestContentDataSplitNumber(t *testing.T) {
+	// The page's Td operands 72 and 720 end one stream and start the next
+	doc := parseFile(t, "../../testdata/split_content.pdf")
+	if got := string(doc.Pages[0].ContentData()); !strings.Contains(got, "Tf 72\n720 Td") {
+		t.Errorf("expected the numbers kept apart, got %q", got)
+	}
+
+	page := &PDFPage{Contents: []PDFStream{{Data: []byte("q")}, {Data: []byte("BT")}, {Data: []byte("/F1")}, {Data: []byte("12 Tf")}}}
+	if got := string(page.ContentData()); got != "q\nBT\n/F1\n12 Tf" {
+		t.Errorf("expected streams separated at token boundaries, got %q", got)
+	}
+}
+
+func TestReadStreamWrongLength(t *testing.T) {
+	// The content stream declares a Length of 20 but is longer
+	doc := parseFile(t, "../../testdata/wrong_length.pdf")
+	expected := "BT /F1 12 Tf 72 720 Td (Wrong length) Tj ET"
+	if got := string(doc.Pages[0].ContentData()); got != expected {
+		t.Errorf("expected the stream read up to endstream, got %q", got)
+	}
+}
+
+func TestReadStreamLengthPastEOF(t *testing.T) {
+	// A terabyte Length and no endstream keyword to correct it
+	data := []byte("\nunterminated")
+	p := NewPDFParser(bytes.NewReader(data), int64(len(data)))
+	stream, err := p.readStream(nil, PDFDict{"Length": PDFInt(1 << 40)}, 0, ObjectRef{})
+	if err != nil || string(stream.Data) != "unterminated" {
+		t.Errorf("expected the stream read to the end of the file, got %v", err)
+	}
+}
+
+func TestDecodeStreamMaxSize(t *testing.T) {
+	// 16 MiB of zeros compress to a few KiB
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(make([]byte, 16<<20))
+	w.Close()
+
+	p := NewPDFParser(bytes.NewReader(nil), 0, WithMaxStreamSize(1<<20))
+	if _, err := p.decodeStream(compressed.Bytes(), PDFName("FlateDecode"), nil); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("expected ErrStreamTooLarge, got %v", err)
+	}
+
+	p = NewPDFParser(bytes.NewReader(nil), 0, WithMaxStreamSize(16<<20))
+	if data, err := p.decodeStream(compressed.Bytes(), PDFName("FlateDecode"), nil); err != nil || len(data) != 16<<20 {
+		t.Errorf("expected the stream decoded within the limit, got %d bytes, %v", len(data), err)
+	}
+}
+
+func TestDecodeFilters(t *testing.T) {
+	// Zeros through ASCIIHex, Flate and the PNG Up predictor
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	for range 1 << 12 {
+		w.Write([]byte{2, 0, 0, 0, 0})
+	}
+	w.Close()
+	encoded := []byte(hex.EncodeToString(compressed.Bytes()) + ">")
+	filters := []Filter{
+		{Name: "ASCIIHexDecode"},
+		{Name: "FlateDecode", Parms: map[string]int{"Predictor": 12, "Columns": 4}},
+	}
+
+	if data, err := DecodeFilters(encoded, filters, 0); err != nil || !bytes.Equal(data, make([]byte, 4<<12)) {
+		t.Errorf("expected %d zeros, got %d bytes, %v", 4<<12, len(data), err)
+	}
+	if _, err := DecodeFilters(encoded, filters, 1<<10); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("expected ErrStreamTooLarge, got %v", err)
+	}
+}
+
+func TestASCII85Decode(t *testing.T) {
+	// Encoded in Adobe's format, wrapped at eight columns
+	tests := []struct {
+		encoded string
+		decoded string
+	}{
+		{"<~9jqo^B\nlbD-BleB\n1DJ+*+F(\nf,q~>", "Man is distinguished"},
+		{"<~zz@:E^\n~>", "\x00\x00\x00\x00\x00\x00\x00\x00abc"},
+		{"@/~>", "a"},
+		{"@:B~>", "ab"},
+		{" @:E^ ~>", "abc"},
+		{"<~BOu!rD\n]j7BEbo7\n~>\n", "hello world"},
+		{"~>", ""},
+	}
+	p := NewPDFParser(bytes.NewReader(nil), 0)
+	for _, test := range tests {
+		data, err := p.decodeStream([]byte(test.encoded), PDFName("ASCII85Decode"), nil)
+		if err != nil || string(data) != test.decoded {
+			t.Errorf("decode %q: expected %q, got %q, %v", test.encoded, test.decoded, data, err)
+		}
+	}
+
+	// Characters past u, and z within a group, are invalid
+	for _, encoded := range []string{"@:{E^~>", "@:zE^~>"} {
+		if _, err := p.decodeStream([]byte(encoded), PDFName("ASCII85Decode"), nil); err == nil {
+			t.Errorf("decode %q: expected an error", encoded)
+		}
+	}
+
+	// A Flate stream encoded as ASCII85 decodes through both filters
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write([]byte("BT /F1 12 Tf (Hello) Tj ET"))
+	w.Close()
+	encoded := make([]byte, ascii85.MaxEncodedLen(compressed.Len()))
+	encoded = append(encoded[:ascii85.Encode(encoded, compressed.Bytes())], "~>"...)
+	data, err := p.decodeStream(encoded, PDFArray{PDFName("ASCII85Decode"), PDFName("FlateDecode")}, nil)
+	if err != nil || string(data) != "BT /F1 12 Tf (Hello) Tj ET" {
+		t.Errorf("expected the content through both filters, got %q, %v", data, err)
+	}
+}
+
+func TestGetObjectBytes(t *testing.T) {
+	// Object 2 is the Flate-compressed content stream of the page
+	doc := parseFile(t, "../../testdata/sample.pdf")
+	data, obj, err := doc.GetObjectBytes(2, 0)
+	if err != nil {
+		t.Fatalf("failed to get object 2: %v", err)
+	}
+	if _, ok := obj.(*PDFStream); !ok {
+		t.Fatalf("expected a stream, got %T", obj)
+	}
+	for _, op := range []string{"BT", "Tf", "ET"} {
+		if !bytes.Contains(data, []byte(op)) {
+			t.Errorf("expected the decoded content to contain %s, got %q", op, data)
+		}
+	}
+
+	// Changing the data leaves the cached stream as it was
+	original := bytes.Clone(data)
+	clear(data)
+	if again, _, _ := doc.GetObjectBytes(2, 0); !bytes.Equal(again, original) {
+		t.Errorf("expected the stream data unchanged, got %q", again)
+	}
+
+	// Object 4 is the Pages dictionary
+	if data, obj, err = doc.GetObjectBytes(4, 0); err != nil || data != nil {
+		t.Errorf("expected no data for the Pages dictionary, got %q (%v)", data, err)
+	}
+	if _, ok := obj.(PDFDict); !ok {
+		t.Errorf("expected a dictionary, got %T", obj)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]error{
+		"../../testdata/not_a_pdf.pdf":    ErrNotPDF,
+		"../../testdata/corrupt_xref.pdf": ErrCorruptXRef,
+		"../../testdata/encrypted.pdf":    ErrEncrypted,
+	}
+	for path, expected := range cases {
+		if _, err := parseFileErr(t, path); !errors.Is(err, expected) {
+			t.Errorf("%s: expected %v, got %v", path, expected, err)
+		}
+	}
+
+	doc := parseFile(t, "../../testdata/two_pages.pdf")
+	if _, err := doc.GetPage(2); !errors.Is(err, ErrPageOutOfRange) {
+		t.Errorf("expected ErrPageOutOfRange, got %v", err)
+	}
+
+	// Image codecs are passed through, other filters fail
+	p := NewPDFParser(bytes.NewReader(nil), 0)
+	if data, err := p.decodeStream([]byte{0xff, 0xd8}, PDFName("DCTDecode"), nil); err != nil || len(data) != 2 {
+		t.Errorf("expected JPEG data passed through, got %v, %v", data, err)
+	}
+	if _, err := p.decodeStream([]byte{0x80, 0x0b}, PDFName("UnknownDecode"), nil); !errors.Is(err, ErrUnsupportedFilter) {
+		t.Errorf("expected ErrUnsupportedFilter, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"fmt"
+)
+
+// predictorParams are the decode parameters of FlateDecode and LZWDecode
+// that describe a predictor applied before compression
+type predictorParams struct {
+	predictor        int // 1 for none, 2 for TIFF, 10 to 15 for PNG
+	colors           int // Color components per sample
+	bitsPerComponent int // 1, 2, 4, 8 or 16
+	columns          int // Samples per row
+}
+
+// newPredictorParams reads the predictor parameters of a filter, with the
+// defaults of the PDF specification for missing entries
+func newPredictorParams(parms PDFDict) (predictorParams, error) {
+	params := predictorParams{predictor: 1, colors: 1, bitsPerComponent: 8, columns: 1}
+	for key, value := range map[PDFName]*int{
+		"Predictor":        &params.predictor,
+		"Colors":           &params.colors,
+		"BitsPerComponent": &params.bitsPerComponent,
+		"Columns":          &params.columns,
+	} {
+		if n, ok := parms.GetInt(key); ok {
+			*value = int(n)
+		}
+	}
+
+	switch params.bitsPerComponent {
+	case 1, 2, 4, 8, 16:
+	default:
+		return params, fmt.Errorf("invalid predictor BitsPerComponent %d", params.bitsPerComponent)
+	}
+	if params.colors < 1 || params.colors > 32 || params.columns < 1 || params.columns > 1<<24 {
+		return params, fmt.Errorf("invalid predictor Colors %d or Columns %d", params.colors, params.columns)
+	}
+	return params, nil
+}
+
+// rowLength returns the bytes of a row of samples, without a PNG filter byte
+func (p predictorParams) rowLength() int {
+	return (p.colors*p.bitsPerComponent*p.columns + 7) / 8
+}
+
+// pixelLength returns the bytes of a sample, at least one
+func (p predictorParams) pixelLength() int {
+	return max((p.colors*p.bitsPerComponent+7)/8, 1)
+}
+
+// unpredict reverses the predictor given by a filter's decode parameters on
+// its decoded data. Rows longer than the data or than maxSize bytes, when
+// above 0, are rejected before anything is allocated for them.
+func unpredict(data []byte, parms PDFDict, maxSize int64) ([]byte, error) {
+	params, err := newPredictorParams(parms)
+	if err != nil {
+		return nil, err
+	}
+	if params.predictor == 1 || len(data) == 0 {
+		return data, nil
+	}
+	rowLength := int64(params.rowLength())
+	if rowLength > int64(len(data)) || maxSize > 0 && rowLength > maxSize {
+		return nil, fmt.Errorf("predictor rows of %d bytes do not fit %d bytes of data", rowLength, len(data))
+	}
+	switch {
+	case params.predictor == 2:
+		return unpredictTIFF(data, params), nil
+	case params.predictor >= 10 && params.predictor <= 15:
+		return unpredictPNG(data, params)
+	}
+	return nil, fmt.Errorf("unsupported predictor %d", params.predictor)
+}
+
+// unpredictPNG reverses PNG predictors. Each row starts with the byte of
+// its own filter type, whatever predictor from 10 to 15 was declared. A
+// final partial row is decoded as far as it goes.
+func unpredictPNG(data []byte, params predictorParams) ([]byte, error) {
+	rowLength, bpp := params.rowLength(), params.pixelLength()
+	out := make([]byte, 0, len(data)/(rowLength+1)*rowLength+rowLength)
+	prev := make([]byte, rowLength)
+
+	for start := 0; start < len(data); start += rowLength + 1 {
+		end := min(start+rowLength+1, len(data))
+		filter, row := data[start], append([]byte(nil), data[start+1:end]...)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 0: // None
+			case 1: // Sub
+				row[i] += left
+			case 2: // Up
+				row[i] += up
+			case 3: // Average
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4: // Paeth
+				row[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("invalid PNG predictor filter type %d", filter)
+			}
+		}
+		out = append(out, row...)
+		copy(prev, row)
+	}
+	return out, nil
+}
+
+// paeth returns whichever of the left, up and upper left bytes is closest
+// to their linear estimate left + up - upLeft
+func paeth(left, up, upLeft byte) byte {
+	estimate := int(left) + int(up) - int(upLeft)
+	distLeft, distUp, distUpLeft := abs(estimate-int(left)), abs(estimate-int(up)), abs(estimate-int(upLeft))
+	switch {
+	case distLeft <= distUp && distLeft <= distUpLeft:
+		return left
+	case distUp <= distUpLeft:
+		return up
+	}
+	return upLeft
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// unpredictTIFF reverses TIFF predictor 2, which stores each component as
+// the difference from the same component of the sample to its left. A
+// final partial row is left as it is.
+func unpredictTIFF(data []byte, params predictorParams) []byte {
+	out := append([]byte(nil), data...)
+	rowLength, bits := params.rowLength(), params.bitsPerComponent
+	components := params.colors * params.columns
+	mask := uint(1)<<bits - 1
+
+	for start := 0; start+rowLength <= len(out); start += rowLength {
+		row := out[start : start+rowLength]
+		for i := params.colors; i < components; i++ {
+			switch bits {
+			case 8:
+				row[i] += row[i-params.colors]
+			case 16:
+				value := uint16(row[2*i])<<8 | uint16(row[2*i+1])
+				value += uint16(row[2*(i-params.colors)])<<8 | uint16(row[2*(i-params.colors)+1])
+				row[2*i], row[2*i+1] = byte(value>>8), byte(value)
+			default:
+				value := (componentBits(row, i, bits) + componentBits(row, i-params.colors, bits)) & mask
+				setComponentBits(row, i, bits, value)
+			}
+		}
+	}
+	return out
+}
+
+// componentBits returns the i-th component of bits bits packed into a row,
+// most significant bit first
+func componentBits(row []byte, i, bits int) uint {
+	bit := i * bits
+	shift := 8 - bits - bit%8
+	return uint(row[bit/8]>>shift) & (1<<bits - 1)
+}
+
+// setComponentBits sets the i-th component of bits bits packed into a row
+func setComponentBits(row []byte, i, bits int, value uint) {
+	bit := i * bits
+	shift := 8 - bits - bit%8
+	mask := byte(1<<bits-1) << shift
+	row[bit/8] = row[bit/8]&^mask | byte(value)<<shift
+}
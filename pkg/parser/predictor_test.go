@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+)
+
+// pngPredict applies a PNG filter type to each row, the forward of
+// unpredictPNG for samples of bpp bytes
+func pngPredict(rows [][]byte, filters []byte, bpp int) []byte {
+	var out []byte
+	prev := make([]byte, len(rows[0]))
+	for r, row := range rows {
+		out = append(out, filters[r])
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			predicted := map[byte]byte{
+				0: 0,
+				1: left,
+				2: prev[i],
+				3: byte((int(left) + int(prev[i])) / 2),
+				4: paeth(left, prev[i], upLeft),
+			}[filters[r]]
+			out = append(out, row[i]-predicted)
+		}
+		prev = row
+	}
+	return out
+}
+
+func TestFlateDecodePNGPredictor(t *testing.T) {
+	// Cross-reference stream entries of widths 1, 2 and 1: five columns
+	rows := [][]byte{
+		{1, 0x00, 0x0f, 0},
+		{1, 0x01, 0x2a, 0},
+		{2, 0x00, 0x09, 3},
+		{1, 0x7f, 0xf0, 0},
+		{0, 0xff, 0xff, 255},
+	}
+	var raw []byte
+	for _, row := range rows {
+		raw = append(raw, row...)
+	}
+
+	// Predictor 12 declares Up, but each row names its own filter type
+	encoded := pngPredict(rows, []byte{2, 2, 1, 4, 3}, 1)
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(encoded)
+	w.Close()
+
+	p := NewPDFParser(bytes.NewReader(nil), 0)
+	parms := PDFDict{"Predictor": PDFInt(12), "Columns": PDFInt(4)}
+	data, err := p.decodeStream(compressed.Bytes(), PDFName("FlateDecode"), parms)
+	if err != nil || !bytes.Equal(data, raw) {
+		t.Errorf("expected %v, got %v, %v", raw, data, err)
+	}
+
+	// Parameters in an array, one per filter
+	data, err = p.decodeStream(compressed.Bytes(), PDFArray{PDFName("FlateDecode")}, PDFArray{parms})
+	if err != nil || !bytes.Equal(data, raw) {
+		t.Errorf("expected %v from array parameters, got %v, %v", raw, data, err)
+	}
+
+	// RGB samples predict from the sample to the left, three bytes back
+	rgb := [][]byte{{10, 20, 30, 40, 50, 60}, {15, 25, 35, 45, 55, 65}}
+	parms = PDFDict{"Predictor": PDFInt(15), "Colors": PDFInt(3), "Columns": PDFInt(2)}
+	data, err = unpredict(pngPredict(rgb, []byte{1, 4}, 3), parms, 0)
+	if err != nil || !bytes.Equal(data, append(append([]byte{}, rgb[0]...), rgb[1]...)) {
+		t.Errorf("expected %v, got %v, %v", rgb, data, err)
+	}
+
+	if _, err := unpredict([]byte{5, 1, 2, 3, 4}, PDFDict{"Predictor": PDFInt(12), "Columns": PDFInt(4)}, 0); err == nil {
+		t.Error("expected an error for an invalid PNG filter type")
+	}
+}
+
+func TestTIFFPredictor(t *testing.T) {
+	tests := []struct {
+		name    string
+		parms   PDFDict
+		encoded []byte
+		decoded []byte
+	}{
+		{
+			"8-bit RGB",
+			PDFDict{"Colors": PDFInt(3), "Columns": PDFInt(3)},
+			[]byte{10, 20, 30, 5, 5, 5, 251, 0, 1},
+			[]byte{10, 20, 30, 15, 25, 35, 10, 25, 36},
+		},
+		{
+			"16-bit gray",
+			PDFDict{"BitsPerComponent": PDFInt(16), "Columns": PDFInt(3)},
+			[]byte{0x01, 0xff, 0x00, 0x01, 0xff, 0xff},
+			[]byte{0x01, 0xff, 0x02, 0x00, 0x01, 0xff},
+		},
+		{
+			"4-bit gray, two rows",
+			PDFDict{"BitsPerComponent": PDFInt(4), "Columns": PDFInt(4)},
+			[]byte{0x31, 0xf2, 0x11, 0x11},
+			[]byte{0x34, 0x35, 0x12, 0x34},
+		},
+	}
+	for _, test := range tests {
+		test.parms["Predictor"] = PDFInt(2)
+		if data, err := unpredict(test.encoded, test.parms, 0); err != nil || !bytes.Equal(data, test.decoded) {
+			t.Errorf("%s: expected %x, got %x, %v", test.name, test.decoded, data, err)
+		}
+	}
+}
+
+func TestPredictorRowsBeyondData(t *testing.T) {
+	// Four bytes of zeros declaring rows of 1 GiB must fail before the rows
+	// are allocated
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(make([]byte, 4))
+	w.Close()
+
+	p := NewPDFParser(bytes.NewReader(nil), 0, WithMaxStreamSize(1<<20))
+	parms := PDFDict{"Predictor": PDFInt(12), "Columns": PDFInt(1 << 24), "Colors": PDFInt(32), "BitsPerComponent": PDFInt(16)}
+	if _, err := p.decodeStream(compressed.Bytes(), PDFName("FlateDecode"), parms); err == nil {
+		t.Error("expected an error for rows longer than the data")
+	}
+	if _, err := unpredict(make([]byte, 64), PDFDict{"Predictor": PDFInt(2), "Columns": PDFInt(64)}, 32); err == nil {
+		t.Error("expected an error for rows longer than the size limit")
+	}
+}
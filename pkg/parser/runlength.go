@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// runLengthDecode decodes RunLengthDecode data: a length byte n below 128
+// is followed by n+1 bytes copied as they are, one above 128 by a byte
+// repeated 257-n times, and 128 ends the data
+func (p *PDFParser) runLengthDecode(data []byte) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+		switch {
+		case n == 128:
+			return out, nil
+		case n < 128:
+			end := min(i+n+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end
+		case i < len(data):
+			out = append(out, bytes.Repeat(data[i:i+1], 257-n)...)
+			i++
+		}
+		if p.maxStreamSize > 0 && int64(len(out)) > p.maxStreamSize {
+			return nil, fmt.Errorf("%w: more than %d bytes", ErrStreamTooLarge, p.maxStreamSize)
+		}
+	}
+	return out, nil
+}
+
+// cryptFilter passes data through the Identity crypt filter, the only one
+// that needs no security handler beyond the document's own
+func cryptFilter(data []byte, parms PDFDict) ([]byte, error) {
+	if name, ok := parms.GetName("Name"); ok && name != "Identity" {
+		return nil, fmt.Errorf("%w: Crypt filter %s", ErrUnsupportedFilter, name)
+	}
+	return data, nil
+}
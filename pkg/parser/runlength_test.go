@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRunLengthDecode(t *testing.T) {
+	p := NewPDFParser(bytes.NewReader(nil), 0)
+
+	// Two bytes copied, a byte repeated four times, then the end marker
+	// before trailing data
+	encoded := []byte{1, 'a', 'b', 253, 'c', 128, 'x'}
+	if data, err := p.decodeStream(encoded, PDFName("RunLengthDecode"), nil); err != nil || string(data) != "abcccc" {
+		t.Errorf("expected abcccc, got %q, %v", data, err)
+	}
+
+	p = NewPDFParser(bytes.NewReader(nil), 0, WithMaxStreamSize(100))
+	repeated := bytes.Repeat([]byte{129, 0}, 2)
+	if _, err := p.decodeStream(repeated, PDFName("RunLengthDecode"), nil); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("expected ErrStreamTooLarge, got %v", err)
+	}
+
+	// Only the Identity crypt filter needs no security handler
+	if data, err := p.decodeStream([]byte("plain"), PDFName("Crypt"), nil); err != nil || string(data) != "plain" {
+		t.Errorf("expected the data of the Identity crypt filter, got %q, %v", data, err)
+	}
+	named := PDFDict{"Name": PDFName("StdCF")}
+	if _, err := p.decodeStream([]byte("plain"), PDFName("Crypt"), named); !errors.Is(err, ErrUnsupportedFilter) {
+		t.Errorf("expected ErrUnsupportedFilter for a named crypt filter, got %v", err)
+	}
+}
+
+func TestPageContentErr(t *testing.T) {
+	// The page's content streams use RunLengthDecode, the Identity crypt
+	// filter and an unknown filter
+	doc := parseFile(t, "../../testdata/run_length.pdf")
+	page := doc.Pages[0]
+	if !errors.Is(page.ContentErr, ErrUnsupportedFilter) {
+		t.Errorf("expected ErrUnsupportedFilter for the unknown filter, got %v", page.ContentErr)
+	}
+	content := string(page.ContentData())
+	if !strings.Contains(content, "(Run) Tj   ET") || !strings.Contains(content, "(Crypt) Tj") {
+		t.Errorf("expected the readable streams' content, got %q", content)
+	}
+}
@@ -0,0 +1,457 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+)
+
+// WithPassword sets the password for encrypted documents, either the user
+// or the owner password. Documents that open without a password need none.
+func WithPassword(password string) Option {
+	return func(p *PDFParser) {
+		p.password = password
+	}
+}
+
+// passwordPadding pads passwords to 32 bytes for the standard security
+// handler
+var passwordPadding = []byte{
+	0x28, 0xbf, 0x4e, 0x5e, 0x4e, 0x75, 0x8a, 0x41, 0x64, 0x00, 0x4e, 0x56, 0xff, 0xfa, 0x01, 0x08,
+	0x2e, 0x2e, 0x00, 0xb6, 0xd0, 0x68, 0x3e, 0x80, 0x2f, 0x0c, 0xa9, 0xfe, 0x64, 0x53, 0x69, 0x7a,
+}
+
+// cryptMethod is how strings or streams are encrypted
+type cryptMethod int
+
+const (
+	cryptNone cryptMethod = iota // Identity crypt filter
+	cryptRC4
+	cryptAESV2
+	cryptAESV3 // AES-256 with the file key for every object
+)
+
+// securityHandler decrypts the strings and streams of a document encrypted
+// with the standard security handler
+type securityHandler struct {
+	key             []byte
+	stringMethod    cryptMethod
+	streamMethod    cryptMethod
+	encryptMetadata bool
+	encryptRef      ObjectRef // The Encrypt dictionary, which is not encrypted
+}
+
+// setupSecurity reads the trailer's Encrypt dictionary and authenticates
+// the password, failing with ErrEncrypted when it is wrong or the security
+// handler is not supported
+func (p *PDFParser) setupSecurity() error {
+	encryptObj := p.trailer.Get(PDFName("Encrypt"))
+	ref, _ := encryptObj.(ObjectRef)
+	dict, ok := p.resolve(encryptObj).(PDFDict)
+	if !ok {
+		return fmt.Errorf("%w: invalid Encrypt dictionary", ErrEncrypted)
+	}
+	if filter, _ := dict.GetName("Filter"); filter != "Standard" {
+		return fmt.Errorf("%w: unsupported security handler %s", ErrEncrypted, filter)
+	}
+
+	v, _ := dict.GetInt("V")
+	r, _ := dict.GetInt("R")
+	h := &securityHandler{encryptMetadata: true, encryptRef: ref}
+	if em, ok := dict.Get(PDFName("EncryptMetadata")).(PDFBool); ok {
+		h.encryptMetadata = bool(em)
+	}
+
+	// Key length in bytes, 40 bits unless given
+	length := 5
+	switch v {
+	case 1:
+		h.stringMethod, h.streamMethod = cryptRC4, cryptRC4
+	case 2:
+		h.stringMethod, h.streamMethod = cryptRC4, cryptRC4
+		if bits, ok := dict.GetInt("Length"); ok {
+			length = int(bits / 8)
+		}
+	case 4, 5:
+		length = 16
+		if v == 5 {
+			length = 32
+		}
+		var err error
+		if h.stringMethod, err = cryptFilterMethod(dict, "StrF"); err != nil {
+			return err
+		}
+		if h.streamMethod, err = cryptFilterMethod(dict, "StmF"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unsupported encryption version %d", ErrEncrypted, v)
+	}
+	if v == 5 && r != 5 && r != 6 || v < 5 && (length < 5 || length > 16 || r < 2 || r > 4) {
+		return fmt.Errorf("%w: unsupported standard security handler revision %d", ErrEncrypted, r)
+	}
+
+	// AES-256 appends salts to the hashes, and wraps the file key in UE
+	// and OE
+	hashLen := 32
+	if r >= 5 {
+		hashLen = 48
+	}
+	o, _ := dict.Get(PDFName("O")).(PDFString)
+	u, _ := dict.Get(PDFName("U")).(PDFString)
+	oe, _ := dict.Get(PDFName("OE")).(PDFString)
+	ue, _ := dict.Get(PDFName("UE")).(PDFString)
+	permissions, _ := dict.GetInt("P")
+	if len(o) < hashLen || len(u) < hashLen || r >= 5 && (len(oe) < 32 || len(ue) < 32) {
+		return fmt.Errorf("%w: invalid O or U entry", ErrEncrypted)
+	}
+	var id []byte
+	if ids, ok := p.trailer.GetArray("ID"); ok && len(ids) > 0 {
+		if s, ok := p.resolve(ids[0]).(PDFString); ok {
+			id = s
+		}
+	}
+
+	params := standardParams{
+		revision:        int(r),
+		length:          length,
+		o:               o[:hashLen],
+		u:               u[:hashLen],
+		oe:              oe,
+		ue:              ue,
+		permissions:     uint32(permissions),
+		id:              id,
+		encryptMetadata: h.encryptMetadata,
+	}
+	key, ok := params.authenticate([]byte(p.password))
+	if !ok {
+		return fmt.Errorf("%w: incorrect password", ErrEncrypted)
+	}
+	h.key = key
+
+	p.security = h
+	return nil
+}
+
+// cryptFilterMethod returns the method of the crypt filter the Encrypt
+// dictionary names under key
+func cryptFilterMethod(dict PDFDict, key PDFName) (cryptMethod, error) {
+	name, ok := dict.GetName(key)
+	if !ok || name == "Identity" {
+		return cryptNone, nil
+	}
+	filters, _ := dict.GetDict("CF")
+	filter, ok := filters.GetDict(name)
+	if !ok {
+		return cryptNone, fmt.Errorf("%w: crypt filter %s not found", ErrEncrypted, name)
+	}
+	switch cfm, _ := filter.GetName("CFM"); cfm {
+	case "None":
+		return cryptNone, nil
+	case "V2":
+		return cryptRC4, nil
+	case "AESV2":
+		return cryptAESV2, nil
+	case "AESV3":
+		return cryptAESV3, nil
+	default:
+		return cryptNone, fmt.Errorf("%w: unsupported crypt filter method %s", ErrEncrypted, cfm)
+	}
+}
+
+// standardParams are the Encrypt dictionary entries of the standard
+// security handler that passwords are checked against
+type standardParams struct {
+	revision        int
+	length          int
+	o, u            []byte
+	oe, ue          []byte // File key encrypted for the owner and user, revision 5 on
+	permissions     uint32
+	id              []byte
+	encryptMetadata bool
+}
+
+// padPassword pads or truncates a password to 32 bytes
+func padPassword(password []byte) []byte {
+	padded := make([]byte, 0, 32)
+	padded = append(padded, password...)
+	if len(padded) > 32 {
+		padded = padded[:32]
+	}
+	return append(padded, passwordPadding[:32-len(padded)]...)
+}
+
+// authenticate checks a user or owner password, returning the file key if
+// it is either
+func (s *standardParams) authenticate(password []byte) ([]byte, bool) {
+	if s.revision >= 5 {
+		// Passwords are UTF-8 of at most 127 bytes
+		if len(password) > 127 {
+			password = password[:127]
+		}
+		if key, ok := s.authenticateAES256(password, s.o, s.u[:48], s.oe); ok {
+			return key, true
+		}
+		return s.authenticateAES256(password, s.u, nil, s.ue)
+	}
+
+	if key, ok := s.authenticateUser(password); ok {
+		return key, true
+	}
+	return s.authenticateUser(s.userPassword(password))
+}
+
+// authenticateAES256 checks a password against the O or U entry of an
+// AES-256 handler, hash then validation and key salts, and decrypts the
+// file key from OE or UE. The owner hashes also cover the U entry, passed
+// as userKey.
+func (s *standardParams) authenticateAES256(password, entry, userKey, encryptedKey []byte) ([]byte, bool) {
+	if !bytes.Equal(s.hashAES256(password, entry[32:40], userKey), entry[:32]) {
+		return nil, false
+	}
+	block, err := aes.NewCipher(s.hashAES256(password, entry[40:48], userKey))
+	if err != nil {
+		return nil, false
+	}
+	key := make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(key, encryptedKey[:32])
+	return key, true
+}
+
+// hashAES256 hashes a password with a salt: SHA-256 for revision 5, and
+// for revision 6 the hardened hash of at least 64 rounds of AES-128 and
+// SHA-2
+func (s *standardParams) hashAES256(password, salt, userKey []byte) []byte {
+	h := sha256.New()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(userKey)
+	k := h.Sum(nil)
+	if s.revision == 5 {
+		return k
+	}
+
+	for round := 1; ; round++ {
+		k1 := make([]byte, 0, 64*(len(password)+len(k)+len(userKey)))
+		for i := 0; i < 64; i++ {
+			k1 = append(k1, password...)
+			k1 = append(k1, k...)
+			k1 = append(k1, userKey...)
+		}
+		block, _ := aes.NewCipher(k[:16])
+		e := make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, k[16:32]).CryptBlocks(e, k1)
+
+		// The first 16 bytes as a number modulo 3 pick the next hash
+		sum := 0
+		for _, b := range e[:16] {
+			sum += int(b)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		k = next.Sum(nil)
+
+		if round >= 64 && int(e[len(e)-1]) <= round-32 {
+			return k[:32]
+		}
+	}
+}
+
+// fileKey computes the file encryption key from a user password
+func (s *standardParams) fileKey(password []byte) []byte {
+	h := md5.New()
+	h.Write(padPassword(password))
+	h.Write(s.o)
+	h.Write([]byte{byte(s.permissions), byte(s.permissions >> 8), byte(s.permissions >> 16), byte(s.permissions >> 24)})
+	h.Write(s.id)
+	if s.revision >= 4 && !s.encryptMetadata {
+		h.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	}
+	key := h.Sum(nil)
+	if s.revision >= 3 {
+		for i := 0; i < 50; i++ {
+			sum := md5.Sum(key[:s.length])
+			key = sum[:]
+		}
+	}
+	return key[:s.length]
+}
+
+// authenticateUser checks a user password, returning the file key if it is
+// right
+func (s *standardParams) authenticateUser(password []byte) ([]byte, bool) {
+	key := s.fileKey(password)
+	if s.revision == 2 {
+		return key, bytes.Equal(rc4Crypt(key, passwordPadding), s.u)
+	}
+
+	h := md5.New()
+	h.Write(passwordPadding)
+	h.Write(s.id)
+	u := rc4Iterate(key, h.Sum(nil), 0, 19)
+	// Only the first 16 bytes are defined, the rest is arbitrary padding
+	return key, bytes.Equal(u, s.u[:16])
+}
+
+// userPassword recovers the user password from an owner password by
+// decrypting the O entry
+func (s *standardParams) userPassword(owner []byte) []byte {
+	sum := md5.Sum(padPassword(owner))
+	key := sum[:]
+	if s.revision >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(key)
+			key = sum[:]
+		}
+	}
+	key = key[:s.length]
+
+	if s.revision == 2 {
+		return rc4Crypt(key, s.o)
+	}
+	return rc4Iterate(key, s.o, 19, 0)
+}
+
+// rc4Iterate encrypts data with RC4 once for each i from first to last,
+// with the key XORed with i
+func rc4Iterate(key, data []byte, first, last int) []byte {
+	step := 1
+	if last < first {
+		step = -1
+	}
+	for i := first; ; i += step {
+		xored := make([]byte, len(key))
+		for j := range key {
+			xored[j] = key[j] ^ byte(i)
+		}
+		data = rc4Crypt(xored, data)
+		if i == last {
+			return data
+		}
+	}
+}
+
+// rc4Crypt encrypts or decrypts data with RC4
+func rc4Crypt(key, data []byte) []byte {
+	c, err := rc4.NewCipher(key)
+	if err != nil {
+		return data
+	}
+	out := make([]byte, len(data))
+	c.XORKeyStream(out, data)
+	return out
+}
+
+// objectKey derives the key of an object's strings and streams from the
+// file key
+func (h *securityHandler) objectKey(ref ObjectRef, method cryptMethod) []byte {
+	if method == cryptAESV3 {
+		return h.key
+	}
+	m := md5.New()
+	m.Write(h.key)
+	m.Write([]byte{byte(ref.Number), byte(ref.Number >> 8), byte(ref.Number >> 16), byte(ref.Generation), byte(ref.Generation >> 8)})
+	if method == cryptAESV2 {
+		m.Write([]byte("sAlT"))
+	}
+	key := m.Sum(nil)
+	if n := len(h.key) + 5; n < len(key) {
+		key = key[:n]
+	}
+	return key
+}
+
+// decrypt decrypts data of an object with a method
+func (h *securityHandler) decrypt(ref ObjectRef, method cryptMethod, data []byte) ([]byte, error) {
+	switch method {
+	case cryptRC4:
+		return rc4Crypt(h.objectKey(ref, method), data), nil
+	case cryptAESV2, cryptAESV3:
+		return aesDecrypt(h.objectKey(ref, method), data)
+	}
+	return data, nil
+}
+
+// aesDecrypt decrypts AES-CBC data led by its initialization vector and
+// padded to whole blocks
+func aesDecrypt(key, data []byte) ([]byte, error) {
+	// An empty string may be stored without even an IV
+	if len(data) == 0 {
+		return data, nil
+	}
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("AES data of %d bytes is not whole blocks", len(data))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+
+	if pad := int(out[len(out)-1]); pad >= 1 && pad <= aes.BlockSize {
+		out = out[:len(out)-pad]
+	}
+	return out, nil
+}
+
+// decryptStrings decrypts the strings in an object, descending into arrays
+// and dictionaries
+func (h *securityHandler) decryptStrings(ref ObjectRef, obj PDFObject) (PDFObject, error) {
+	switch v := obj.(type) {
+	case PDFString:
+		data, err := h.decrypt(ref, h.stringMethod, v)
+		if err != nil {
+			return nil, err
+		}
+		return PDFString(data), nil
+	case PDFArray:
+		out := make(PDFArray, len(v))
+		for i, item := range v {
+			decrypted, err := h.decryptStrings(ref, item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = decrypted
+		}
+		return out, nil
+	case PDFDict:
+		out := make(PDFDict, len(v))
+		for key, item := range v {
+			decrypted, err := h.decryptStrings(ref, item)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = decrypted
+		}
+		return out, nil
+	}
+	return obj, nil
+}
+
+// encryptsStream checks if the data of a stream is encrypted.
+// Cross-reference streams never are, nor metadata unless the handler says
+// so.
+func (h *securityHandler) encryptsStream(dict PDFDict) bool {
+	switch t, _ := dict.GetName("Type"); t {
+	case "XRef":
+		return false
+	case "Metadata":
+		return h.encryptMetadata
+	}
+	return true
+}
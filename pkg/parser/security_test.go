@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecryptStandardSecurity(t *testing.T) {
+	// User password "secret" and owner password "owner", except for the
+	// 40-bit file, which has an empty user password
+	tests := []struct {
+		path     string
+		password string
+		content  string
+	}{
+		{"encrypted.pdf", "secret", "(First page)"}, // V4 AESV2
+		{"encrypted.pdf", "owner", "(First page)"},
+		{"encrypted_rc4.pdf", "secret", "(RC4 encrypted)"}, // V2 RC4 128-bit
+		{"encrypted_rc4.pdf", "owner", "(RC4 encrypted)"},
+		{"encrypted_rc4_40.pdf", "", "(First page)"}, // V1 RC4 40-bit
+		{"encrypted_rc4_40.pdf", "owner", "(First page)"},
+		{"encrypted_aes256.pdf", "secret", "(First page)"}, // V5 R5 AES-256
+		{"encrypted_aes256.pdf", "owner", "(First page)"},
+		{"encrypted_aes256_r6.pdf", "secret", "(First page)"}, // V5 R6 AES-256
+		{"encrypted_aes256_r6.pdf", "owner", "(First page)"},
+	}
+	for _, test := range tests {
+		doc, err := parseFileErr(t, "../../testdata/"+test.path, WithPassword(test.password))
+		if err != nil {
+			t.Errorf("%s with %q: failed to parse: %v", test.path, test.password, err)
+			continue
+		}
+		if got := string(doc.Pages[0].ContentData()); !strings.Contains(got, test.content) {
+			t.Errorf("%s with %q: expected %s in the contents, got %q", test.path, test.password, test.content, got)
+		}
+	}
+
+	// Strings are decrypted with the key of the object holding them
+	doc := parseFile(t, "../../testdata/encrypted_rc4.pdf", WithPassword("secret"))
+	info, _ := doc.GetObject(doc.Trailer.Get("Info").(ObjectRef))
+	if title := info.(PDFDict).Get("Title"); string(title.(PDFString)) != "Secret title" {
+		t.Errorf("expected the decrypted title, got %q", title)
+	}
+
+	for _, path := range []string{"encrypted.pdf", "encrypted_aes256_r6.pdf"} {
+		for _, password := range []string{"", "wrong"} {
+			if _, err := parseFileErr(t, "../../testdata/"+path, WithPassword(password)); !errors.Is(err, ErrEncrypted) {
+				t.Errorf("%s with %q: expected ErrEncrypted, got %v", path, password, err)
+			}
+		}
+	}
+}
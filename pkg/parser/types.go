@@ -126,6 +126,9 @@ type XRefEntry struct {
 	Offset     int64
 	Generation int
 	InUse      bool
+	Compressed bool // Stored in an object stream rather than at Offset
+	Stream     int  // Object number of the object stream, if Compressed
+	Index      int  // Index of the object within the object stream
 }
 
 // XRefTable represents the cross-reference table
@@ -171,4 +174,7 @@ type PDFPage struct {
 	MediaBox  []float64
 	CropBox   []float64
 	Document  *PDFDocument // Reference to parent document
+	// ContentErr is the first error reading the page's content streams;
+	// the streams that could not be read are left out of Contents
+	ContentErr error
 }
\ No newline at end of file
@@ -0,0 +1,109 @@
+package parser
+
+import "fmt"
+
+// Entry types of cross-reference streams, from the first field of each
+// entry
+const (
+	xrefTypeFree       = 0
+	xrefTypeInFile     = 1
+	xrefTypeCompressed = 2
+)
+
+// parseXRefStream parses the cross-reference stream (/Type /XRef) at
+// offset, adding its entries, and returns its dictionary, which doubles as
+// the trailer
+func (p *PDFParser) parseXRefStream(offset int64) (PDFDict, error) {
+	_, obj, err := p.readIndirectObject(offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read xref stream: %v", err)
+	}
+	stream, ok := obj.(*PDFStream)
+	if !ok {
+		return nil, fmt.Errorf("xref stream is not a stream")
+	}
+	if name, _ := stream.Dict.GetName("Type"); name != "XRef" {
+		return nil, fmt.Errorf("expected an xref stream, got type %q", name)
+	}
+
+	// Field widths in bytes; a zero width field takes its default
+	w, ok := stream.Dict.GetArray("W")
+	if !ok || len(w) < 3 {
+		return nil, fmt.Errorf("xref stream has no valid W array")
+	}
+	var widths [3]int
+	rowLen := 0
+	for i := range widths {
+		width, ok := w[i].(PDFInt)
+		if !ok || width < 0 || width > 8 {
+			return nil, fmt.Errorf("invalid xref stream field width %v", w[i])
+		}
+		widths[i] = int(width)
+		rowLen += int(width)
+	}
+	if rowLen == 0 {
+		return nil, fmt.Errorf("xref stream has empty entries")
+	}
+
+	// Subsections as pairs of first object number and count, by default a
+	// single one covering every object
+	size, _ := stream.Dict.GetInt("Size")
+	index := PDFArray{PDFInt(0), PDFInt(size)}
+	if arr, ok := stream.Dict.GetArray("Index"); ok {
+		index = arr
+	}
+	if len(index)%2 != 0 {
+		return nil, fmt.Errorf("xref stream Index has an odd length")
+	}
+
+	data := stream.Data
+	for i := 0; i < len(index); i += 2 {
+		first, ok1 := index[i].(PDFInt)
+		count, ok2 := index[i+1].(PDFInt)
+		if !ok1 || !ok2 || first < 0 || count < 0 {
+			return nil, fmt.Errorf("invalid xref stream subsection %v %v", index[i], index[i+1])
+		}
+		for j := 0; j < int(count); j++ {
+			if len(data) < rowLen {
+				return nil, fmt.Errorf("xref stream data ends before entry %d", int(first)+j)
+			}
+			var fields [3]int64
+			pos := 0
+			for f, width := range widths {
+				for _, b := range data[pos : pos+width] {
+					fields[f] = fields[f]<<8 | int64(b)
+				}
+				pos += width
+			}
+			data = data[rowLen:]
+			if widths[0] == 0 {
+				fields[0] = xrefTypeInFile
+			}
+
+			number := int(first) + j
+			switch fields[0] {
+			case xrefTypeFree:
+				p.addXRefEntry(ObjectRef{Number: number, Generation: int(fields[2])}, &XRefEntry{
+					Generation: int(fields[2]),
+				})
+			case xrefTypeInFile:
+				p.addXRefEntry(ObjectRef{Number: number, Generation: int(fields[2])}, &XRefEntry{
+					Offset:     fields[1],
+					Generation: int(fields[2]),
+					InUse:      true,
+				})
+			case xrefTypeCompressed:
+				// Objects in object streams always have generation 0
+				p.addXRefEntry(ObjectRef{Number: number}, &XRefEntry{
+					InUse:      true,
+					Compressed: true,
+					Stream:     int(fields[1]),
+					Index:      int(fields[2]),
+				})
+			}
+			// Other types are reserved and refer to the null object
+		}
+	}
+
+	return stream.Dict, nil
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseXRefStream(t *testing.T) {
+	// A predictor-encoded cross-reference stream, and an incremental update
+	// with its own stream replacing page object 3 and chaining back by /Prev
+	doc := parseFile(t, "../../testdata/xref_stream.pdf")
+
+	if size, _ := doc.Trailer.GetInt("Size"); size != 11 {
+		t.Errorf("expected the newest trailer with Size 11, got %d", size)
+	}
+	if got := string(doc.Pages[0].ContentData()); !strings.Contains(got, "(Updated)") {
+		t.Errorf("expected the updated page contents, got %q", got)
+	}
+
+	entry, ok := doc.XRef.Get(ObjectRef{Number: 7})
+	if !ok || !entry.InUse || !entry.Compressed || entry.Stream != 6 || entry.Index != 0 {
+		t.Errorf("expected object 7 at index 0 of object stream 6, got %+v", entry)
+	}
+	for _, num := range []int{1, 2, 4, 5, 6, 8, 9, 10} {
+		if entry, ok := doc.XRef.Get(ObjectRef{Number: num}); !ok || !entry.InUse || entry.Compressed {
+			t.Errorf("expected object %d in the file, got %+v", num, entry)
+		}
+	}
+	if entry, ok := doc.XRef.Get(ObjectRef{Number: 0, Generation: 255}); !ok || entry.InUse {
+		t.Errorf("expected object 0 free, got %+v", entry)
+	}
+
+	// The original page object is still in the file, but superseded
+	original, _ := doc.XRef.Get(ObjectRef{Number: 4})
+	if page, _ := doc.XRef.Get(ObjectRef{Number: 3}); page.Offset < original.Offset {
+		t.Errorf("expected page object 3 from the update, got offset %d", page.Offset)
+	}
+}
+
+func TestParseHybridXRef(t *testing.T) {
+	// A classic table whose trailer names, with /XRefStm, a cross-reference
+	// stream listing the compressed object 7
+	doc := parseFile(t, "../../testdata/hybrid_xref.pdf")
+
+	if got := string(doc.Pages[0].ContentData()); !strings.Contains(got, "(Original)") {
+		t.Errorf("expected the page contents, got %q", got)
+	}
+	entry, ok := doc.XRef.Get(ObjectRef{Number: 7})
+	if !ok || !entry.Compressed || entry.Stream != 6 {
+		t.Errorf("expected object 7 in object stream 6, got %+v", entry)
+	}
+}
+
+func TestParseXRefFreedObject(t *testing.T) {
+	// An update frees the font, object 5, under generation 1; the entry of
+	// the original section for generation 0 must not bring it back
+	doc := parseFile(t, "../../testdata/freed_object.pdf")
+
+	if entry, ok := doc.XRef.Get(ObjectRef{Number: 5}); ok {
+		t.Errorf("expected no entry for the freed object 5 0, got %+v", entry)
+	}
+	if entry, ok := doc.XRef.Get(ObjectRef{Number: 5, Generation: 1}); !ok || entry.InUse {
+		t.Errorf("expected object 5 free under generation 1, got %+v", entry)
+	}
+	if entry, ok := doc.XRef.Get(ObjectRef{Number: 4}); !ok || !entry.InUse {
+		t.Errorf("expected object 4 from the original section, got %+v", entry)
+	}
+}
@@ -0,0 +1,15 @@
+package pdf
+
+import "time"
+
+// Attachment is a file embedded in the document's /EmbeddedFiles name tree
+type Attachment struct {
+	Name     string    // File name, from /UF or /F of the file specification
+	MIMEType string    // From the embedded file's /Subtype, empty if not given
+	ModDate  time.Time // From the embedded file's /Params, zero if not given
+	Data     []byte
+}
+
+// maxNameTreeDepth bounds the recursion into /Kids of a name tree so that
+// malformed documents with cyclic references terminate
+const maxNameTreeDepth = 32
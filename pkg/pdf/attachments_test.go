@@ -0,0 +1,66 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestAttachments(t *testing.T) {
+	expected := []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Invoice><ID>INV-001</ID><Total>42.00</Total></Invoice>\n")
+
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/attachment.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			attachments := doc.Attachments()
+			if len(attachments) != 1 {
+				t.Fatalf("expected 1 attachment, got %d", len(attachments))
+			}
+
+			got := attachments[0]
+			if got.Name != "factur-x.xml" || got.MIMEType != "text/xml" {
+				t.Errorf("expected factur-x.xml of type text/xml, got %q of type %q", got.Name, got.MIMEType)
+			}
+			if !got.ModDate.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+				t.Errorf("expected modification date 2024-01-15 10:30, got %v", got.ModDate)
+			}
+			if !bytes.Equal(got.Data, expected) {
+				t.Errorf("expected data %q, got %q", expected, got.Data)
+			}
+		})
+	}
+}
+
+func TestAttachmentsSharedKids(t *testing.T) {
+	// Every node of the tree lists the next one twice, giving 2^25 paths
+	// down to the one leaf. pdfcpu is left out: it validates the tree on
+	// opening the document, along every path.
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/shared_name_tree.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			attachments := doc.Attachments()
+			if len(attachments) != 1 || attachments[0].Name != "shared.txt" {
+				t.Errorf("expected shared.txt once, got %+v", attachments)
+			}
+		})
+	}
+}
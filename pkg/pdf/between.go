@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"regexp"
+	"strings"
+)
+
+// extractBetween returns the text of pages between the first match of the
+// start anchor and the next match of the end anchor after it, trimmed of
+// surrounding whitespace. Anchors are literal text matched in reading order;
+// the whitespace in them also matches line breaks, so an anchor may span
+// lines. An empty start anchor starts at the beginning; an empty or missing
+// end anchor runs to the end. It reports false if the start anchor is not
+// found. topDown tells whether Y grows downwards (true) or upwards as in raw
+// PDF space (false).
+func extractBetween(pages []Page, topDown bool, start, end string, opts []SearchOption) (string, bool) {
+	config := newSearchConfig(opts)
+	var texts []string
+	for _, page := range pages {
+		if config.Context.Err() != nil {
+			break
+		}
+		text, _ := searchText(page.GetObjects().Chars, topDown, config)
+		texts = append(texts, text)
+	}
+	text := strings.Join(texts, "\n")
+
+	from := 0
+	if re := anchorPattern(start, config.CaseSensitive); re != nil {
+		loc := re.FindStringIndex(text)
+		if loc == nil {
+			return "", false
+		}
+		from = loc[1]
+	}
+	to := len(text)
+	if re := anchorPattern(end, config.CaseSensitive); re != nil {
+		if loc := re.FindStringIndex(text[from:]); loc != nil {
+			to = from + loc[0]
+		}
+	}
+	return strings.TrimSpace(text[from:to]), true
+}
+
+// anchorPattern matches the words of anchor separated by any whitespace,
+// nil for an anchor without words
+func anchorPattern(anchor string, caseSensitive bool) *regexp.Regexp {
+	words := strings.Fields(anchor)
+	if len(words) == 0 {
+		return nil
+	}
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	pattern := strings.Join(words, `\s+`)
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.MustCompile(pattern)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 // ToUnicodeCMap represents a PDF ToUnicode CMap that maps CIDs to Unicode values
@@ -15,8 +16,29 @@ type ToUnicodeCMap struct {
 	// Range mappings (from beginbfrange sections)
 	ranges []cmapRange
 	
+	// Valid code byte sequences (from begincodespacerange sections)
+	codespaces []codespaceRange
+	
 	// Raw CMap data for debugging
 	rawData []byte
+	
+	// Mapping consulted for CIDs this CMap does not map
+	fallback *ToUnicodeCMap
+	
+	// Repair malformed UTF-16 in destination values, read little-endian
+	// when the CMap was written byte-swapped
+	repair       bool
+	littleEndian bool
+	
+	// Reverse mapping for codeFor, built on first use
+	codes *codeIndex
+}
+
+// codeIndex maps Unicode strings back to the lowest CID mapped to each. A
+// CMap is shared by the pages using its font, so it is built once.
+type codeIndex struct {
+	once  sync.Once
+	codes map[string]uint16
 }
 
 // cmapRange represents a contiguous range mapping from beginbfrange
@@ -27,21 +49,36 @@ type cmapRange struct {
 	unicodeArray []string // For non-contiguous mappings
 }
 
+// codespaceRange is a range of codes of one byte length, from
+// begincodespacerange. Each byte of a code lies within the bounds of the
+// byte at the same position.
+type codespaceRange struct {
+	low  []byte
+	high []byte
+}
+
 // NewToUnicodeCMap creates a new ToUnicode CMap parser
 func NewToUnicodeCMap() *ToUnicodeCMap {
 	return &ToUnicodeCMap{
 		cidToUnicode: make(map[uint16]string),
 		ranges:       []cmapRange{},
+		codes:        &codeIndex{},
 	}
 }
 
 // Parse parses a ToUnicode CMap stream
 func (cmap *ToUnicodeCMap) Parse(data []byte) error {
 	cmap.rawData = data
+	cmap.codes = &codeIndex{}
 	
 	// Convert to string for easier processing
 	content := string(data)
 	
+	cmap.parseCodespaceRanges(content)
+	if cmap.repair {
+		cmap.littleEndian = isByteSwapped(bfcharDestinations(content))
+	}
+	
 	// Parse beginbfchar sections
 	if err := cmap.parseBeginBFChar(content); err != nil {
 		return fmt.Errorf("failed to parse beginbfchar: %w", err)
@@ -55,6 +92,60 @@ func (cmap *ToUnicodeCMap) Parse(data []byte) error {
 	return nil
 }
 
+// parseCodespaceRanges parses begincodespacerange...endcodespacerange sections
+func (cmap *ToUnicodeCMap) parseCodespaceRanges(content string) {
+	re := regexp.MustCompile(`begincodespacerange\s*((?:<[0-9A-Fa-f]+>\s*<[0-9A-Fa-f]+>\s*)*)endcodespacerange`)
+	rangeRe := regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
+	for _, match := range re.FindAllStringSubmatch(content, -1) {
+		for _, r := range rangeRe.FindAllStringSubmatch(match[1], -1) {
+			low, err := hex.DecodeString(r[1])
+			if err != nil {
+				continue
+			}
+			high, err := hex.DecodeString(r[2])
+			if err != nil || len(low) != len(high) || len(low) == 0 || len(low) > 4 {
+				continue
+			}
+			cmap.codespaces = append(cmap.codespaces, codespaceRange{low: low, high: high})
+		}
+	}
+}
+
+// codeLength returns the byte length of the code at the start of data by
+// matching the codespace ranges, shortest first. It returns 0 when the CMap
+// declares no codespace or no range matches.
+func (cmap *ToUnicodeCMap) codeLength(data []byte) int {
+	for n := 1; n <= 4 && n <= len(data); n++ {
+		for _, r := range cmap.codespaces {
+			if len(r.low) != n {
+				continue
+			}
+			matches := true
+			for i := 0; i < n; i++ {
+				if data[i] < r.low[i] || data[i] > r.high[i] {
+					matches = false
+					break
+				}
+			}
+			if matches {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// hasMultiByteCodespace reports whether the CMap declares codes longer than
+// one byte
+func (cmap *ToUnicodeCMap) hasMultiByteCodespace() bool {
+	for _, r := range cmap.codespaces {
+		if len(r.low) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
 // parseBeginBFChar parses beginbfchar...endbfchar sections
 func (cmap *ToUnicodeCMap) parseBeginBFChar(content string) error {
 	// Regular expression to find beginbfchar sections
@@ -202,6 +293,9 @@ func (cmap *ToUnicodeCMap) bytesToUnicode(data []byte) string {
 	if len(data) == 0 {
 		return ""
 	}
+	if cmap.repair {
+		return repairUTF16(data, cmap.littleEndian)
+	}
 	
 	// Handle different byte lengths
 	if len(data) == 1 {
@@ -242,24 +336,86 @@ func (cmap *ToUnicodeCMap) MapCIDToUnicode(cid uint16) (string, bool) {
 	// Then check ranges
 	for _, r := range cmap.ranges {
 		if cid >= r.startCID && cid <= r.endCID {
-			if len(r.unicodeArray) > 0 {
-				// Array mapping
-				index := int(cid - r.startCID)
-				if index < len(r.unicodeArray) {
-					return r.unicodeArray[index], true
-				}
-			} else {
-				// Contiguous range mapping
-				offset := cid - r.startCID
-				unicodePoint := r.startUnicode + offset
-				return string(rune(unicodePoint)), true
+			if unicode, ok := rangeUnicode(r, cid); ok {
+				return unicode, true
 			}
 		}
 	}
 	
+	if cmap.fallback != nil {
+		return cmap.fallback.MapCIDToUnicode(cid)
+	}
 	return "", false
 }
 
+// codeFor returns the lowest CID mapped to a Unicode string
+func (cmap *ToUnicodeCMap) codeFor(unicode string) (uint16, bool) {
+	index := cmap.codes
+	if index == nil {
+		index = &codeIndex{}
+	}
+	index.once.Do(func() {
+		index.codes = cmap.reverseMapping()
+	})
+	code, found := index.codes[unicode]
+	if !found && cmap.fallback != nil {
+		return cmap.fallback.codeFor(unicode)
+	}
+	return code, found
+}
+
+// reverseMapping maps each Unicode string of the CMap's own mappings to the
+// lowest CID mapped to it
+func (cmap *ToUnicodeCMap) reverseMapping() map[string]uint16 {
+	codes := make(map[string]uint16, len(cmap.cidToUnicode))
+	add := func(cid uint16, unicode string) {
+		if code, ok := codes[unicode]; !ok || cid < code {
+			codes[unicode] = cid
+		}
+	}
+	for cid, u := range cmap.cidToUnicode {
+		add(cid, u)
+	}
+	for _, r := range cmap.ranges {
+		for cid := uint32(r.startCID); cid <= uint32(r.endCID); cid++ {
+			if u, ok := rangeUnicode(r, uint16(cid)); ok {
+				add(uint16(cid), u)
+			}
+		}
+	}
+	return codes
+}
+
+// rangeUnicode maps a CID within a range
+func rangeUnicode(r cmapRange, cid uint16) (string, bool) {
+	if len(r.unicodeArray) > 0 {
+		// Array mapping
+		index := int(cid - r.startCID)
+		if index < len(r.unicodeArray) {
+			return r.unicodeArray[index], true
+		}
+		return "", false
+	}
+	
+	// Contiguous range mapping
+	offset := cid - r.startCID
+	unicodePoint := r.startUnicode + offset
+	return string(rune(unicodePoint)), true
+}
+
+// AddMapping maps a CID to a Unicode string, replacing any existing mapping
+func (cmap *ToUnicodeCMap) AddMapping(cid uint16, unicode string) {
+	cmap.cidToUnicode[cid] = unicode
+	cmap.codes = &codeIndex{}
+}
+
+// withFallback returns a copy of the CMap that maps CIDs it lacks with fallback
+func (cmap *ToUnicodeCMap) withFallback(fallback *ToUnicodeCMap) *ToUnicodeCMap {
+	merged := *cmap
+	merged.fallback = fallback
+	return &merged
+}
+
 // Decode decodes raw bytes using this CMap
 func (cmap *ToUnicodeCMap) Decode(data []byte) string {
 	var result strings.Builder
@@ -1,6 +1,7 @@
 package pdf
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -365,6 +366,39 @@ func TestComplexRealWorldCMap(t *testing.T) {
 	}
 }
 
+func TestCodeFor(t *testing.T) {
+	// The space is mapped from <0005> directly and from <0021> in the range
+	cmap := NewToUnicodeCMap()
+	if err := cmap.Parse([]byte(`
+		beginbfchar
+		<0005> <0020>
+		endbfchar
+		beginbfrange
+		<0001> <FFFF> <0000>
+		endbfrange
+	`)); err != nil {
+		t.Fatalf("failed to parse CMap: %v", err)
+	}
+
+	for unicode, want := range map[string]uint16{" ": 0x0005, "A": 0x0042} {
+		if code, ok := cmap.codeFor(unicode); !ok || code != want {
+			t.Errorf("%q: expected code %04X, got %04X (%v)", unicode, want, code, ok)
+		}
+	}
+
+	// Mappings added later are seen, and unmapped strings go to the fallback
+	cmap.AddMapping(0x0002, " ")
+	fallback := NewToUnicodeCMap()
+	fallback.AddMapping(0x0007, "\U0001F600")
+	merged := cmap.withFallback(fallback)
+	if code, ok := merged.codeFor(" "); !ok || code != 0x0002 {
+		t.Errorf("expected the added mapping, got %04X (%v)", code, ok)
+	}
+	if code, ok := merged.codeFor("\U0001F600"); !ok || code != 0x0007 {
+		t.Errorf("expected the fallback's code, got %04X (%v)", code, ok)
+	}
+}
+
 func BenchmarkParse(b *testing.B) {
 	cmapData := []byte(`
 		beginbfchar
@@ -417,4 +451,120 @@ func BenchmarkDecode(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = cmap.Decode(data)
 	}
-}
\ No newline at end of file
+}
+
+func BenchmarkCodeFor(b *testing.B) {
+	cmap := NewToUnicodeCMap()
+	_ = cmap.Parse([]byte(`
+		beginbfrange
+		<0100> <FFFF> <0100>
+		<0020> <007E> <0020>
+		endbfrange
+	`))
+	
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = cmap.codeFor(" ")
+	}
+}
+
+func TestRepairUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[uint16]string
+	}{
+		{
+			name: "Byte-swapped CMap",
+			input: `
+				beginbfchar
+				<0001> <4100>
+				<0002> <41004200>
+				<0003> <E900AC20>
+				<0004> <FEFF0043>
+				endbfchar
+			`,
+			expected: map[uint16]string{
+				0x0001: "A",
+				0x0002: "AB",
+				0x0003: "é€",
+				0x0004: "C", // A byte order mark overrides the CMap's order
+			},
+		},
+		{
+			name: "Malformed values",
+			input: `
+				beginbfchar
+				<0001> <FFFE4100>
+				<0002> <410042>
+				<0003> <0041D83D>
+				<0004> <AC00>
+				<0005> <4E00>
+				<0006> <D83DDE00>
+				endbfchar
+			`,
+			expected: map[uint16]string{
+				0x0001: "A",  // Little-endian byte order mark
+				0x0002: "AB", // Odd length, the first unit missing its zero byte
+				0x0003: "A",  // Lone high surrogate
+				0x0004: "가",  // Valid as it is
+				0x0005: "一",  // Valid as it is, though it ends in a zero byte
+				0x0006: "😀", // Surrogate pair
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmap := NewToUnicodeCMap()
+			cmap.repair = true
+			if err := cmap.Parse([]byte(tt.input)); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			for cid, want := range tt.expected {
+				if got, ok := cmap.MapCIDToUnicode(cid); !ok || got != want {
+					t.Errorf("CID %04X: expected %q, got %q", cid, want, got)
+				}
+			}
+		})
+	}
+
+	// Without repair the byte-swapped value decodes as it is
+	cmap := NewToUnicodeCMap()
+	if err := cmap.Parse([]byte(tests[0].input)); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, _ := cmap.MapCIDToUnicode(0x0001); got != "䄀" {
+		t.Errorf("expected %q without repair, got %q", "䄀", got)
+	}
+}
+
+func TestOpenWithRepairUnicode(t *testing.T) {
+	// The font's ToUnicode CMap maps each code to its character byte-swapped
+	for repair, expected := range map[bool]string{true: "Hi!", false: "䠀椀℀"} {
+		doc, err := Open("../../testdata/swapped_cmap.pdf", WithRepairUnicode(repair))
+		if err != nil {
+			t.Fatalf("failed to open PDF: %v", err)
+		}
+		page, _ := doc.GetPage(0)
+		if text := page.ExtractText(); text != expected {
+			t.Errorf("repair %v: expected %q, got %q", repair, expected, text)
+		}
+		doc.Close()
+	}
+}
+
+func TestRepairUnicodeLibraries(t *testing.T) {
+	// The libraries decode text themselves and cannot repair it
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			if _, err := open("../../testdata/swapped_cmap.pdf", WithRepairUnicode(true)); !errors.Is(err, ErrNotImplemented) {
+				t.Errorf("expected ErrNotImplemented, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package pdf
+
+// ColorsMatch checks if two colors are equal within a per-channel tolerance.
+// The alpha channel is ignored.
+func ColorsMatch(a, b Color, tolerance uint8) bool {
+	return channelDiff(a.R, b.R) <= tolerance &&
+		channelDiff(a.G, b.G) <= tolerance &&
+		channelDiff(a.B, b.B) <= tolerance
+}
+
+func channelDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// colorSet reports whether a color was recorded for an object.
+// Colors taken from the graphics state are always opaque, so a zero
+// alpha means the object carries no such color (e.g. a fill-only rect's stroke).
+func colorSet(c Color) bool {
+	return c.A != 0
+}
+
+// StrokeColorMatches returns a predicate for Page.Filter selecting objects
+// whose stroke color matches c within tolerance
+func StrokeColorMatches(c Color, tolerance uint8) func(Object) bool {
+	return func(obj Object) bool {
+		switch o := obj.(type) {
+		case LineObject:
+			return colorSet(o.StrokeColor) && ColorsMatch(o.StrokeColor, c, tolerance)
+		case RectObject:
+			return colorSet(o.StrokeColor) && ColorsMatch(o.StrokeColor, c, tolerance)
+		case CurveObject:
+			return colorSet(o.StrokeColor) && ColorsMatch(o.StrokeColor, c, tolerance)
+		}
+		return false
+	}
+}
+
+// FillColorMatches returns a predicate for Page.Filter selecting objects
+// whose fill color matches c within tolerance. Characters are matched by
+// their text color.
+func FillColorMatches(c Color, tolerance uint8) func(Object) bool {
+	return func(obj Object) bool {
+		switch o := obj.(type) {
+		case CharObject:
+			return colorSet(o.Color) && ColorsMatch(o.Color, c, tolerance)
+		case RectObject:
+			return colorSet(o.FillColor) && ColorsMatch(o.FillColor, c, tolerance)
+		case CurveObject:
+			return colorSet(o.FillColor) && ColorsMatch(o.FillColor, c, tolerance)
+		}
+		return false
+	}
+}
+
+// FilterByStrokeColor returns the lines, rects and curves whose stroke
+// color matches c within a per-channel tolerance
+func (o Objects) FilterByStrokeColor(c Color, tolerance uint8) Objects {
+	return o.filter(StrokeColorMatches(c, tolerance))
+}
+
+// FilterByFillColor returns the chars, rects and curves whose fill
+// color matches c within a per-channel tolerance
+func (o Objects) FilterByFillColor(c Color, tolerance uint8) Objects {
+	return o.filter(FillColorMatches(c, tolerance))
+}
+
+// filter returns the objects satisfying a predicate
+func (o Objects) filter(predicate func(Object) bool) Objects {
+	filtered := Objects{}
+
+	for _, char := range o.Chars {
+		if predicate(char) {
+			filtered.Chars = append(filtered.Chars, char)
+		}
+	}
+
+	for _, line := range o.Lines {
+		if predicate(line) {
+			filtered.Lines = append(filtered.Lines, line)
+		}
+	}
+
+	for _, rect := range o.Rects {
+		if predicate(rect) {
+			filtered.Rects = append(filtered.Rects, rect)
+		}
+	}
+
+	for _, curve := range o.Curves {
+		if predicate(curve) {
+			filtered.Curves = append(filtered.Curves, curve)
+		}
+	}
+
+	for _, image := range o.Images {
+		if predicate(image) {
+			filtered.Images = append(filtered.Images, image)
+		}
+	}
+
+	for _, anno := range o.Annos {
+		if predicate(anno) {
+			filtered.Annos = append(filtered.Annos, anno)
+		}
+	}
+
+	for _, shading := range o.Shadings {
+		if predicate(shading) {
+			filtered.Shadings = append(filtered.Shadings, shading)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,48 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestFilterByStrokeColor(t *testing.T) {
+	// Two black rules and two red rules, one of them slightly off-red
+	content := []byte(`
+		0 0 0 RG 72 700 m 540 700 l S
+		1 0 0 RG 72 650 m 540 650 l S
+		0 G 72 600 m 540 600 l S
+		0.98 0.02 0 RG 72 550 m 540 550 l S
+		1 0 0 rg 72 400 100 20 re f
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d", len(objects.Lines))
+	}
+
+	red := Color{R: 255, G: 0, B: 0, A: 255}
+	filtered := objects.FilterByStrokeColor(red, 8)
+	if len(filtered.Lines) != 2 {
+		t.Fatalf("expected 2 red lines, got %d", len(filtered.Lines))
+	}
+	for _, line := range filtered.Lines {
+		if line.Y0 != 650 && line.Y0 != 550 {
+			t.Errorf("unexpected line selected at y=%.1f", line.Y0)
+		}
+	}
+
+	// The red-filled rect has no stroke and must not match
+	if len(filtered.Rects) != 0 {
+		t.Errorf("expected no stroked rects, got %d", len(filtered.Rects))
+	}
+
+	// Exact matching drops the off-red rule
+	if exact := objects.FilterByStrokeColor(red, 0); len(exact.Lines) != 1 {
+		t.Errorf("expected 1 exact red line, got %d", len(exact.Lines))
+	}
+
+	if fills := objects.FilterByFillColor(red, 0); len(fills.Rects) != 1 || len(fills.Lines) != 0 {
+		t.Errorf("expected 1 red-filled rect and no lines, got %d rects and %d lines", len(fills.Rects), len(fills.Lines))
+	}
+}
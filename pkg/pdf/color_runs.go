@@ -0,0 +1,53 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// colorRunTolerance is the largest per-channel difference between the fill
+// colors of characters in the same run
+const colorRunTolerance = 8
+
+// ColoredRun is a stretch of text in reading order drawn in one fill color.
+// Colors come from the pdfcpu backend only; pages read through the
+// ledongthuc and dslipak libraries take all their text as black.
+type ColoredRun struct {
+	Text  string      // Words of the run, separated by single spaces
+	Color Color       // Fill color of the first character
+	BBox  BoundingBox // Bounds of the run's characters
+}
+
+// colorRuns splits words, in reading order, into maximal runs of characters
+// sharing a fill color. A word whose color changes partway is split.
+func colorRuns(words []Word) []ColoredRun {
+	var runs []ColoredRun
+	var text strings.Builder
+	var run *ColoredRun
+	flush := func() {
+		if run != nil {
+			run.Text = text.String()
+			runs = append(runs, *run)
+			run = nil
+			text.Reset()
+		}
+	}
+
+	for _, word := range words {
+		for i, char := range word.Characters {
+			if run != nil && !ColorsMatch(run.Color, char.Color, colorRunTolerance) {
+				flush()
+			}
+			if run == nil {
+				run = &ColoredRun{Color: char.Color, BBox: char.GetBBox().Normalize()}
+			} else {
+				if i == 0 {
+					text.WriteByte(' ')
+				}
+				run.BBox = run.BBox.Union(char.GetBBox())
+			}
+			text.WriteString(char.Text)
+		}
+	}
+	flush()
+	return runs
+}
@@ -0,0 +1,53 @@
+package pdf
+
+import (
+	"testing"
+)
+
+func TestColorRuns(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/color_runs.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			// A black sentence with the three words of a defined term in blue
+			page, _ := doc.GetPage(0)
+			blue := Color{B: 255, A: 255}
+			var blueRuns []ColoredRun
+			for _, run := range page.ColorRuns() {
+				if ColorsMatch(run.Color, blue, 0) {
+					blueRuns = append(blueRuns, run)
+				}
+			}
+			if len(blueRuns) != 1 {
+				t.Fatalf("expected 1 blue run, got %d: %+v", len(blueRuns), page.ColorRuns())
+			}
+
+			run := blueRuns[0]
+			if run.Text != "Purchase Price Amount" {
+				t.Errorf("expected blue run %q, got %q", "Purchase Price Amount", run.Text)
+			}
+
+			// The run covers exactly the blue characters
+			var bbox BoundingBox
+			for _, char := range page.GetObjects().FilterByFillColor(blue, 0).Chars {
+				if bbox.Width() == 0 {
+					bbox = char.GetBBox().Normalize()
+				} else {
+					bbox = bbox.Union(char.GetBBox())
+				}
+			}
+			if run.BBox != bbox {
+				t.Errorf("expected blue run bounds %+v, got %+v", bbox, run.BBox)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// minColumnGutter is the narrowest whitespace strip treated as a column gutter
+	minColumnGutter = 10.0
+	// minColumnLines is how many lines must sit on each side of a gutter
+	minColumnLines = 3
+)
+
+// columnGutter is a vertical strip of whitespace separating two text columns
+type columnGutter struct {
+	X0, X1 float64
+}
+
+// extractColumnText extracts text reading each column top-to-bottom before
+// moving to the next one, or line by line with the columns joined by the
+// column separator when one is set. topDown tells whether Y grows downwards
+// (true) or upwards as in raw PDF space (false).
+func extractColumnText(chars []CharObject, config *textExtractionConfig, topDown bool) string {
+	lines := groupCharsIntoTextLines(chars, config.YTolerance, topDown)
+	gutters := findColumnGutters(lines)
+	
+	if config.ColumnSeparator != "" {
+		return extractSeparatedColumns(lines, gutters, config)
+	}
+
+	var result []string
+	appendLine := func(line []CharObject) {
+		if text := extractLineText(line, config.XTolerance); text != "" {
+			result = append(result, text)
+		}
+	}
+
+	if len(gutters) == 0 {
+		for _, line := range lines {
+			appendLine(line)
+		}
+		return strings.Join(result, "\n")
+	}
+
+	// Lines between full-width lines are collected per column and flushed
+	// column by column
+	columns := make([][][]CharObject, len(gutters)+1)
+	flush := func() {
+		for i, column := range columns {
+			for _, line := range column {
+				appendLine(line)
+			}
+			columns[i] = nil
+		}
+	}
+
+	for _, line := range lines {
+		if spansGutter(line, gutters) {
+			flush()
+			appendLine(line)
+			continue
+		}
+
+		for i, part := range splitAtGutters(line, gutters) {
+			if len(part) > 0 {
+				columns[i] = append(columns[i], part)
+			}
+		}
+	}
+	flush()
+
+	return strings.Join(result, "\n")
+}
+
+// extractSeparatedColumns extracts text line by line, joining the text of
+// each column with the column separator. Whole words are assigned to columns,
+// so every line has the same number of fields, empty where a column has no
+// text, and a word running into a gutter is not cut.
+func extractSeparatedColumns(lines [][]CharObject, gutters []columnGutter, config *textExtractionConfig) string {
+	var result []string
+	for _, line := range lines {
+		parts := make([][]CharObject, len(gutters)+1)
+		for _, word := range splitLineIntoWords(line, config.XTolerance) {
+			first, last := word[0], word[len(word)-1]
+			index := columnIndex((first.X0+last.X1)/2, gutters)
+			parts[index] = append(parts[index], word...)
+		}
+
+		fields := make([]string, len(parts))
+		for i, part := range parts {
+			fields[i] = extractLineText(part, config.XTolerance)
+		}
+		result = append(result, strings.Join(fields, config.ColumnSeparator))
+	}
+	return strings.Join(result, "\n")
+}
+
+// splitLineIntoWords splits a line into runs of characters separated by gaps
+// wider than xTolerance, ordered left to right
+func splitLineIntoWords(line []CharObject, xTolerance float64) [][]CharObject {
+	sorted := make([]CharObject, len(line))
+	copy(sorted, line)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].X0 < sorted[j].X0
+	})
+
+	var words [][]CharObject
+	for i, char := range sorted {
+		if i == 0 || char.X0-sorted[i-1].X1 > xTolerance {
+			words = append(words, nil)
+		}
+		words[len(words)-1] = append(words[len(words)-1], char)
+	}
+	return words
+}
+
+// splitAtGutters splits a line into one part per column
+func splitAtGutters(line []CharObject, gutters []columnGutter) [][]CharObject {
+	parts := make([][]CharObject, len(gutters)+1)
+	for _, char := range line {
+		index := columnIndex((char.X0+char.X1)/2, gutters)
+		parts[index] = append(parts[index], char)
+	}
+	return parts
+}
+
+// groupCharsIntoTextLines groups characters into lines ordered top to bottom
+func groupCharsIntoTextLines(chars []CharObject, yTolerance float64, topDown bool) [][]CharObject {
+	if len(chars) == 0 {
+		return nil
+	}
+
+	sorted := make([]CharObject, len(chars))
+	copy(sorted, chars)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if topDown {
+			return sorted[i].Y0 < sorted[j].Y0
+		}
+		return sorted[i].Y0 > sorted[j].Y0
+	})
+
+	var lines [][]CharObject
+	current := []CharObject{sorted[0]}
+	for _, char := range sorted[1:] {
+		if abs(char.Y0-current[0].Y0) > yTolerance {
+			lines = append(lines, current)
+			current = nil
+		}
+		current = append(current, char)
+	}
+	lines = append(lines, current)
+
+	return lines
+}
+
+// findColumnGutters finds vertical whitespace strips that run through
+// most lines and have text on both sides
+func findColumnGutters(lines [][]CharObject) []columnGutter {
+	if len(lines) < 2*minColumnLines {
+		return nil
+	}
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, line := range lines {
+		for _, char := range line {
+			minX = min(minX, char.X0)
+			maxX = max(maxX, char.X1)
+		}
+	}
+	if maxX-minX < 3*minColumnGutter {
+		return nil
+	}
+
+	// Count how many lines cover each 1pt slot across the page
+	slots := int(math.Ceil(maxX - minX))
+	coverage := make([]int, slots)
+	for _, line := range lines {
+		covered := make([]bool, slots)
+		for _, char := range line {
+			start := int(math.Floor(char.X0 - minX))
+			end := int(math.Ceil(char.X1 - minX))
+			if start < 0 {
+				start = 0
+			}
+			for i := start; i < end && i < slots; i++ {
+				covered[i] = true
+			}
+		}
+		for i, c := range covered {
+			if c {
+				coverage[i]++
+			}
+		}
+	}
+
+	// A few full-width lines such as headings may cross a gutter
+	allowed := len(lines)/10 + 1
+
+	var gutters []columnGutter
+	start := -1
+	for i := 0; i <= slots; i++ {
+		if i < slots && coverage[i] <= allowed {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start > 0 && i < slots && float64(i-start) >= minColumnGutter {
+			gutter := columnGutter{X0: minX + float64(start), X1: minX + float64(i)}
+			if hasTextOnBothSides(lines, gutter) {
+				gutters = append(gutters, gutter)
+			}
+		}
+		start = -1
+	}
+
+	return gutters
+}
+
+// hasTextOnBothSides checks that enough lines have text left and right of a gutter
+func hasTextOnBothSides(lines [][]CharObject, gutter columnGutter) bool {
+	left, right := 0, 0
+	for _, line := range lines {
+		hasLeft, hasRight := false, false
+		for _, char := range line {
+			if char.X1 <= gutter.X0 {
+				hasLeft = true
+			} else if char.X0 >= gutter.X1 {
+				hasRight = true
+			}
+		}
+		if hasLeft {
+			left++
+		}
+		if hasRight {
+			right++
+		}
+	}
+	return left >= minColumnLines && right >= minColumnLines
+}
+
+// spansGutter checks if any character of a line runs into a gutter
+func spansGutter(line []CharObject, gutters []columnGutter) bool {
+	for _, char := range line {
+		for _, gutter := range gutters {
+			if char.X0 < gutter.X1 && char.X1 > gutter.X0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// columnIndex returns the column a horizontal position falls into
+func columnIndex(x float64, gutters []columnGutter) int {
+	index := 0
+	for _, gutter := range gutters {
+		if x > (gutter.X0+gutter.X1)/2 {
+			index++
+		}
+	}
+	return index
+}
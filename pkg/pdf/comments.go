@@ -0,0 +1,167 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// Comment is a comment or text markup annotation of a page. Boxes are in the
+// coordinates of the page's objects.
+type Comment struct {
+	Type     string        // Annotation subtype: Text, FreeText, Popup, Highlight, Underline, StrikeOut or Squiggly
+	Contents string        // The note, from /Contents, or that of the parent annotation for a popup
+	Author   string        // From /T, or that of the parent annotation for a popup
+	BBox     BoundingBox   // The annotation's /Rect
+	Areas    []BoundingBox // Marked areas from /QuadPoints, for text markup annotations
+	Text     string        // Page text within the marked areas
+}
+
+// commentTypes are the annotation subtypes returned as comments, true for
+// text markup annotations
+var commentTypes = map[string]bool{
+	"Text":      false,
+	"FreeText":  false,
+	"Popup":     false,
+	"Highlight": true,
+	"Underline": true,
+	"StrikeOut": true,
+	"Squiggly":  true,
+}
+
+// quadPointAreas returns the bounding boxes of the quadrilaterals given by
+// /QuadPoints, eight coordinates each
+func quadPointAreas(points []float64) []BoundingBox {
+	var areas []BoundingBox
+	for i := 0; i+8 <= len(points); i += 8 {
+		quad := points[i : i+8]
+		area := BoundingBox{X0: quad[0], Y0: quad[1], X1: quad[0], Y1: quad[1]}
+		for j := 2; j < 8; j += 2 {
+			area.X0, area.X1 = min(area.X0, quad[j]), max(area.X1, quad[j])
+			area.Y0, area.Y1 = min(area.Y0, quad[j+1]), max(area.Y1, quad[j+1])
+		}
+		areas = append(areas, area)
+	}
+	return areas
+}
+
+// placeComments moves comments from PDF space into the coordinates of a
+// page's objects, relative to the page box if one is set, top-down for
+// backends placing objects so, and through the crops and rotations of view.
+// Comments a crop leaves out are dropped; the others get the text within
+// their areas.
+func placeComments(comments []Comment, chars []CharObject, pageBox *BoundingBox, view pageView, height float64, topDown bool) []Comment {
+	var dx, dy float64
+	if pageBox != nil {
+		dx, dy = -pageBox.X0, -pageBox.Y0
+	}
+	height = view.baseHeight(height)
+	place := func(box BoundingBox) (BoundingBox, bool) {
+		box = BoundingBox{X0: box.X0 + dx, Y0: box.Y0 + dy, X1: box.X1 + dx, Y1: box.Y1 + dy}.Normalize()
+		if topDown {
+			box.Y0, box.Y1 = height-box.Y1, height-box.Y0
+		}
+		return view.place(box, topDown)
+	}
+
+	placed := comments[:0]
+	for _, comment := range comments {
+		var ok bool
+		if comment.BBox, ok = place(comment.BBox); !ok {
+			continue
+		}
+		areas := comment.Areas[:0]
+		for _, area := range comment.Areas {
+			if area, ok = place(area); ok {
+				areas = append(areas, area)
+			}
+		}
+		comment.Areas = areas
+		if len(comment.Areas) > 0 {
+			comment.Text = markedText(chars, comment.Areas, view.rotation(), topDown)
+		}
+		placed = append(placed, comment)
+	}
+	return placed
+}
+
+// markedText returns the text of the characters whose center lies in one of
+// the areas, line by line joined with spaces. The characters of a page
+// rotated by degrees are turned back upright to be read in lines.
+func markedText(chars []CharObject, areas []BoundingBox, degrees int, topDown bool) string {
+	var marked []CharObject
+	for _, char := range chars {
+		if centerExcluded(char.GetBBox(), areas) {
+			marked = append(marked, char)
+		}
+	}
+	if degrees != 0 {
+		upright, _ := RotateObjectsInBox(Objects{Chars: marked}, 360-degrees, BoundingBox{}, topDown)
+		marked = upright.Chars
+	}
+
+	var lines []string
+	for _, line := range groupCharsIntoTextLines(marked, 3, topDown) {
+		if text := strings.TrimSpace(extractLineText(line, 3)); text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// libraryValue is a PDF value of the ledongthuc and dslipak libraries, which
+// share their API
+type libraryValue[V any] interface {
+	Key(key string) V
+	Index(i int) V
+	Len() int
+	Name() string
+	Keys() []string
+	Text() string
+	RawString() string
+	Float64() float64
+	Int64() int64
+	Bool() bool
+	IsNull() bool
+	String() string
+}
+
+// libraryComments reads the comments of a page's /Annots array through the
+// libraries, in PDF space
+func libraryComments[V libraryValue[V]](annots V) []Comment {
+	var comments []Comment
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		subtype := annot.Key("Subtype").Name()
+		if _, ok := commentTypes[subtype]; !ok {
+			continue
+		}
+
+		comment := Comment{
+			Type:     subtype,
+			Contents: annot.Key("Contents").Text(),
+			Author:   annot.Key("T").Text(),
+		}
+		parent := annot.Key("Parent")
+		if comment.Contents == "" {
+			comment.Contents = parent.Key("Contents").Text()
+		}
+		if comment.Author == "" {
+			comment.Author = parent.Key("T").Text()
+		}
+		if rect := annot.Key("Rect"); rect.Len() == 4 {
+			comment.BBox = BoundingBox{
+				X0: rect.Index(0).Float64(),
+				Y0: rect.Index(1).Float64(),
+				X1: rect.Index(2).Float64(),
+				Y1: rect.Index(3).Float64(),
+			}
+		}
+		quadPoints := annot.Key("QuadPoints")
+		points := make([]float64, quadPoints.Len())
+		for j := range points {
+			points[j] = quadPoints.Index(j).Float64()
+		}
+		comment.Areas = quadPointAreas(points)
+		comments = append(comments, comment)
+	}
+	return comments
+}
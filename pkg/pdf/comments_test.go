@@ -0,0 +1,58 @@
+package pdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPageComments(t *testing.T) {
+	// A highlight over "brown fox" with its popup, a sticky note with a
+	// UTF-16 contents string, and a link that is not a comment
+	expected := []Comment{
+		{Type: "Highlight", Contents: "Nice phrase", Author: "Reviewer A", Text: "brown fox"},
+		{Type: "Text", Contents: "Check", Author: "Reviewer B"},
+		{Type: "Popup", Contents: "Nice phrase", Author: "Reviewer A"},
+	}
+
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/comments.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, _ := doc.GetPage(0)
+			comments := page.Comments()
+			if len(comments) != len(expected) {
+				t.Fatalf("expected %d comments, got %+v", len(expected), comments)
+			}
+			for i, comment := range comments {
+				want := expected[i]
+				if comment.Type != want.Type || comment.Contents != want.Contents || comment.Author != want.Author || comment.Text != want.Text {
+					t.Errorf("comment %d: expected %+v, got %+v", i, want, comment)
+				}
+			}
+
+			highlight := comments[0]
+			if len(highlight.Areas) != 1 || math.Abs(highlight.Areas[0].Width()-64.8) > FloatTolerance || highlight.Areas[0].Height() != 18 {
+				t.Errorf("expected one 64.8x18 highlighted area, got %+v", highlight.Areas)
+			}
+			if highlight.BBox.X0 != 144 {
+				t.Errorf("expected the highlight's rect to start at x=144, got %+v", highlight.BBox)
+			}
+
+			// A quarter turn clockwise stands the highlight, 696 above the
+			// bottom of the page, 696 from its left
+			rotated := page.Rotate(90).Comments()
+			if len(rotated) != len(expected) || rotated[0].Text != "brown fox" || rotated[0].BBox.X0 != 696 {
+				t.Errorf("expected the highlight over brown fox at x=696 on the rotated page, got %+v", rotated)
+			}
+		})
+	}
+}
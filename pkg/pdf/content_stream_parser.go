@@ -3,6 +3,7 @@ package pdf
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -27,10 +28,40 @@ type ContentStreamParser struct {
 	
 	// Current path
 	currentPath   []PathElement
+	clipPending   bool // W or W* seen, applied when the path is painted
 	
 	// Resources
-	resources     types.Dict
-	fonts         map[string]*FontInfo
+	resources      types.Dict
+	outerResources []types.Dict // Resources of the streams enclosing the current form, innermost last
+	fonts          map[string]*FontInfo
+	fontOverrides  []fontUnicodeOverride // Applied again to the fonts of form XObjects
+	fallbackFont   *FontInfo             // Font of text shown without a usable Tf
+	fontCache      *fontCache            // Fonts of the document loaded so far, nil to load every font anew
+	
+	// Options
+	detectSpaceGlyphs bool // Decode each font's designated space code as a space
+	repairUnicode     bool // Repair malformed UTF-16 in ToUnicode CMaps
+	pendingSpace      bool // A zero-width space glyph was skipped since the last character
+	patternDepth      int  // Nesting of pattern cells parsed to resolve their color
+	formDepth         int  // Nesting of form XObjects being parsed
+	patternSpace      Matrix // Maps the pattern space of the current stream to the page
+	patternCells      map[int]Objects // Parsed cells of tiling patterns by object number
+	maxObjects        int  // Objects emitted before parsing stops, 0 for no limit
+	maxStreamSize     int64 // Largest decoded stream in bytes, 0 for no limit
+	truncated         bool // Parsing stopped at maxObjects
+	painted           int  // Chars and rects painted so far, numbering their painting order
+	
+	// Marked content
+	lang      string         // Language of the current text, from the innermost /Lang
+	langStack []string       // Languages outside each open marked-content sequence
+	mcidLangs map[int]string // Languages of marked-content IDs from the structure tree
+	
+	markedContentID    int   // MCID + 1 of the innermost sequence that has one, 0 outside any
+	markedContentStack []int // Marked-content IDs outside each open sequence
+	
+	layers        []string          // Names of the layers marked in the content, in order of first use
+	hiddenLayers  map[int]bool      // Object numbers of the layers whose content is dropped
+	optionalStack []optionalContent // Layer state of each open sequence
 }
 
 // GraphicsState represents the PDF graphics state
@@ -44,6 +75,7 @@ type GraphicsState struct {
 	MiterLimit    float64
 	DashPattern   []float64
 	DashPhase     float64
+	ClipBBox      *BoundingBox // Bounds of the current clipping path, nil if unclipped
 }
 
 // TextState represents the PDF text state
@@ -67,6 +99,29 @@ type FontInfo struct {
 	SpaceWidth   float64
 	FontMatrix   Matrix
 	ToUnicodeCMap *ToUnicodeCMap // Added for proper text decoding
+	Widths       []float64          // Advances of a simple font's codes from FirstChar, from the Widths array
+	FirstChar    int                // Code of the first entry of Widths
+	MissingWidth float64            // Advance of codes outside Widths from the font descriptor, 0 if not given
+	CIDWidths    map[uint16]float64 // Glyph widths of CID fonts from the W array
+	DefaultWidth float64            // Width of CIDs missing from W (DW)
+	StandardFont string             // Standard 14 font whose AFM widths apply to a simple font without Widths
+	FontBBox     BoundingBox        // Glyph bounding box from the font descriptor, in glyph space units
+	Ascent       float64            // Height of ascenders above the baseline from the font descriptor
+	Descent      float64            // Depth of descenders below the baseline, negative, from the font descriptor
+	Flags        int                // Font descriptor flags, such as fixed pitch, serif and italic
+	AvgWidth     float64            // Average glyph width from the font descriptor, in glyph space units
+}
+
+// cidWidth returns the width of a CID in glyph space units (1/1000 em).
+// It reports false for fonts without CID widths.
+func (f *FontInfo) cidWidth(cid uint16) (float64, bool) {
+	if f.CIDWidths == nil {
+		return 0, false
+	}
+	if width, ok := f.CIDWidths[cid]; ok {
+		return width, true
+	}
+	return f.DefaultWidth, true
 }
 
 // Matrix represents a 2D transformation matrix
@@ -78,6 +133,8 @@ type Matrix struct {
 type PDFColor struct {
 	R, G, B float64
 	ColorSpace string
+	Pattern    string // Pattern resource name when filling with a pattern
+	Unresolved bool   // A pattern without a representative solid color
 }
 
 // PathElement represents an element in a path
@@ -93,7 +150,22 @@ type PDFPoint struct {
 
 // NewContentStreamParser creates a new content stream parser
 func NewContentStreamParser(ctx *model.Context, pageDict types.Dict) *ContentStreamParser {
-	parser := &ContentStreamParser{
+	parser := newContentStreamParser(ctx, pageDict)
+	
+	// Extract resources
+	if res := pageDict["Resources"]; res != nil {
+		if resDict, ok := res.(types.Dict); ok {
+			parser.setResources(resDict)
+		}
+	}
+	
+	return parser
+}
+
+// newContentStreamParser creates a content stream parser without loading
+// the page's resources, for options that affect fonts to be set first
+func newContentStreamParser(ctx *model.Context, pageDict types.Dict) *ContentStreamParser {
+	return &ContentStreamParser{
 		ctx:      ctx,
 		pageDict: pageDict,
 		objects:  Objects{},
@@ -109,20 +181,19 @@ func NewContentStreamParser(ctx *model.Context, pageDict types.Dict) *ContentStr
 			Scale:      100,
 			RenderMode: 0,
 		},
-		textMatrix: IdentityMatrix(),
-		lineMatrix: IdentityMatrix(),
-		fonts:      make(map[string]*FontInfo),
-	}
-	
-	// Extract resources
-	if res := pageDict["Resources"]; res != nil {
-		if resDict, ok := res.(types.Dict); ok {
-			parser.resources = resDict
-			parser.extractFonts()
-		}
+		textMatrix:   IdentityMatrix(),
+		lineMatrix:   IdentityMatrix(),
+		patternSpace: IdentityMatrix(),
+		fonts:        make(map[string]*FontInfo),
+		fallbackFont: newFallbackFont(defaultFallbackFont),
 	}
-	
-	return parser
+}
+
+// setResources replaces the resources that fonts and XObjects are looked up in
+func (p *ContentStreamParser) setResources(resources types.Dict) {
+	p.resources = resources
+	p.fonts = make(map[string]*FontInfo)
+	p.extractFonts()
 }
 
 // extractFonts extracts font information from resources
@@ -168,128 +239,371 @@ func (p *ContentStreamParser) extractFonts() {
 	
 	for name, fontRef := range fonts {
 		// fmt.Printf("[DEBUG-FONT] Processing font %s, type: %T\n", name, fontRef)
-		fontObj := fontRef
+		if fontInfo := p.loadFont(name, fontRef); fontInfo != nil {
+			p.fonts[name] = fontInfo
+		}
+	}
+}
+
+// parseFont reads the font dictionary of a font resource, nil if it is not one
+func (p *ContentStreamParser) parseFont(name string, fontRef types.Object) *FontInfo {
+	fontObj := fontRef
+	
+	// Dereference font object
+	if indRef, ok := fontRef.(types.IndirectRef); ok {
+		// fmt.Printf("[DEBUG-FONT] Font %s is IndirectRef, using DereferenceDict...\n", name)
+		dict, err := p.ctx.DereferenceDict(indRef)
+		if err != nil {
+			// fmt.Printf("[DEBUG-FONT] Failed to DereferenceDict font %s: %v\n", name, err)
+			return nil
+		}
+		if dict != nil {
+			fontObj = dict
+		}
+	} else if indRef, ok := fontRef.(*types.IndirectRef); ok {
+		// fmt.Printf("[DEBUG-FONT] Font %s is *IndirectRef, using DereferenceDict...\n", name)
+		dict, err := p.ctx.DereferenceDict(*indRef)
+		if err != nil {
+			// fmt.Printf("[DEBUG-FONT] Failed to DereferenceDict font %s: %v\n", name, err)
+			return nil
+		}
+		if dict != nil {
+			fontObj = dict
+		}
+	}
+	
+	// fmt.Printf("[DEBUG-FONT] After dereference, font %s type: %T\n", name, fontObj)
+	if fontDict, ok := fontObj.(types.Dict); ok {
+		// fmt.Printf("[DEBUG-FONT] Font %s is a Dict\n", name)
+		fontInfo := &FontInfo{
+			Name:       name,
+			FontMatrix: Matrix{A: 0.001, B: 0, C: 0, D: 0.001, E: 0, F: 0}, // Default
+		}
 		
-		// Dereference font object
-		if indRef, ok := fontRef.(types.IndirectRef); ok {
-			// fmt.Printf("[DEBUG-FONT] Font %s is IndirectRef, using DereferenceDict...\n", name)
-			dict, err := p.ctx.DereferenceDict(indRef)
-			if err != nil {
-				// fmt.Printf("[DEBUG-FONT] Failed to DereferenceDict font %s: %v\n", name, err)
-				continue
-			}
-			if dict != nil {
-				fontObj = dict
-			}
-		} else if indRef, ok := fontRef.(*types.IndirectRef); ok {
-			// fmt.Printf("[DEBUG-FONT] Font %s is *IndirectRef, using DereferenceDict...\n", name)
-			dict, err := p.ctx.DereferenceDict(*indRef)
-			if err != nil {
-				// fmt.Printf("[DEBUG-FONT] Failed to DereferenceDict font %s: %v\n", name, err)
-				continue
-			}
-			if dict != nil {
-				fontObj = dict
+		// Extract BaseFont
+		if baseFont := fontDict["BaseFont"]; baseFont != nil {
+			if bf, ok := baseFont.(types.Name); ok {
+				fontInfo.BaseFont = string(bf)
 			}
 		}
 		
-		// fmt.Printf("[DEBUG-FONT] After dereference, font %s type: %T\n", name, fontObj)
-		if fontDict, ok := fontObj.(types.Dict); ok {
-			// fmt.Printf("[DEBUG-FONT] Font %s is a Dict\n", name)
-			fontInfo := &FontInfo{
-				Name:       name,
-				FontMatrix: Matrix{A: 0.001, B: 0, C: 0, D: 0.001, E: 0, F: 0}, // Default
-				SpaceWidth: 0.25, // Default estimate
+		// Non-embedded standard fonts usually come without Widths, their
+		// advances are those of the standard font metrics
+		if subtype := fontDict.Subtype(); fontDict["Widths"] == nil && (subtype == nil || *subtype == "Type1" || *subtype == "TrueType") {
+			if standard, ok := standardFontName(fontInfo.BaseFont); ok {
+				fontInfo.StandardFont = standard
 			}
-			
-			// Extract BaseFont
-			if baseFont := fontDict["BaseFont"]; baseFont != nil {
-				if bf, ok := baseFont.(types.Name); ok {
-					fontInfo.BaseFont = string(bf)
-				}
+		}
+		
+		// Extract Encoding
+		if encoding := fontDict["Encoding"]; encoding != nil {
+			if enc, ok := encoding.(types.Name); ok {
+				fontInfo.Encoding = string(enc)
+			} else if p.dereferenceDict(encoding) != nil {
+				fontInfo.Encoding = "Differences"
 			}
+		}
+		
+		// Extract ToUnicode CMap
+		if toUnicode := fontDict["ToUnicode"]; toUnicode != nil {
+			// fmt.Printf("[DEBUG-FONT] Font %s has ToUnicode, type: %T\n", name, toUnicode)
 			
-			// Extract Encoding
-			if encoding := fontDict["Encoding"]; encoding != nil {
-				if enc, ok := encoding.(types.Name); ok {
-					fontInfo.Encoding = string(enc)
-				}
-			}
+			// Try to dereference ToUnicode stream
+			var cmapData []byte
 			
-			// Extract ToUnicode CMap
-			if toUnicode := fontDict["ToUnicode"]; toUnicode != nil {
-				// fmt.Printf("[DEBUG-FONT] Font %s has ToUnicode, type: %T\n", name, toUnicode)
-				
-				// Try to dereference ToUnicode stream
-				var cmapData []byte
-				
-				if indRef, ok := toUnicode.(types.IndirectRef); ok {
-					streamDict, _, err := p.ctx.DereferenceStreamDict(indRef)
-					if err == nil && streamDict != nil {
-						if err := streamDict.Decode(); err == nil {
-							cmapData = streamDict.Content
-							// fmt.Printf("[DEBUG-FONT] Got ToUnicode CMap data for %s: %d bytes\n", name, len(cmapData))
-						}
-					}
-				} else if indRef, ok := toUnicode.(*types.IndirectRef); ok {
-					streamDict, _, err := p.ctx.DereferenceStreamDict(*indRef)
-					if err == nil && streamDict != nil {
-						if err := streamDict.Decode(); err == nil {
-							cmapData = streamDict.Content
-							// fmt.Printf("[DEBUG-FONT] Got ToUnicode CMap data for %s: %d bytes\n", name, len(cmapData))
-						}
+			if indRef, ok := toUnicode.(types.IndirectRef); ok {
+				streamDict, _, err := p.ctx.DereferenceStreamDict(indRef)
+				if err == nil && streamDict != nil {
+					if data, err := decodeStream(streamDict, p.maxStreamSize); err == nil {
+						cmapData = data
+						// fmt.Printf("[DEBUG-FONT] Got ToUnicode CMap data for %s: %d bytes\n", name, len(cmapData))
 					}
 				}
-				
-				// Parse CMap if we got data
-				if len(cmapData) > 0 {
-					cmap := NewToUnicodeCMap()
-					if err := cmap.Parse(cmapData); err == nil {
-						fontInfo.ToUnicodeCMap = cmap
-						// fmt.Printf("[DEBUG-FONT] Successfully parsed CMap for %s: %d mappings\n", name, cmap.GetMappingCount())
-					} else {
-						// fmt.Printf("[DEBUG-FONT] Failed to parse CMap for %s: %v\n", name, err)
+			} else if indRef, ok := toUnicode.(*types.IndirectRef); ok {
+				streamDict, _, err := p.ctx.DereferenceStreamDict(*indRef)
+				if err == nil && streamDict != nil {
+					if data, err := decodeStream(streamDict, p.maxStreamSize); err == nil {
+						cmapData = data
+						// fmt.Printf("[DEBUG-FONT] Got ToUnicode CMap data for %s: %d bytes\n", name, len(cmapData))
 					}
 				}
 			}
 			
-			p.fonts[name] = fontInfo
-			// fmt.Printf("[DEBUG-FONT] Added font %s: %+v\n", name, fontInfo)
+			// Parse CMap if we got data
+			if len(cmapData) > 0 {
+				cmap := NewToUnicodeCMap()
+				cmap.repair = p.repairUnicode
+				if err := cmap.Parse(cmapData); err == nil {
+					fontInfo.ToUnicodeCMap = cmap
+					// fmt.Printf("[DEBUG-FONT] Successfully parsed CMap for %s: %d mappings\n", name, cmap.GetMappingCount())
+				} else {
+					// fmt.Printf("[DEBUG-FONT] Failed to parse CMap for %s: %v\n", name, err)
+				}
+			}
 		}
+		
+		// Extract CID glyph widths from the descendant font, or the widths
+		// of a simple font's codes
+		if subtype := fontDict.Subtype(); subtype != nil && *subtype == "Type0" {
+			p.extractCIDWidths(fontInfo, fontDict)
+		} else {
+			p.extractWidths(fontInfo, fontDict)
+		}
+		
+		p.extractFontMetrics(fontInfo, fontDict)
+		fontInfo.SpaceWidth = fontInfo.spaceGlyphWidth()
+		
+		// fmt.Printf("[DEBUG-FONT] Added font %s: %+v\n", name, fontInfo)
+		return fontInfo
+	}
+	return nil
+}
+
+// applyFontOverrides replaces the ToUnicode CMaps of fonts matched by an
+// override. The first matching override wins.
+func (p *ContentStreamParser) applyFontOverrides(overrides []fontUnicodeOverride) {
+	p.fontOverrides = overrides
+	for _, font := range p.fonts {
+		p.overrideFont(font)
+	}
+}
+
+// overrideFont applies the first font override matching a font, if any
+func (p *ContentStreamParser) overrideFont(font *FontInfo) {
+	if font == nil {
+		return
+	}
+	for _, override := range p.fontOverrides {
+		if override.cmap == nil || !override.pattern.MatchString(font.BaseFont) {
+			continue
+		}
+		font.ToUnicodeCMap = override.cmap.withFallback(font.ToUnicodeCMap)
+		break
+	}
+}
+
+// extractCIDWidths reads the W and DW entries of a Type0 font's descendant font
+func (p *ContentStreamParser) extractCIDWidths(fontInfo *FontInfo, fontDict types.Dict) {
+	descendants, ok := p.resolveObject(fontDict["DescendantFonts"]).(types.Array)
+	if !ok || len(descendants) == 0 {
+		return
+	}
+	cidFont := p.dereferenceDict(descendants[0])
+	if cidFont == nil {
+		return
+	}
+	
+	fontInfo.DefaultWidth = 1000
+	if dw := p.resolveObject(cidFont["DW"]); dw != nil {
+		fontInfo.DefaultWidth = numberValue(dw)
+	}
+	
+	fontInfo.CIDWidths = map[uint16]float64{}
+	if w, ok := p.resolveObject(cidFont["W"]).(types.Array); ok {
+		fontInfo.CIDWidths = p.parseCIDWidths(w)
+	}
+}
+
+// extractWidths reads the Widths, FirstChar and LastChar entries of a simple
+// font. Type3 widths are in the font's glyph space, so they are scaled by
+// its FontMatrix to the usual 1/1000 em.
+func (p *ContentStreamParser) extractWidths(fontInfo *FontInfo, fontDict types.Dict) {
+	widths, ok := p.resolveObject(fontDict["Widths"]).(types.Array)
+	if !ok || len(widths) == 0 {
+		return
+	}
+	
+	scale := 1.0
+	if subtype := fontDict.Subtype(); subtype != nil && *subtype == "Type3" {
+		matrix, ok := p.resolveObject(fontDict["FontMatrix"]).(types.Array)
+		if !ok || len(matrix) != 6 {
+			return
+		}
+		scale = numberValue(p.resolveObject(matrix[0])) * 1000
+	}
+	
+	first := int(numberValue(p.resolveObject(fontDict["FirstChar"])))
+	count := len(widths)
+	if last := p.resolveObject(fontDict["LastChar"]); last != nil && int(numberValue(last))-first+1 < count {
+		count = int(numberValue(last)) - first + 1
+	}
+	if count <= 0 {
+		return
+	}
+	fontInfo.FirstChar = first
+	fontInfo.Widths = make([]float64, count)
+	for i := range fontInfo.Widths {
+		fontInfo.Widths[i] = numberValue(p.resolveObject(widths[i])) * scale
+	}
+}
+
+// parseCIDWidths parses a CID font W array, which mixes "c [w1 w2 ...]"
+// entries for consecutive CIDs and "cFirst cLast w" entries for ranges
+func (p *ContentStreamParser) parseCIDWidths(w types.Array) map[uint16]float64 {
+	widths := map[uint16]float64{}
+	
+	for i := 0; i+1 < len(w); {
+		first := int(numberValue(p.resolveObject(w[i])))
+		
+		if list, ok := p.resolveObject(w[i+1]).(types.Array); ok {
+			for j, width := range list {
+				if cid := first + j; cid >= 0 && cid <= 0xFFFF {
+					widths[uint16(cid)] = numberValue(p.resolveObject(width))
+				}
+			}
+			i += 2
+			continue
+		}
+		
+		if i+2 >= len(w) {
+			break
+		}
+		last := int(numberValue(p.resolveObject(w[i+1])))
+		width := numberValue(p.resolveObject(w[i+2]))
+		for cid := first; cid <= last && cid <= 0xFFFF; cid++ {
+			if cid >= 0 {
+				widths[uint16(cid)] = width
+			}
+		}
+		i += 3
 	}
+	
+	return widths
 }
 
 // Parse parses a content stream and returns extracted objects
 func (p *ContentStreamParser) Parse(content []byte) Objects {
-	// Tokenize the content stream
-	tokens := p.tokenize(content)
+	if !p.parseOperators(content) {
+		p.closeAllOptionalContent()
+		return p.objects
+	}
+	p.closeAllOptionalContent()
+	p.paintAnnotations()
+	
+	// Rectangles drawn as separate edge segments are not caught by
+	// isRectanglePath, so join them up from the collected lines
+	p.addRectanglesFromLines()
+	if limitObjects(&p.objects, p.maxObjects) {
+		p.truncated = true
+	}
 	
-	// Process tokens
+	return p.objects
+}
+
+// parseOperators processes the operators of a content stream. It reports
+// false when parsing stopped at the object limit, leaving the rest of the
+// stream untokenized.
+func (p *ContentStreamParser) parseOperators(content []byte) bool {
+	if p.objects.Chars == nil {
+		// Size the characters up front rather than growing the slice
+		// glyph by glyph on text-heavy pages
+		if n := estimateChars(content, p.maxObjects); n > 0 {
+			p.objects.Chars = make([]CharObject, 0, n)
+		}
+	}
+	
+	// Process tokens as they are read
+	reader := bytes.NewReader(content)
 	operands := []string{}
-	for i := 0; i < len(tokens); i++ {
-		token := tokens[i]
+	for {
+		token, ok := p.nextToken(reader)
+		if !ok {
+			break
+		}
 		
 		// Check if it's an operator
 		if p.isOperator(token) {
 			// Process the operator with accumulated operands
 			p.processOperator(token, operands)
+			if limitObjects(&p.objects, p.maxObjects) {
+				p.truncated = true
+				return false
+			}
 			
 			// Clear operands for next operator
 			operands = []string{}
+		} else if isUnknownOperator(token) {
+			// Operators we don't handle, as allowed inside BX/EX sections,
+			// still consume their operands
+			operands = []string{}
 		} else {
 			// Accumulate operands
 			operands = append(operands, token)
 		}
 	}
-	
-	return p.objects
+	return true
 }
 
-// tokenize splits content stream into tokens
-func (p *ContentStreamParser) tokenize(content []byte) []string {
-	var tokens []string
-	reader := bytes.NewReader(content)
-	
+// estimateChars returns how many characters the strings of a content
+// stream show at most with one-byte codes, counting no further than limit
+// when it is positive
+func estimateChars(content []byte, limit int) int {
+	n := 0
+	for i := 0; i < len(content) && (limit <= 0 || n < limit); i++ {
+		switch content[i] {
+		case '(':
+			depth := 1
+			for i++; i < len(content) && depth > 0; i++ {
+				switch content[i] {
+				case '\\':
+					i++
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				if depth > 0 {
+					n++
+				}
+			}
+			i--
+		case '<':
+			if i+1 < len(content) && content[i+1] == '<' {
+				i++
+				continue
+			}
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				end = len(content) - i
+			}
+			n += (end - 1) / 2
+			i += end
+		case '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		}
+	}
+	if limit > 0 && n > limit {
+		n = limit
+	}
+	return n
+}
+
+// addRectanglesFromLines synthesizes rectangles from closed loops of solid
+// lines. The original lines are kept. Dashed lines form no rectangles, so
+// leaving them out of table detection leaves out the boxes they draw too.
+func (p *ContentStreamParser) addRectanglesFromLines() {
+	solid := make([]LineObject, 0, len(p.objects.Lines))
+	for _, line := range p.objects.Lines {
+		if !line.Dashed() {
+			solid = append(solid, line)
+		}
+	}
+	existing := make(map[rectKey]bool, len(p.objects.Rects))
+	for _, rect := range p.objects.Rects {
+		existing[keyOfRect(rect)] = true
+	}
+	for _, rect := range DetectRectanglesFromLines(solid, LineJoinTolerance) {
+		if !existing[keyOfRect(rect)] {
+			p.objects.Rects = append(p.objects.Rects, rect)
+		}
+	}
+}
+
+// nextToken reads the next token of a content stream, reporting false at
+// the end of the stream
+func (p *ContentStreamParser) nextToken(reader *bytes.Reader) (string, bool) {
 	for reader.Len() > 0 {
 		// Skip whitespace
 		b, err := reader.ReadByte()
@@ -306,38 +620,36 @@ func (p *ContentStreamParser) tokenize(content []byte) []string {
 		case '(':
 			// String literal
 			str := p.readStringLiteral(reader)
-			tokens = append(tokens, "("+str+")")
+			return "(" + str + ")", true
 			
 		case '<':
 			// Hex string or dictionary
 			next, _ := reader.ReadByte()
 			if next == '<' {
-				tokens = append(tokens, "<<")
-			} else {
-				reader.UnreadByte()
-				hex := p.readHexString(reader)
-				tokens = append(tokens, "<"+hex+">")
+				return "<<", true
 			}
+			reader.UnreadByte()
+			hex := p.readHexString(reader)
+			return "<" + hex + ">", true
 			
 		case '>':
 			// Dictionary end
 			next, _ := reader.ReadByte()
 			if next == '>' {
-				tokens = append(tokens, ">>")
-			} else {
-				reader.UnreadByte()
+				return ">>", true
 			}
+			reader.UnreadByte()
 			
 		case '[':
-			tokens = append(tokens, "[")
+			return "[", true
 			
 		case ']':
-			tokens = append(tokens, "]")
+			return "]", true
 			
 		case '/':
 			// Name
 			name := p.readName(reader)
-			tokens = append(tokens, "/"+name)
+			return "/" + name, true
 			
 		case '%':
 			// Comment - skip to end of line
@@ -346,14 +658,16 @@ func (p *ContentStreamParser) tokenize(content []byte) []string {
 		default:
 			// Number or operator
 			reader.UnreadByte()
-			token := p.readToken(reader)
-			if token != "" {
-				tokens = append(tokens, token)
+			if token := p.readToken(reader); token != "" {
+				return token, true
 			}
+			// A stray delimiter such as ")" reads as no token; skip it
+			// rather than reading it again
+			reader.ReadByte()
 		}
 	}
 	
-	return tokens
+	return "", false
 }
 
 // readStringLiteral reads a string literal from the reader
@@ -488,8 +802,10 @@ func (p *ContentStreamParser) isOperator(token string) bool {
 		"S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n",
 		// Color
 		"CS", "cs", "SC", "SCN", "sc", "scn", "G", "g", "RG", "rg", "K", "k",
+		// Type 3 glyph metrics
+		"d0", "d1",
 		// Other
-		"W", "W*", "BX", "EX", "Do", "MP", "DP", "BMC", "BDC", "EMC",
+		"W", "W*", "sh", "BX", "EX", "Do", "MP", "DP", "BMC", "BDC", "EMC",
 	}
 	
 	for _, op := range operators {
@@ -502,6 +818,16 @@ func (p *ContentStreamParser) isOperator(token string) bool {
 }
 
 
+// isUnknownOperator checks if a token is an operator keyword that isn't
+// handled. Keywords start with a letter, unlike numbers, strings and names.
+func isUnknownOperator(token string) bool {
+	if token == "" || token == "true" || token == "false" || token == "null" {
+		return false
+	}
+	b := token[0]
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
 // processOperator processes a PDF operator with its operands
 func (p *ContentStreamParser) processOperator(operator string, operands []string) {
 	switch operator {
@@ -586,6 +912,18 @@ func (p *ContentStreamParser) processOperator(operator string, operands []string
 	case "n":
 		p.endPath()
 		
+	// Clipping and shading
+	case "W", "W*":
+		p.clipPending = true
+	case "sh":
+		p.paintShading(operands)
+	case "Do":
+		p.paintXObject(operands)
+		
+	// Compatibility sections and Type 3 glyph metrics don't affect the
+	// extracted objects
+	case "BX", "EX", "d0", "d1":
+		
 	// Line width and style
 	case "w":
 		p.setLineWidth(operands)
@@ -619,6 +957,14 @@ func (p *ContentStreamParser) processOperator(operator string, operands []string
 		p.setStrokeColor(operands)
 	case "sc", "scn":
 		p.setFillColor(operands)
+		
+	// Marked content
+	case "BMC":
+		p.beginMarkedContent(operands, false)
+	case "BDC":
+		p.beginMarkedContent(operands, true)
+	case "EMC":
+		p.endMarkedContent()
 	}
 }
 
@@ -630,7 +976,10 @@ func (p *ContentStreamParser) beginText() {
 }
 
 func (p *ContentStreamParser) endText() {
-	// Text object ended
+	// The text matrix is undefined outside BT/ET. Reset it so stray text
+	// operators after ET start from the origin instead of a stale position.
+	p.textMatrix = IdentityMatrix()
+	p.lineMatrix = IdentityMatrix()
 }
 
 // Text positioning operators
@@ -687,8 +1036,7 @@ func (p *ContentStreamParser) showText(operands []string) {
 		return
 	}
 	
-	text := p.extractString(operands[0])
-	p.addTextChars(text)
+	p.addTextChars(p.extractGlyphs(operands[0]))
 }
 
 func (p *ContentStreamParser) showTextArray(operands []string) {
@@ -710,8 +1058,7 @@ func (p *ContentStreamParser) showTextArray(operands []string) {
 	
 	for _, elem := range elements {
 		if strings.HasPrefix(elem, "(") || strings.HasPrefix(elem, "<") {
-			text := p.extractString(elem)
-			p.addTextChars(text)
+			p.addTextChars(p.extractGlyphs(elem))
 		} else {
 			// It's a number (spacing adjustment)
 			spacing := parseFloat(elem) / 1000.0 * p.textState.FontSize
@@ -773,12 +1120,48 @@ func (p *ContentStreamParser) setFont(operands []string) {
 	fontName := strings.TrimPrefix(operands[0], "/")
 	fontSize := parseFloat(operands[1])
 	
-	if font, ok := p.fonts[fontName]; ok {
-		p.textState.Font = font
+	// Fonts not loaded up front are looked up on first use. A font missing
+	// from the resources, or that failed to load, leaves the text to the
+	// fallback font.
+	font, ok := p.fonts[fontName]
+	if !ok {
+		font = p.resolveFont(fontName)
+		p.fonts[fontName] = font
 	}
+	p.textState.Font = font
 	p.textState.FontSize = fontSize
 }
 
+// maxPageTreeDepth bounds the walk up the page tree for inherited resources
+const maxPageTreeDepth = 32
+
+// resolveFont loads a font missing from the current resources. It is looked
+// up in the resources of the streams enclosing the current form, then in
+// those of the page and of the Pages nodes above it, which some writers
+// leave fonts to even when the page has resources of its own.
+func (p *ContentStreamParser) resolveFont(name string) *FontInfo {
+	for i := len(p.outerResources) - 1; i >= 0; i-- {
+		if fonts := p.dereferenceDict(p.outerResources[i]["Font"]); fonts[name] != nil {
+			if font := p.loadFont(name, fonts[name]); font != nil {
+				p.overrideFont(font)
+				return font
+			}
+		}
+	}
+	node := p.pageDict
+	for depth := 0; node != nil && depth < maxPageTreeDepth; depth++ {
+		resources := p.dereferenceDict(node["Resources"])
+		if fonts := p.dereferenceDict(resources["Font"]); fonts[name] != nil {
+			if font := p.loadFont(name, fonts[name]); font != nil {
+				p.overrideFont(font)
+				return font
+			}
+		}
+		node = p.dereferenceDict(node["Parent"])
+	}
+	return nil
+}
+
 func (p *ContentStreamParser) setTextRenderMode(operands []string) {
 	if len(operands) < 1 {
 		return
@@ -932,17 +1315,21 @@ func (p *ContentStreamParser) rectangle(operands []string) {
 // Path painting operators
 
 func (p *ContentStreamParser) stroke() {
+	p.applyPendingClip()
 	p.createLineFromPath()
+	p.addStrokedRoundedRect()
 	p.currentPath = nil
 }
 
 func (p *ContentStreamParser) fill() {
+	p.applyPendingClip()
 	p.createFilledPath()
 	p.currentPath = nil
 }
 
 func (p *ContentStreamParser) fillAndStroke() {
 	// First create filled object, then stroked lines
+	p.applyPendingClip()
 	p.createFilledPath()
 	p.createLineFromPath()
 	p.currentPath = nil
@@ -954,47 +1341,203 @@ func (p *ContentStreamParser) createFilledPath() {
 		return
 	}
 	
-	// Check if path forms a rectangle
-	if p.isRectanglePath() {
-		// Extract rectangle bounds
-		minX, minY, maxX, maxY := p.getPathBounds()
-		
-		// Apply transformation
-		x0, y0 := p.transformPoint(minX, minY)
-		x1, y1 := p.transformPoint(maxX, maxY)
-		
-		fillColor := p.convertPDFColorToColor(p.graphicsState.FillColor)
-		
-		rect := RectObject{
-			X0:          min(x0, x1),
-			Y0:          min(y0, y1),
-			X1:          max(x0, x1),
-			Y1:          max(y0, y1),
-			Width:       0, // Filled rectangle has no stroke width
-			FillColor:   fillColor,
-			NonStroking: true, // This is a filled (non-stroking) rectangle
+	// Shading pattern fills are gradients, not solid rectangles
+	if pattern := p.graphicsState.FillColor.Pattern; pattern != "" && p.isShadingPattern(pattern) {
+		p.createPatternShading(pattern)
+		return
+	}
+	if pattern := p.graphicsState.FillColor.Pattern; pattern != "" {
+		p.paintPatternText(pattern)
+	}
+	
+	// Check if path forms a rectangle, possibly with rounded corners, and
+	// extract its bounds
+	minX, minY, maxX, maxY := p.getPathBounds()
+	rounded := false
+	if !p.isRectanglePath() {
+		bounds, ok := p.roundedRectangleBounds()
+		if !ok {
+			return
 		}
-		
-		p.objects.Rects = append(p.objects.Rects, rect)
+		minX, minY, maxX, maxY = bounds.X0, bounds.Y0, bounds.X1, bounds.Y1
+		rounded = true
+	}
+	
+	// Apply transformation
+	x0, y0 := p.transformPoint(minX, minY)
+	x1, y1 := p.transformPoint(maxX, maxY)
+	
+	fillColor := p.convertPDFColorToColor(p.graphicsState.FillColor)
+	
+	rect := RectObject{
+		X0:          min(x0, x1),
+		Y0:          min(y0, y1),
+		X1:          max(x0, x1),
+		Y1:          max(y0, y1),
+		Width:       0, // Filled rectangle has no stroke width
+		FillColor:   fillColor,
+		FillPattern: p.graphicsState.FillColor.Pattern,
+		NonStroking: true, // This is a filled (non-stroking) rectangle
+		Rounded:     rounded,
+		order:       p.paintOrder(),
 	}
+	
+	p.objects.Rects = append(p.objects.Rects, rect)
 	// For complex paths, we could create a more general filled shape object
 }
 
-// isRectanglePath checks if the current path forms a rectangle
+// rectangleTolerance is how far the corners of a path may be off an
+// axis-aligned box, in user space units, for it to count as a rectangle
+const rectangleTolerance = 0.1
+
+// isRectanglePath checks if the current path forms an axis-aligned
+// rectangle: a single subpath of 3 linetos, or 4 with the last returning to
+// the start, each edge horizontal or vertical in turn. A closing h is
+// optional, as filling closes the path anyway.
 func (p *ContentStreamParser) isRectanglePath() bool {
-	// Simple heuristic: 4 lines with close
-	lineCount := 0
-	hasClose := false
+	var points []PDFPoint
+	for i, elem := range p.currentPath {
+		switch {
+		case elem.Type == "moveto" && i == 0, elem.Type == "lineto":
+			if len(elem.Points) == 0 {
+				return false
+			}
+			points = append(points, elem.Points[0])
+		case elem.Type == "close" && i == len(p.currentPath)-1:
+		default:
+			return false
+		}
+	}
 	
-	for _, elem := range p.currentPath {
-		if elem.Type == "lineto" {
-			lineCount++
-		} else if elem.Type == "close" {
-			hasClose = true
+	if len(points) == 5 {
+		last := points[4]
+		if abs(last.X-points[0].X) > rectangleTolerance || abs(last.Y-points[0].Y) > rectangleTolerance {
+			return false
+		}
+		points = points[:4]
+	}
+	if len(points) != 4 {
+		return false
+	}
+	
+	// Edges alternate between horizontal and vertical, whichever comes first
+	horizontal := func(a, b PDFPoint) bool { return abs(a.Y-b.Y) <= rectangleTolerance }
+	vertical := func(a, b PDFPoint) bool { return abs(a.X-b.X) <= rectangleTolerance }
+	for _, firstHorizontal := range []bool{true, false} {
+		ok := true
+		for i := 0; i < 4 && ok; i++ {
+			a, b := points[i], points[(i+1)%4]
+			if (i%2 == 0) == firstHorizontal {
+				ok = horizontal(a, b)
+			} else {
+				ok = vertical(a, b)
+			}
+		}
+		if ok {
+			return true
 		}
 	}
+	return false
+}
+
+// maxCornerRadiusFraction bounds the corner curves of a rounded rectangle
+// relative to the shorter side of its bounds
+const maxCornerRadiusFraction = 0.5
+
+// roundedRectangleBounds checks if the current path is a closed rectangle
+// with rounded corners: two to four axis-aligned edges joined by four short
+// curves, each turning a corner. Circles and ellipses, having no edges, are
+// not rectangles. It returns the path's bounds in user space.
+func (p *ContentStreamParser) roundedRectangleBounds() (BoundingBox, bool) {
+	if len(p.currentPath) < 5 || p.currentPath[0].Type != "moveto" || len(p.currentPath[0].Points) == 0 {
+		return BoundingBox{}, false
+	}
+	
+	// Follow the on-curve points; control points of corner curves stay
+	// inside the corner
+	start := p.currentPath[0].Points[0]
+	current := start
+	bounds := BoundingBox{X0: start.X, Y0: start.Y, X1: start.X, Y1: start.Y}
+	type segment struct {
+		from, to PDFPoint
+		curve    bool
+	}
+	var segments []segment
+	closed := false
+	for _, elem := range p.currentPath[1:] {
+		var next PDFPoint
+		switch elem.Type {
+		case "lineto":
+			next = elem.Points[0]
+		case "curveto":
+			next = elem.Points[2]
+		case "close":
+			next = start
+			closed = true
+		default:
+			return BoundingBox{}, false
+		}
+		if closed && elem.Type != "close" {
+			return BoundingBox{}, false
+		}
+		if next != current || elem.Type == "curveto" {
+			segments = append(segments, segment{from: current, to: next, curve: elem.Type == "curveto"})
+		}
+		bounds = bounds.Union(BoundingBox{X0: next.X, Y0: next.Y, X1: next.X, Y1: next.Y})
+		current = next
+	}
+	if !closed && (abs(current.X-start.X) > LineJoinTolerance || abs(current.Y-start.Y) > LineJoinTolerance) {
+		return BoundingBox{}, false
+	}
 	
-	return lineCount == 3 && hasClose // 3 lineto + 1 implicit line from close
+	maxRadius := maxCornerRadiusFraction*min(bounds.Width(), bounds.Height()) + LineJoinTolerance
+	curves, edges := 0, 0
+	for _, seg := range segments {
+		dx, dy := abs(seg.to.X-seg.from.X), abs(seg.to.Y-seg.from.Y)
+		if seg.curve {
+			// A corner moves both across and along, by at most the radius
+			if dx < FloatTolerance || dy < FloatTolerance || dx > maxRadius || dy > maxRadius {
+				return BoundingBox{}, false
+			}
+			curves++
+			continue
+		}
+		if dx > LineJoinTolerance && dy > LineJoinTolerance {
+			return BoundingBox{}, false
+		}
+		edges++
+	}
+	if curves != 4 || edges < 2 || edges > 4 || bounds.IsEmpty() {
+		return BoundingBox{}, false
+	}
+	return bounds, true
+}
+
+// addStrokedRoundedRect records a stroked rounded rectangle, whose edges do
+// not meet at the corners and so are not joined up from the stroked lines
+func (p *ContentStreamParser) addStrokedRoundedRect() {
+	bounds, ok := p.roundedRectangleBounds()
+	if !ok {
+		return
+	}
+	bbox := p.transformedBounds(bounds.X0, bounds.Y0, bounds.X1, bounds.Y1)
+	p.objects.Rects = append(p.objects.Rects, RectObject{
+		X0:          bbox.X0,
+		Y0:          bbox.Y0,
+		X1:          bbox.X1,
+		Y1:          bbox.Y1,
+		Width:       p.graphicsState.LineWidth,
+		StrokeColor: p.convertPDFColorToColor(p.graphicsState.StrokeColor),
+		Stroked:     true,
+		Rounded:     true,
+		order:       p.paintOrder(),
+	})
+}
+
+// paintOrder numbers the next char or rect painted, from 1
+func (p *ContentStreamParser) paintOrder() int {
+	p.painted++
+	return p.painted
 }
 
 // getPathBounds returns the bounding box of the current path
@@ -1020,9 +1563,680 @@ func (p *ContentStreamParser) getPathBounds() (minX, minY, maxX, maxY float64) {
 }
 
 func (p *ContentStreamParser) endPath() {
+	p.applyPendingClip()
 	p.currentPath = nil
 }
 
+// applyPendingClip intersects the clipping region with the current path
+// once a W or W* operator has been followed by a painting operator
+func (p *ContentStreamParser) applyPendingClip() {
+	if !p.clipPending {
+		return
+	}
+	p.clipPending = false
+	
+	if len(p.currentPath) == 0 {
+		return
+	}
+	
+	clip := p.transformedPathBounds()
+	if current := p.graphicsState.ClipBBox; current != nil {
+		clip = BoundingBox{
+			X0: max(clip.X0, current.X0),
+			Y0: max(clip.Y0, current.Y0),
+			X1: min(clip.X1, current.X1),
+			Y1: min(clip.Y1, current.Y1),
+		}
+	}
+	p.graphicsState.ClipBBox = &clip
+}
+
+// transformedPathBounds returns the bounds of the current path in page space
+func (p *ContentStreamParser) transformedPathBounds() BoundingBox {
+	minX, minY, maxX, maxY := p.getPathBounds()
+	return p.transformedBounds(minX, minY, maxX, maxY)
+}
+
+// transformedBounds transforms a box by the CTM and returns its bounds
+func (p *ContentStreamParser) transformedBounds(minX, minY, maxX, maxY float64) BoundingBox {
+	x0, y0 := p.transformPoint(minX, minY)
+	x1, y1 := p.transformPoint(maxX, minY)
+	x2, y2 := p.transformPoint(maxX, maxY)
+	x3, y3 := p.transformPoint(minX, maxY)
+	
+	return BoundingBox{
+		X0: min(min(x0, x1), min(x2, x3)),
+		Y0: min(min(y0, y1), min(y2, y3)),
+		X1: max(max(x0, x1), max(x2, x3)),
+		Y1: max(max(y0, y1), max(y2, y3)),
+	}
+}
+
+// paintShading handles the sh operator, which fills the clipping region
+// with a shading rather than painting a path
+func (p *ContentStreamParser) paintShading(operands []string) {
+	if len(operands) < 1 {
+		return
+	}
+	name := strings.TrimPrefix(operands[0], "/")
+	
+	shading := ShadingObject{Name: name}
+	shadingDict := p.lookupResource("Shading", name)
+	if shadingDict != nil {
+		if shadingType := shadingDict.IntEntry("ShadingType"); shadingType != nil {
+			shading.ShadingType = *shadingType
+		}
+	}
+	
+	// The painted area is the clipping region, limited by the shading's
+	// own BBox when it has one
+	var bbox *BoundingBox
+	if p.graphicsState.ClipBBox != nil {
+		clip := *p.graphicsState.ClipBBox
+		bbox = &clip
+	}
+	if shadingDict != nil {
+		if arr := shadingDict.ArrayEntry("BBox"); len(arr) == 4 {
+			box := p.transformedBounds(numberValue(arr[0]), numberValue(arr[1]), numberValue(arr[2]), numberValue(arr[3]))
+			if bbox != nil {
+				box = BoundingBox{
+					X0: max(box.X0, bbox.X0),
+					Y0: max(box.Y0, bbox.Y0),
+					X1: min(box.X1, bbox.X1),
+					Y1: min(box.Y1, bbox.Y1),
+				}
+			}
+			bbox = &box
+		}
+	}
+	if bbox == nil {
+		// Unclipped shadings cover the whole page
+		if arr := p.pageDict.ArrayEntry("MediaBox"); len(arr) == 4 {
+			bbox = &BoundingBox{
+				X0: numberValue(arr[0]),
+				Y0: numberValue(arr[1]),
+				X1: numberValue(arr[2]),
+				Y1: numberValue(arr[3]),
+			}
+		}
+	}
+	if bbox != nil {
+		shading.X0, shading.Y0, shading.X1, shading.Y1 = bbox.X0, bbox.Y0, bbox.X1, bbox.Y1
+	}
+	
+	p.objects.Shadings = append(p.objects.Shadings, shading)
+}
+
+// paintXObject records an image XObject painted into the unit square of the
+// CTM, or parses the content of a form XObject
+func (p *ContentStreamParser) paintXObject(operands []string) {
+	if len(operands) < 1 {
+		return
+	}
+	name := strings.TrimPrefix(operands[0], "/")
+	
+	xobject := p.lookupResource("XObject", name)
+	if xobject == nil {
+		return
+	}
+	if subtype := xobject.Subtype(); subtype != nil && *subtype == "Form" {
+		p.paintForm(name)
+		return
+	}
+	if subtype := xobject.Subtype(); subtype == nil || *subtype != "Image" {
+		return
+	}
+	
+	bbox := p.transformedBounds(0, 0, 1, 1)
+	image := ImageObject{
+		X0:   bbox.X0,
+		Y0:   bbox.Y0,
+		X1:   bbox.X1,
+		Y1:   bbox.Y1,
+		Name: name,
+	}
+	if width := xobject.IntEntry("Width"); width != nil {
+		image.Width = *width
+	}
+	if height := xobject.IntEntry("Height"); height != nil {
+		image.Height = *height
+	}
+	if bpc := xobject.IntEntry("BitsPerComponent"); bpc != nil {
+		image.BitsPerComponent = *bpc
+	}
+	if colorSpace := xobject.NameEntry("ColorSpace"); colorSpace != nil {
+		image.ColorSpace = *colorSpace
+	}
+	
+	p.objects.Images = append(p.objects.Images, image)
+}
+
+// maxFormDepth bounds the nesting of form XObjects, which also stops forms
+// that paint themselves
+const maxFormDepth = 8
+
+// paintForm parses the content of a form XObject painted with Do
+func (p *ContentStreamParser) paintForm(name string) {
+	if p.formDepth >= maxFormDepth {
+		return
+	}
+	if stream := p.lookupStream("XObject", name); stream != nil {
+		p.paintFormStream(stream)
+	}
+}
+
+// paintFormStream parses the content of a form XObject in place, with the
+// form's matrix composed with the CTM, its bounding box clipping and its own
+// resources, if it has any, in effect
+func (p *ContentStreamParser) paintFormStream(stream *types.StreamDict) {
+	// The form is painted as if enclosed in q and Q, and leaves the text
+	// state, resources and path of the enclosing stream as they were
+	depth := len(p.stateStack)
+	p.saveGraphicsState()
+	textState, textMatrix, lineMatrix := *p.textState, p.textMatrix, p.lineMatrix
+	resources, fonts := p.resources, p.fonts
+	path, clipPending := p.currentPath, p.clipPending
+	patternSpace := p.patternSpace
+	p.formDepth++
+	p.outerResources = append(p.outerResources, resources)
+	defer func() {
+		p.formDepth--
+		p.outerResources = p.outerResources[:len(p.outerResources)-1]
+		p.graphicsState = p.stateStack[depth]
+		p.stateStack = p.stateStack[:depth]
+		*p.textState, p.textMatrix, p.lineMatrix = textState, textMatrix, lineMatrix
+		p.resources, p.fonts = resources, fonts
+		p.currentPath, p.clipPending = path, clipPending
+		p.patternSpace = patternSpace
+	}()
+	
+	if m := stream.Dict.ArrayEntry("Matrix"); len(m) == 6 {
+		matrix := Matrix{
+			A: numberValue(m[0]),
+			B: numberValue(m[1]),
+			C: numberValue(m[2]),
+			D: numberValue(m[3]),
+			E: numberValue(m[4]),
+			F: numberValue(m[5]),
+		}
+		p.graphicsState.CTM = MultiplyMatrix(matrix, p.graphicsState.CTM)
+	}
+	p.patternSpace = p.graphicsState.CTM
+	if b := stream.Dict.ArrayEntry("BBox"); len(b) == 4 {
+		x0, y0, x1, y1 := numberValue(b[0]), numberValue(b[1]), numberValue(b[2]), numberValue(b[3])
+		clip := p.transformedBounds(min(x0, x1), min(y0, y1), max(x0, x1), max(y0, y1))
+		if current := p.graphicsState.ClipBBox; current != nil {
+			clip = BoundingBox{
+				X0: max(clip.X0, current.X0),
+				Y0: max(clip.Y0, current.Y0),
+				X1: min(clip.X1, current.X1),
+				Y1: min(clip.Y1, current.Y1),
+			}
+		}
+		p.graphicsState.ClipBBox = &clip
+	}
+	if formResources := p.dereferenceDict(stream.Dict["Resources"]); formResources != nil {
+		p.setResources(formResources)
+		p.applyFontOverrides(p.fontOverrides)
+	}
+	p.currentPath, p.clipPending = nil, false
+	
+	p.parseOperators(stream.Content)
+}
+
+// Annotation flags that keep an annotation off the page
+const (
+	annotationHidden = 1 << 1
+	annotationNoView = 1 << 5
+)
+
+// paintAnnotations parses the normal appearances of the page's visible
+// annotations after its content, each a form mapped onto the annotation's
+// rectangle. Appearances without resources use the form's default
+// resources (/DR), then the page's.
+func (p *ContentStreamParser) paintAnnotations() {
+	annots, _ := p.resolveObject(p.pageDict["Annots"]).(types.Array)
+	if len(annots) == 0 {
+		return
+	}
+	var defaults types.Dict
+	if p.ctx != nil {
+		defaults = p.dereferenceDict(p.dereferenceDict(p.ctx.RootDict["AcroForm"])["DR"])
+	}
+	resources, fonts := p.resources, p.fonts
+	for _, annot := range annots {
+		if p.truncated {
+			break
+		}
+		dict := p.dereferenceDict(annot)
+		if flags := dict.IntEntry("F"); flags != nil && *flags&(annotationHidden|annotationNoView) != 0 {
+			continue
+		}
+		appearance := p.dereferenceDict(dict["AP"])["N"]
+		if state := dict.NameEntry("AS"); state != nil {
+			if states := p.dereferenceDict(appearance); states.Type() == nil && states["BBox"] == nil {
+				appearance = states[*state]
+			}
+		}
+		stream := p.decodedStream(appearance)
+		rect := p.numbers(dict["Rect"])
+		if stream == nil || len(rect) != 4 {
+			continue
+		}
+		
+		// The form's bounding box, transformed by its matrix, is fitted
+		// to the rectangle
+		p.graphicsState.CTM = IdentityMatrix()
+		if m := stream.Dict.ArrayEntry("Matrix"); len(m) == 6 {
+			p.graphicsState.CTM = Matrix{A: numberValue(m[0]), B: numberValue(m[1]), C: numberValue(m[2]), D: numberValue(m[3]), E: numberValue(m[4]), F: numberValue(m[5])}
+		}
+		b := stream.Dict.ArrayEntry("BBox")
+		if len(b) != 4 {
+			continue
+		}
+		box := p.transformedBounds(min(numberValue(b[0]), numberValue(b[2])), min(numberValue(b[1]), numberValue(b[3])), max(numberValue(b[0]), numberValue(b[2])), max(numberValue(b[1]), numberValue(b[3])))
+		if box.Width() == 0 || box.Height() == 0 {
+			continue
+		}
+		sx := (max(rect[0], rect[2]) - min(rect[0], rect[2])) / box.Width()
+		sy := (max(rect[1], rect[3]) - min(rect[1], rect[3])) / box.Height()
+		p.graphicsState = &GraphicsState{
+			CTM:         Matrix{A: sx, D: sy, E: min(rect[0], rect[2]) - box.X0*sx, F: min(rect[1], rect[3]) - box.Y0*sy},
+			LineWidth:   1.0,
+			MiterLimit:  10.0,
+			StrokeColor: PDFColor{ColorSpace: "Gray"},
+			FillColor:   PDFColor{ColorSpace: "Gray"},
+		}
+		*p.textState = TextState{FontSize: 12, Scale: 100}
+		p.textMatrix, p.lineMatrix = IdentityMatrix(), IdentityMatrix()
+		p.resources, p.fonts = resources, fonts
+		if stream.Dict["Resources"] == nil && defaults != nil {
+			p.setResources(defaults)
+			p.applyFontOverrides(p.fontOverrides)
+		}
+		p.paintFormStream(stream)
+	}
+	p.resources, p.fonts = resources, fonts
+}
+
+// numbers resolves an array of numbers
+func (p *ContentStreamParser) numbers(obj types.Object) []float64 {
+	array, _ := p.resolveObject(obj).(types.Array)
+	values := make([]float64, len(array))
+	for i, value := range array {
+		values[i] = numberValue(p.resolveObject(value))
+	}
+	return values
+}
+
+// createPatternShading records a path filled with a shading pattern
+func (p *ContentStreamParser) createPatternShading(name string) {
+	shading := ShadingObject{Name: name, Pattern: true}
+	if patternDict := p.lookupResource("Pattern", name); patternDict != nil {
+		if shadingDict := p.dereferenceDict(patternDict["Shading"]); shadingDict != nil {
+			if shadingType := shadingDict.IntEntry("ShadingType"); shadingType != nil {
+				shading.ShadingType = *shadingType
+			}
+		}
+	}
+	
+	bbox := p.transformedPathBounds()
+	if clip := p.graphicsState.ClipBBox; clip != nil {
+		bbox = BoundingBox{
+			X0: max(bbox.X0, clip.X0),
+			Y0: max(bbox.Y0, clip.Y0),
+			X1: min(bbox.X1, clip.X1),
+			Y1: min(bbox.Y1, clip.Y1),
+		}
+	}
+	shading.X0, shading.Y0, shading.X1, shading.Y1 = bbox.X0, bbox.Y0, bbox.X1, bbox.Y1
+	
+	p.objects.Shadings = append(p.objects.Shadings, shading)
+}
+
+// isShadingPattern checks if a pattern resource is a shading pattern.
+// Patterns that cannot be resolved are assumed to be shadings, since they
+// are never a plain solid fill.
+func (p *ContentStreamParser) isShadingPattern(name string) bool {
+	patternDict := p.lookupResource("Pattern", name)
+	if patternDict == nil {
+		return true
+	}
+	patternType := patternDict.IntEntry("PatternType")
+	return patternType == nil || *patternType == 2
+}
+
+// maxPatternDepth bounds the nesting of pattern cells parsed for their color
+const maxPatternDepth = 4
+
+// patternColor resolves the color painted by a pattern to a representative
+// solid color: the components given with an uncolored tiling pattern, or the
+// area-weighted average fill of a colored tiling pattern's cell. Shading
+// patterns, and cells without filled rectangles, stay unresolved.
+func (p *ContentStreamParser) patternColor(name string, components []string) PDFColor {
+	unresolved := PDFColor{ColorSpace: "Pattern", Pattern: name, Unresolved: true}
+	pattern := p.lookupResource("Pattern", name)
+	if pattern == nil {
+		return unresolved
+	}
+	if patternType := pattern.IntEntry("PatternType"); patternType == nil || *patternType != 1 {
+		return unresolved
+	}
+	
+	if paintType := pattern.IntEntry("PaintType"); paintType != nil && *paintType == 2 {
+		color, ok := solidColor(components)
+		if !ok {
+			return unresolved
+		}
+		color.ColorSpace, color.Pattern = "Pattern", name
+		return color
+	}
+	
+	stream := p.lookupStream("Pattern", name)
+	if stream == nil || p.patternDepth >= maxPatternDepth {
+		return unresolved
+	}
+	
+	var r, g, b, area float64
+	for _, rect := range p.patternCell(name, stream).Rects {
+		if !rect.NonStroking || rect.FillColor.A == 0 {
+			continue
+		}
+		a := (rect.X1 - rect.X0) * (rect.Y1 - rect.Y0)
+		r += float64(rect.FillColor.R) / 255 * a
+		g += float64(rect.FillColor.G) / 255 * a
+		b += float64(rect.FillColor.B) / 255 * a
+		area += a
+	}
+	if area == 0 {
+		return unresolved
+	}
+	return PDFColor{R: r / area, G: g / area, B: b / area, ColorSpace: "Pattern", Pattern: name}
+}
+
+// patternCell parses the content of the named tiling pattern's cell, in
+// pattern space, stopping at the page's object limit. Each pattern object's
+// cell is parsed once, however often the pattern is set or filled with.
+func (p *ContentStreamParser) patternCell(name string, stream *types.StreamDict) Objects {
+	number := objectNumber(p.resourceEntry("Pattern", name))
+	if objects, ok := p.patternCells[number]; ok && number != 0 {
+		return objects
+	}
+
+	cell := NewContentStreamParser(p.ctx, types.Dict{})
+	cell.patternDepth = p.patternDepth + 1
+	cell.maxObjects, cell.maxStreamSize = p.maxObjects, p.maxStreamSize
+	cell.repairUnicode, cell.fontCache = p.repairUnicode, p.fontCache
+	if resources := p.dereferenceDict(stream.Dict["Resources"]); resources != nil {
+		cell.setResources(resources)
+	}
+	objects := cell.Parse(stream.Content)
+	if number != 0 {
+		if p.patternCells == nil {
+			p.patternCells = make(map[int]Objects)
+		}
+		p.patternCells[number] = objects
+	}
+	return objects
+}
+
+// maxPatternTiles bounds the tiles of a pattern that its cell's text is
+// placed on
+const maxPatternTiles = 256
+
+// paintPatternText places the text of a tiling pattern's cell on each tile
+// overlapping the path being filled, keeping the characters whose centers
+// fall within the path's bounds
+func (p *ContentStreamParser) paintPatternText(name string) {
+	pattern := p.lookupResource("Pattern", name)
+	if pattern == nil || p.patternDepth >= maxPatternDepth {
+		return
+	}
+	if patternType := pattern.IntEntry("PatternType"); patternType == nil || *patternType != 1 {
+		return
+	}
+	stream := p.lookupStream("Pattern", name)
+	if stream == nil {
+		return
+	}
+	xStep := abs(numberValue(p.resolveObject(stream.Dict["XStep"])))
+	yStep := abs(numberValue(p.resolveObject(stream.Dict["YStep"])))
+	cellBox := p.numbers(stream.Dict["BBox"])
+	if xStep == 0 || yStep == 0 || len(cellBox) != 4 {
+		return
+	}
+	chars := p.patternCell(name, stream).Chars
+	if len(chars) == 0 {
+		return
+	}
+	
+	// Pattern space is that of the page or form the pattern is used in
+	space := p.patternSpace
+	if m := p.numbers(stream.Dict["Matrix"]); len(m) == 6 {
+		space = MultiplyMatrix(Matrix{A: m[0], B: m[1], C: m[2], D: m[3], E: m[4], F: m[5]}, space)
+	}
+	inverse, ok := invertMatrix(space)
+	if !ok {
+		return
+	}
+	area := p.transformedPathBounds()
+	if clip := p.graphicsState.ClipBBox; clip != nil {
+		area = BoundingBox{
+			X0: max(area.X0, clip.X0),
+			Y0: max(area.Y0, clip.Y0),
+			X1: min(area.X1, clip.X1),
+			Y1: min(area.Y1, clip.Y1),
+		}
+	}
+	
+	// The tiles overlapping the area, found in pattern space
+	inPattern := transformBox(area, inverse)
+	i0 := math.Ceil((inPattern.X0 - max(cellBox[0], cellBox[2])) / xStep)
+	i1 := math.Floor((inPattern.X1 - min(cellBox[0], cellBox[2])) / xStep)
+	j0 := math.Ceil((inPattern.Y0 - max(cellBox[1], cellBox[3])) / yStep)
+	j1 := math.Floor((inPattern.Y1 - min(cellBox[1], cellBox[3])) / yStep)
+	tiles := 0
+	for j := j0; j <= j1; j++ {
+		for i := i0; i <= i1; i++ {
+			if tiles++; tiles > maxPatternTiles {
+				return
+			}
+			tile := MultiplyMatrix(TranslationMatrix(i*xStep, j*yStep), space)
+			for _, char := range chars {
+				char = transformChar(char, tile)
+				x, y := (char.X0+char.X1)/2, (char.Y0+char.Y1)/2
+				if x < area.X0 || x > area.X1 || y < area.Y0 || y > area.Y1 {
+					continue
+				}
+				char.lang, char.mcid = p.lang, p.markedContentID
+				char.order = p.paintOrder()
+				p.objects.Chars = append(p.objects.Chars, char)
+			}
+			if limitObjects(&p.objects, p.maxObjects) {
+				p.truncated = true
+				return
+			}
+		}
+	}
+}
+
+// invertMatrix returns the inverse of a matrix, reporting false for one
+// that flattens space
+func invertMatrix(m Matrix) (Matrix, bool) {
+	det := m.A*m.D - m.B*m.C
+	if det == 0 {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// transformBox returns the bounds of a box mapped through a matrix
+func transformBox(box BoundingBox, m Matrix) BoundingBox {
+	xs := [4]float64{box.X0, box.X1, box.X1, box.X0}
+	ys := [4]float64{box.Y0, box.Y0, box.Y1, box.Y1}
+	out := BoundingBox{X0: math.Inf(1), Y0: math.Inf(1), X1: math.Inf(-1), Y1: math.Inf(-1)}
+	for i := range xs {
+		x := m.A*xs[i] + m.C*ys[i] + m.E
+		y := m.B*xs[i] + m.D*ys[i] + m.F
+		out.X0, out.Y0 = min(out.X0, x), min(out.Y0, y)
+		out.X1, out.Y1 = max(out.X1, x), max(out.Y1, y)
+	}
+	return out
+}
+
+// transformChar maps a character parsed in another space, such as a
+// pattern cell's, through a matrix
+func transformChar(char CharObject, m Matrix) CharObject {
+	box := transformBox(char.GetBBox(), m)
+	char.X0, char.Y0, char.X1, char.Y1 = box.X0, box.Y0, box.X1, box.Y1
+	char.Width, char.Height = box.Width(), box.Height()
+	scale := math.Hypot(m.C, m.D)
+	char.FontSize *= scale
+	char.descent *= scale
+	char.rise *= scale
+	trm := MultiplyMatrix(Matrix(char.Matrix), m)
+	char.Matrix = TransformMatrix(trm)
+	return char
+}
+
+// solidColor converts gray, RGB or CMYK components, told apart by their
+// count, to a color
+func solidColor(components []string) (PDFColor, bool) {
+	v := make([]float64, len(components))
+	for i, component := range components {
+		v[i] = parseFloat(component)
+	}
+	return solidColorOf(v)
+}
+
+// solidColorOf converts gray, RGB or CMYK component values, told apart by
+// their count, to a color
+func solidColorOf(v []float64) (PDFColor, bool) {
+	switch len(v) {
+	case 1:
+		return PDFColor{R: v[0], G: v[0], B: v[0], ColorSpace: "Gray"}, true
+	case 3:
+		return PDFColor{R: v[0], G: v[1], B: v[2], ColorSpace: "RGB"}, true
+	case 4:
+		return PDFColor{
+			R:          (1 - v[0]) * (1 - v[3]),
+			G:          (1 - v[1]) * (1 - v[3]),
+			B:          (1 - v[2]) * (1 - v[3]),
+			ColorSpace: "CMYK",
+		}, true
+	}
+	return PDFColor{}, false
+}
+
+// lookupStream finds a named stream in a resource category, decoded
+func (p *ContentStreamParser) lookupStream(category, name string) *types.StreamDict {
+	return p.decodedStream(p.resourceEntry(category, name))
+}
+
+// decodedStream resolves an object to a stream and decodes it, returning
+// nil for objects that are not streams
+func (p *ContentStreamParser) decodedStream(obj types.Object) *types.StreamDict {
+	var stream *types.StreamDict
+	switch s := p.resolveObject(obj).(type) {
+	case types.StreamDict:
+		stream = &s
+	case *types.StreamDict:
+		stream = s
+	default:
+		return nil
+	}
+	if _, err := decodeStream(stream, p.maxStreamSize); err != nil {
+		return nil
+	}
+	return stream
+}
+
+// lookupResource finds a named entry in a resource category such as Shading or Pattern
+func (p *ContentStreamParser) lookupResource(category, name string) types.Dict {
+	return p.dereferenceDict(p.resourceEntry(category, name))
+}
+
+// resourceEntry finds a named entry in a resource category of the current
+// resources. Inside a form XObject, entries missing from the form's
+// resources are looked up in those of the enclosing streams, innermost
+// first, as forms written before PDF 1.2 rely on.
+func (p *ContentStreamParser) resourceEntry(category, name string) types.Object {
+	if entry := p.dereferenceDict(p.resources[category])[name]; entry != nil {
+		return entry
+	}
+	for i := len(p.outerResources) - 1; i >= 0; i-- {
+		if entry := p.dereferenceDict(p.outerResources[i][category])[name]; entry != nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+// resolveObject follows indirect references. Without a context they
+// resolve to nil.
+func (p *ContentStreamParser) resolveObject(obj types.Object) types.Object {
+	if ref, ok := obj.(*types.IndirectRef); ok && ref != nil {
+		obj = *ref
+	}
+	if ref, ok := obj.(types.IndirectRef); ok {
+		if p.ctx == nil {
+			return nil
+		}
+		resolved, err := p.ctx.Dereference(ref)
+		if err != nil {
+			return nil
+		}
+		return resolved
+	}
+	return obj
+}
+
+// dereferenceDict resolves an object to a dictionary, following indirect
+// references. Stream objects resolve to their stream dictionary.
+func (p *ContentStreamParser) dereferenceDict(obj types.Object) types.Dict {
+	if ref, ok := obj.(*types.IndirectRef); ok && ref != nil {
+		obj = *ref
+	}
+	if ref, ok := obj.(types.IndirectRef); ok {
+		if p.ctx == nil {
+			return nil
+		}
+		resolved, err := p.ctx.Dereference(ref)
+		if err != nil {
+			return nil
+		}
+		obj = resolved
+	}
+	
+	switch o := obj.(type) {
+	case types.Dict:
+		return o
+	case types.StreamDict:
+		return o.Dict
+	case *types.StreamDict:
+		return o.Dict
+	}
+	return nil
+}
+
+// numberValue converts a PDF number object to float64
+func numberValue(obj types.Object) float64 {
+	switch v := obj.(type) {
+	case types.Integer:
+		return float64(v)
+	case types.Float:
+		return float64(v)
+	}
+	return 0
+}
+
 func (p *ContentStreamParser) setLineWidth(operands []string) {
 	if len(operands) < 1 {
 		return
@@ -1051,14 +2265,28 @@ func (p *ContentStreamParser) setMiterLimit(operands []string) {
 	p.graphicsState.MiterLimit = parseFloat(operands[0])
 }
 
+// setDashPattern sets the dash array and phase of [array] phase d. An
+// empty array, or one of only zeros, draws solid lines.
 func (p *ContentStreamParser) setDashPattern(operands []string) {
-	// Format: [array] phase d
-	// For now, just store whether dashed or not
-	if len(operands) >= 2 {
-		// Parse array and phase
-		// Simplified: just check if we have a dash pattern
-		p.graphicsState.DashPattern = []float64{1} // Placeholder
+	if len(operands) < 2 {
+		return
+	}
+	array := strings.Join(operands[:len(operands)-1], " ")
+	if !strings.HasPrefix(array, "[") || !strings.HasSuffix(array, "]") {
+		return
+	}
+	var pattern []float64
+	dashed := false
+	for _, field := range strings.Fields(strings.Trim(array, "[]")) {
+		length := parseFloat(field)
+		pattern = append(pattern, length)
+		dashed = dashed || length > 0
 	}
+	if !dashed {
+		pattern = nil
+	}
+	p.graphicsState.DashPattern = pattern
+	p.graphicsState.DashPhase = parseFloat(operands[len(operands)-1])
 }
 
 // Color operators
@@ -1160,6 +2388,13 @@ func (p *ContentStreamParser) setFillColorSpace(operands []string) {
 }
 
 func (p *ContentStreamParser) setStrokeColor(operands []string) {
+	// A trailing name selects a pattern, e.g. "/P0 SCN"
+	if len(operands) > 0 && strings.HasPrefix(operands[len(operands)-1], "/") {
+		name := strings.TrimPrefix(operands[len(operands)-1], "/")
+		p.graphicsState.StrokeColor = p.patternColor(name, operands[:len(operands)-1])
+		return
+	}
+	
 	// Generic color setting based on current color space
 	// Simplified: treat as grayscale or RGB
 	if len(operands) == 1 {
@@ -1170,6 +2405,13 @@ func (p *ContentStreamParser) setStrokeColor(operands []string) {
 }
 
 func (p *ContentStreamParser) setFillColor(operands []string) {
+	// A trailing name selects a pattern, e.g. "/P0 scn"
+	if len(operands) > 0 && strings.HasPrefix(operands[len(operands)-1], "/") {
+		name := strings.TrimPrefix(operands[len(operands)-1], "/")
+		p.graphicsState.FillColor = p.patternColor(name, operands[:len(operands)-1])
+		return
+	}
+	
 	// Generic color setting based on current color space
 	// Simplified: treat as grayscale or RGB
 	if len(operands) == 1 {
@@ -1181,32 +2423,28 @@ func (p *ContentStreamParser) setFillColor(operands []string) {
 
 // Helper functions
 
-func (p *ContentStreamParser) addTextChars(text string) {
-	// fmt.Printf("[DEBUG-TEXT] addTextChars called with text: %q, Font: %v\n", text, p.textState.Font)
-	if text == "" {
+func (p *ContentStreamParser) addTextChars(glyphs []glyph) {
+	if len(glyphs) == 0 {
 		// fmt.Println("[DEBUG-TEXT] Empty text, skipping")
 		return
 	}
-	if p.textState.Font == nil {
-		// fmt.Println("[DEBUG-TEXT] Font is nil, skipping")
-		return
-	}
+	p.useFallbackFontIfUnset()
+	fallback := p.textState.Font == p.fallbackFont
 	
 	// Process each character individually for better positioning
-	for _, runeValue := range text {
-		charStr := string(runeValue)
+	for _, g := range glyphs {
+		charStr := g.text
 		
-		// Calculate character width (simplified - should use font metrics)
-		// For now use a better approximation based on character type
-		charWidth := p.getCharWidth(charStr) * p.textState.FontSize
+		// Estimate the width from the font's class for fonts without metrics
+		charWidth := p.textState.Font.estimatedWidth(charStr) * p.textState.FontSize
+		if g.hasWidth {
+			charWidth = g.width / 1000 * p.textState.FontSize
+		}
 		
 		// Transform coordinates - apply both text matrix and CTM
-		textX, textY := p.textMatrix.E, p.textMatrix.F
-		
-		// Apply CTM transformation to get actual page coordinates
-		ctm := p.graphicsState.CTM
-		x := ctm.A*textX + ctm.C*textY + ctm.E
-		y := ctm.B*textX + ctm.D*textY + ctm.F
+		trm := MultiplyMatrix(p.textMatrix, p.graphicsState.CTM)
+		x, y := trm.E, trm.F
+		bottom, top := p.textState.Font.verticalExtent()
 		
 		// Create character object
 		char := CharObject{
@@ -1214,19 +2452,56 @@ func (p *ContentStreamParser) addTextChars(text string) {
 			Font:     p.textState.Font.Name,
 			FontSize: p.textState.FontSize,
 			X0:       x,
-			Y0:       y,
+			Y0:       y + bottom*p.textState.FontSize,
 			X1:       x + charWidth,
-			Y1:       y + p.textState.FontSize,
+			Y1:       y + top*p.textState.FontSize,
 			Width:    charWidth,
-			Height:   p.textState.FontSize,
+			Height:   (top - bottom) * p.textState.FontSize,
+			Color:    p.convertPDFColorToColor(p.graphicsState.FillColor),
+			Matrix:   TransformMatrix{A: trm.A, B: trm.B, C: trm.C, D: trm.D, E: trm.E, F: trm.F},
+			lang:     p.lang,
+			unmapped: g.unmapped,
+			mcid:     p.markedContentID,
+			rise:     p.textState.Rise * trm.D,
+			descent:  bottom * p.textState.FontSize,
+			
+			spaceWidth: p.textState.Font.SpaceWidth,
+			
+			FontFallback: fallback,
+			RenderMode:   p.textState.RenderMode,
+			Outlined:     strokesGlyphs(p.textState.RenderMode),
+		}
+		// Glyphs that are only stroked take the stroke color
+		if mode := p.textState.RenderMode; mode == 1 || mode == 5 {
+			char.Color = p.convertPDFColorToColor(p.graphicsState.StrokeColor)
+		}
+		
+		// Rotated or skewed text: take the bbox of the glyph box mapped
+		// through the text rendering matrix
+		if trm.B != 0 || trm.C != 0 || trm.A < 0 {
+			alongX, alongY := trm.A*charWidth, trm.B*charWidth
+			upX, upY := trm.C*p.textState.FontSize, trm.D*p.textState.FontSize
+			char.X0 = x + min(0, alongX) + min(upX*bottom, upX*top)
+			char.X1 = x + max(0, alongX) + max(upX*bottom, upX*top)
+			char.Y0 = y + min(0, alongY) + min(upY*bottom, upY*top)
+			char.Y1 = y + max(0, alongY) + max(upY*bottom, upY*top)
 		}
 		
-		p.objects.Chars = append(p.objects.Chars, char)
+		// A zero-width space glyph draws nothing, but the next character
+		// still starts a new word
+		if g.space && g.hasWidth && g.width == 0 {
+			p.pendingSpace = true
+		} else {
+			char.followsSpace = p.pendingSpace
+			p.pendingSpace = false
+			char.order = p.paintOrder()
+			p.objects.Chars = append(p.objects.Chars, char)
+		}
 		
 		// Update text matrix for next character
-		// Include character spacing and word spacing if it's a space
+		// Include character spacing and word spacing for single-byte code 32
 		displacement := charWidth
-		if charStr == " " {
+		if g.wordSpace {
 			displacement += p.textState.WordSpace
 		}
 		displacement += p.textState.CharSpace
@@ -1240,19 +2515,30 @@ func (p *ContentStreamParser) addTextChars(text string) {
 	}
 }
 
-// getCharWidth returns an approximate width factor for a character
-func (p *ContentStreamParser) getCharWidth(char string) float64 {
-	// This is a simplified approximation
-	// In reality, we should use font metrics from the font dictionary
-	switch char {
-	case " ":
-		return 0.25
-	case "i", "l", "I", "!", ".", ",", ";", ":", "'", "\"":
-		return 0.3
-	case "m", "M", "W", "w":
-		return 0.8
-	default:
-		return 0.5
+// defaultFallbackFont is the standard font whose metrics apply to text shown
+// without a usable font
+const defaultFallbackFont = "Helvetica"
+
+// newFallbackFont returns the font used when no valid Tf has been seen,
+// with the AFM metrics of baseFont when it names a standard font
+func newFallbackFont(baseFont string) *FontInfo {
+	font := &FontInfo{
+		Name:       baseFont,
+		BaseFont:   baseFont,
+		FontMatrix: Matrix{A: 0.001, B: 0, C: 0, D: 0.001, E: 0, F: 0},
+	}
+	if standard, ok := standardFontName(baseFont); ok {
+		font.StandardFont = standard
+	}
+	font.SpaceWidth = font.spaceGlyphWidth()
+	return font
+}
+
+// useFallbackFontIfUnset sets the fallback font when text is shown before
+// any Tf, or after a Tf naming a font that could not be loaded
+func (p *ContentStreamParser) useFallbackFontIfUnset() {
+	if p.textState.Font == nil {
+		p.textState.Font = p.fallbackFont
 	}
 }
 
@@ -1296,6 +2582,9 @@ func (p *ContentStreamParser) createLineFromPath() {
 					Y1:          endYTransformed,
 					Width:       p.graphicsState.LineWidth,
 					StrokeColor: strokeColor,
+					DashPattern: p.graphicsState.DashPattern,
+					LineCap:     p.graphicsState.LineCap,
+					LineJoin:    p.graphicsState.LineJoin,
 				}
 				
 				p.objects.Lines = append(p.objects.Lines, line)
@@ -1317,6 +2606,9 @@ func (p *ContentStreamParser) createLineFromPath() {
 					Y1:          endY,
 					Width:       p.graphicsState.LineWidth,
 					StrokeColor: strokeColor,
+					DashPattern: p.graphicsState.DashPattern,
+					LineCap:     p.graphicsState.LineCap,
+					LineJoin:    p.graphicsState.LineJoin,
 				}
 				
 				p.objects.Lines = append(p.objects.Lines, line)
@@ -1366,6 +2658,12 @@ func (p *ContentStreamParser) transformPoint(x, y float64) (float64, float64) {
 
 // convertPDFColorToColor converts PDFColor to Color type
 func (p *ContentStreamParser) convertPDFColorToColor(pdfColor PDFColor) Color {
+	// Patterns without a solid color come out fully transparent rather
+	// than black
+	if pdfColor.Unresolved {
+		return Color{}
+	}
+	
 	// Convert float RGB values (0-1) to uint8 (0-255)
 	return Color{
 		R: uint8(pdfColor.R * 255),
@@ -1375,7 +2673,10 @@ func (p *ContentStreamParser) convertPDFColorToColor(pdfColor PDFColor) Color {
 	}
 }
 
-func (p *ContentStreamParser) extractString(str string) string {
+// extractGlyphs decodes a string operand into the glyphs it shows
+func (p *ContentStreamParser) extractGlyphs(str string) []glyph {
+	// Glyphs are decoded and measured with the font they are shown in
+	p.useFallbackFontIfUnset()
 	if strings.HasPrefix(str, "(") && strings.HasSuffix(str, ")") {
 		// String literal
 		str = strings.TrimPrefix(str, "(")
@@ -1385,11 +2686,7 @@ func (p *ContentStreamParser) extractString(str string) string {
 		str = p.unescapeString(str)
 		
 		// Apply font encoding if available
-		if p.textState.Font != nil {
-			str = p.decodeString(str)
-		}
-		
-		return str
+		return p.decodeGlyphs(str)
 	} else if strings.HasPrefix(str, "<") && strings.HasSuffix(str, ">") {
 		// Hex string
 		str = strings.TrimPrefix(str, "<")
@@ -1399,13 +2696,9 @@ func (p *ContentStreamParser) extractString(str string) string {
 		decoded := p.decodeHexString(str)
 		
 		// Apply font encoding if available
-		if p.textState.Font != nil {
-			decoded = p.decodeString(decoded)
-		}
-		
-		return decoded
+		return p.decodeGlyphs(decoded)
 	}
-	return str
+	return p.decodeGlyphs(str)
 }
 
 // unescapeString handles PDF escape sequences
@@ -1482,67 +2775,197 @@ func (p *ContentStreamParser) decodeHexString(hexStr string) string {
 	return result.String()
 }
 
-// decodeString applies font encoding to decode the string
-func (p *ContentStreamParser) decodeString(str string) string {
-	// Check if we have a font with ToUnicode CMap
-	if p.textState.Font != nil && p.textState.Font.ToUnicodeCMap != nil {
-		// Convert string to bytes for CID extraction
-		// Assuming the string is already in binary form (from hex or literal)
-		// We need to process it as 2-byte CIDs
-		
-		var result strings.Builder
-		data := []byte(str)
-		
-		// Process based on encoding type
-		if p.textState.Font.Encoding == "Identity-H" || p.textState.Font.Encoding == "Identity-V" {
-			// Identity encoding - 2-byte CIDs
-			for i := 0; i < len(data); i += 2 {
-				if i+1 >= len(data) {
-					// Odd byte at the end
-					cid := uint16(data[i])
-					if unicode, ok := p.textState.Font.ToUnicodeCMap.MapCIDToUnicode(cid); ok {
-						result.WriteString(unicode)
-					} else {
-						result.WriteByte(data[i])
-					}
-				} else {
-					// Extract 2-byte CID
-					cid := uint16(data[i])<<8 | uint16(data[i+1])
-					
-					if unicode, ok := p.textState.Font.ToUnicodeCMap.MapCIDToUnicode(cid); ok {
-						result.WriteString(unicode)
-					} else {
-						// Try single-byte CIDs as fallback
-						if unicode1, ok := p.textState.Font.ToUnicodeCMap.MapCIDToUnicode(uint16(data[i])); ok {
-							result.WriteString(unicode1)
-						} else {
-							result.WriteByte(data[i])
-						}
-						if unicode2, ok := p.textState.Font.ToUnicodeCMap.MapCIDToUnicode(uint16(data[i+1])); ok {
-							result.WriteString(unicode2)
-						} else {
-							result.WriteByte(data[i+1])
-						}
-					}
-				}
+// glyph is a character decoded from a shown string
+type glyph struct {
+	text      string
+	wordSpace bool    // Single-byte code 32, the only code word spacing applies to
+	width     float64 // Advance in glyph space units, when hasWidth is set
+	hasWidth  bool
+	space     bool    // The font's designated space code, when space glyphs are detected
+	unmapped  bool    // Taken from the raw code bytes, the font having no mapping for it
+}
+
+// spaceCode returns the code of the font's space glyph: the code its ToUnicode
+// CMap maps to a space, or else to a no-break space. Single-byte fonts
+// without such a mapping use code 32.
+func (f *FontInfo) spaceCode(multiByte bool) (uint16, bool) {
+	if f.ToUnicodeCMap != nil {
+		for _, space := range []string{" ", "\u00a0"} {
+			if code, ok := f.ToUnicodeCMap.codeFor(space); ok {
+				return code, true
+			}
+		}
+	}
+	return 32, !multiByte
+}
+
+// isMultiByteFont checks if the current font uses two-byte character codes
+func (p *ContentStreamParser) isMultiByteFont() bool {
+	font := p.textState.Font
+	return font != nil && (font.Encoding == "Identity-H" || font.Encoding == "Identity-V")
+}
+
+// decodeGlyphs applies font encoding to decode the string into glyphs
+func (p *ContentStreamParser) decodeGlyphs(str string) []glyph {
+	var glyphs []glyph
+	multiByte := p.isMultiByteFont()
+	
+	// addCode adds the characters a code decodes to. Word spacing is applied
+	// once, after the last of them.
+	addCode := func(text string, wordSpace bool) {
+		runes := []rune(text)
+		for i, r := range runes {
+			glyphs = append(glyphs, glyph{text: string(r), wordSpace: wordSpace && i == len(runes)-1})
+		}
+	}
+	
+	// addUnmapped adds the raw bytes of a code the font has no mapping for
+	addUnmapped := func(code []byte, wordSpace bool) {
+		start := len(glyphs)
+		addCode(string(code), wordSpace)
+		for i := start; i < len(glyphs); i++ {
+			glyphs[i].unmapped = true
+		}
+	}
+	
+	// addCID adds the characters of a two-byte code, sharing out its width
+	addCID := func(text string, cid uint16) {
+		width, ok := p.textState.Font.cidWidth(cid)
+		runes := []rune(text)
+		for _, r := range runes {
+			glyphs = append(glyphs, glyph{text: string(r), width: width / float64(len(runes)), hasWidth: ok})
+		}
+	}
+	
+	// No ToUnicode CMap, use the string as-is. CID fonts with widths are
+	// still split into codes to position each glyph.
+	font := p.textState.Font
+	if font == nil || (font.ToUnicodeCMap == nil && (!multiByte || font.CIDWidths == nil)) {
+		for i, r := range str {
+			g := glyph{text: string(r), wordSpace: r == ' ' && !multiByte, unmapped: multiByte}
+			if !multiByte {
+				g.width, g.hasWidth = font.codeWidth(str[i])
 			}
-			return result.String()
+			glyphs = append(glyphs, g)
+		}
+		return glyphs
+	}
+	
+	// The font's designated space code is decoded as a space whatever its
+	// ToUnicode mapping, such as a no-break space
+	spaceCode, hasSpaceCode := uint16(0), false
+	if p.detectSpaceGlyphs {
+		spaceCode, hasSpaceCode = font.spaceCode(multiByte)
+	}
+	
+	cmap := font.ToUnicodeCMap
+	mapCode := func(code uint16) (string, bool) {
+		if cmap == nil {
+			return "", false
+		}
+		return cmap.MapCIDToUnicode(code)
+	}
+	data := []byte(str)
+	
+	// addByte adds a single-byte code of a simple font
+	addByte := func(b byte) {
+		start := len(glyphs)
+		if hasSpaceCode && uint16(b) == spaceCode {
+			glyphs = append(glyphs, glyph{text: " ", wordSpace: true, space: true})
+		} else if unicode, ok := cmap.MapCIDToUnicode(uint16(b)); ok {
+			addCode(unicode, b == ' ')
 		} else {
+			addUnmapped([]byte{b}, b == ' ')
+		}
+		
+		// Share the code's advance out among the characters it decodes to
+		if width, ok := font.codeWidth(b); ok {
+			for i := start; i < len(glyphs); i++ {
+				glyphs[i].width, glyphs[i].hasWidth = width/float64(len(glyphs)-start), true
+			}
+		}
+	}
+	
+	if !multiByte {
+		if !cmap.hasMultiByteCodespace() {
 			// Other encodings - try single-byte CIDs
 			for _, b := range data {
-				cid := uint16(b)
-				if unicode, ok := p.textState.Font.ToUnicodeCMap.MapCIDToUnicode(cid); ok {
-					result.WriteString(unicode)
+				addByte(b)
+			}
+			return glyphs
+		}
+		
+		// A simple font whose ToUnicode CMap declares longer codes, whose
+		// lengths its codespace ranges tell. Codes outside them are read as
+		// single bytes.
+		for i := 0; i < len(data); {
+			n := cmap.codeLength(data[i:])
+			if n <= 1 {
+				addByte(data[i])
+				i++
+				continue
+			}
+			code := data[i : i+n]
+			i += n
+			if n == 2 {
+				if unicode, ok := mapCode(uint16(code[0])<<8 | uint16(code[1])); ok {
+					addCode(unicode, false)
+					continue
+				}
+			}
+			for _, b := range code {
+				if unicode, ok := mapCode(uint16(b)); ok {
+					addCode(unicode, false)
 				} else {
-					result.WriteByte(b)
+					addUnmapped([]byte{b}, false)
 				}
 			}
-			return result.String()
 		}
+		return glyphs
 	}
 	
-	// No ToUnicode CMap, return as-is
-	return str
+	// Identity encoding - 2-byte CIDs, never subject to word spacing
+	for i := 0; i < len(data); i += 2 {
+		if i+1 >= len(data) {
+			// Odd byte at the end
+			if unicode, ok := mapCode(uint16(data[i])); ok {
+				addCode(unicode, false)
+			} else {
+				addUnmapped(data[i:i+1], false)
+			}
+			continue
+		}
+		
+		// Extract 2-byte CID
+		cid := uint16(data[i])<<8 | uint16(data[i+1])
+		if hasSpaceCode && cid == spaceCode {
+			width, ok := font.cidWidth(cid)
+			glyphs = append(glyphs, glyph{text: " ", width: width, hasWidth: ok, space: true})
+			continue
+		}
+		if unicode, ok := mapCode(cid); ok {
+			addCID(unicode, cid)
+			continue
+		}
+		
+		// Try single-byte CIDs as fallback
+		text := ""
+		unmapped := false
+		start := len(glyphs)
+		for _, b := range data[i : i+2] {
+			if unicode, ok := mapCode(uint16(b)); ok {
+				text += unicode
+			} else {
+				text += string([]byte{b})
+				unmapped = true
+			}
+		}
+		addCID(text, cid)
+		for j := start; j < len(glyphs); j++ {
+			glyphs[j].unmapped = unmapped
+		}
+	}
+	return glyphs
 }
 
 func (p *ContentStreamParser) parseTextArray(arrayStr string) []string {
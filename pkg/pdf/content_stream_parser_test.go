@@ -0,0 +1,907 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestParseShadingWithoutPhantomRects(t *testing.T) {
+	pageDict := types.Dict{
+		"MediaBox": types.Array{types.Integer(0), types.Integer(0), types.Integer(612), types.Integer(792)},
+		"Resources": types.Dict{
+			"Shading": types.Dict{
+				"Sh0": types.Dict{"ShadingType": types.Integer(2)},
+			},
+			"Pattern": types.Dict{
+				"P0": types.Dict{
+					"PatternType": types.Integer(2),
+					"Shading":     types.Dict{"ShadingType": types.Integer(2)},
+				},
+			},
+		},
+	}
+
+	// An axial shading painted with sh inside a clip, and a rectangle
+	// filled with a shading pattern
+	content := []byte(`
+		q 100 500 200 50 re W n /Sh0 sh Q
+		/Pattern cs /P0 scn 100 300 200 50 re f
+	`)
+
+	objects := NewContentStreamParser(nil, pageDict).Parse(content)
+	if len(objects.Rects) != 0 {
+		t.Errorf("expected no rectangles, got %d", len(objects.Rects))
+	}
+	if len(objects.Shadings) != 2 {
+		t.Fatalf("expected 2 shadings, got %d", len(objects.Shadings))
+	}
+
+	sh := objects.Shadings[0]
+	if sh.ShadingType != 2 || sh.Name != "Sh0" || sh.Pattern {
+		t.Errorf("unexpected sh shading: %+v", sh)
+	}
+	if sh.X0 != 100 || sh.Y0 != 500 || sh.X1 != 300 || sh.Y1 != 550 {
+		t.Errorf("expected sh shading clipped to (100,500)-(300,550), got %+v", sh.GetBBox())
+	}
+
+	pattern := objects.Shadings[1]
+	if pattern.ShadingType != 2 || pattern.Name != "P0" || !pattern.Pattern {
+		t.Errorf("unexpected pattern shading: %+v", pattern)
+	}
+	if pattern.X0 != 100 || pattern.Y0 != 300 || pattern.X1 != 300 || pattern.Y1 != 350 {
+		t.Errorf("expected pattern shading at (100,300)-(300,350), got %+v", pattern.GetBBox())
+	}
+}
+
+func TestParseTextOutsideTextObject(t *testing.T) {
+	// Text shown before any BT and without a Tf, followed by a proper
+	// text object and another stray Tj after ET
+	content := []byte(`
+		q 1 0 0 1 72 720 cm (Hi) Tj Q
+		BT 300 400 Td (Ok) Tj ET
+		(Yo) Tj
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Chars) != 6 {
+		t.Fatalf("expected 6 chars, got %d", len(objects.Chars))
+	}
+
+	expected := []struct {
+		text string
+		x, y float64
+	}{
+		{"H", 72, 720},
+		{"O", 300, 400},
+		{"Y", 0, 0}, // Starts at the origin rather than after "Ok"
+	}
+	for i, want := range expected {
+		char := objects.Chars[i*2]
+		if char.Text != want.text || char.X0 != want.x || char.Y0 != want.y {
+			t.Errorf("expected %q at (%.0f, %.0f), got %q at (%.2f, %.2f)",
+				want.text, want.x, want.y, char.Text, char.X0, char.Y0)
+		}
+		if char.Font == "" || char.FontSize <= 0 {
+			t.Errorf("expected a default font for %q, got font=%q size=%.1f", char.Text, char.Font, char.FontSize)
+		}
+	}
+}
+
+func TestParseCompatibilitySection(t *testing.T) {
+	// Unknown operators inside BX/EX must not leave their operands behind
+	// for the Td that follows
+	content := []byte(`
+		BX
+		BT 7 8 /Foo unknownOp 100 200 Td (Hi) Tj ET
+		EX
+		0 0 d0
+		BT 300 400 Td (Ok) Tj ET
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Chars) != 4 {
+		t.Fatalf("expected 4 chars, got %d", len(objects.Chars))
+	}
+
+	expected := []struct {
+		text string
+		x, y float64
+	}{
+		{"H", 100, 200},
+		{"O", 300, 400},
+	}
+	for i, want := range expected {
+		char := objects.Chars[i*2]
+		if char.Text != want.text || char.X0 != want.x || char.Y0 != want.y {
+			t.Errorf("expected %q at (%.0f, %.0f), got %q at (%.2f, %.2f)",
+				want.text, want.x, want.y, char.Text, char.X0, char.Y0)
+		}
+	}
+}
+
+func TestParseStopsTokenizingAtObjectLimit(t *testing.T) {
+	// Ten rects, then a long tail of operands that are never reached
+	content := []byte(strings.Repeat("0 0 10 10 re f\n", 10) + strings.Repeat("1.5 ", 1<<18))
+
+	var objects Objects
+	allocs := testing.AllocsPerRun(1, func() {
+		parser := NewContentStreamParser(nil, types.Dict{})
+		parser.maxObjects = 5
+		objects = parser.Parse(content)
+	})
+	if len(objects.Rects) != 5 {
+		t.Errorf("expected parsing to stop at 5 rects, got %d", len(objects.Rects))
+	}
+	if allocs > 10000 {
+		t.Errorf("expected the tail after the limit to go untokenized, got %.0f allocations", allocs)
+	}
+}
+
+func TestParseWordSpacingSingleByteOnly(t *testing.T) {
+	cmap := NewToUnicodeCMap()
+	cmap.cidToUnicode[0x0001] = "A"
+	cmap.cidToUnicode[0x0002] = "B"
+	cmap.cidToUnicode[0x0003] = " "
+
+	parser := NewContentStreamParser(nil, types.Dict{})
+	parser.fonts["CID"] = &FontInfo{Name: "CID", Encoding: "Identity-H", ToUnicodeCMap: cmap}
+	parser.fonts["F1"] = &FontInfo{Name: "F1"}
+
+	// Both strings decode to "A B" with 20pt word spacing set
+	content := []byte(`
+		BT /CID 10 Tf 20 Tw 0 100 Td <000100030002> Tj ET
+		BT /F1 10 Tf 20 Tw 0 200 Td (A B) Tj ET
+	`)
+	objects := parser.Parse(content)
+	if len(objects.Chars) != 6 {
+		t.Fatalf("expected 6 chars, got %d", len(objects.Chars))
+	}
+
+	// "A" and the space advance 5 and 2.5 points at 10pt
+	cidB, latinB := objects.Chars[2], objects.Chars[5]
+	if cidB.Text != "B" || cidB.X0 != 7.5 {
+		t.Errorf("expected CID %q at x=7.5 without word spacing, got %q at x=%.2f", "B", cidB.Text, cidB.X0)
+	}
+	if latinB.Text != "B" || latinB.X0 != 27.5 {
+		t.Errorf("expected single-byte %q at x=27.5 with word spacing, got %q at x=%.2f", "B", latinB.Text, latinB.X0)
+	}
+}
+
+func TestParseCIDFontWidths(t *testing.T) {
+	// W uses both forms: "1 [1000 250]" for CIDs 1 and 2, and
+	// "10 12 750" for CIDs 10 to 12. Other CIDs take DW.
+	font := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type0"),
+		"Encoding": types.Name("Identity-H"),
+		"DescendantFonts": types.Array{
+			types.Dict{
+				"Subtype": types.Name("CIDFontType2"),
+				"DW":      types.Integer(500),
+				"W": types.Array{
+					types.Integer(1), types.Array{types.Integer(1000), types.Integer(250)},
+					types.Integer(10), types.Integer(12), types.Integer(750),
+				},
+			},
+		},
+	}
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F0": font}}}
+
+	parser := NewContentStreamParser(nil, pageDict)
+	widths := parser.fonts["F0"].CIDWidths
+	expected := map[uint16]float64{1: 1000, 2: 250, 10: 750, 11: 750, 12: 750}
+	if !reflect.DeepEqual(widths, expected) {
+		t.Errorf("expected widths %v, got %v", expected, widths)
+	}
+
+	cmap := NewToUnicodeCMap()
+	for cid, text := range map[uint16]string{1: "W", 2: "i", 5: "n", 11: "d"} {
+		cmap.cidToUnicode[cid] = text
+	}
+	parser.fonts["F0"].ToUnicodeCMap = cmap
+
+	// At 10pt: CID 1 advances 10, CID 2 advances 2.5, CID 11 advances 7.5
+	// and CID 5 falls back to DW for 5
+	objects := parser.Parse([]byte(`BT /F0 10 Tf <00010002000B00050001> Tj ET`))
+	if len(objects.Chars) != 5 {
+		t.Fatalf("expected 5 chars, got %d", len(objects.Chars))
+	}
+	for i, want := range []float64{0, 10, 12.5, 20, 25} {
+		if got := objects.Chars[i].X0; got != want {
+			t.Errorf("char %d: expected x=%.1f, got %.2f", i, want, got)
+		}
+	}
+}
+
+func TestParseSimpleFontWidths(t *testing.T) {
+	// Arial without a descriptor has Widths for codes 65 to 67 and LastChar
+	// cutting off the fourth entry; the Type3 font's glyph space is 1/100 em
+	fonts := types.Dict{
+		"F1": types.Dict{
+			"Subtype":   types.Name("TrueType"),
+			"BaseFont":  types.Name("Arial"),
+			"FirstChar": types.Integer(65),
+			"LastChar":  types.Integer(67),
+			"Widths":    types.Array{types.Integer(667), types.Integer(722), types.Float(500.5), types.Integer(900)},
+			"FontDescriptor": types.Dict{
+				"MissingWidth": types.Integer(250),
+			},
+		},
+		"F2": types.Dict{
+			"Subtype":    types.Name("Type3"),
+			"FirstChar":  types.Integer(97),
+			"LastChar":   types.Integer(97),
+			"Widths":     types.Array{types.Integer(80)},
+			"FontMatrix": types.Array{types.Float(0.01), types.Integer(0), types.Integer(0), types.Float(0.01), types.Integer(0), types.Integer(0)},
+		},
+	}
+	parser := NewContentStreamParser(nil, types.Dict{"Resources": types.Dict{"Font": fonts}})
+	if widths := parser.fonts["F1"].Widths; !reflect.DeepEqual(widths, []float64{667, 722, 500.5}) {
+		t.Errorf("expected widths [667 722 500.5], got %v", widths)
+	}
+
+	// At 10pt: A, B and C advance by their widths, D past LastChar by the
+	// MissingWidth, and the Type3 a by 80/100 em
+	objects := parser.Parse([]byte(`BT /F1 10 Tf (ABCD) Tj /F2 10 Tf (a) Tj ET`))
+	if len(objects.Chars) != 5 {
+		t.Fatalf("expected 5 chars, got %d", len(objects.Chars))
+	}
+	for i, want := range []float64{6.67, 7.22, 5.005, 2.5, 8} {
+		if got := objects.Chars[i].Width; abs(got-want) > 1e-9 {
+			t.Errorf("char %q: expected width %.3f, got %.3f", objects.Chars[i].Text, want, got)
+		}
+	}
+	if got := objects.Chars[4].X0; abs(got-21.395) > 1e-9 {
+		t.Errorf("expected the Type3 char at x=21.395, got %.3f", got)
+	}
+}
+
+func TestSimpleFontWidthsDocument(t *testing.T) {
+	// "ABC" at 10pt from x 72 in an Arial with Widths 667, 722 and 500
+	doc, err := Open("../../testdata/simple_widths.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	chars := page.GetObjects().Chars
+	if len(chars) != 3 {
+		t.Fatalf("expected 3 chars, got %d", len(chars))
+	}
+	for i, want := range []float64{72, 78.67, 85.89} {
+		if got := chars[i].X0; abs(got-want) > 1e-9 {
+			t.Errorf("char %q: expected x=%.2f, got %.3f", chars[i].Text, want, got)
+		}
+	}
+}
+
+func TestMixedSizesShareBaseline(t *testing.T) {
+	// 36pt "AB" and 10pt "CA" on one baseline, in a font descending 0.212 em
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/mixed_sizes.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			// The libraries group characters into lines once cropped
+			if text := page.Crop(page.GetBBox()).ExtractText(); text != "AB CA" {
+				t.Errorf("expected one line, got %q", text)
+			}
+			chars := page.GetObjects().Chars
+			topDown := name == "ledongthuc"
+			if shift := baselineShift(chars[2], chars[0], topDown); abs(shift) > 1e-9 {
+				t.Errorf("expected no baseline shift between the sizes, got %v", shift)
+			}
+		})
+	}
+}
+
+func TestFontUnicodeOverride(t *testing.T) {
+	// The font's ToUnicode CMap maps codes 1-10 to A-J instead of "HelloWorld"
+	doc, err := Open("../../testdata/garbled_font.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	page, _ := doc.GetPage(0)
+	if got := page.ExtractText(); got != "ABCDE FGHIJ" {
+		t.Fatalf("expected garbled text without override, got %q", got)
+	}
+	doc.Close()
+
+	// The override maps the letters; the space still comes from the font's CMap
+	cmap := NewToUnicodeCMap()
+	for i, r := range "HelloWorld" {
+		cmap.AddMapping(uint16(i+1), string(r))
+	}
+	doc, err = Open("../../testdata/garbled_font.pdf", WithFontUnicodeOverride(`\+Garbled$`, cmap))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ = doc.GetPage(0)
+	if got := page.ExtractText(); got != "Hello World" {
+		t.Errorf("expected overridden text %q, got %q", "Hello World", got)
+	}
+
+	if _, err := Open("../../testdata/garbled_font.pdf", WithFontUnicodeOverride(`(`, cmap)); err == nil {
+		t.Error("expected an error for an invalid font name pattern")
+	}
+}
+
+func TestSpaceGlyphDetection(t *testing.T) {
+	// Both fonts map their space code 3 to U+00A0. The second is a CID font
+	// whose space glyph has zero width.
+	doc, err := Open("../../testdata/nbsp_space.pdf", WithSpaceGlyphDetection(true))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	if got := page.ExtractText(); got != "Hello World\nHi You" {
+		t.Errorf("expected spaces decoded from the space glyphs, got %q", got)
+	}
+
+	for _, char := range page.GetObjects().Chars {
+		if char.Text == "\u00a0" || (char.Text == " " && char.Font == "F2") {
+			t.Errorf("expected no char for the space glyph %q of font %s", char.Text, char.Font)
+		}
+	}
+
+	var words []string
+	for _, word := range page.ExtractWords() {
+		words = append(words, word.Text)
+	}
+	if len(words) != 3 || words[1] != "Hi" || words[2] != "You" {
+		t.Errorf("expected the zero-width space to separate words, got %q", words)
+	}
+}
+
+func TestParsePatternFillColor(t *testing.T) {
+	tiling := func(paintType int, cell string) types.StreamDict {
+		return types.StreamDict{
+			Dict: types.Dict{
+				"PatternType": types.Integer(1),
+				"PaintType":   types.Integer(paintType),
+				"BBox":        types.Array{types.Integer(0), types.Integer(0), types.Integer(20), types.Integer(10)},
+			},
+			Raw: []byte(cell),
+		}
+	}
+	pageDict := types.Dict{
+		"Resources": types.Dict{
+			"Pattern": types.Dict{
+				// Half red, half blue
+				"P1": tiling(1, "1 0 0 rg 0 0 10 10 re f 0 0 1 rg 10 0 10 10 re f"),
+				// Uncolored, colored by the scn components
+				"P2": tiling(2, "0 0 10 10 re f"),
+				// Hatching without filled areas
+				"P3": tiling(1, "0 0 m 10 10 l S"),
+			},
+		},
+	}
+
+	content := []byte(`
+		/Pattern cs /P1 scn 100 100 50 20 re f
+		/Pattern cs 0.2 0.4 0.6 /P2 scn 100 200 50 20 re f
+		/Pattern cs /P3 scn 100 300 50 20 re f
+	`)
+
+	objects := NewContentStreamParser(nil, pageDict).Parse(content)
+	if len(objects.Rects) != 3 {
+		t.Fatalf("expected 3 rectangles, got %d", len(objects.Rects))
+	}
+
+	expected := []struct {
+		pattern string
+		color   Color
+	}{
+		{"P1", Color{R: 127, G: 0, B: 127, A: 255}},
+		{"P2", Color{R: 51, G: 102, B: 153, A: 255}},
+		{"P3", Color{}},
+	}
+	for i, want := range expected {
+		rect := objects.Rects[i]
+		if rect.FillPattern != want.pattern || rect.FillColor != want.color {
+			t.Errorf("rect %d: expected pattern %s with fill %+v, got %s with %+v",
+				i, want.pattern, want.color, rect.FillPattern, rect.FillColor)
+		}
+	}
+}
+
+func TestParsePatternCellOnce(t *testing.T) {
+	doc, err := Open("../../testdata/nested_text.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// Pattern P1, object 6, is set twice and filled with twice
+	parser := page.(*PDFCPUPage).newParser()
+	parser.Parse([]byte(`
+		/Pattern cs /P1 scn 72 600 200 40 re f
+		/Pattern cs /P1 scn 72 500 200 40 re f
+	`))
+	if len(parser.patternCells) != 1 || len(parser.patternCells[6].Chars) != 4 {
+		t.Errorf("expected the cell of object 6 parsed once, got %v", parser.patternCells)
+	}
+}
+
+func TestParseDashedBoxWithoutRect(t *testing.T) {
+	// The same box drawn from four solid lines, then four dashed ones
+	box := `100 100 m 200 100 l S 200 100 m 200 150 l S
+		200 150 m 100 150 l S 100 150 m 100 100 l S`
+	content := []byte(box + ` [3 2] 0 d ` + box)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Lines) != 8 {
+		t.Fatalf("expected 8 lines, got %d", len(objects.Lines))
+	}
+	if len(objects.Rects) != 1 {
+		t.Errorf("expected only the solid box as a rectangle, got %+v", objects.Rects)
+	}
+
+	dashed := NewContentStreamParser(nil, types.Dict{}).Parse([]byte(`[3 2] 0 d ` + box))
+	if len(dashed.Rects) != 0 {
+		t.Errorf("expected no rectangle from dashed lines, got %+v", dashed.Rects)
+	}
+}
+
+func TestParseRoundedRectangle(t *testing.T) {
+	// A cell border with corners of radius 5, stroked, then the same
+	// shape filled, then a circle drawn from four curves
+	roundedRect := `
+		105 100 m 195 100 l 197.76 100 200 102.24 200 105 c
+		200 145 l 200 147.76 197.76 150 195 150 c
+		105 150 l 102.24 150 100 147.76 100 145 c
+		100 105 l 100 102.24 102.24 100 105 100 c h`
+	content := []byte(roundedRect + ` S
+		` + roundedRect + ` f
+		300 100 m 300 113.8 288.8 125 275 125 c 261.2 125 250 113.8 250 100 c
+		250 86.2 261.2 75 275 75 c 288.8 75 300 86.2 300 100 c f
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Rects) != 2 {
+		t.Fatalf("expected the stroked and the filled rounded rectangle, got %d rects: %+v", len(objects.Rects), objects.Rects)
+	}
+	for _, rect := range objects.Rects {
+		if !rect.Rounded {
+			t.Errorf("expected a rounded rectangle, got %+v", rect)
+		}
+		if rect.X0 != 100 || rect.Y0 != 100 || rect.X1 != 200 || rect.Y1 != 150 {
+			t.Errorf("unexpected rectangle bounds: %+v", rect)
+		}
+	}
+	if !objects.Rects[0].Stroked || !objects.Rects[1].NonStroking {
+		t.Errorf("expected a stroked then a filled rectangle, got %+v", objects.Rects)
+	}
+}
+
+func TestParseSimpleFontTwoByteCodes(t *testing.T) {
+	// A simple font whose ToUnicode CMap has one-byte codes below 0x80 and
+	// two-byte codes from 0x8000
+	font := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("TrueType"),
+		"BaseFont": types.Name("ABCDEF+Custom"),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	}
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F1": font}}}
+	parser := NewContentStreamParser(nil, pageDict)
+
+	cmap := NewToUnicodeCMap()
+	err := cmap.Parse([]byte(`
+		2 begincodespacerange
+		<00> <7F>
+		<8000> <FFFF>
+		endcodespacerange
+		2 beginbfchar
+		<8141> <4E2D>
+		<8142> <6587>
+		endbfchar
+		1 beginbfrange
+		<41> <5A> <0041>
+		endbfrange`))
+	if err != nil {
+		t.Fatalf("failed to parse CMap: %v", err)
+	}
+	parser.fonts["F1"].ToUnicodeCMap = cmap
+
+	objects := parser.Parse([]byte(`BT /F1 10 Tf <41814181424243> Tj ET`))
+	var text strings.Builder
+	for _, char := range objects.Chars {
+		text.WriteString(char.Text)
+	}
+	if got := text.String(); got != "A中文BC" {
+		t.Errorf("expected two-byte codes decoded with the codespace ranges, got %q", got)
+	}
+}
+
+func TestWordConfidenceUnmappedCodes(t *testing.T) {
+	// A font whose ToUnicode CMap maps only the capital letters
+	font := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("TrueType"),
+		"BaseFont": types.Name("ABCDEF+Custom"),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	}
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F1": font}}}
+
+	cmap := NewToUnicodeCMap()
+	err := cmap.Parse([]byte(`
+		1 begincodespacerange
+		<00> <FF>
+		endcodespacerange
+		1 beginbfrange
+		<41> <5A> <0041>
+		endbfrange`))
+	if err != nil {
+		t.Fatalf("failed to parse CMap: %v", err)
+	}
+	parse := func(content string) Word {
+		parser := NewContentStreamParser(nil, pageDict)
+		parser.fonts["F1"].ToUnicodeCMap = cmap
+		return createWord(parser.Parse([]byte(content)).Chars)
+	}
+
+	mapped := parse(`BT /F1 10 Tf (ABC) Tj ET`)
+	if mapped.Confidence != 1 {
+		t.Errorf("expected confidence 1 for mapped text %q, got %v", mapped.Text, mapped.Confidence)
+	}
+
+	garbled := parse(`BT /F1 10 Tf <0102A3> Tj ET`)
+	if len(garbled.Characters) != 3 {
+		t.Fatalf("expected 3 characters, got %d", len(garbled.Characters))
+	}
+	if garbled.Confidence > 0.5 {
+		t.Errorf("expected low confidence for unmapped codes %q, got %v", garbled.Text, garbled.Confidence)
+	}
+}
+
+func TestParseFormXObjectText(t *testing.T) {
+	doc, err := Open("../../testdata/form_xobject.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// The page paints form Fm1 translated to (100, 500); Fm1 paints Fm2,
+	// whose own matrix moves it up by 30
+	page, _ := doc.GetPage(0)
+	text := page.ExtractText()
+	for _, label := range []string{"Page text", "Form label", "Nested label", "After form"} {
+		if !strings.Contains(text, label) {
+			t.Errorf("expected %q in the extracted text, got %q", label, text)
+		}
+	}
+
+	positions := make(map[string]CharObject)
+	for _, char := range page.GetObjects().Chars {
+		if _, ok := positions[char.Text]; !ok {
+			positions[char.Text] = char
+		}
+	}
+	if f := positions["F"]; abs(f.X0-105) > 0.01 || abs(f.Y0-520) > 0.01 {
+		t.Errorf("expected the form label at (105, 520), got (%v, %v)", f.X0, f.Y0)
+	}
+	if n := positions["N"]; abs(n.X0-105) > 0.01 || abs(n.Y0-550) > 0.01 {
+		t.Errorf("expected the nested label at (105, 550), got (%v, %v)", n.X0, n.Y0)
+	}
+	// Text after the form is back in page space with the page's font
+	if a := positions["A"]; abs(a.X0-72) > 0.01 || a.FontSize != 12 {
+		t.Errorf("expected the text after the form at x 72 in 12pt, got %v in %vpt", a.X0, a.FontSize)
+	}
+}
+
+func TestFormXObjectTextLibraries(t *testing.T) {
+	// The libraries skip forms; their text is read and put where the form
+	// is painted
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/form_xobject.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			text := strings.ReplaceAll(page.ExtractText(), " ", "")
+			if !strings.Contains(text, "PagetextFormlabelNestedlabelAfterform") {
+				t.Errorf("expected the form labels between the page's text, got %q", text)
+			}
+			for _, char := range page.GetObjects().Chars {
+				if (char.Text == "F" || char.Text == "N") && abs(char.X0-105) > 0.01 {
+					t.Errorf("expected %q of a form label at x 105, got %v", char.Text, char.X0)
+				}
+			}
+		})
+	}
+}
+
+func TestNestedText(t *testing.T) {
+	// A rectangle filled with a tiling pattern whose cell shows "Tile",
+	// two tiles wide, a stamp annotation and a hidden one
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/nested_text.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			var text strings.Builder
+			for _, char := range page.GetObjects().Chars {
+				text.WriteString(strings.TrimSpace(char.Text))
+				if char.Text == "S" && abs(char.X0-302) > 0.01 {
+					t.Errorf("expected the stamp's appearance fitted to its rectangle at x 302, got %v", char.X0)
+				}
+			}
+			if !strings.Contains(text.String(), "Stampnote") {
+				t.Errorf("expected the annotation's appearance text, got %q", text.String())
+			}
+			if strings.Contains(text.String(), "Hidden") {
+				t.Errorf("expected no text from the hidden annotation, got %q", text.String())
+			}
+			// The libraries read no fills, so pattern cells are pdfcpu's alone
+			if name == "pdfcpu" && strings.Count(text.String(), "Tile") != 2 {
+				t.Errorf("expected the pattern cell's text on both tiles, got %q", text.String())
+			}
+		})
+	}
+}
+
+// textHeavyContent returns a content stream showing 50,000 characters in
+// 2,500 lines of 20
+func textHeavyContent() []byte {
+	var content bytes.Buffer
+	content.WriteString("BT /F1 10 Tf 12 TL 72 780 Td\n")
+	for i := 0; i < 2500; i++ {
+		fmt.Fprintf(&content, "(Line %05d text here) '\n", i)
+	}
+	content.WriteString("ET")
+	return content.Bytes()
+}
+
+func BenchmarkParseTextHeavyPage(b *testing.B) {
+	content := textHeavyContent()
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F1": types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+	}}}}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		objects := NewContentStreamParser(nil, pageDict).Parse(content)
+		if len(objects.Chars) != 50000 {
+			b.Fatalf("expected 50000 chars, got %d", len(objects.Chars))
+		}
+	}
+}
+
+func TestParseTextWithoutFont(t *testing.T) {
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F1": types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Times-Roman"),
+	}}}}
+
+	// Text before any Tf, after a Tf naming a missing font, and in F1
+	content := []byte(`BT 72 720 Td (Hi) Tj /F9 12 Tf (Ok) Tj /F1 12 Tf (Yes) Tj ET`)
+	objects := NewContentStreamParser(nil, pageDict).Parse(content)
+	if len(objects.Chars) != 7 {
+		t.Fatalf("expected 7 chars, got %d", len(objects.Chars))
+	}
+	for i, char := range objects.Chars {
+		if fallback := i < 4; char.FontFallback != fallback {
+			t.Errorf("char %d %q: expected FontFallback %v", i, char.Text, fallback)
+		}
+	}
+	// The fallback font measures glyphs with the Helvetica metrics
+	if h := objects.Chars[0]; h.Font != "Helvetica" || abs(h.Width-0.722*12) > 0.001 {
+		t.Errorf("expected H in Helvetica 0.722 em wide, got %q %v", h.Font, h.Width)
+	}
+
+	parser := NewContentStreamParser(nil, types.Dict{})
+	parser.fallbackFont = newFallbackFont("Courier")
+	objects = parser.Parse([]byte(`BT 72 720 Td (Hi) Tj ET`))
+	if len(objects.Chars) != 2 || abs(objects.Chars[1].X0-(72+0.6*12)) > 0.001 {
+		t.Errorf("expected Courier advances for the fallback font, got %+v", objects.Chars)
+	}
+
+	if _, err := Open("../../testdata/two_pages.pdf", WithFallbackFont("Comic Sans")); err == nil {
+		t.Error("expected an error for a fallback font that is not a standard font")
+	}
+}
+
+func TestParseFontFromInheritedResources(t *testing.T) {
+	doc, err := Open("../../testdata/inherited_font.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// The page's resources only have F1; F2 is in the resources of the
+	// Pages node, which the page's own resources would normally replace
+	page, _ := doc.GetPage(0)
+	var inherited []CharObject
+	for _, char := range page.GetObjects().Chars {
+		if char.Y0 < 710 {
+			inherited = append(inherited, char)
+		}
+	}
+	if len(inherited) != len("Inherited") {
+		t.Fatalf("expected %d characters in F2, got %d", len("Inherited"), len(inherited))
+	}
+	for _, char := range inherited {
+		if char.FontFallback || char.Font != "F2" {
+			t.Errorf("expected %q in F2 from the inherited resources, got %q (fallback %v)", char.Text, char.Font, char.FontFallback)
+		}
+	}
+}
+
+func TestFontResourcesLibraries(t *testing.T) {
+	// Fonts only in the Pages node's resources, or only in a form's own,
+	// resolve on the libraries too
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	files := map[string][]string{
+		"inherited_font.pdf": {"Own", "Inherited"},
+		"form_resources.pdf": {"Pagetext", "Formfont", "Pagefont", "Afterform"},
+	}
+	for name, open := range backends {
+		for file, expected := range files {
+			doc, err := open("../../testdata/" + file)
+			if err != nil {
+				t.Fatalf("%s: failed to open %s: %v", name, file, err)
+			}
+			page, _ := doc.GetPage(0)
+			text := strings.ReplaceAll(page.ExtractText(), " ", "")
+			for _, want := range expected {
+				if !strings.Contains(text, want) {
+					t.Errorf("%s: expected %q in the text of %s, got %q", name, want, file, text)
+				}
+			}
+			doc.Close()
+		}
+	}
+}
+
+func TestParseCharHeightFromFontDescriptor(t *testing.T) {
+	described := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+		"FontDescriptor": types.Dict{
+			"Type":     types.Name("FontDescriptor"),
+			"FontBBox": types.Array{types.Integer(-166), types.Integer(-225), types.Integer(1000), types.Integer(931)},
+			"Ascent":   types.Integer(718),
+			"Descent":  types.Integer(-207),
+		},
+	}
+	plain := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name("Helvetica"),
+	}
+	pageDict := types.Dict{"Resources": types.Dict{"Font": types.Dict{"F1": described, "F2": plain}}}
+
+	// The same descender-bearing glyph on the same baseline in both fonts
+	content := []byte(`BT /F1 20 Tf 72 700 Td (g) Tj /F2 20 Tf (g) Tj ET`)
+	objects := NewContentStreamParser(nil, pageDict).Parse(content)
+	if len(objects.Chars) != 2 {
+		t.Fatalf("expected 2 chars, got %d", len(objects.Chars))
+	}
+	describedG, plainG := objects.Chars[0], objects.Chars[1]
+
+	// Without a descriptor the box spans from the baseline up by the size
+	if plainG.Y0 != 700 || plainG.Y1 != 720 {
+		t.Errorf("expected the flat box from 700 to 720, got %v to %v", plainG.Y0, plainG.Y1)
+	}
+	// With one it reaches below the baseline for the descender
+	if abs(describedG.Y0-(700-0.207*20)) > 0.001 || abs(describedG.Y1-(700+0.718*20)) > 0.001 {
+		t.Errorf("expected the box from %v to %v, got %v to %v", 700-0.207*20, 700+0.718*20, describedG.Y0, describedG.Y1)
+	}
+	if abs(describedG.Height-0.925*20) > 0.001 {
+		t.Errorf("expected height %v, got %v", 0.925*20, describedG.Height)
+	}
+
+	// The FontBBox applies to descriptors without Ascent
+	delete(described["FontDescriptor"].(types.Dict), "Ascent")
+	objects = NewContentStreamParser(nil, pageDict).Parse(content)
+	if g := objects.Chars[0]; abs(g.Y0-(700-0.225*20)) > 0.001 || abs(g.Y1-(700+0.931*20)) > 0.001 {
+		t.Errorf("expected the FontBBox extent, got %v to %v", g.Y0, g.Y1)
+	}
+}
+
+func TestParseFilledRectanglePaths(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		rects   int
+	}{
+		{"three linetos and close", "10 20 m 110 20 l 110 70 l 10 70 l h f", 1},
+		{"four linetos", "10 20 m 110 20 l 110 70 l 10 70 l 10 20 l f", 1},
+		{"four linetos and close", "10 20 m 10 70 l 110 70 l 110 20 l 10 20 l h f", 1},
+		{"three linetos without close", "10 20 m 110 20 l 110 70 l 10 70 l f", 1},
+		{"diamond", "60 20 m 110 45 l 60 70 l 10 45 l h f", 0},
+		{"open fourth edge", "10 20 m 110 20 l 110 70 l 10 70 l 10 30 l f", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := NewContentStreamParser(nil, types.Dict{}).Parse([]byte(tt.content))
+			if len(objects.Rects) != tt.rects {
+				t.Fatalf("expected %d rects, got %d", tt.rects, len(objects.Rects))
+			}
+			if tt.rects == 1 {
+				rect := objects.Rects[0]
+				if rect.X0 != 10 || rect.Y0 != 20 || rect.X1 != 110 || rect.Y1 != 70 {
+					t.Errorf("expected rect (10, 20)-(110, 70), got (%v, %v)-(%v, %v)", rect.X0, rect.Y0, rect.X1, rect.Y1)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFormXObjectResources(t *testing.T) {
+	doc, err := Open("../../testdata/form_resources.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Form Fm1 shows text in F2, defined only in its own resources, and
+	// uses F1 and the Logo form from the page's resources
+	page, _ := doc.GetPage(0)
+	objects := page.GetObjects()
+	fonts := make(map[string]string)
+	for _, char := range objects.Chars {
+		if char.FontFallback {
+			t.Errorf("expected %q in a resolved font, got the fallback font", char.Text)
+		}
+		if _, ok := fonts[char.Text]; !ok {
+			fonts[char.Text] = char.Font
+		}
+	}
+	for text, font := range map[string]string{"m": "F2", "P": "F1", "A": "F1"} {
+		if fonts[text] != font {
+			t.Errorf("expected %q in %s, got %q", text, font, fonts[text])
+		}
+	}
+	if text := page.ExtractText(); !strings.Contains(text, "Form font") || !strings.Contains(text, "Page font") {
+		t.Errorf("expected the form's text, got %q", text)
+	}
+
+	if len(objects.Rects) != 1 || abs(objects.Rects[0].X0-100) > 0.01 {
+		t.Errorf("expected the rect of the Logo form at x 100, got %v", objects.Rects)
+	}
+}
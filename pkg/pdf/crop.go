@@ -0,0 +1,160 @@
+package pdf
+
+// CropObjects keeps the objects that intersect bbox, clamping characters
+// that cross its edges to it, and returns them with the box of the cropped
+// page in their coordinates. Coordinates stay those of the page unless
+// WithRelativeCoordinates moves the corner (X0, Y0) of bbox to the origin.
+func CropObjects(objects Objects, bbox BoundingBox, opts ...CropOption) (Objects, BoundingBox) {
+	config := cropConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	bbox = bbox.Normalize()
+	inside := func(obj Object) bool {
+		return bbox.Intersects(obj.GetBBox().Normalize())
+	}
+	cropped := Objects{}
+
+	for _, char := range objects.Chars {
+		if !inside(char) {
+			continue
+		}
+		if clamped, ok := clampChar(char, bbox); ok {
+			cropped.Chars = append(cropped.Chars, clamped)
+		}
+	}
+
+	for _, line := range objects.Lines {
+		if inside(line) {
+			cropped.Lines = append(cropped.Lines, line)
+		}
+	}
+
+	for _, rect := range objects.Rects {
+		if inside(rect) {
+			cropped.Rects = append(cropped.Rects, rect)
+		}
+	}
+
+	for _, curve := range objects.Curves {
+		if inside(curve) {
+			cropped.Curves = append(cropped.Curves, curve)
+		}
+	}
+
+	for _, image := range objects.Images {
+		if inside(image) {
+			cropped.Images = append(cropped.Images, image)
+		}
+	}
+
+	for _, anno := range objects.Annos {
+		if inside(anno) {
+			cropped.Annos = append(cropped.Annos, anno)
+		}
+	}
+
+	for _, shading := range objects.Shadings {
+		if inside(shading) {
+			cropped.Shadings = append(cropped.Shadings, shading)
+		}
+	}
+
+	if config.Relative {
+		width, height := bbox.Width(), bbox.Height()
+		return clipToPageBox(cropped, -bbox.X0, -bbox.Y0, width, height), BoundingBox{X1: width, Y1: height}
+	}
+	return cropped, bbox
+}
+
+// clampChar limits a character's box to bbox. It reports false for a
+// character that only touches an edge, which would be left with no width.
+func clampChar(char CharObject, bbox BoundingBox) (CharObject, bool) {
+	clamp := func(v, lo, hi float64) float64 {
+		return max(lo, min(v, hi))
+	}
+	hadWidth := char.X0 != char.X1
+	char.X0 = clamp(char.X0, bbox.X0, bbox.X1)
+	char.X1 = clamp(char.X1, bbox.X0, bbox.X1)
+	char.Y0 = clamp(char.Y0, bbox.Y0, bbox.Y1)
+	char.Y1 = clamp(char.Y1, bbox.Y0, bbox.Y1)
+	if hadWidth && char.X0 == char.X1 {
+		return char, false
+	}
+	char.Width = abs(char.X1 - char.X0)
+	char.Height = abs(char.Y1 - char.Y0)
+	return char, true
+}
+
+// pageView records the crops and rotations applied to a page since it was
+// read, to place what is read from the page dictionary afterwards, such as
+// comments, where the page's objects are
+type pageView struct {
+	height float64 // Height of the page as read, set with the first step
+	steps  []viewStep
+}
+
+// viewStep is a crop or a rotation of a page
+type viewStep struct {
+	box      BoundingBox // Box of the page before the step
+	crop     bool        // Keep only what intersects box
+	relative bool        // Move the corner (X0, Y0) of a crop box to the origin
+	degrees  int         // Clockwise rotation within box
+}
+
+// cropped returns the view with a crop to bbox added, for a page of the
+// given height
+func (v pageView) cropped(bbox BoundingBox, height float64, opts ...CropOption) pageView {
+	config := cropConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return v.with(viewStep{box: bbox.Normalize(), crop: true, relative: config.Relative}, height)
+}
+
+// rotated returns the view with a rotation within box added, for a page of
+// the given height
+func (v pageView) rotated(degrees int, box BoundingBox, height float64) pageView {
+	return v.with(viewStep{box: box.Normalize(), degrees: degrees}, height)
+}
+
+func (v pageView) with(step viewStep, height float64) pageView {
+	if len(v.steps) == 0 {
+		v.height = height
+	}
+	v.steps = append(v.steps[:len(v.steps):len(v.steps)], step)
+	return v
+}
+
+// baseHeight returns the height of the page as read, given its current one
+func (v pageView) baseHeight(height float64) float64 {
+	if len(v.steps) == 0 {
+		return height
+	}
+	return v.height
+}
+
+// rotation returns the clockwise rotation of the view in degrees
+func (v pageView) rotation() int {
+	degrees := 0
+	for _, step := range v.steps {
+		degrees += step.degrees
+	}
+	return normalizeRotation(degrees)
+}
+
+// place moves a box from the coordinates of the page as read into those of
+// the view. It reports false for a box that a crop leaves out.
+func (v pageView) place(box BoundingBox, topDown bool) (BoundingBox, bool) {
+	for _, step := range v.steps {
+		switch {
+		case step.crop && !step.box.Intersects(box):
+			return box, false
+		case step.crop && step.relative:
+			box = BoundingBox{X0: box.X0 - step.box.X0, Y0: box.Y0 - step.box.Y0, X1: box.X1 - step.box.X0, Y1: box.Y1 - step.box.Y0}
+		case !step.crop:
+			box = rotateBoxInBox(box, step.degrees, step.box, topDown)
+		}
+	}
+	return box, true
+}
@@ -0,0 +1,160 @@
+package pdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCrop(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/grid_table.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			// The first column runs from x 72 to 200 down the whole page
+			column := BoundingBox{X0: 0, Y0: 0, X1: 199, Y1: page.GetBBox().Y1}
+			text := page.Crop(column).ExtractText()
+			for _, word := range []string{"Name", "Apple", "Cherry"} {
+				if !strings.Contains(text, word) {
+					t.Errorf("expected %q in the cropped text, got %q", word, text)
+				}
+			}
+			if strings.Contains(text, "Qty") || strings.Contains(text, "1.20") {
+				t.Errorf("expected only the first column, got %q", text)
+			}
+			for _, word := range page.Crop(column).ExtractWords() {
+				if word.X1 > 199 {
+					t.Errorf("expected words inside the crop box, got %q to x %v", word.Text, word.X1)
+				}
+			}
+		})
+	}
+}
+
+func TestCropCoordinates(t *testing.T) {
+	// "Name" starts with a box from (76, 686) to (82, 696)
+	doc, err := Open("../../testdata/grid_table.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+	box := BoundingBox{X0: 72, Y0: 620, X1: 200, Y1: 700}
+
+	absolute := page.Crop(box)
+	if got := absolute.GetBBox(); got != box {
+		t.Errorf("expected the crop box in page coordinates, got %v", got)
+	}
+	if char := absolute.GetObjects().Chars[0]; char.X0 != 76 || char.Y0 != 686 {
+		t.Errorf("expected page coordinates by default, got (%v, %v)", char.X0, char.Y0)
+	}
+
+	relative := page.Crop(box, WithRelativeCoordinates(true))
+	if got := relative.GetBBox(); got != (BoundingBox{X1: 128, Y1: 80}) {
+		t.Errorf("expected the crop box at the origin, got %v", got)
+	}
+	if w, h := relative.GetWidth(), relative.GetHeight(); w != 128 || h != 80 {
+		t.Errorf("expected a 128x80 page, got %vx%v", w, h)
+	}
+	if char := relative.GetObjects().Chars[0]; char.X0 != 4 || char.Y0 != 66 {
+		t.Errorf("expected coordinates relative to the crop box, got (%v, %v)", char.X0, char.Y0)
+	}
+
+	// The box cuts through the first character of each row, which is
+	// clamped to it
+	chars := page.Crop(BoundingBox{X0: 72, Y0: 620, X1: 79, Y1: 700}).GetObjects().Chars
+	if len(chars) != 4 {
+		t.Fatalf("expected the first character of the 4 rows, got %d characters", len(chars))
+	}
+	if char := chars[0]; char.Text != "N" || char.X0 != 76 || char.X1 != 79 || char.Width != 3 {
+		t.Errorf("expected the first character clamped to x 79, got %+v", char)
+	}
+
+	// The table's top three rows, cut below the rule at y 640
+	tables := page.Crop(BoundingBox{X0: 72, Y0: 639, X1: 456, Y1: 700}).ExtractTables()
+	if len(tables) != 1 || !reflect.DeepEqual(tables[0].Rows, gridTableRows[:3]) {
+		t.Errorf("expected the first three rows, got %+v", tables)
+	}
+}
+
+func TestCropRotate(t *testing.T) {
+	// The box of TestCropCoordinates, measured from the top for ledongthuc
+	backends := map[string]struct {
+		open func(string, ...OpenOption) (Document, error)
+		box  BoundingBox
+	}{
+		"pdfcpu":     {Open, BoundingBox{X0: 72, Y0: 620, X1: 200, Y1: 700}},
+		"ledongthuc": {OpenWithLedongthuc, BoundingBox{X0: 72, Y0: 92, X1: 200, Y1: 172}},
+		"dslipak":    {OpenWithDslipak, BoundingBox{X0: 72, Y0: 620, X1: 200, Y1: 700}},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := backend.open("../../testdata/grid_table.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			// The rotated page keeps the corner of the crop box and swaps
+			// its sides
+			rotated := page.Crop(backend.box).Rotate(90)
+			box := backend.box
+			expected := BoundingBox{X0: box.X0, Y0: box.Y0, X1: box.X0 + 80, Y1: box.Y0 + 128}
+			if got := rotated.GetBBox(); got != expected {
+				t.Errorf("expected the rotated crop box %v, got %v", expected, got)
+			}
+			if w, h := rotated.GetWidth(), rotated.GetHeight(); w != 80 || h != 128 {
+				t.Errorf("expected an 80x128 page, got %vx%v", w, h)
+			}
+			chars := rotated.GetObjects().Chars
+			if len(chars) == 0 {
+				t.Fatal("expected the characters of the crop box")
+			}
+			for _, char := range chars {
+				if b := char.GetBBox(); !expected.Contains(b.X0, b.Y0) || !expected.Contains(b.X1, b.Y1) {
+					t.Errorf("expected %q inside the rotated box, got %+v", char.Text, char.GetBBox())
+				}
+			}
+		})
+	}
+}
+
+func TestCropComments(t *testing.T) {
+	// Only the highlight at x 144 lies left of x 300
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/comments.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			comments := page.Crop(BoundingBox{X1: 300, Y1: 792}).Comments()
+			if len(comments) != 1 || comments[0].Type != "Highlight" || comments[0].Text != "brown fox" {
+				t.Errorf("expected only the highlight over brown fox, got %+v", comments)
+			}
+
+			comments = page.Crop(BoundingBox{X0: 100, X1: 300, Y1: 792}, WithRelativeCoordinates(true)).Comments()
+			if len(comments) != 1 || comments[0].BBox.X0 != 44 || comments[0].Text != "brown fox" {
+				t.Errorf("expected the highlight relative to the crop box, got %+v", comments)
+			}
+		})
+	}
+}
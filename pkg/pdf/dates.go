@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParsePDFDate parses a PDF date string, D:YYYYMMDDHHmmSSOHH'mm'. The D:
+// prefix is optional, and so is everything after the year: missing fields
+// default to the start of their range and a missing offset to UTC. The
+// offset is Z or a sign followed by hours and optional minutes, each
+// optionally terminated by an apostrophe.
+func ParsePDFDate(s string) (time.Time, error) {
+	raw := s
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+
+	// Year, month, day, hour, minute and second, with their defaults
+	fields := []int{0, 1, 1, 0, 0, 0}
+	widths := []int{4, 2, 2, 2, 2, 2}
+	for i, width := range widths {
+		if len(s) == 0 || !isDigit(s[0]) {
+			if i == 0 {
+				return time.Time{}, fmt.Errorf("invalid PDF date %q: missing year", raw)
+			}
+			break
+		}
+		if len(s) < width {
+			return time.Time{}, fmt.Errorf("invalid PDF date %q: truncated field", raw)
+		}
+		value, err := strconv.Atoi(s[:width])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid PDF date %q: %w", raw, err)
+		}
+		fields[i] = value
+		s = s[width:]
+	}
+	if fields[1] < 1 || fields[1] > 12 || fields[2] < 1 || fields[2] > 31 ||
+		fields[3] > 23 || fields[4] > 59 || fields[5] > 59 {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q: field out of range", raw)
+	}
+
+	loc, err := pdfDateLocation(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid PDF date %q: %w", raw, err)
+	}
+	return time.Date(fields[0], time.Month(fields[1]), fields[2], fields[3], fields[4], fields[5], 0, loc), nil
+}
+
+// parsePDFDate parses a PDF date string, returning the zero time when it is
+// missing or invalid
+func parsePDFDate(s string) time.Time {
+	t, err := ParsePDFDate(s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// pdfDateLocation parses the offset from UT that ends a PDF date
+func pdfDateLocation(s string) (*time.Location, error) {
+	if s == "" || s == "Z" || s == "Z00'00'" || s == "Z00'00" {
+		return time.UTC, nil
+	}
+	sign := 1
+	switch s[0] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return nil, fmt.Errorf("unexpected %q after the date", s)
+	}
+
+	var parts []int
+	for _, part := range strings.Split(strings.TrimSuffix(s[1:], "'"), "'") {
+		value, err := strconv.Atoi(part)
+		if err != nil || len(part) != 2 {
+			return nil, fmt.Errorf("invalid offset %q", s)
+		}
+		parts = append(parts, value)
+	}
+	if len(parts) > 2 || parts[0] > 23 || (len(parts) == 2 && parts[1] > 59) {
+		return nil, fmt.Errorf("invalid offset %q", s)
+	}
+	offset := parts[0] * 3600
+	if len(parts) == 2 {
+		offset += parts[1] * 60
+	}
+	return time.FixedZone("", sign*offset), nil
+}
+
+// isDigit reports whether b is an ASCII digit
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
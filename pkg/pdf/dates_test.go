@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePDFDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"full with offset", "D:20230102150405+09'00'", time.Date(2023, 1, 2, 15, 4, 5, 0, time.FixedZone("", 9*3600))},
+		{"offset without trailing apostrophe", "D:20230102150405+05'30", time.Date(2023, 1, 2, 15, 4, 5, 0, time.FixedZone("", 5*3600+30*60))},
+		{"negative offset", "D:19991231235959-08'00'", time.Date(1999, 12, 31, 23, 59, 59, 0, time.FixedZone("", -8*3600))},
+		{"Z timezone", "D:20240115103000Z", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"Z with zero offset", "D:20240115103000Z00'00'", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"no prefix or timezone", "20240115103000", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"year only", "D:2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"year and month", "D:202306", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"date only with offset", "D:20230615-05'00'", time.Date(2023, 6, 15, 0, 0, 0, 0, time.FixedZone("", -5*3600))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePDFDate(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			_, gotOffset := got.Zone()
+			_, expectedOffset := tt.expected.Zone()
+			if gotOffset != expectedOffset {
+				t.Errorf("expected offset %d, got %d", expectedOffset, gotOffset)
+			}
+		})
+	}
+}
+
+func TestParsePDFDateInvalid(t *testing.T) {
+	for _, input := range []string{"", "D:", "D:20", "D:2023130", "D:20231301", "D:20230102+9", "yesterday"} {
+		if got, err := ParsePDFDate(input); err == nil {
+			t.Errorf("expected an error for %q, got %v", input, got)
+		}
+	}
+}
+
+func TestMetadataDates(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/metadata.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			metadata := doc.GetMetadata()
+			if metadata.Title != "Quarterly report" || metadata.Author != "Jane Doe" || metadata.Trapped != "False" {
+				t.Errorf("unexpected metadata: %+v", metadata)
+			}
+			created := time.Date(2023, 1, 2, 6, 4, 5, 0, time.UTC)
+			if !metadata.CreationDate.Equal(created) {
+				t.Errorf("expected creation date %v, got %v", created, metadata.CreationDate)
+			}
+			modified := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+			if !metadata.ModDate.Equal(modified) {
+				t.Errorf("expected modification date %v, got %v", modified, metadata.ModDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package pdf
+
+import "io"
+
+// drawDebug renders a page as ToImage does and outlines the objects
+// selected by opts over it, one pixel wide in their class's color
+func drawDebug(page Page, bbox BoundingBox, topDown bool, opts DebugOptions) (io.Reader, error) {
+	var imageOpts []ImageOption
+	if opts.Resolution != 0 {
+		imageOpts = append(imageOpts, WithResolution(opts.Resolution))
+	}
+	canvas, err := newRenderCanvas(bbox, topDown, imageOpts)
+	if err != nil {
+		return nil, err
+	}
+	objects := page.GetObjects()
+	canvas.drawObjects(objects)
+
+	defaults := DefaultDebugOptions()
+	ink := func(c, fallback Color) Color {
+		if colorSet(c) {
+			return c
+		}
+		return fallback
+	}
+
+	if opts.Rects {
+		stroke := renderColor(ink(opts.RectColor, defaults.RectColor), 255)
+		for _, rect := range objects.Rects {
+			canvas.strokeBox(rect.GetBBox(), stroke, 0)
+		}
+	}
+	if opts.Curves {
+		stroke := renderColor(ink(opts.CurveColor, defaults.CurveColor), 255)
+		for _, curve := range objects.Curves {
+			for i := 1; i < len(curve.Points); i++ {
+				from, to := curve.Points[i-1], curve.Points[i]
+				canvas.line(from.X, from.Y, to.X, to.Y, stroke, 0)
+			}
+		}
+	}
+	if opts.Lines {
+		stroke := renderColor(ink(opts.LineColor, defaults.LineColor), 255)
+		for _, line := range objects.Lines {
+			canvas.line(line.X0, line.Y0, line.X1, line.Y1, stroke, 0)
+		}
+	}
+	if opts.Chars {
+		stroke := renderColor(ink(opts.CharColor, defaults.CharColor), 255)
+		for _, char := range objects.Chars {
+			canvas.strokeBox(char.GetBBox(), stroke, 0)
+		}
+	}
+	if opts.Tables {
+		stroke := renderColor(ink(opts.TableColor, defaults.TableColor), 255)
+		for _, table := range page.ExtractTables(opts.TableOptions...) {
+			if len(table.Cells) == 0 {
+				// Tables found from text alignment have no cells, only
+				// their bounds and the rules across them
+				canvas.strokeBox(table.BBox, stroke, 0)
+				for _, rule := range table.Rules {
+					canvas.line(rule.X0, rule.Y, rule.X1, rule.Y, stroke, 0)
+				}
+				continue
+			}
+			for _, row := range table.Cells {
+				for _, cell := range row {
+					canvas.strokeBox(cell, stroke, 0)
+				}
+			}
+		}
+	}
+	return canvas.encode()
+}
@@ -0,0 +1,194 @@
+package pdf
+
+import (
+	"math"
+)
+
+// defaultCharDedupeTolerance is how far apart duplicate characters may be,
+// matching pdfplumber's dedupe_chars default
+const defaultCharDedupeTolerance = 1.0
+
+// DedupeOption configures Objects.Deduped
+type DedupeOption func(*dedupeConfig)
+
+type dedupeConfig struct {
+	CharTolerance  float64 // Largest offset between duplicate characters
+	ShapeTolerance float64 // Largest offset between duplicate lines, rects and curves
+	MergeLines     bool    // Join overlapping horizontal and vertical segments
+}
+
+// WithDedupeCharTolerance sets how far apart two characters with the same
+// text, font and size may be and still count as duplicates
+func WithDedupeCharTolerance(tolerance float64) DedupeOption {
+	return func(c *dedupeConfig) {
+		c.CharTolerance = tolerance
+	}
+}
+
+// WithDedupeShapeTolerance sets how far apart the coordinates of lines,
+// rects and curves may be and still count as duplicates
+func WithDedupeShapeTolerance(tolerance float64) DedupeOption {
+	return func(c *dedupeConfig) {
+		c.ShapeTolerance = tolerance
+	}
+}
+
+// WithDedupeMergeLines sets whether overlapping or touching horizontal and
+// vertical segments on the same line are joined into one (the default)
+func WithDedupeMergeLines(enabled bool) DedupeOption {
+	return func(c *dedupeConfig) {
+		c.MergeLines = enabled
+	}
+}
+
+// Deduped returns the objects with duplicate characters, lines, rects and
+// curves removed, as left by PDFs that draw text twice for a bold effect or
+// stroke and fill the same path separately. The first of each set of
+// duplicates is kept, taking on the paint of the others: a rect filled and
+// then stroked with the same corners becomes one rect that is both.
+// Images, annotations and shadings are kept as they are.
+func (o Objects) Deduped(opts ...DedupeOption) Objects {
+	config := &dedupeConfig{
+		CharTolerance:  defaultCharDedupeTolerance,
+		ShapeTolerance: FloatTolerance,
+		MergeLines:     true,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	result := o
+	result.Chars = dedupeChars(o.Chars, config.CharTolerance)
+	result.Lines = dedupeLines(o.Lines, config.ShapeTolerance)
+	if config.MergeLines {
+		result.Lines = mergeLines(result.Lines)
+	}
+	result.Rects = dedupeRects(o.Rects, config.ShapeTolerance)
+	result.Curves = dedupeCurves(o.Curves, config.ShapeTolerance)
+	return result
+}
+
+// dedupeChars drops characters repeating the text, font and size of an
+// earlier character at about the same position
+func dedupeChars(chars []CharObject, tolerance float64) []CharObject {
+	type charKey struct {
+		text, font string
+		size       float64
+	}
+	seen := make(map[charKey][]CharObject)
+	var result []CharObject
+	for _, char := range chars {
+		key := charKey{char.Text, char.Font, char.FontSize}
+		duplicate := false
+		for _, other := range seen[key] {
+			if math.Abs(char.X0-other.X0) <= tolerance && math.Abs(char.Y0-other.Y0) <= tolerance {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			seen[key] = append(seen[key], char)
+			result = append(result, char)
+		}
+	}
+	return result
+}
+
+// dedupeLines drops lines whose endpoints match an earlier line, in either
+// direction
+func dedupeLines(lines []LineObject, tolerance float64) []LineObject {
+	var result []LineObject
+	for _, line := range lines {
+		duplicate := false
+		for _, other := range result {
+			if linesWithin(line, other, tolerance) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// mergeLines joins overlapping horizontal and vertical segments, keeping
+// other lines as they are
+func mergeLines(lines []LineObject) []LineObject {
+	var axisAligned, other []LineObject
+	for _, line := range lines {
+		if math.Abs(line.Y0-line.Y1) < FloatTolerance || math.Abs(line.X0-line.X1) < FloatTolerance {
+			axisAligned = append(axisAligned, line)
+		} else {
+			other = append(other, line)
+		}
+	}
+	if len(axisAligned) == 0 {
+		return other
+	}
+	return append(ConsolidateTableLines(axisAligned), other...)
+}
+
+// dedupeRects drops rects with the corners of an earlier rect. A duplicate
+// that fills or strokes what the earlier rect did not adds its paint.
+func dedupeRects(rects []RectObject, tolerance float64) []RectObject {
+	var result []RectObject
+	for _, rect := range rects {
+		duplicate := false
+		for i := range result {
+			kept := &result[i]
+			if !rectsWithin(rect, *kept, tolerance) {
+				continue
+			}
+			if rect.Filled && !kept.Filled {
+				kept.Filled = true
+				kept.FillColor = rect.FillColor
+				kept.FillPattern = rect.FillPattern
+			}
+			if rect.Stroked && !kept.Stroked {
+				kept.Stroked = true
+				kept.StrokeColor = rect.StrokeColor
+				kept.Width = rect.Width
+			}
+			duplicate = true
+			break
+		}
+		if !duplicate {
+			result = append(result, rect)
+		}
+	}
+	return result
+}
+
+// dedupeCurves drops curves whose points all match those of an earlier curve
+func dedupeCurves(curves []CurveObject, tolerance float64) []CurveObject {
+	var result []CurveObject
+	for _, curve := range curves {
+		duplicate := false
+		for _, other := range result {
+			if curvesWithin(curve, other, tolerance) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			result = append(result, curve)
+		}
+	}
+	return result
+}
+
+// curvesWithin checks if two curves have the same number of points, each
+// less than tolerance from its counterpart
+func curvesWithin(a, b CurveObject, tolerance float64) bool {
+	if len(a.Points) != len(b.Points) {
+		return false
+	}
+	for i, p := range a.Points {
+		if math.Abs(p.X-b.Points[i].X) >= tolerance || math.Abs(p.Y-b.Points[i].Y) >= tolerance {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"testing"
+)
+
+func TestObjectsDeduped(t *testing.T) {
+	red := Color{R: 255, A: 255}
+	black := Color{A: 255}
+	objects := Objects{
+		Chars: []CharObject{
+			{Text: "A", Font: "Helvetica", FontSize: 12, X0: 100, Y0: 700, X1: 108, Y1: 712},
+			{Text: "A", Font: "Helvetica", FontSize: 12, X0: 100.5, Y0: 700.2, X1: 108.5, Y1: 712.2},
+			{Text: "B", Font: "Helvetica", FontSize: 12, X0: 108, Y0: 700, X1: 116, Y1: 712},
+		},
+		Lines: []LineObject{
+			{X0: 100, Y0: 600, X1: 200, Y1: 600, Width: 1},
+			{X0: 200, Y0: 600, X1: 100, Y1: 600, Width: 1},
+			{X0: 200, Y0: 600, X1: 300, Y1: 600, Width: 1},
+			{X0: 100, Y0: 500, X1: 150, Y1: 550, Width: 1},
+			{X0: 100, Y0: 500, X1: 150, Y1: 550, Width: 1},
+		},
+		Rects: []RectObject{
+			{X0: 100, Y0: 400, X1: 200, Y1: 450, Filled: true, FillColor: red},
+			{X0: 100, Y0: 400, X1: 200, Y1: 450.05, Stroked: true, StrokeColor: black, Width: 2},
+			{X0: 300, Y0: 400, X1: 350, Y1: 450, Filled: true, FillColor: red},
+		},
+		Curves: []CurveObject{
+			{Points: []Point{{100, 300}, {120, 320}, {140, 300}}},
+			{Points: []Point{{100, 300}, {120, 320}, {140, 300}}},
+			{Points: []Point{{100, 300}, {120, 340}, {140, 300}}},
+		},
+	}
+
+	deduped := objects.Deduped()
+	if len(deduped.Chars) != 2 {
+		t.Errorf("expected the overprinted A collapsed, got %d chars", len(deduped.Chars))
+	}
+	// The reversed duplicate is dropped, the touching segment joined and
+	// the repeated diagonal dropped
+	if len(deduped.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %+v", len(deduped.Lines), deduped.Lines)
+	}
+	if line := deduped.Lines[0]; line.X0 != 100 || line.X1 != 300 {
+		t.Errorf("expected the horizontal segments joined from 100 to 300, got %+v", line)
+	}
+	if len(deduped.Rects) != 2 {
+		t.Fatalf("expected 2 rects, got %d", len(deduped.Rects))
+	}
+	if rect := deduped.Rects[0]; !rect.Filled || !rect.Stroked || rect.FillColor != red || rect.StrokeColor != black || rect.Width != 2 {
+		t.Errorf("expected the filled and stroked copies merged, got %+v", rect)
+	}
+	if len(deduped.Curves) != 2 {
+		t.Errorf("expected 2 curves, got %d", len(deduped.Curves))
+	}
+
+	if len(objects.Lines) != 5 || len(objects.Chars) != 3 {
+		t.Error("expected the original objects left unchanged")
+	}
+
+	// Without merging, only exact duplicates go
+	if lines := objects.Deduped(WithDedupeMergeLines(false)).Lines; len(lines) != 3 {
+		t.Errorf("expected 3 lines without merging, got %d", len(lines))
+	}
+	// A tolerance below the offset keeps both A's
+	if chars := objects.Deduped(WithDedupeCharTolerance(0.1)).Chars; len(chars) != 3 {
+		t.Errorf("expected 3 chars with a tight tolerance, got %d", len(chars))
+	}
+}
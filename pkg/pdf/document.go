@@ -1,13 +1,17 @@
 package pdf
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"time"
+	"strings"
 
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pyhub-apps/pdfplumber-golang/pkg/parser"
 )
 
 // PDFDocument implements the Document interface using pdfcpu
@@ -16,21 +20,60 @@ type PDFDocument struct {
 	filepath string
 	pages    []Page
 	metadata Metadata
+	config   *openConfig
 }
 
 // Open opens a PDF file and returns a Document
-func Open(filepath string) (Document, error) {
-	return OpenWithPassword(filepath, "")
+func Open(filepath string, opts ...OpenOption) (Document, error) {
+	return OpenWithPassword(filepath, "", opts...)
 }
 
 // OpenWithPassword opens a password-protected PDF file
-func OpenWithPassword(filepath string, password string) (Document, error) {
+func OpenWithPassword(filepath string, password string, opts ...OpenOption) (Document, error) {
 	// Read PDF file
 	f, err := os.Open(filepath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+
+	return openPDFCPU(io.NewSectionReader(f, 0, info.Size()), filepath, password, opts)
+}
+
+// OpenReader opens a PDF from the first size bytes of r, such as an upload
+// or an object fetched from storage, without a file on disk. The document
+// is read in full, so r is not used after OpenReader returns.
+func OpenReader(r io.ReaderAt, size int64, opts ...OpenOption) (Document, error) {
+	return openPDFCPU(io.NewSectionReader(r, 0, size), "", "", opts)
+}
+
+// OpenBytes opens a PDF held in memory
+func OpenBytes(data []byte, opts ...OpenOption) (Document, error) {
+	return OpenReader(bytes.NewReader(data), int64(len(data)), opts...)
+}
+
+// openPDFCPU reads a PDF with pdfcpu; filepath is empty for documents not
+// read from a file
+func openPDFCPU(r *io.SectionReader, filepath, password string, opts []OpenOption) (Document, error) {
+	config, err := newOpenConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The native parser authenticates the password, and its cycle-checked
+	// walk of the page tree, which stops at the page limit where pdfcpu
+	// walks the whole tree first, rejects documents with too many pages or
+	// a page tree cycle. Its other errors are left to pdfcpu, which repairs
+	// more damage.
+	options := append(config.parserOptions(), parser.WithPassword(password))
+	_, err = parser.NewPDFParser(r, r.Size(), options...).Parse()
+	if errors.Is(err, ErrEncrypted) || errors.Is(err, ErrTooManyPages) || errors.Is(err, ErrPageTreeCycle) {
+		return nil, err
+	}
 
 	// Create pdfcpu configuration
 	conf := model.NewDefaultConfiguration()
@@ -40,9 +83,9 @@ func OpenWithPassword(filepath string, password string) (Document, error) {
 	}
 
 	// Parse PDF with pdfcpu
-	ctx, err := api.ReadContextFile(filepath)
+	ctx, err := api.ReadContext(r, conf)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read PDF context: %w", err)
+		return nil, fmt.Errorf("failed to read PDF context: %w", pdfcpuReadError(err))
 	}
 
 	// Validate the PDF
@@ -53,6 +96,7 @@ func OpenWithPassword(filepath string, password string) (Document, error) {
 	doc := &PDFDocument{
 		ctx:      ctx,
 		filepath: filepath,
+		config:   config,
 	}
 
 	// Extract metadata
@@ -66,36 +110,71 @@ func OpenWithPassword(filepath string, password string) (Document, error) {
 	return doc, nil
 }
 
-// extractMetadata extracts PDF metadata from the context
+// extractMetadata extracts PDF metadata from the trailer's /Info dictionary
 func (d *PDFDocument) extractMetadata() {
 	if d.ctx.Info == nil {
 		return
 	}
-
-	// The Info field is an IndirectRef, we need to resolve it to get the actual Dict
-	// For now, we'll skip metadata extraction as it requires more complex handling
-	// TODO: Implement proper metadata extraction from IndirectRef
+	info, err := d.ctx.DereferenceDict(*d.ctx.Info)
+	if err != nil || info == nil {
+		return
+	}
 	
 	d.metadata = Metadata{
-		Title:    "",
-		Author:   "",
-		Subject:  "",
-		Keywords: "",
-		Creator:  "",
-		Producer: "",
+		Title:        d.textString(info["Title"]),
+		Author:       d.textString(info["Author"]),
+		Subject:      d.textString(info["Subject"]),
+		Keywords:     d.textString(info["Keywords"]),
+		Creator:      d.textString(info["Creator"]),
+		Producer:     d.textString(info["Producer"]),
+		CreationDate: parsePDFDate(d.textString(info["CreationDate"])),
+		ModDate:      parsePDFDate(d.textString(info["ModDate"])),
+	}
+	if trapped := info.NameEntry("Trapped"); trapped != nil {
+		d.metadata.Trapped = *trapped
 	}
 }
 
 // initializePages initializes all pages in the document
 func (d *PDFDocument) initializePages() error {
-	pageCount := d.ctx.PageCount
+	pageCount, err := d.config.pageCount(d.ctx.PageCount)
+	if err != nil {
+		return err
+	}
 	d.pages = make([]Page, pageCount)
+	lang := d.Language()
+	mcidLangs := d.structTreeLanguages()
+	mcidOrder := d.structTreeOrder()
+	tagged := d.isTagged()
+	var hiddenLayers map[int]bool
+	if d.config.VisibleLayersOnly {
+		hiddenLayers = d.hiddenLayers()
+	}
+	fonts := newFontCache()
 
 	for i := 1; i <= pageCount; i++ {
-		page, err := NewPDFCPUPage(d.ctx, i)
+		page, err := newPDFCPUPage(d.ctx, i, d.config.MaxStreamSize)
 		if err != nil {
 			return fmt.Errorf("failed to create page %d: %w", i, err)
 		}
+		page.fontOverrides = d.config.FontUnicodeOverrides
+		page.spaceGlyphs = d.config.SpaceGlyphDetection
+		page.repairUnicode = d.config.RepairUnicode
+		page.fontCache = fonts
+		page.lang = lang
+		page.mcidLangs = mcidLangs[page.objectNumber]
+		page.mcidOrder = mcidOrder[page.objectNumber]
+		page.tagged = tagged
+		page.maxObjects = d.config.MaxObjectsPerPage
+		page.fallbackFont = d.config.FallbackFont
+		page.hiddenLayers = hiddenLayers
+		page.exclusions = d.config.ExcludeRegions
+		page.physical = d.config.PhysicalDimensions
+		page.snapGrid = d.config.SnapGrid
+		if box := d.config.PageBBox; box != nil {
+			page.pageBox = box
+			page.width, page.height = box.Width(), box.Height()
+		}
 		d.pages[i-1] = page
 	}
 
@@ -115,7 +194,7 @@ func (d *PDFDocument) GetPages() []Page {
 // GetPage returns a specific page by index (0-based)
 func (d *PDFDocument) GetPage(index int) (Page, error) {
 	if index < 0 || index >= len(d.pages) {
-		return nil, fmt.Errorf("page index %d out of range [0, %d)", index, len(d.pages))
+		return nil, fmt.Errorf("%w: index %d not in [0, %d)", ErrPageOutOfRange, index, len(d.pages))
 	}
 	return d.pages[index], nil
 }
@@ -125,6 +204,273 @@ func (d *PDFDocument) PageCount() int {
 	return len(d.pages)
 }
 
+// PageDimensions returns the size and rotation of every page, read from the
+// page dictionaries
+func (d *PDFDocument) PageDimensions() []PageDim {
+	dims := make([]PageDim, len(d.pages))
+	for i := range d.pages {
+		var mediaBox, cropBox *BoundingBox
+		rotate := 0
+		if _, _, attrs, err := d.ctx.PageDict(i+1, false); err == nil && attrs != nil {
+			mediaBox, cropBox = rectangleBox(attrs.MediaBox), rectangleBox(attrs.CropBox)
+			rotate = attrs.Rotate
+		}
+		dims[i] = pageDim(i, mediaBox, cropBox, d.config.PageBBox, rotate).withUserUnit(d.pages[i].UserUnit(), d.config.PhysicalDimensions)
+	}
+	return dims
+}
+
+// Validate reports structural problems that affect extraction
+func (d *PDFDocument) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	if d.ctx.Encrypt != nil {
+		issues = append(issues, ValidationIssue{Severity: SeverityInfo, PageIndex: -1, Message: "document is encrypted"})
+	}
+	for _, page := range d.pages {
+		if p, ok := page.(*PDFCPUPage); ok {
+			issues = append(issues, validatePage(p.validationFacts())...)
+		}
+	}
+	return issues
+}
+
+// Info returns an overview of the document: its metadata, object counts of
+// its pages, encryption, tagging, fonts and validation issues
+func (d *PDFDocument) Info() DocumentInfo {
+	return documentInfo(d, d.ctx.Encrypt != nil, d.isTagged())
+}
+
+// Search finds a pattern, a regular expression unless configured otherwise,
+// in the text of all pages
+func (d *PDFDocument) Search(pattern string, opts ...SearchOption) []DocSearchMatch {
+	return searchDocument(d.pages, false, pattern, opts)
+}
+
+// ExtractBetween returns the text of the document between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the document.
+func (d *PDFDocument) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween(d.pages, false, start, end, opts)
+}
+
+// ExtractAllTables extracts the tables of all pages with the given number of
+// workers, one per CPU if fewer than one, keyed by page index from 0
+func (d *PDFDocument) ExtractAllTables(workers int, opts ...TableExtractionOption) map[int][]Table {
+	return extractAllTables(d.pages, workers, opts)
+}
+
+// Language returns the natural language of the document from the catalog's
+// /Lang, empty if not given
+func (d *PDFDocument) Language() string {
+	return d.textString(d.ctx.RootDict["Lang"])
+}
+
+// Attachments returns the files embedded in the document. pdfcpu moves the
+// catalog's name trees into its own cache while reading, so the
+// /EmbeddedFiles tree is taken from there.
+func (d *PDFDocument) Attachments() []Attachment {
+	if !d.ctx.Valid {
+		if err := d.ctx.LocateNameTree("EmbeddedFiles", false); err != nil {
+			return nil
+		}
+	}
+	tree := d.ctx.Names["EmbeddedFiles"]
+	if tree == nil {
+		return nil
+	}
+	
+	var attachments []Attachment
+	tree.Process(d.ctx.XRefTable, func(_ *model.XRefTable, name string, value *types.Object) error {
+		if attachment, ok := d.attachment(name, *value); ok {
+			attachments = append(attachments, attachment)
+		}
+		return nil
+	})
+	return attachments
+}
+
+// attachment reads the embedded file of a file specification
+func (d *PDFDocument) attachment(name string, value types.Object) (Attachment, bool) {
+	spec, err := d.ctx.DereferenceDict(value)
+	if err != nil || spec == nil {
+		return Attachment{}, false
+	}
+	ef, err := d.ctx.DereferenceDict(spec["EF"])
+	if err != nil || ef == nil {
+		return Attachment{}, false
+	}
+	streamRef := ef["UF"]
+	if streamRef == nil {
+		streamRef = ef["F"]
+	}
+	stream, _, err := d.ctx.DereferenceStreamDict(streamRef)
+	if err != nil || stream == nil {
+		return Attachment{}, false
+	}
+	data, err := decodeStream(stream, d.config.MaxStreamSize)
+	if err != nil {
+		return Attachment{}, false
+	}
+	
+	if fileName := d.textString(spec["UF"]); fileName != "" {
+		name = fileName
+	} else if fileName := d.textString(spec["F"]); fileName != "" {
+		name = fileName
+	}
+	
+	attachment := Attachment{Name: name, Data: data}
+	if subtype := stream.Dict.NameEntry("Subtype"); subtype != nil {
+		attachment.MIMEType = *subtype
+	}
+	if params, err := d.ctx.DereferenceDict(stream.Dict["Params"]); err == nil && params != nil {
+		attachment.ModDate = parsePDFDate(d.textString(params["ModDate"]))
+	}
+	return attachment, true
+}
+
+// FormFields returns the terminal fields of the document's interactive
+// form. Fields without a /V take their value from the text of their
+// widget's normal appearance stream.
+func (d *PDFDocument) FormFields() []FormField {
+	form, err := d.ctx.DereferenceDict(d.ctx.RootDict["AcroForm"])
+	if err != nil || form == nil {
+		return nil
+	}
+	dr, _ := d.ctx.DereferenceDict(form["DR"])
+	fields, err := d.ctx.DereferenceArray(form["Fields"])
+	if err != nil {
+		return nil
+	}
+	
+	var result []FormField
+	for _, field := range fields {
+		d.walkFormFields(field, FormField{}, "", dr, 0, &result)
+	}
+	return result
+}
+
+// walkFormFields collects the terminal fields below a field tree node. parent
+// carries the inherited name and type, value the inherited /V.
+func (d *PDFDocument) walkFormFields(obj types.Object, parent FormField, value string, dr types.Dict, depth int, fields *[]FormField) {
+	if depth > maxFieldTreeDepth {
+		return
+	}
+	node, err := d.ctx.DereferenceDict(obj)
+	if err != nil || node == nil {
+		return
+	}
+	
+	field := FormField{Name: qualifiedFieldName(parent.Name, d.textString(node["T"])), Type: parent.Type}
+	if ft := node.NameEntry("FT"); ft != nil {
+		field.Type = *ft
+	}
+	if v, ok := node.Find("V"); ok {
+		value = d.fieldValue(v)
+	}
+	
+	// Kids with a /T are fields of their own, the others are widgets
+	kids, _ := d.ctx.DereferenceArray(node["Kids"])
+	var widgets []types.Dict
+	hasFieldKids := false
+	for _, kid := range kids {
+		kidDict, err := d.ctx.DereferenceDict(kid)
+		if err != nil || kidDict == nil {
+			continue
+		}
+		if _, ok := kidDict.Find("T"); ok {
+			hasFieldKids = true
+			d.walkFormFields(kid, field, value, dr, depth+1, fields)
+		} else {
+			widgets = append(widgets, kidDict)
+		}
+	}
+	if hasFieldKids && len(widgets) == 0 {
+		return
+	}
+	
+	widget := node
+	if len(widgets) > 0 {
+		widget = widgets[0]
+	}
+	if rect, err := d.ctx.DereferenceArray(widget["Rect"]); err == nil && len(rect) == 4 {
+		field.BBox = BoundingBox{
+			X0: numberValue(rect[0]),
+			Y0: numberValue(rect[1]),
+			X1: numberValue(rect[2]),
+			Y1: numberValue(rect[3]),
+		}.Normalize()
+	}
+	field.Value = value
+	if field.Value == "" {
+		field.Value = d.appearanceValue(widget, dr)
+	}
+	*fields = append(*fields, field)
+}
+
+// fieldValue converts a field's /V to text: strings as is, names for check
+// boxes and radio buttons, and the entries of multiple selections joined
+func (d *PDFDocument) fieldValue(obj types.Object) string {
+	obj, err := d.ctx.Dereference(obj)
+	if err != nil || obj == nil {
+		return ""
+	}
+	switch v := obj.(type) {
+	case types.Name:
+		return v.Value()
+	case types.Array:
+		values := make([]string, 0, len(v))
+		for _, entry := range v {
+			if s := d.fieldValue(entry); s != "" {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+	return d.textString(obj)
+}
+
+// appearanceValue returns the text of a widget's normal appearance stream.
+// Fonts come from the stream's /Resources, or the form's /DR without them.
+func (d *PDFDocument) appearanceValue(widget, dr types.Dict) string {
+	ap, err := d.ctx.DereferenceDict(widget["AP"])
+	if err != nil || ap == nil {
+		return ""
+	}
+	stream, _, err := d.ctx.DereferenceStreamDict(ap["N"])
+	if err != nil || stream == nil {
+		return ""
+	}
+	content, err := decodeStream(stream, d.config.MaxStreamSize)
+	if err != nil {
+		return ""
+	}
+	
+	resources, err := d.ctx.DereferenceDict(stream.Dict["Resources"])
+	if err != nil || resources == nil {
+		resources = dr
+	}
+	return appearanceText(d.ctx, resources, content, d.config.MaxStreamSize)
+}
+
+// textString resolves a PDF text string, decoding UTF-16 if marked so
+func (d *PDFDocument) textString(obj types.Object) string {
+	return contextTextString(d.ctx, obj)
+}
+
+// contextTextString resolves a PDF text string of a pdfcpu context, decoding
+// UTF-16 if marked so
+func contextTextString(ctx *model.Context, obj types.Object) string {
+	obj, err := ctx.Dereference(obj)
+	if err != nil || obj == nil {
+		return ""
+	}
+	s, err := types.StringOrHexLiteral(obj)
+	if err != nil {
+		return ""
+	}
+	return *s
+}
+
 // Close releases resources associated with the document
 func (d *PDFDocument) Close() error {
 	// Clean up resources if needed
@@ -154,27 +500,3 @@ func getStringFromDict(dict types.Dict, key string) string {
 		return ""
 	}
 }
-
-func parsePDFDate(dateStr string) time.Time {
-	// PDF date format: D:YYYYMMDDHHmmSSOHH'mm
-	// For simplicity, we'll use a basic parser
-	if len(dateStr) < 16 {
-		return time.Time{}
-	}
-	
-	// Remove "D:" prefix if present
-	if dateStr[:2] == "D:" {
-		dateStr = dateStr[2:]
-	}
-	
-	// Try to parse the date
-	layout := "20060102150405"
-	if len(dateStr) >= 14 {
-		t, err := time.Parse(layout, dateStr[:14])
-		if err == nil {
-			return t
-		}
-	}
-	
-	return time.Time{}
-}
\ No newline at end of file
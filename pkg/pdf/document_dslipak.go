@@ -3,6 +3,7 @@ package pdf
 import (
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -11,22 +12,35 @@ import (
 
 // DsliPakDocument implements the Document interface using dslipak/pdf library
 type DsliPakDocument struct {
+	file     *os.File
 	reader   *gopdf.Reader
 	filepath string
 	pages    []Page
 	metadata Metadata
+	config   *openConfig
 }
 
 // OpenWithDslipak opens a PDF file using the dslipak/pdf library
-func OpenWithDslipak(filepath string) (Document, error) {
-	r, err := gopdf.Open(filepath)
+func OpenWithDslipak(filepath string, opts ...OpenOption) (Document, error) {
+	config, err := newLibraryOpenConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	
+	var r *gopdf.Reader
+	f, err := readLibraryFile(filepath, config.parserOptions(), func(f *os.File, size int64) (err error) {
+		r, err = gopdf.NewReader(f, size)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PDF with dslipak: %w", err)
 	}
 	
 	doc := &DsliPakDocument{
+		file:     f,
 		reader:   r,
 		filepath: filepath,
+		config:   config,
 	}
 	
 	// Extract metadata
@@ -34,25 +48,35 @@ func OpenWithDslipak(filepath string) (Document, error) {
 	
 	// Initialize pages
 	if err := doc.initializePages(); err != nil {
+		f.Close()
 		return nil, fmt.Errorf("failed to initialize pages: %w", err)
 	}
 	
 	return doc, nil
 }
 
-// extractMetadata extracts PDF metadata
+// extractMetadata extracts PDF metadata from the trailer's /Info dictionary
 func (d *DsliPakDocument) extractMetadata() {
-	// The dslipak/pdf library doesn't directly expose metadata
-	// We'll leave this empty for now
-	d.metadata = Metadata{}
-	
-	// In a real implementation, we would parse the PDF's info dictionary
-	// which contains metadata like Title, Author, etc.
+	info := d.reader.Trailer().Key("Info")
+	d.metadata = Metadata{
+		Title:        info.Key("Title").Text(),
+		Author:       info.Key("Author").Text(),
+		Subject:      info.Key("Subject").Text(),
+		Keywords:     info.Key("Keywords").Text(),
+		Creator:      info.Key("Creator").Text(),
+		Producer:     info.Key("Producer").Text(),
+		CreationDate: parsePDFDate(info.Key("CreationDate").RawString()),
+		ModDate:      parsePDFDate(info.Key("ModDate").RawString()),
+		Trapped:      info.Key("Trapped").Name(),
+	}
 }
 
 // initializePages initializes all pages in the document
 func (d *DsliPakDocument) initializePages() error {
-	pageCount := d.reader.NumPage()
+	pageCount, err := d.config.pageCount(d.reader.NumPage())
+	if err != nil {
+		return err
+	}
 	d.pages = make([]Page, pageCount)
 	
 	for i := 1; i <= pageCount; i++ {
@@ -60,6 +84,19 @@ func (d *DsliPakDocument) initializePages() error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize page %d: %w", i, err)
 		}
+		if p, ok := page.(*DsliPakPage); ok {
+			p.maxObjects = d.config.MaxObjectsPerPage
+			p.exclusions = d.config.ExcludeRegions
+			p.physical = d.config.PhysicalDimensions
+			p.snapGrid = d.config.SnapGrid
+			p.emitSpaces = d.config.EmitSpaces
+			if d.reader.Trailer().Key("Encrypt").IsNull() {
+				p.file = d.file
+			}
+			if box := d.config.PageBBox; box != nil {
+				p.setPageBox(*box)
+			}
+		}
 		d.pages[i-1] = page
 	}
 	
@@ -79,7 +116,7 @@ func (d *DsliPakDocument) GetPages() []Page {
 // GetPage returns a specific page by index (0-based)
 func (d *DsliPakDocument) GetPage(index int) (Page, error) {
 	if index < 0 || index >= len(d.pages) {
-		return nil, fmt.Errorf("page index %d out of range [0, %d)", index, len(d.pages))
+		return nil, fmt.Errorf("%w: index %d not in [0, %d)", ErrPageOutOfRange, index, len(d.pages))
 	}
 	return d.pages[index], nil
 }
@@ -89,10 +126,255 @@ func (d *DsliPakDocument) PageCount() int {
 	return len(d.pages)
 }
 
+// PageDimensions returns the size and rotation of every page, read from the
+// page dictionaries
+func (d *DsliPakDocument) PageDimensions() []PageDim {
+	dims := make([]PageDim, len(d.pages))
+	for i := range d.pages {
+		page := d.reader.Page(i + 1).V
+		mediaBox := dsliPakBox(dsliPakInherited(page, "MediaBox"))
+		cropBox := dsliPakBox(dsliPakInherited(page, "CropBox"))
+		rotate := int(dsliPakInherited(page, "Rotate").Int64())
+		dims[i] = pageDim(i, mediaBox, cropBox, d.config.PageBBox, rotate).withUserUnit(d.pages[i].UserUnit(), d.config.PhysicalDimensions)
+	}
+	return dims
+}
+
+// dsliPakInherited looks a key up in a page dictionary, then in the Pages
+// nodes above it, visiting at most maxPageTreeDepth dictionaries
+func dsliPakInherited(page gopdf.Value, key string) gopdf.Value {
+	v := page
+	for depth := 0; v.Kind() == gopdf.Dict && depth < maxPageTreeDepth; depth++ {
+		if value := v.Key(key); !value.IsNull() {
+			return value
+		}
+		v = v.Key("Parent")
+	}
+	return gopdf.Value{}
+}
+
+// dsliPakBox converts a rectangle array to a bounding box, nil if it is not one
+func dsliPakBox(rect gopdf.Value) *BoundingBox {
+	if rect.Kind() != gopdf.Array || rect.Len() != 4 {
+		return nil
+	}
+	return &BoundingBox{
+		X0: rect.Index(0).Float64(),
+		Y0: rect.Index(1).Float64(),
+		X1: rect.Index(2).Float64(),
+		Y1: rect.Index(3).Float64(),
+	}
+}
+
+// Validate reports structural problems that affect extraction
+func (d *DsliPakDocument) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	if !d.reader.Trailer().Key("Encrypt").IsNull() {
+		issues = append(issues, ValidationIssue{Severity: SeverityInfo, PageIndex: -1, Message: "document is encrypted"})
+	}
+	for _, page := range d.pages {
+		if p, ok := page.(*DsliPakPage); ok {
+			issues = append(issues, validatePage(p.validationFacts())...)
+		}
+	}
+	return issues
+}
+
+// Info returns an overview of the document: its metadata, object counts of
+// its pages, encryption, tagging, fonts and validation issues
+func (d *DsliPakDocument) Info() DocumentInfo {
+	trailer := d.reader.Trailer()
+	tagged := trailer.Key("Root").Key("MarkInfo").Key("Marked").Bool()
+	return documentInfo(d, !trailer.Key("Encrypt").IsNull(), tagged)
+}
+
+// Search finds a pattern, a regular expression unless configured otherwise,
+// in the text of all pages
+func (d *DsliPakDocument) Search(pattern string, opts ...SearchOption) []DocSearchMatch {
+	return searchDocument(d.pages, false, pattern, opts)
+}
+
+// ExtractBetween returns the text of the document between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the document.
+func (d *DsliPakDocument) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween(d.pages, false, start, end, opts)
+}
+
+// ExtractAllTables extracts the tables of all pages with the given number of
+// workers, one per CPU if fewer than one, keyed by page index from 0
+func (d *DsliPakDocument) ExtractAllTables(workers int, opts ...TableExtractionOption) map[int][]Table {
+	return extractAllTables(d.pages, workers, opts)
+}
+
+// Language returns the natural language of the document from the catalog's
+// /Lang, empty if not given
+func (d *DsliPakDocument) Language() string {
+	return d.reader.Trailer().Key("Root").Key("Lang").Text()
+}
+
+// Attachments returns the files embedded in the document
+func (d *DsliPakDocument) Attachments() []Attachment {
+	var attachments []Attachment
+	names := d.reader.Trailer().Key("Root").Key("Names").Key("EmbeddedFiles")
+	walkDsliPakNameTree(names, 0, map[string]bool{}, func(name string, spec gopdf.Value) {
+		stream := spec.Key("EF").Key("UF")
+		if stream.Kind() != gopdf.Stream {
+			stream = spec.Key("EF").Key("F")
+		}
+		data, err := readDsliPakStream(stream)
+		if err != nil {
+			return
+		}
+		
+		if fileName := spec.Key("UF").Text(); fileName != "" {
+			name = fileName
+		} else if fileName := spec.Key("F").Text(); fileName != "" {
+			name = fileName
+		}
+		
+		attachments = append(attachments, Attachment{
+			Name:     name,
+			MIMEType: stream.Key("Subtype").Name(),
+			ModDate:  parsePDFDate(stream.Key("Params").Key("ModDate").RawString()),
+			Data:     data,
+		})
+	})
+	return attachments
+}
+
+// FormFields returns the terminal fields of the document's interactive
+// form. Fields without a /V take their value from the text of their
+// widget's normal appearance stream.
+func (d *DsliPakDocument) FormFields() []FormField {
+	var fields []FormField
+	form := d.reader.Trailer().Key("Root").Key("AcroForm")
+	roots := form.Key("Fields")
+	for i := 0; i < roots.Len(); i++ {
+		walkDsliPakFormFields(roots.Index(i), FormField{}, "", 0, &fields)
+	}
+	return fields
+}
+
+// walkDsliPakFormFields collects the terminal fields below a field tree
+// node. parent carries the inherited name and type, value the inherited /V.
+func walkDsliPakFormFields(node gopdf.Value, parent FormField, value string, depth int, fields *[]FormField) {
+	if node.Kind() != gopdf.Dict || depth > maxFieldTreeDepth {
+		return
+	}
+	
+	field := FormField{Name: qualifiedFieldName(parent.Name, node.Key("T").Text()), Type: parent.Type}
+	if ft := node.Key("FT").Name(); ft != "" {
+		field.Type = ft
+	}
+	if v := node.Key("V"); !v.IsNull() {
+		value = dsliPakFieldValue(v)
+	}
+	
+	// Kids with a /T are fields of their own, the others are widgets
+	kids := node.Key("Kids")
+	var widgets []gopdf.Value
+	hasFieldKids := false
+	for i := 0; i < kids.Len(); i++ {
+		kid := kids.Index(i)
+		if kid.Kind() != gopdf.Dict {
+			continue
+		}
+		if !kid.Key("T").IsNull() {
+			hasFieldKids = true
+			walkDsliPakFormFields(kid, field, value, depth+1, fields)
+		} else {
+			widgets = append(widgets, kid)
+		}
+	}
+	if hasFieldKids && len(widgets) == 0 {
+		return
+	}
+	
+	widget := node
+	if len(widgets) > 0 {
+		widget = widgets[0]
+	}
+	if rect := widget.Key("Rect"); rect.Len() == 4 {
+		field.BBox = BoundingBox{
+			X0: rect.Index(0).Float64(),
+			Y0: rect.Index(1).Float64(),
+			X1: rect.Index(2).Float64(),
+			Y1: rect.Index(3).Float64(),
+		}.Normalize()
+	}
+	field.Value = value
+	if field.Value == "" {
+		if data, err := readDsliPakStream(widget.Key("AP").Key("N")); err == nil {
+			// The library's font objects cannot be handed to the parser
+			field.Value = appearanceText(nil, nil, data, 0)
+		}
+	}
+	*fields = append(*fields, field)
+}
+
+// dsliPakFieldValue converts a field's /V to text: strings as is, names for
+// check boxes and radio buttons, and the entries of multiple selections joined
+func dsliPakFieldValue(v gopdf.Value) string {
+	switch v.Kind() {
+	case gopdf.Name:
+		return v.Name()
+	case gopdf.Array:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if s := dsliPakFieldValue(v.Index(i)); s != "" {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+	return v.Text()
+}
+
+// walkDsliPakNameTree calls fn for each key and value of a name tree.
+// Nodes are told apart by their String, which shows references unresolved,
+// so that a node listed among several /Kids is walked once.
+func walkDsliPakNameTree(node gopdf.Value, depth int, seen map[string]bool, fn func(name string, value gopdf.Value)) {
+	if node.Kind() != gopdf.Dict || depth > maxNameTreeDepth || seen[node.String()] {
+		return
+	}
+	seen[node.String()] = true
+	
+	names := node.Key("Names")
+	for i := 0; i+1 < names.Len(); i += 2 {
+		fn(names.Index(i).Text(), names.Index(i+1))
+	}
+	
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		walkDsliPakNameTree(kids.Index(i), depth+1, seen, fn)
+	}
+}
+
+// readDsliPakStream reads and decodes a stream. The library panics on
+// filters it does not support, which is reported as an error instead.
+func readDsliPakStream(stream gopdf.Value) (data []byte, err error) {
+	if stream.Kind() != gopdf.Stream {
+		return nil, fmt.Errorf("not a stream")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to decode stream: %v", r)
+		}
+	}()
+	
+	rc := stream.Reader()
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
 // Close releases resources associated with the document
 func (d *DsliPakDocument) Close() error {
 	d.reader = nil
 	d.pages = nil
+	if d.file != nil {
+		return d.file.Close()
+	}
 	return nil
 }
 
@@ -104,13 +386,27 @@ type DsliPakPage struct {
 	width      float64
 	height     float64
 	bbox       BoundingBox
+	rotation   int     // Rotation applied on top of the page's /Rotate
+	userUnit   float64 // Size of a default user space unit in 1/72 inch
+	physical   bool    // Report the size in physical points, scaled by userUnit
 	objects    Objects
+	extracted  bool     // objects are extracted lazily on first use
+	cropped    bool     // Objects are those of a crop box, not the whole page content
+	view       pageView // Crops and rotations applied since the page was read
+	words      wordCache
+	maxObjects int           // Objects kept, 0 for no limit
+	truncated  bool          // Objects past maxObjects were dropped
+	pageBox    *BoundingBox  // Overriding page box in PDF space, nil for the MediaBox
+	exclusions []BoundingBox // Areas whose objects are dropped, in PDF space
+	snapGrid   float64       // Grid object coordinates are rounded to, 0 for none
+	emitSpaces bool          // Keep spaces as characters
+	file       io.ReaderAt   // File streams are read from as stored, nil when encrypted
 }
 
 // NewDsliPakPage creates a new page using dslipak/pdf
 func NewDsliPakPage(reader *gopdf.Reader, pageNumber int) (Page, error) {
 	if pageNumber < 1 || pageNumber > reader.NumPage() {
-		return nil, fmt.Errorf("invalid page number: %d", pageNumber)
+		return nil, fmt.Errorf("%w: number %d not in [1, %d]", ErrPageOutOfRange, pageNumber, reader.NumPage())
 	}
 	
 	page := reader.Page(pageNumber)
@@ -126,6 +422,7 @@ func NewDsliPakPage(reader *gopdf.Reader, pageNumber int) (Page, error) {
 		page:       page,
 		width:      width,
 		height:     height,
+		userUnit:   validUserUnit(page.V.Key("UserUnit").Float64()),
 		bbox: BoundingBox{
 			X0: 0,
 			Y0: 0,
@@ -134,16 +431,20 @@ func NewDsliPakPage(reader *gopdf.Reader, pageNumber int) (Page, error) {
 		},
 	}
 	
-	// Extract objects from the page
-	if err := p.extractObjects(); err != nil {
-		return nil, fmt.Errorf("failed to extract objects: %w", err)
-	}
-	
+	// Objects are extracted on first use so opening large documents stays cheap
 	return p, nil
 }
 
+// setPageBox overrides the page box, given in PDF space
+func (p *DsliPakPage) setPageBox(box BoundingBox) {
+	p.pageBox = &box
+	p.width, p.height = box.Width(), box.Height()
+	p.bbox = BoundingBox{X1: p.width, Y1: p.height}
+}
+
 // extractObjects extracts all objects from the page
 func (p *DsliPakPage) extractObjects() error {
+	p.extracted = true
 	p.objects = Objects{
 		Chars:  []CharObject{},
 		Lines:  []LineObject{},
@@ -153,16 +454,86 @@ func (p *DsliPakPage) extractObjects() error {
 		Annos:  []AnnotationObject{},
 	}
 	
-	// Extract text content
-	content := p.page.Content()
-	p.extractTextObjects(content)
+	// Extract text content, and the filled rectangles the library leaves
+	// out
+	content, marks, fills := p.content()
+	p.extractTextObjects(content, marks)
+	p.objects.Rects = append(p.objects.Rects, fills...)
+	if p.pageBox != nil {
+		p.objects = clipToPageBox(p.objects, -p.pageBox.X0, -p.pageBox.Y0, p.width, p.height)
+	}
+	p.objects = excludeRegions(p.objects, p.exclusions)
+	p.objects = snapToGrid(p.objects, p.snapGrid)
+	p.truncated = limitObjects(&p.objects, p.maxObjects)
 	
 	return nil
 }
 
+// dsliPakFont reads a font's widths and encoding through dslipak/pdf
+type dsliPakFont struct {
+	gopdf.Font
+	enc gopdf.TextEncoding
+}
+
+func (f dsliPakFont) decode(raw string) string {
+	if f.enc == nil {
+		return raw
+	}
+	return f.enc.Decode(raw)
+}
+
+// dsliPakContent reads the text of forms through dslipak/pdf
+var dsliPakContent = libraryContent[gopdf.Value]{
+	interpret: func(stream gopdf.Value, do func(string, []gopdf.Value)) {
+		// The library interprets one stream at a time
+		streams := []gopdf.Value{stream}
+		if stream.Kind() == gopdf.Array {
+			streams = streams[:0]
+			for i := 0; i < stream.Len(); i++ {
+				streams = append(streams, stream.Index(i))
+			}
+		}
+		for _, s := range streams {
+			gopdf.Interpret(s, func(stk *gopdf.Stack, op string) {
+				do(op, popOperands(stk.Len(), stk.Pop))
+			})
+		}
+	},
+	font: func(dict gopdf.Value) libraryFont {
+		font := gopdf.Font{V: dict}
+		return dsliPakFont{font, font.Encoder()}
+	},
+	tjBreak: false,
+}
+
+// content returns the page's text as the library reads it, with the text
+// of form XObjects and annotation appearances, which it skips, put in.
+// Tiling pattern cells are left out: the library does not read fills. The
+// marks give the render mode, marked content and language of each glyph of
+// the text. Glyphs of fonts without Widths get the widths of the standard
+// font they name.
+func (p *DsliPakPage) content() (gopdf.Content, []glyphMark, []RectObject) {
+	content := p.page.Content()
+	catalog := p.reader.Trailer().Key("Root")
+	langs := libraryStructTreeLanguages(catalog, p.page.V)
+	forms, marks, fills := dsliPakContent.formTexts(p.page.V, dsliPakInherited(p.page.V, "Resources"), catalog.Key("AcroForm").Key("DR"), langs)
+	content.Text, marks = spliceFormText(content.Text, marks, forms, func(text libraryText) gopdf.Text {
+		return gopdf.Text(text)
+	})
+	fillLibraryWidths(content.Text)
+	return content, marks, fills
+}
+
 // extractTextObjects extracts text objects from page content
-func (p *DsliPakPage) extractTextObjects(content gopdf.Content) {
-	for _, text := range content.Text {
+func (p *DsliPakPage) extractTextObjects(content gopdf.Content, marks []glyphMark) {
+	glyphs := make([]glyphPosition, len(content.Text))
+	for i, text := range content.Text {
+		glyphs[i] = glyphPosition{x: text.X, y: text.Y, w: text.W, size: text.FontSize}
+	}
+	angles := glyphAngles(glyphs)
+	
+	followsSpace := false
+	for i, text := range content.Text {
 		// Convert each text item to CharObjects
 		x := text.X
 		y := text.Y
@@ -170,8 +541,9 @@ func (p *DsliPakPage) extractTextObjects(content gopdf.Content) {
 		fontHeight := fontSize    // Approximate height as font size
 		
 		for _, ch := range text.S {
-			if ch == ' ' || ch == '\n' || ch == '\r' {
+			if ch == ' ' && !p.emitSpaces || ch == '\n' || ch == '\r' {
 				x += text.W / float64(len(text.S))
+				followsSpace = ch == ' '
 				continue
 			}
 			
@@ -187,8 +559,17 @@ func (p *DsliPakPage) extractTextObjects(content gopdf.Content) {
 				Y1:       y + fontHeight,
 				Width:    charWidth,
 				Height:   fontHeight,
-				Color:    Color{R: 0, G: 0, B: 0, A: 255}, // Default black color
+				Color:    marks[i].color,
+				Matrix:   baselineMatrix(angles[i], x, y),
+				
+				RenderMode:   marks[i].render,
+				Outlined:     strokesGlyphs(marks[i].render),
+				followsSpace: followsSpace,
+				mcid:         marks[i].mcid,
+				lang:         marks[i].lang,
+				order:        marks[i].order,
 			}
+			followsSpace = ch == ' '
 			
 			p.objects.Chars = append(p.objects.Chars, char)
 			x += charWidth
@@ -203,17 +584,27 @@ func (p *DsliPakPage) GetPageNumber() int {
 
 // GetWidth returns the page width
 func (p *DsliPakPage) GetWidth() float64 {
-	return p.width
+	return p.width * sizeScale(p.userUnit, p.physical)
 }
 
 // GetHeight returns the page height
 func (p *DsliPakPage) GetHeight() float64 {
-	return p.height
+	return p.height * sizeScale(p.userUnit, p.physical)
 }
 
 // GetRotation returns the page rotation in degrees
 func (p *DsliPakPage) GetRotation() int {
-	return 0 // TODO: Extract from page.Rotate if available
+	// The page's Rotate, which it may inherit, composed with Page.Rotate
+	rotate := dsliPakInherited(p.page.V, "Rotate")
+	if rotate.Kind() == gopdf.Integer {
+		return normalizeRotation(int(rotate.Int64()) + p.rotation)
+	}
+	return p.rotation
+}
+
+// UserUnit returns the size of a default user space unit in 1/72 inch
+func (p *DsliPakPage) UserUnit() float64 {
+	return p.userUnit
 }
 
 // GetBBox returns the page bounding box
@@ -223,6 +614,9 @@ func (p *DsliPakPage) GetBBox() BoundingBox {
 
 // GetObjects returns all objects on the page
 func (p *DsliPakPage) GetObjects() Objects {
+	if !p.extracted {
+		p.extractObjects()
+	}
 	return p.objects
 }
 
@@ -230,54 +624,117 @@ func (p *DsliPakPage) GetObjects() Objects {
 func (p *DsliPakPage) ExtractText(opts ...TextExtractionOption) string {
 	// Apply options
 	config := &textExtractionConfig{
-		Layout:     false,
-		XTolerance: 3.0,
-		YTolerance: 3.0,
+		Layout:               false,
+		XTolerance:           3.0,
+		YTolerance:           3.0,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
 	}
 	for _, opt := range opts {
 		opt(config)
 	}
 	
-	// If layout mode is enabled, use the text organizer
-	if config.Layout && len(p.objects.Chars) > 0 {
-		// This would use the TextOrganizer to preserve layout
-		// For now, we'll use the simple extraction
+	chars := p.GetObjects().Chars
+	if config.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, config.RotatedTextTolerance)
+	}
+	if config.ExcludeOutlined {
+		chars = filterOutlinedChars(chars)
+	}
+	
+	// If layout mode is enabled, place the characters on a grid
+	if config.Layout && len(chars) > 0 {
+		return formatLines(layoutText(charGrid(chars, p.bbox, false)), config)
+	}
+	
+	if config.ColumnDetection && len(chars) > 0 {
+		return formatLines(extractColumnText(chars, config, false), config)
+	}
+	
+	if config.ParagraphBreaks && len(chars) > 0 {
+		return formatLines(extractParagraphText(chars, config, false), config)
+	}
+	
+	// A cropped page has only the characters in its box, and rotated text
+	// is told apart by the characters' inferred baselines
+	if p.cropped || config.IgnoreRotatedText {
+		return formatLines(strings.Join(textLines(chars, config, false), "\n"), config)
+	}
+	
+	if config.ReadingOrder != ReadingOrderGeometric {
+		catalog := p.reader.Trailer().Key("Root")
+		if order, tagged := libraryStructTreeOrder(catalog, p.page.V); config.usesStructTree(order, tagged) {
+			return formatLines(strings.Join(structOrderLines(chars, order, config, false), "\n"), config)
+		}
 	}
 	
 	// Simple text extraction from content
-	content := p.page.Content()
+	content, marks, _ := p.content()
 	
 	var text strings.Builder
-	for _, item := range content.Text {
+	for i, item := range content.Text {
+		itemBox := BoundingBox{X0: item.X, Y0: item.Y, X1: item.X + item.W, Y1: item.Y + item.FontSize}
+		if p.pageBox != nil && !p.pageBox.Intersects(itemBox) || itemExcluded(itemBox, p.exclusions, p.pageBox) {
+			continue
+		}
+		if config.ExcludeOutlined && strokesGlyphs(marks[i].render) {
+			continue
+		}
 		text.WriteString(item.S)
 		if !strings.HasSuffix(item.S, " ") && !strings.HasSuffix(item.S, "\n") {
 			text.WriteString(" ")
 		}
 	}
 	
-	return text.String()
+	return formatLines(text.String(), config)
+}
+
+// ExtractTextSpans extracts text lines along with their page and position
+func (p *DsliPakPage) ExtractTextSpans(opts ...TextExtractionOption) []TextSpan {
+	return extractTextSpans(p.GetObjects().Chars, p.pageNumber-1, false, opts...)
+}
+
+// CharGrid returns the characters placed on the grid of layout-mode text
+func (p *DsliPakPage) CharGrid(opts ...TextExtractionOption) [][]rune {
+	return pageCharGrid(p.GetObjects().Chars, p.bbox, false, opts...)
 }
 
-// ExtractTables extracts tables from the page
+// ExtractTables extracts tables from the page. The library reads no
+// graphics, so tables are found by the text and explicit strategies only.
 func (p *DsliPakPage) ExtractTables(opts ...TableExtractionOption) []Table {
-	// TODO: Implement table extraction
-	return []Table{}
+	return newTableExtractor(p, opts...).ExtractTables()
 }
 
 // Crop returns a new page cropped to the specified bounding box
-func (p *DsliPakPage) Crop(bbox BoundingBox) Page {
-	// Create a new page with cropped dimensions
-	croppedPage := &DsliPakPage{
-		reader:     p.reader,
-		pageNumber: p.pageNumber,
-		page:       p.page,
-		width:      bbox.Width(),
-		height:     bbox.Height(),
-		bbox:       bbox,
-		objects:    p.filterObjectsInBBox(bbox),
-	}
+func (p *DsliPakPage) Crop(bbox BoundingBox, opts ...CropOption) Page {
+	objects, box := CropObjects(p.GetObjects(), bbox, opts...)
+	
+	cropped := *p
+	cropped.objects = objects
+	cropped.width, cropped.height = box.Width(), box.Height()
+	cropped.bbox = box
+	cropped.cropped = true
+	cropped.view = p.view.cropped(bbox, p.height, opts...)
+	cropped.words = wordCache{}
+	
+	return &cropped
+}
+
+// Rotate returns a new page rotated clockwise by a multiple of 90 degrees
+func (p *DsliPakPage) Rotate(degrees int) Page {
+	rotated := *p
+	rotated.objects, rotated.bbox = RotateObjectsInBox(p.GetObjects(), degrees, p.bbox, false)
+	rotated.width, rotated.height = rotatedSize(degrees, p.width, p.height)
+	rotated.rotation = normalizeRotation(p.rotation + degrees)
+	rotated.view = p.view.rotated(degrees, p.bbox, p.height)
+	rotated.words = wordCache{}
 	
-	return croppedPage
+	return &rotated
+}
+
+// Comments returns the page's comment and text markup annotations
+func (p *DsliPakPage) Comments() []Comment {
+	comments := libraryComments(p.page.V.Key("Annots"))
+	return placeComments(comments, p.GetObjects().Chars, p.pageBox, p.view, p.height, false)
 }
 
 // WithinBBox filters objects within a bounding box
@@ -296,19 +753,19 @@ func (p *DsliPakPage) Filter(predicate func(Object) bool) Objects {
 		Annos:  []AnnotationObject{},
 	}
 	
-	for _, obj := range p.objects.Chars {
+	for _, obj := range p.GetObjects().Chars {
 		if predicate(obj) {
 			filtered.Chars = append(filtered.Chars, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Lines {
+	for _, obj := range p.GetObjects().Lines {
 		if predicate(obj) {
 			filtered.Lines = append(filtered.Lines, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Rects {
+	for _, obj := range p.GetObjects().Rects {
 		if predicate(obj) {
 			filtered.Rects = append(filtered.Rects, obj)
 		}
@@ -317,24 +774,88 @@ func (p *DsliPakPage) Filter(predicate func(Object) bool) Objects {
 	return filtered
 }
 
+// LayerNames returns the names of the layers in the page's Properties
+// resources, in order of resource name. This backend does not read marked
+// content, so layers declared but not used are listed too.
+func (p *DsliPakPage) LayerNames() []string {
+	properties := p.page.Resources().Key("Properties")
+	var names []string
+	seen := make(map[string]bool)
+	for _, key := range properties.Keys() {
+		entry := properties.Key(key)
+		groups := []gopdf.Value{entry}
+		if entry.Key("Type").Name() == "OCMD" {
+			groups = []gopdf.Value{entry.Key("OCGs")}
+			if ocgs := entry.Key("OCGs"); ocgs.Kind() == gopdf.Array {
+				groups = groups[:0]
+				for i := 0; i < ocgs.Len(); i++ {
+					groups = append(groups, ocgs.Index(i))
+				}
+			}
+		}
+		for _, group := range groups {
+			name := group.Key("Name").Text()
+			if group.Key("Type").Name() == "OCG" && name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ExtractBetween returns the text of the page between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the page.
+func (p *DsliPakPage) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween([]Page{p}, false, start, end, opts)
+}
+
+// RecognizeText is not supported by this backend, which records no image
+// objects to find the scanned image of a page by
+func (p *DsliPakPage) RecognizeText(opts ...TextExtractionOption) (string, error) {
+	return "", fmt.Errorf("OCR: %w", ErrNotImplemented)
+}
+
+// PotentialRedactions returns the filled boxes painted over text, which is
+// hidden on the page but still extractable
+func (p *DsliPakPage) PotentialRedactions() ([]RedactionWarning, error) {
+	return potentialRedactions(p.GetObjects(), false), nil
+}
+
+// ColorRuns returns the page's text split into runs of one fill color
+func (p *DsliPakPage) ColorRuns() []ColoredRun {
+	return colorRuns(p.ExtractWords())
+}
+
 // ExtractWords extracts individual words from the page
 func (p *DsliPakPage) ExtractWords(opts ...WordExtractionOption) []Word {
 	// Apply options
 	config := &wordExtractionConfig{
-		XTolerance: 3.0,
-		YTolerance: 3.0,
+		XTolerance:           3.0,
+		YTolerance:           3.0,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
+		TextAngleThreshold:   defaultTextAngleThreshold,
 	}
 	for _, opt := range opts {
 		opt(config)
 	}
 	
-	if len(p.objects.Chars) == 0 {
+	if words, ok := p.words.get(config); ok {
+		return words
+	}
+	
+	chars := p.GetObjects().Chars
+	if config.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, config.RotatedTextTolerance)
+	}
+	if len(chars) == 0 {
 		return nil
 	}
 	
 	// Sort characters by position (top to bottom, left to right)
-	sortedChars := make([]CharObject, len(p.objects.Chars))
-	copy(sortedChars, p.objects.Chars)
+	sortedChars := make([]CharObject, len(chars))
+	copy(sortedChars, chars)
 	
 	sort.Slice(sortedChars, func(i, j int) bool {
 		// First sort by Y position (top to bottom)
@@ -371,15 +892,20 @@ func (p *DsliPakPage) ExtractWords(opts ...WordExtractionOption) []Word {
 	// Extract words from each line
 	var words []Word
 	for _, line := range lines {
-		lineWords := p.extractWordsFromLine(line, config.XTolerance)
+		lineWords := p.extractWordsFromLine(line, config)
 		words = append(words, lineWords...)
 	}
 	
+	if config.SeparateSuperscripts {
+		words = separateScripts(words, false)
+	}
+	words = scaleWords(words, config.Scale)
+	p.words.set(config, words)
 	return words
 }
 
 // extractWordsFromLine extracts words from a single line of characters
-func (p *DsliPakPage) extractWordsFromLine(lineChars []CharObject, xTolerance float64) []Word {
+func (p *DsliPakPage) extractWordsFromLine(lineChars []CharObject, config *wordExtractionConfig) []Word {
 	if len(lineChars) == 0 {
 		return nil
 	}
@@ -392,13 +918,25 @@ func (p *DsliPakPage) extractWordsFromLine(lineChars []CharObject, xTolerance fl
 	var words []Word
 	var currentWord []CharObject
 	
-	for i, char := range lineChars {
-		if i == 0 {
+	for _, char := range lineChars {
+		if char.Text == " " {
+			// A kept space ends the current word
+			if len(currentWord) > 0 {
+				words = append(words, p.createWord(currentWord))
+			}
+			currentWord = nil
+		} else if len(currentWord) == 0 {
 			currentWord = []CharObject{char}
 		} else {
 			// Check if this character starts a new word
-			gap := char.X0 - lineChars[i-1].X1
-			if gap > xTolerance || gap > char.Width*0.3 {
+			last := currentWord[len(currentWord)-1]
+			// Without MergeAdjacentChars, the gaps guessed from the equal
+			// widths the text items are split into also end words
+			split := splitsWord(char, last, config)
+			if !config.MergeAdjacentChars {
+				split = split || char.X0-last.X1 > char.Width*0.3
+			}
+			if split {
 				// Save current word and start new one
 				if len(currentWord) > 0 {
 					words = append(words, p.createWord(currentWord))
@@ -421,31 +959,51 @@ func (p *DsliPakPage) extractWordsFromLine(lineChars []CharObject, xTolerance fl
 // createWord creates a Word from a group of characters
 func (p *DsliPakPage) createWord(chars []CharObject) Word {
 	var text strings.Builder
-	minX, minY := chars[0].X0, chars[0].Y0
-	maxX, maxY := chars[0].X1, chars[0].Y1
+	bbox := chars[0].GetBBox().Normalize()
 	
 	for _, char := range chars {
 		text.WriteString(char.Text)
-		minX = min(minX, char.X0)
-		minY = min(minY, char.Y0)
-		maxX = max(maxX, char.X1)
-		maxY = max(maxY, char.Y1)
+		bbox = bbox.Union(char.GetBBox())
 	}
 	
 	return Word{
 		Text:       text.String(),
-		X0:         minX,
-		Y0:         minY,
-		X1:         maxX,
-		Y1:         maxY,
+		X0:         bbox.X0,
+		Y0:         bbox.Y0,
+		X1:         bbox.X1,
+		Y1:         bbox.Y1,
 		Characters: chars,
+		Confidence: wordConfidence(chars),
 	}
 }
 
 
-// ToImage renders the page to an image (for visual debugging)
+// WriteCharsJSONL writes each character as a line of JSON
+func (p *DsliPakPage) WriteCharsJSONL(w io.Writer) error {
+	return WriteCharsJSONL(w, p.GetObjects().Chars, p.pageNumber, p.height, false)
+}
+
+// Image returns the image XObject with the given resource name, with its
+// filters removed. Images in standalone formats such as JPEG are returned
+// as stored, read from the file past the library, which cannot decode them;
+// in encrypted documents they cannot be read through this backend.
+func (p *DsliPakPage) Image(name string) (ExtractedImage, error) {
+	xobject := p.page.Resources().Key("XObject").Key(name)
+	if xobject.Kind() != gopdf.Stream {
+		return ExtractedImage{}, fmt.Errorf("%w: %s", ErrImageNotFound, name)
+	}
+	return libraryImage(name, xobject, p.file, readDsliPakStream)
+}
+
+// ToImage renders the page's objects to a PNG (for visual debugging), with
+// glyphs drawn as boxes and images as placeholders
 func (p *DsliPakPage) ToImage(opts ...ImageOption) (io.Reader, error) {
-	return nil, fmt.Errorf("image rendering not yet implemented")
+	return renderPage(p.GetObjects(), p.bbox, false, opts)
+}
+
+// DrawDebug renders the page with the objects selected by opts outlined
+func (p *DsliPakPage) DrawDebug(opts DebugOptions) (io.Reader, error) {
+	return drawDebug(p, p.bbox, false, opts)
 }
 
 // filterObjectsInBBox filters objects that are within the given bounding box
@@ -459,23 +1017,66 @@ func (p *DsliPakPage) filterObjectsInBBox(bbox BoundingBox) Objects {
 		Annos:  []AnnotationObject{},
 	}
 	
-	for _, obj := range p.objects.Chars {
+	for _, obj := range p.GetObjects().Chars {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Chars = append(filtered.Chars, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Lines {
+	for _, obj := range p.GetObjects().Lines {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Lines = append(filtered.Lines, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Rects {
+	for _, obj := range p.GetObjects().Rects {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Rects = append(filtered.Rects, obj)
 		}
 	}
 	
 	return filtered
-}
\ No newline at end of file
+}
+
+// validationFacts collects what validation checks about the page. Images are
+// counted from the image XObjects in the page resources.
+func (p *DsliPakPage) validationFacts() pageFacts {
+	facts := pageFacts{
+		index:  p.pageNumber - 1,
+		width:  p.width,
+		height: p.height,
+		chars:  len(p.GetObjects().Chars),
+	}
+	if p.truncated {
+		facts.objectLimit = p.maxObjects
+	}
+	
+	facts.hasMediaBox = dsliPakInherited(p.page.V, "MediaBox").Kind() == gopdf.Array
+	
+	resources := p.page.Resources()
+	xobjects := resources.Key("XObject")
+	for _, name := range xobjects.Keys() {
+		if xobjects.Key(name).Key("Subtype").Name() == "Image" {
+			facts.images++
+		}
+	}
+	
+	fonts := resources.Key("Font")
+	for _, name := range fonts.Keys() {
+		font := fonts.Key(name)
+		encoding := font.Key("Encoding")
+		encodingName := encoding.Name()
+		if encoding.Kind() == gopdf.Dict {
+			encodingName = "Differences"
+		}
+		facts.fonts = append(facts.fonts, fontFacts{
+			name:      name,
+			baseFont:  font.Key("BaseFont").Name(),
+			encoding:  encodingName,
+			toUnicode: !font.Key("ToUnicode").IsNull(),
+		})
+	}
+	facts.formFonts = libraryFormFonts(resources, map[string]bool{})
+	
+	return facts
+}
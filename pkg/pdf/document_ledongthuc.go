@@ -3,6 +3,7 @@ package pdf
 import (
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -11,16 +12,26 @@ import (
 
 // LedongthucDocument implements the Document interface using ledongthuc/pdf library
 type LedongthucDocument struct {
-	file     io.Closer
+	file     *os.File
 	reader   *lpdf.Reader
 	filepath string
 	pages    []Page
 	metadata Metadata
+	config   *openConfig
 }
 
 // OpenWithLedongthuc opens a PDF file using the ledongthuc/pdf library
-func OpenWithLedongthuc(filepath string) (Document, error) {
-	f, r, err := lpdf.Open(filepath)
+func OpenWithLedongthuc(filepath string, opts ...OpenOption) (Document, error) {
+	config, err := newLibraryOpenConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	
+	var r *lpdf.Reader
+	f, err := readLibraryFile(filepath, config.parserOptions(), func(f *os.File, size int64) (err error) {
+		r, err = lpdf.NewReader(f, size)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to open PDF with ledongthuc: %w", err)
 	}
@@ -29,6 +40,7 @@ func OpenWithLedongthuc(filepath string) (Document, error) {
 		file:     f,
 		reader:   r,
 		filepath: filepath,
+		config:   config,
 	}
 	
 	// Extract metadata
@@ -43,18 +55,28 @@ func OpenWithLedongthuc(filepath string) (Document, error) {
 	return doc, nil
 }
 
-// extractMetadata extracts PDF metadata
+// extractMetadata extracts PDF metadata from the trailer's /Info dictionary
 func (d *LedongthucDocument) extractMetadata() {
-	// Extract metadata from trailer/info dictionary if available
-	d.metadata = Metadata{}
-	
-	// The ledongthuc/pdf library may have metadata in trailer
-	// This needs to be implemented based on the library's capabilities
+	info := d.reader.Trailer().Key("Info")
+	d.metadata = Metadata{
+		Title:        info.Key("Title").Text(),
+		Author:       info.Key("Author").Text(),
+		Subject:      info.Key("Subject").Text(),
+		Keywords:     info.Key("Keywords").Text(),
+		Creator:      info.Key("Creator").Text(),
+		Producer:     info.Key("Producer").Text(),
+		CreationDate: parsePDFDate(info.Key("CreationDate").RawString()),
+		ModDate:      parsePDFDate(info.Key("ModDate").RawString()),
+		Trapped:      info.Key("Trapped").Name(),
+	}
 }
 
 // initializePages initializes all pages in the document
 func (d *LedongthucDocument) initializePages() error {
-	pageCount := d.reader.NumPage()
+	pageCount, err := d.config.pageCount(d.reader.NumPage())
+	if err != nil {
+		return err
+	}
 	d.pages = make([]Page, pageCount)
 	
 	for i := 1; i <= pageCount; i++ {
@@ -62,6 +84,19 @@ func (d *LedongthucDocument) initializePages() error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize page %d: %w", i, err)
 		}
+		if p, ok := page.(*LedongthucPage); ok {
+			p.maxObjects = d.config.MaxObjectsPerPage
+			p.exclusions = d.config.ExcludeRegions
+			p.physical = d.config.PhysicalDimensions
+			p.snapGrid = d.config.SnapGrid
+			p.emitSpaces = d.config.EmitSpaces
+			if d.reader.Trailer().Key("Encrypt").IsNull() {
+				p.file = d.file
+			}
+			if box := d.config.PageBBox; box != nil {
+				p.setPageBox(*box)
+			}
+		}
 		d.pages[i-1] = page
 	}
 	
@@ -81,7 +116,7 @@ func (d *LedongthucDocument) GetPages() []Page {
 // GetPage returns a specific page by index (0-based)
 func (d *LedongthucDocument) GetPage(index int) (Page, error) {
 	if index < 0 || index >= len(d.pages) {
-		return nil, fmt.Errorf("page index %d out of range [0, %d)", index, len(d.pages))
+		return nil, fmt.Errorf("%w: index %d not in [0, %d)", ErrPageOutOfRange, index, len(d.pages))
 	}
 	return d.pages[index], nil
 }
@@ -91,6 +126,248 @@ func (d *LedongthucDocument) PageCount() int {
 	return len(d.pages)
 }
 
+// PageDimensions returns the size and rotation of every page, read from the
+// page dictionaries
+func (d *LedongthucDocument) PageDimensions() []PageDim {
+	dims := make([]PageDim, len(d.pages))
+	for i := range d.pages {
+		page := d.reader.Page(i + 1).V
+		mediaBox := ledongthucBox(ledongthucInherited(page, "MediaBox"))
+		cropBox := ledongthucBox(ledongthucInherited(page, "CropBox"))
+		rotate := int(ledongthucInherited(page, "Rotate").Int64())
+		dims[i] = pageDim(i, mediaBox, cropBox, d.config.PageBBox, rotate).withUserUnit(d.pages[i].UserUnit(), d.config.PhysicalDimensions)
+	}
+	return dims
+}
+
+// ledongthucInherited looks a key up in a page dictionary, then in the Pages
+// nodes above it, visiting at most maxPageTreeDepth dictionaries
+func ledongthucInherited(page lpdf.Value, key string) lpdf.Value {
+	v := page
+	for depth := 0; v.Kind() == lpdf.Dict && depth < maxPageTreeDepth; depth++ {
+		if value := v.Key(key); !value.IsNull() {
+			return value
+		}
+		v = v.Key("Parent")
+	}
+	return lpdf.Value{}
+}
+
+// ledongthucBox converts a rectangle array to a bounding box, nil if it is not one
+func ledongthucBox(rect lpdf.Value) *BoundingBox {
+	if rect.Kind() != lpdf.Array || rect.Len() != 4 {
+		return nil
+	}
+	return &BoundingBox{
+		X0: rect.Index(0).Float64(),
+		Y0: rect.Index(1).Float64(),
+		X1: rect.Index(2).Float64(),
+		Y1: rect.Index(3).Float64(),
+	}
+}
+
+// Validate reports structural problems that affect extraction
+func (d *LedongthucDocument) Validate() []ValidationIssue {
+	var issues []ValidationIssue
+	if !d.reader.Trailer().Key("Encrypt").IsNull() {
+		issues = append(issues, ValidationIssue{Severity: SeverityInfo, PageIndex: -1, Message: "document is encrypted"})
+	}
+	for _, page := range d.pages {
+		if p, ok := page.(*LedongthucPage); ok {
+			issues = append(issues, validatePage(p.validationFacts())...)
+		}
+	}
+	return issues
+}
+
+// Info returns an overview of the document: its metadata, object counts of
+// its pages, encryption, tagging, fonts and validation issues
+func (d *LedongthucDocument) Info() DocumentInfo {
+	trailer := d.reader.Trailer()
+	tagged := trailer.Key("Root").Key("MarkInfo").Key("Marked").Bool()
+	return documentInfo(d, !trailer.Key("Encrypt").IsNull(), tagged)
+}
+
+// Search finds a pattern, a regular expression unless configured otherwise,
+// in the text of all pages
+func (d *LedongthucDocument) Search(pattern string, opts ...SearchOption) []DocSearchMatch {
+	return searchDocument(d.pages, true, pattern, opts)
+}
+
+// ExtractBetween returns the text of the document between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the document.
+func (d *LedongthucDocument) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween(d.pages, true, start, end, opts)
+}
+
+// ExtractAllTables extracts the tables of all pages with the given number of
+// workers, one per CPU if fewer than one, keyed by page index from 0
+func (d *LedongthucDocument) ExtractAllTables(workers int, opts ...TableExtractionOption) map[int][]Table {
+	return extractAllTables(d.pages, workers, opts)
+}
+
+// Language returns the natural language of the document from the catalog's
+// /Lang, empty if not given
+func (d *LedongthucDocument) Language() string {
+	return d.reader.Trailer().Key("Root").Key("Lang").Text()
+}
+
+// Attachments returns the files embedded in the document
+func (d *LedongthucDocument) Attachments() []Attachment {
+	var attachments []Attachment
+	names := d.reader.Trailer().Key("Root").Key("Names").Key("EmbeddedFiles")
+	walkLedongthucNameTree(names, 0, map[string]bool{}, func(name string, spec lpdf.Value) {
+		stream := spec.Key("EF").Key("UF")
+		if stream.Kind() != lpdf.Stream {
+			stream = spec.Key("EF").Key("F")
+		}
+		data, err := readLedongthucStream(stream)
+		if err != nil {
+			return
+		}
+		
+		if fileName := spec.Key("UF").Text(); fileName != "" {
+			name = fileName
+		} else if fileName := spec.Key("F").Text(); fileName != "" {
+			name = fileName
+		}
+		
+		attachments = append(attachments, Attachment{
+			Name:     name,
+			MIMEType: stream.Key("Subtype").Name(),
+			ModDate:  parsePDFDate(stream.Key("Params").Key("ModDate").RawString()),
+			Data:     data,
+		})
+	})
+	return attachments
+}
+
+// FormFields returns the terminal fields of the document's interactive
+// form. Fields without a /V take their value from the text of their
+// widget's normal appearance stream.
+func (d *LedongthucDocument) FormFields() []FormField {
+	var fields []FormField
+	form := d.reader.Trailer().Key("Root").Key("AcroForm")
+	roots := form.Key("Fields")
+	for i := 0; i < roots.Len(); i++ {
+		walkLedongthucFormFields(roots.Index(i), FormField{}, "", 0, &fields)
+	}
+	return fields
+}
+
+// walkLedongthucFormFields collects the terminal fields below a field tree
+// node. parent carries the inherited name and type, value the inherited /V.
+func walkLedongthucFormFields(node lpdf.Value, parent FormField, value string, depth int, fields *[]FormField) {
+	if node.Kind() != lpdf.Dict || depth > maxFieldTreeDepth {
+		return
+	}
+	
+	field := FormField{Name: qualifiedFieldName(parent.Name, node.Key("T").Text()), Type: parent.Type}
+	if ft := node.Key("FT").Name(); ft != "" {
+		field.Type = ft
+	}
+	if v := node.Key("V"); !v.IsNull() {
+		value = ledongthucFieldValue(v)
+	}
+	
+	// Kids with a /T are fields of their own, the others are widgets
+	kids := node.Key("Kids")
+	var widgets []lpdf.Value
+	hasFieldKids := false
+	for i := 0; i < kids.Len(); i++ {
+		kid := kids.Index(i)
+		if kid.Kind() != lpdf.Dict {
+			continue
+		}
+		if !kid.Key("T").IsNull() {
+			hasFieldKids = true
+			walkLedongthucFormFields(kid, field, value, depth+1, fields)
+		} else {
+			widgets = append(widgets, kid)
+		}
+	}
+	if hasFieldKids && len(widgets) == 0 {
+		return
+	}
+	
+	widget := node
+	if len(widgets) > 0 {
+		widget = widgets[0]
+	}
+	if rect := widget.Key("Rect"); rect.Len() == 4 {
+		field.BBox = BoundingBox{
+			X0: rect.Index(0).Float64(),
+			Y0: rect.Index(1).Float64(),
+			X1: rect.Index(2).Float64(),
+			Y1: rect.Index(3).Float64(),
+		}.Normalize()
+	}
+	field.Value = value
+	if field.Value == "" {
+		if data, err := readLedongthucStream(widget.Key("AP").Key("N")); err == nil {
+			// The library's font objects cannot be handed to the parser
+			field.Value = appearanceText(nil, nil, data, 0)
+		}
+	}
+	*fields = append(*fields, field)
+}
+
+// ledongthucFieldValue converts a field's /V to text: strings as is, names for
+// check boxes and radio buttons, and the entries of multiple selections joined
+func ledongthucFieldValue(v lpdf.Value) string {
+	switch v.Kind() {
+	case lpdf.Name:
+		return v.Name()
+	case lpdf.Array:
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if s := ledongthucFieldValue(v.Index(i)); s != "" {
+				values = append(values, s)
+			}
+		}
+		return strings.Join(values, ", ")
+	}
+	return v.Text()
+}
+
+// walkLedongthucNameTree calls fn for each key and value of a name tree.
+// Nodes are told apart by their String, which shows references unresolved,
+// so that a node listed among several /Kids is walked once.
+func walkLedongthucNameTree(node lpdf.Value, depth int, seen map[string]bool, fn func(name string, value lpdf.Value)) {
+	if node.Kind() != lpdf.Dict || depth > maxNameTreeDepth || seen[node.String()] {
+		return
+	}
+	seen[node.String()] = true
+	
+	names := node.Key("Names")
+	for i := 0; i+1 < names.Len(); i += 2 {
+		fn(names.Index(i).Text(), names.Index(i+1))
+	}
+	
+	kids := node.Key("Kids")
+	for i := 0; i < kids.Len(); i++ {
+		walkLedongthucNameTree(kids.Index(i), depth+1, seen, fn)
+	}
+}
+
+// readLedongthucStream reads and decodes a stream. The library panics on
+// filters it does not support, which is reported as an error instead.
+func readLedongthucStream(stream lpdf.Value) (data []byte, err error) {
+	if stream.Kind() != lpdf.Stream {
+		return nil, fmt.Errorf("not a stream")
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to decode stream: %v", r)
+		}
+	}()
+	
+	rc := stream.Reader()
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
 // Close releases resources associated with the document
 func (d *LedongthucDocument) Close() error {
 	if d.file != nil {
@@ -107,13 +384,27 @@ type LedongthucPage struct {
 	width      float64
 	height     float64
 	bbox       BoundingBox
+	rotation   int     // Rotation applied on top of the page's /Rotate
+	userUnit   float64 // Size of a default user space unit in 1/72 inch
+	physical   bool    // Report the size in physical points, scaled by userUnit
 	objects    Objects
+	extracted  bool     // objects are extracted lazily on first use
+	cropped    bool     // Objects are those of a crop box, not the whole page content
+	view       pageView // Crops and rotations applied since the page was read
+	words      wordCache
+	maxObjects int           // Objects kept, 0 for no limit
+	truncated  bool          // Objects past maxObjects were dropped
+	pageBox    *BoundingBox  // Overriding page box in PDF space, nil for the MediaBox
+	exclusions []BoundingBox // Areas whose objects are dropped, in PDF space
+	snapGrid   float64       // Grid object coordinates are rounded to, 0 for none
+	emitSpaces bool          // Keep spaces as characters
+	file       io.ReaderAt   // File streams are read from as stored, nil when encrypted
 }
 
 // NewLedongthucPage creates a new page using ledongthuc/pdf
 func NewLedongthucPage(reader *lpdf.Reader, pageNumber int) (Page, error) {
 	if pageNumber < 1 || pageNumber > reader.NumPage() {
-		return nil, fmt.Errorf("invalid page number: %d", pageNumber)
+		return nil, fmt.Errorf("%w: number %d not in [1, %d]", ErrPageOutOfRange, pageNumber, reader.NumPage())
 	}
 	
 	page := reader.Page(pageNumber)
@@ -139,6 +430,7 @@ func NewLedongthucPage(reader *lpdf.Reader, pageNumber int) (Page, error) {
 		page:       page,
 		width:      width,
 		height:     height,
+		userUnit:   validUserUnit(page.V.Key("UserUnit").Float64()),
 		bbox: BoundingBox{
 			X0: 0,
 			Y0: 0,
@@ -147,16 +439,20 @@ func NewLedongthucPage(reader *lpdf.Reader, pageNumber int) (Page, error) {
 		},
 	}
 	
-	// Extract objects from the page
-	if err := p.extractObjects(); err != nil {
-		return nil, fmt.Errorf("failed to extract objects: %w", err)
-	}
-	
+	// Objects are extracted on first use so opening large documents stays cheap
 	return p, nil
 }
 
+// setPageBox overrides the page box, given in PDF space
+func (p *LedongthucPage) setPageBox(box BoundingBox) {
+	p.pageBox = &box
+	p.width, p.height = box.Width(), box.Height()
+	p.bbox = BoundingBox{X1: p.width, Y1: p.height}
+}
+
 // extractObjects extracts all objects from the page
 func (p *LedongthucPage) extractObjects() error {
+	p.extracted = true
 	p.objects = Objects{
 		Chars:  []CharObject{},
 		Lines:  []LineObject{},
@@ -166,16 +462,80 @@ func (p *LedongthucPage) extractObjects() error {
 		Annos:  []AnnotationObject{},
 	}
 	
-	// Extract text content
-	content := p.page.Content()
-	p.extractTextObjects(content)
+	// Extract text content, and the filled rectangles the library leaves
+	// out, with Y inverted like the characters'
+	content, marks, fills := p.content()
+	p.extractTextObjects(content, marks)
+	for _, rect := range fills {
+		rect.Y0, rect.Y1 = p.height-rect.Y1, p.height-rect.Y0
+		p.objects.Rects = append(p.objects.Rects, rect)
+	}
+	if p.pageBox != nil {
+		// Y was inverted against the box's height rather than its top
+		p.objects = clipToPageBox(p.objects, -p.pageBox.X0, p.pageBox.Y0, p.width, p.height)
+	}
+	p.objects = excludeRegions(p.objects, flipRegions(p.exclusions, p.height))
+	p.objects = snapToGrid(p.objects, p.snapGrid)
+	p.truncated = limitObjects(&p.objects, p.maxObjects)
 	
 	return nil
 }
 
+// ledongthucFont reads a font's widths and encoding through ledongthuc/pdf
+type ledongthucFont struct {
+	lpdf.Font
+	enc lpdf.TextEncoding
+}
+
+func (f ledongthucFont) decode(raw string) string {
+	if f.enc == nil {
+		return raw
+	}
+	return f.enc.Decode(raw)
+}
+
+// ledongthucContent reads the text of forms through ledongthuc/pdf
+var ledongthucContent = libraryContent[lpdf.Value]{
+	interpret: func(stream lpdf.Value, do func(string, []lpdf.Value)) {
+		lpdf.Interpret(stream, func(stk *lpdf.Stack, op string) {
+			do(op, popOperands(stk.Len(), stk.Pop))
+		})
+	},
+	font: func(dict lpdf.Value) libraryFont {
+		font := lpdf.Font{V: dict}
+		return ledongthucFont{font, font.Encoder()}
+	},
+	tjBreak: true,
+}
+
+// content returns the page's text as the library reads it, with the text
+// of form XObjects and annotation appearances, which it skips, put in.
+// Tiling pattern cells are left out: the library does not read fills. The
+// marks give the render mode, marked content and language of each glyph of
+// the text. Glyphs of fonts without Widths get the widths of the standard
+// font they name.
+func (p *LedongthucPage) content() (lpdf.Content, []glyphMark, []RectObject) {
+	content := p.page.Content()
+	catalog := p.reader.Trailer().Key("Root")
+	langs := libraryStructTreeLanguages(catalog, p.page.V)
+	forms, marks, fills := ledongthucContent.formTexts(p.page.V, ledongthucInherited(p.page.V, "Resources"), catalog.Key("AcroForm").Key("DR"), langs)
+	content.Text, marks = spliceFormText(content.Text, marks, forms, func(text libraryText) lpdf.Text {
+		return lpdf.Text(text)
+	})
+	fillLibraryWidths(content.Text)
+	return content, marks, fills
+}
+
 // extractTextObjects extracts text objects from page content
-func (p *LedongthucPage) extractTextObjects(content lpdf.Content) {
-	for _, text := range content.Text {
+func (p *LedongthucPage) extractTextObjects(content lpdf.Content, marks []glyphMark) {
+	glyphs := make([]glyphPosition, len(content.Text))
+	for i, text := range content.Text {
+		glyphs[i] = glyphPosition{x: text.X, y: text.Y, w: text.W, size: text.FontSize}
+	}
+	angles := glyphAngles(glyphs)
+	
+	followsSpace := false
+	for i, text := range content.Text {
 		// For pdfplumber compatibility, we need to:
 		// 1. Invert Y coordinates (PDF uses bottom-left, pdfplumber uses top-left)
 		// 2. Extract individual characters with their positions
@@ -207,23 +567,36 @@ func (p *LedongthucPage) extractTextObjects(content lpdf.Content) {
 		x := text.X
 		
 		for _, ch := range chars {
-			// Skip space characters as they're used for word separation
-			if ch != ' ' {
+			// Skip space characters as they're used for word separation,
+			// unless spaces are kept. The line breaks the library adds at
+			// BT, T* and the end of each TJ are no glyphs: they are always
+			// skipped, as the dslipak backend does, and split no words.
+			space := ch == ' ' || ch == '\n' || ch == '\r'
+			if !space || ch == ' ' && p.emitSpaces {
 				char := CharObject{
-					Text:     string(ch),
-					Font:     text.Font,
-					FontSize: fontSize, // Use actual font size from PDF
-					X0:       x,
-					Y0:       y0_plumber,
-					X1:       x + charWidth,
-					Y1:       y0_plumber + fontHeight,
-					Width:    charWidth,
-					Height:   fontHeight,
-					Color:    Color{R: 0, G: 0, B: 0, A: 255},
+					Text:         string(ch),
+					Font:         text.Font,
+					FontSize:     fontSize, // Use actual font size from PDF
+					X0:           x,
+					Y0:           y0_plumber,
+					X1:           x + charWidth,
+					Y1:           y0_plumber + fontHeight,
+					Width:        charWidth,
+					Height:       fontHeight,
+					Color:        marks[i].color,
+					Matrix:       baselineMatrix(angles[i], x, text.Y),
+					RenderMode:   marks[i].render,
+					Outlined:     strokesGlyphs(marks[i].render),
+					followsSpace: followsSpace,
+					descent:      y_top_pdf - fontHeight - y_baseline_pdf,
+					mcid:         marks[i].mcid,
+					lang:         marks[i].lang,
+					order:        marks[i].order,
 				}
 				
 				p.objects.Chars = append(p.objects.Chars, char)
 			}
+			followsSpace = ch == ' '
 			x += charWidth
 		}
 	}
@@ -236,22 +609,27 @@ func (p *LedongthucPage) GetPageNumber() int {
 
 // GetWidth returns the page width
 func (p *LedongthucPage) GetWidth() float64 {
-	return p.width
+	return p.width * sizeScale(p.userUnit, p.physical)
 }
 
 // GetHeight returns the page height
 func (p *LedongthucPage) GetHeight() float64 {
-	return p.height
+	return p.height * sizeScale(p.userUnit, p.physical)
 }
 
 // GetRotation returns the page rotation in degrees
 func (p *LedongthucPage) GetRotation() int {
-	// Check for Rotate key in page dictionary
-	rotate := p.page.V.Key("Rotate")
+	// The page's Rotate, which it may inherit, composed with Page.Rotate
+	rotate := ledongthucInherited(p.page.V, "Rotate")
 	if rotate.Kind() == lpdf.Integer {
-		return int(rotate.Int64())
+		return normalizeRotation(int(rotate.Int64()) + p.rotation)
 	}
-	return 0
+	return p.rotation
+}
+
+// UserUnit returns the size of a default user space unit in 1/72 inch
+func (p *LedongthucPage) UserUnit() float64 {
+	return p.userUnit
 }
 
 // GetBBox returns the page bounding box
@@ -261,6 +639,9 @@ func (p *LedongthucPage) GetBBox() BoundingBox {
 
 // GetObjects returns all objects on the page
 func (p *LedongthucPage) GetObjects() Objects {
+	if !p.extracted {
+		p.extractObjects()
+	}
 	return p.objects
 }
 
@@ -268,46 +649,114 @@ func (p *LedongthucPage) GetObjects() Objects {
 func (p *LedongthucPage) ExtractText(opts ...TextExtractionOption) string {
 	// Apply options
 	config := &textExtractionConfig{
-		Layout:     false,
-		XTolerance: 3.0,
-		YTolerance: 3.0,
+		Layout:               false,
+		XTolerance:           3.0,
+		YTolerance:           3.0,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
 	}
 	for _, opt := range opts {
 		opt(config)
 	}
 	
+	chars := p.GetObjects().Chars
+	if config.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, config.RotatedTextTolerance)
+	}
+	if config.ExcludeOutlined {
+		chars = filterOutlinedChars(chars)
+	}
+	
+	if config.Layout && len(chars) > 0 {
+		return formatLines(layoutText(charGrid(chars, p.bbox, true)), config)
+	}
+	
+	if config.ColumnDetection && len(chars) > 0 {
+		return formatLines(extractColumnText(chars, config, true), config)
+	}
+	
+	if config.ParagraphBreaks && len(chars) > 0 {
+		return formatLines(extractParagraphText(chars, config, true), config)
+	}
+	
+	// A cropped page has only the characters in its box, and rotated text
+	// is told apart by the characters' inferred baselines
+	if p.cropped || config.IgnoreRotatedText {
+		return formatLines(strings.Join(textLines(chars, config, true), "\n"), config)
+	}
+	
+	if config.ReadingOrder != ReadingOrderGeometric {
+		catalog := p.reader.Trailer().Key("Root")
+		if order, tagged := libraryStructTreeOrder(catalog, p.page.V); config.usesStructTree(order, tagged) {
+			return formatLines(strings.Join(structOrderLines(chars, order, config, true), "\n"), config)
+		}
+	}
+	
 	// Simple text extraction from content
-	content := p.page.Content()
+	content, marks, _ := p.content()
 	
 	var text strings.Builder
-	for _, item := range content.Text {
+	for i, item := range content.Text {
+		itemBox := BoundingBox{X0: item.X, Y0: item.Y, X1: item.X + item.W, Y1: item.Y + item.FontSize}
+		if p.pageBox != nil && !p.pageBox.Intersects(itemBox) || itemExcluded(itemBox, p.exclusions, p.pageBox) {
+			continue
+		}
+		if config.ExcludeOutlined && strokesGlyphs(marks[i].render) {
+			continue
+		}
 		text.WriteString(item.S)
 		// ledongthuc/pdf already handles spacing properly
 	}
 	
-	return text.String()
+	return formatLines(text.String(), config)
 }
 
-// ExtractTables extracts tables from the page
+// ExtractTextSpans extracts text lines along with their page and position
+func (p *LedongthucPage) ExtractTextSpans(opts ...TextExtractionOption) []TextSpan {
+	return extractTextSpans(p.GetObjects().Chars, p.pageNumber-1, true, opts...)
+}
+
+// CharGrid returns the characters placed on the grid of layout-mode text
+func (p *LedongthucPage) CharGrid(opts ...TextExtractionOption) [][]rune {
+	return pageCharGrid(p.GetObjects().Chars, p.bbox, true, opts...)
+}
+
+// ExtractTables extracts tables from the page. The library reads no
+// graphics, so tables are found by the text and explicit strategies only.
 func (p *LedongthucPage) ExtractTables(opts ...TableExtractionOption) []Table {
-	// TODO: Implement table extraction
-	return []Table{}
+	return extractTopDownTables(p, p.height, opts...)
 }
 
 // Crop returns a new page cropped to the specified bounding box
-func (p *LedongthucPage) Crop(bbox BoundingBox) Page {
-	// Create a new page with cropped dimensions
-	croppedPage := &LedongthucPage{
-		reader:     p.reader,
-		pageNumber: p.pageNumber,
-		page:       p.page,
-		width:      bbox.Width(),
-		height:     bbox.Height(),
-		bbox:       bbox,
-		objects:    p.filterObjectsInBBox(bbox),
-	}
+func (p *LedongthucPage) Crop(bbox BoundingBox, opts ...CropOption) Page {
+	objects, box := CropObjects(p.GetObjects(), bbox, opts...)
+	
+	cropped := *p
+	cropped.objects = objects
+	cropped.width, cropped.height = box.Width(), box.Height()
+	cropped.bbox = box
+	cropped.cropped = true
+	cropped.view = p.view.cropped(bbox, p.height, opts...)
+	cropped.words = wordCache{}
+	
+	return &cropped
+}
+
+// Rotate returns a new page rotated clockwise by a multiple of 90 degrees
+func (p *LedongthucPage) Rotate(degrees int) Page {
+	rotated := *p
+	rotated.objects, rotated.bbox = RotateObjectsInBox(p.GetObjects(), degrees, p.bbox, true)
+	rotated.width, rotated.height = rotatedSize(degrees, p.width, p.height)
+	rotated.rotation = normalizeRotation(p.rotation + degrees)
+	rotated.view = p.view.rotated(degrees, p.bbox, p.height)
+	rotated.words = wordCache{}
 	
-	return croppedPage
+	return &rotated
+}
+
+// Comments returns the page's comment and text markup annotations
+func (p *LedongthucPage) Comments() []Comment {
+	comments := libraryComments(p.page.V.Key("Annots"))
+	return placeComments(comments, p.GetObjects().Chars, p.pageBox, p.view, p.height, true)
 }
 
 // WithinBBox filters objects within a bounding box
@@ -326,19 +775,19 @@ func (p *LedongthucPage) Filter(predicate func(Object) bool) Objects {
 		Annos:  []AnnotationObject{},
 	}
 	
-	for _, obj := range p.objects.Chars {
+	for _, obj := range p.GetObjects().Chars {
 		if predicate(obj) {
 			filtered.Chars = append(filtered.Chars, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Lines {
+	for _, obj := range p.GetObjects().Lines {
 		if predicate(obj) {
 			filtered.Lines = append(filtered.Lines, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Rects {
+	for _, obj := range p.GetObjects().Rects {
 		if predicate(obj) {
 			filtered.Rects = append(filtered.Rects, obj)
 		}
@@ -347,24 +796,88 @@ func (p *LedongthucPage) Filter(predicate func(Object) bool) Objects {
 	return filtered
 }
 
+// LayerNames returns the names of the layers in the page's Properties
+// resources, in order of resource name. This backend does not read marked
+// content, so layers declared but not used are listed too.
+func (p *LedongthucPage) LayerNames() []string {
+	properties := p.page.Resources().Key("Properties")
+	var names []string
+	seen := make(map[string]bool)
+	for _, key := range properties.Keys() {
+		entry := properties.Key(key)
+		groups := []lpdf.Value{entry}
+		if entry.Key("Type").Name() == "OCMD" {
+			groups = []lpdf.Value{entry.Key("OCGs")}
+			if ocgs := entry.Key("OCGs"); ocgs.Kind() == lpdf.Array {
+				groups = groups[:0]
+				for i := 0; i < ocgs.Len(); i++ {
+					groups = append(groups, ocgs.Index(i))
+				}
+			}
+		}
+		for _, group := range groups {
+			name := group.Key("Name").Text()
+			if group.Key("Type").Name() == "OCG" && name != "" && !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// ExtractBetween returns the text of the page between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the page.
+func (p *LedongthucPage) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween([]Page{p}, true, start, end, opts)
+}
+
+// RecognizeText is not supported by this backend, which records no image
+// objects to find the scanned image of a page by
+func (p *LedongthucPage) RecognizeText(opts ...TextExtractionOption) (string, error) {
+	return "", fmt.Errorf("OCR: %w", ErrNotImplemented)
+}
+
+// PotentialRedactions returns the filled boxes painted over text, which is
+// hidden on the page but still extractable
+func (p *LedongthucPage) PotentialRedactions() ([]RedactionWarning, error) {
+	return potentialRedactions(p.GetObjects(), true), nil
+}
+
+// ColorRuns returns the page's text split into runs of one fill color
+func (p *LedongthucPage) ColorRuns() []ColoredRun {
+	return colorRuns(p.ExtractWords())
+}
+
 // ExtractWords extracts individual words from the page
 func (p *LedongthucPage) ExtractWords(opts ...WordExtractionOption) []Word {
 	// Apply options
 	config := &wordExtractionConfig{
-		XTolerance: 3.0,
-		YTolerance: 3.0,
+		XTolerance:           3.0,
+		YTolerance:           3.0,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
+		TextAngleThreshold:   defaultTextAngleThreshold,
 	}
 	for _, opt := range opts {
 		opt(config)
 	}
 	
-	if len(p.objects.Chars) == 0 {
+	if words, ok := p.words.get(config); ok {
+		return words
+	}
+	
+	chars := p.GetObjects().Chars
+	if config.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, config.RotatedTextTolerance)
+	}
+	if len(chars) == 0 {
 		return nil
 	}
 	
 	// Sort characters by position (top to bottom, left to right)
-	sortedChars := make([]CharObject, len(p.objects.Chars))
-	copy(sortedChars, p.objects.Chars)
+	sortedChars := make([]CharObject, len(chars))
+	copy(sortedChars, chars)
 	
 	sort.Slice(sortedChars, func(i, j int) bool {
 		// First sort by Y position (top to bottom)
@@ -401,15 +914,20 @@ func (p *LedongthucPage) ExtractWords(opts ...WordExtractionOption) []Word {
 	// Extract words from each line
 	var words []Word
 	for _, line := range lines {
-		lineWords := p.extractWordsFromLine(line, config.XTolerance)
+		lineWords := p.extractWordsFromLine(line, config)
 		words = append(words, lineWords...)
 	}
 	
+	if config.SeparateSuperscripts {
+		words = separateScripts(words, true)
+	}
+	words = scaleWords(words, config.Scale)
+	p.words.set(config, words)
 	return words
 }
 
 // extractWordsFromLine extracts words from a single line of characters
-func (p *LedongthucPage) extractWordsFromLine(lineChars []CharObject, xTolerance float64) []Word {
+func (p *LedongthucPage) extractWordsFromLine(lineChars []CharObject, config *wordExtractionConfig) []Word {
 	if len(lineChars) == 0 {
 		return nil
 	}
@@ -422,13 +940,25 @@ func (p *LedongthucPage) extractWordsFromLine(lineChars []CharObject, xTolerance
 	var words []Word
 	var currentWord []CharObject
 	
-	for i, char := range lineChars {
-		if i == 0 {
+	for _, char := range lineChars {
+		if char.Text == " " {
+			// A kept space ends the current word
+			if len(currentWord) > 0 {
+				words = append(words, p.createWord(currentWord))
+			}
+			currentWord = nil
+		} else if len(currentWord) == 0 {
 			currentWord = []CharObject{char}
 		} else {
 			// Check if this character starts a new word
-			gap := char.X0 - lineChars[i-1].X1
-			if gap > xTolerance || gap > char.Width*0.3 {
+			last := currentWord[len(currentWord)-1]
+			// Without MergeAdjacentChars, the gaps guessed from the equal
+			// widths the text items are split into also end words
+			split := splitsWord(char, last, config)
+			if !config.MergeAdjacentChars {
+				split = split || char.X0-last.X1 > char.Width*0.3
+			}
+			if split {
 				// Save current word and start new one
 				if len(currentWord) > 0 {
 					words = append(words, p.createWord(currentWord))
@@ -451,31 +981,51 @@ func (p *LedongthucPage) extractWordsFromLine(lineChars []CharObject, xTolerance
 // createWord creates a Word from a group of characters
 func (p *LedongthucPage) createWord(chars []CharObject) Word {
 	var text strings.Builder
-	minX, minY := chars[0].X0, chars[0].Y0
-	maxX, maxY := chars[0].X1, chars[0].Y1
+	bbox := chars[0].GetBBox().Normalize()
 	
 	for _, char := range chars {
 		text.WriteString(char.Text)
-		minX = min(minX, char.X0)
-		minY = min(minY, char.Y0)
-		maxX = max(maxX, char.X1)
-		maxY = max(maxY, char.Y1)
+		bbox = bbox.Union(char.GetBBox())
 	}
 	
 	return Word{
 		Text:       text.String(),
-		X0:         minX,
-		Y0:         minY,
-		X1:         maxX,
-		Y1:         maxY,
+		X0:         bbox.X0,
+		Y0:         bbox.Y0,
+		X1:         bbox.X1,
+		Y1:         bbox.Y1,
 		Characters: chars,
+		Confidence: wordConfidence(chars),
 	}
 }
 
 
-// ToImage renders the page to an image (for visual debugging)
+// WriteCharsJSONL writes each character as a line of JSON
+func (p *LedongthucPage) WriteCharsJSONL(w io.Writer) error {
+	return WriteCharsJSONL(w, p.GetObjects().Chars, p.pageNumber, p.height, true)
+}
+
+// Image returns the image XObject with the given resource name, with its
+// filters removed. Images in standalone formats such as JPEG are returned
+// as stored, read from the file past the library, which cannot decode them;
+// in encrypted documents they cannot be read through this backend.
+func (p *LedongthucPage) Image(name string) (ExtractedImage, error) {
+	xobject := p.page.Resources().Key("XObject").Key(name)
+	if xobject.Kind() != lpdf.Stream {
+		return ExtractedImage{}, fmt.Errorf("%w: %s", ErrImageNotFound, name)
+	}
+	return libraryImage(name, xobject, p.file, readLedongthucStream)
+}
+
+// ToImage renders the page's objects to a PNG (for visual debugging), with
+// glyphs drawn as boxes and images as placeholders
 func (p *LedongthucPage) ToImage(opts ...ImageOption) (io.Reader, error) {
-	return nil, fmt.Errorf("image rendering not yet implemented")
+	return renderPage(p.GetObjects(), p.bbox, true, opts)
+}
+
+// DrawDebug renders the page with the objects selected by opts outlined
+func (p *LedongthucPage) DrawDebug(opts DebugOptions) (io.Reader, error) {
+	return drawDebug(p, p.bbox, true, opts)
 }
 
 // filterObjectsInBBox filters objects that are within the given bounding box
@@ -489,23 +1039,66 @@ func (p *LedongthucPage) filterObjectsInBBox(bbox BoundingBox) Objects {
 		Annos:  []AnnotationObject{},
 	}
 	
-	for _, obj := range p.objects.Chars {
+	for _, obj := range p.GetObjects().Chars {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Chars = append(filtered.Chars, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Lines {
+	for _, obj := range p.GetObjects().Lines {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Lines = append(filtered.Lines, obj)
 		}
 	}
 	
-	for _, obj := range p.objects.Rects {
+	for _, obj := range p.GetObjects().Rects {
 		if bbox.Intersects(obj.GetBBox()) {
 			filtered.Rects = append(filtered.Rects, obj)
 		}
 	}
 	
 	return filtered
-}
\ No newline at end of file
+}
+
+// validationFacts collects what validation checks about the page. Images are
+// counted from the image XObjects in the page resources.
+func (p *LedongthucPage) validationFacts() pageFacts {
+	facts := pageFacts{
+		index:  p.pageNumber - 1,
+		width:  p.width,
+		height: p.height,
+		chars:  len(p.GetObjects().Chars),
+	}
+	if p.truncated {
+		facts.objectLimit = p.maxObjects
+	}
+	
+	facts.hasMediaBox = ledongthucInherited(p.page.V, "MediaBox").Kind() == lpdf.Array
+	
+	resources := p.page.Resources()
+	xobjects := resources.Key("XObject")
+	for _, name := range xobjects.Keys() {
+		if xobjects.Key(name).Key("Subtype").Name() == "Image" {
+			facts.images++
+		}
+	}
+	
+	fonts := resources.Key("Font")
+	for _, name := range fonts.Keys() {
+		font := fonts.Key(name)
+		encoding := font.Key("Encoding")
+		encodingName := encoding.Name()
+		if encoding.Kind() == lpdf.Dict {
+			encodingName = "Differences"
+		}
+		facts.fonts = append(facts.fonts, fontFacts{
+			name:      name,
+			baseFont:  font.Key("BaseFont").Name(),
+			encoding:  encodingName,
+			toUnicode: !font.Key("ToUnicode").IsNull(),
+		})
+	}
+	facts.formFonts = libraryFormFonts(resources, map[string]bool{})
+	
+	return facts
+}
@@ -0,0 +1,533 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	lpdf "github.com/ledongthuc/pdf"
+)
+
+func TestOpenMaxPages(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			if _, err := open("../../testdata/two_pages.pdf", WithMaxPages(1)); !errors.Is(err, ErrTooManyPages) {
+				t.Errorf("expected ErrTooManyPages for a document exceeding the page limit, got %v", err)
+			}
+
+			doc, err := open("../../testdata/two_pages.pdf", WithMaxPages(1), WithTruncatePages(true))
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			if doc.PageCount() != 1 {
+				t.Fatalf("expected 1 page when truncating, got %d", doc.PageCount())
+			}
+			page, _ := doc.GetPage(0)
+			if text := page.ExtractText(); text == "" {
+				t.Error("expected the first page to keep its text")
+			}
+		})
+	}
+}
+
+func TestOpenPageTreeCycle(t *testing.T) {
+	// A Pages node lists the root Pages node among its kids; every backend
+	// rejects the document rather than reading some of its pages
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		for _, opts := range [][]OpenOption{nil, {WithMaxPages(5), WithTruncatePages(true)}} {
+			if _, err := open("../../testdata/page_tree_cycle.pdf", opts...); !errors.Is(err, ErrPageTreeCycle) {
+				t.Errorf("%s with %d options: expected ErrPageTreeCycle, got %v", name, len(opts), err)
+			}
+		}
+	}
+}
+
+func TestPdfcpuOptionsLibraries(t *testing.T) {
+	// The libraries decode text and run content streams themselves, so
+	// options changing either fail rather than being ignored
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	options := map[string]OpenOption{
+		"WithFontUnicodeOverride": WithFontUnicodeOverride(`Helvetica`, NewToUnicodeCMap()),
+		"WithSpaceGlyphDetection": WithSpaceGlyphDetection(true),
+		"WithFallbackFont":        WithFallbackFont("Courier"),
+		"WithVisibleLayersOnly":   WithVisibleLayersOnly(true),
+		"WithMaxStreamSize":       WithMaxStreamSize(1 << 20),
+	}
+	for name, open := range backends {
+		for option, opt := range options {
+			if _, err := open("../../testdata/sample.pdf", opt); !errors.Is(err, ErrNotImplemented) {
+				t.Errorf("%s with %s: expected ErrNotImplemented, got %v", name, option, err)
+			}
+		}
+		doc, err := open("../../testdata/sample.pdf", WithSpaceGlyphDetection(false), WithVisibleLayersOnly(false))
+		if err != nil {
+			t.Errorf("%s: expected disabled options to open, got %v", name, err)
+			continue
+		}
+		doc.Close()
+	}
+}
+
+func TestOpenWithPassword(t *testing.T) {
+	// AES-128, and AES-256 of revisions 5 and 6, with user password
+	// "secret" and owner password "owner"
+	for _, path := range []string{"encrypted.pdf", "encrypted_aes256.pdf", "encrypted_aes256_r6.pdf"} {
+		for _, password := range []string{"secret", "owner"} {
+			doc, err := OpenWithPassword("../../testdata/"+path, password)
+			if err != nil {
+				t.Fatalf("%s with %q: failed to open PDF: %v", path, password, err)
+			}
+			page, _ := doc.GetPage(0)
+			if text := page.ExtractText(); !strings.Contains(text, "First page") {
+				t.Errorf("%s with %q: expected the decrypted text, got %q", path, password, text)
+			}
+			if !doc.Info().Encrypted {
+				t.Errorf("%s with %q: expected the document reported encrypted", path, password)
+			}
+			doc.Close()
+		}
+	}
+
+	// The native parser authenticates passwords for every backend
+	if _, err := OpenWithPassword("../../testdata/encrypted.pdf", "wrong"); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected ErrEncrypted for a wrong password, got %v", err)
+	}
+	for name, open := range map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	} {
+		if _, err := open("../../testdata/encrypted_rc4.pdf"); !errors.Is(err, ErrEncrypted) {
+			t.Errorf("%s: expected ErrEncrypted without the password, got %v", name, err)
+		}
+	}
+}
+
+func TestOpenBytes(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+
+	// The reader may hold more than the document
+	padded := append(append([]byte{}, data...), "trailing junk"...)
+	opened := map[string]func() (Document, error){
+		"bytes":  func() (Document, error) { return OpenBytes(data) },
+		"reader": func() (Document, error) { return OpenReader(bytes.NewReader(padded), int64(len(data))) },
+	}
+	for name, open := range opened {
+		doc, err := open()
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		page, _ := doc.GetPage(1)
+		if doc.PageCount() != 2 || !strings.Contains(page.ExtractText(), "Page two") {
+			t.Errorf("%s: expected two pages, got %d with %q", name, doc.PageCount(), page.ExtractText())
+		}
+		if err := doc.Close(); err != nil {
+			t.Errorf("%s: failed to close: %v", name, err)
+		}
+		if err := doc.Close(); err != nil {
+			t.Errorf("%s: failed to close twice: %v", name, err)
+		}
+	}
+
+	if _, err := OpenBytes([]byte("not a PDF")); !errors.Is(err, ErrNotPDF) {
+		t.Errorf("expected ErrNotPDF, got %v", err)
+	}
+}
+
+func TestInheritedResources(t *testing.T) {
+	// The page's font, and the ToUnicode CMap decoding its codes, are only
+	// defined on the root Pages node
+	doc, err := Open("../../testdata/inherited_resources.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	if got := page.ExtractText(); got != "Hi" {
+		t.Errorf("expected text decoded with the inherited font, got %q", got)
+	}
+}
+
+func TestInheritedParentCycleLibraries(t *testing.T) {
+	// The page's /Parent is a Pages node that is its own parent, and neither
+	// has a CropBox or Rotate to find
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		doc, err := open("../../testdata/parent_cycle.pdf")
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		dims := doc.PageDimensions()
+		if len(dims) != 1 || dims[0].Width != 612 || dims[0].Rotation != 0 {
+			t.Errorf("%s: expected an unrotated 612pt page, got %+v", name, dims)
+		}
+		doc.Close()
+	}
+}
+
+func TestSplitContentStreams(t *testing.T) {
+	// The Td operands 72 and 720 end one content stream and start the next
+	doc, err := Open("../../testdata/split_content.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	chars := page.GetObjects().Chars
+	if len(chars) == 0 {
+		t.Fatal("expected characters")
+	}
+	if chars[0].X0 != 72 || chars[0].Y0 != 720 {
+		t.Errorf("expected the text at (72, 720), got (%v, %v)", chars[0].X0, chars[0].Y0)
+	}
+	if text := page.ExtractText(); text != "Split" {
+		t.Errorf("expected %q, got %q", "Split", text)
+	}
+}
+
+func TestOpenMaxObjectsPerPage(t *testing.T) {
+	// One line of text followed by 1000 filled rectangles
+	doc, err := Open("../../testdata/many_rects.pdf", WithMaxObjectsPerPage(100))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	objects := page.GetObjects()
+	if n := len(objects.All()); n != 100 {
+		t.Errorf("expected parsing to stop at 100 objects, got %d", n)
+	}
+	if text := page.ExtractText(); text != "Graphics bomb" {
+		t.Errorf("expected the text before the cut-off, got %q", text)
+	}
+
+	truncated := false
+	for _, issue := range doc.Validate() {
+		if issue.Severity == SeverityWarning && issue.Message == "content truncated at 100 objects" {
+			truncated = true
+		}
+	}
+	if !truncated {
+		t.Errorf("expected a truncation warning, got %v", doc.Validate())
+	}
+
+	unbounded, err := Open("../../testdata/many_rects.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer unbounded.Close()
+	page, _ = unbounded.GetPage(0)
+	if n := len(page.GetObjects().Rects); n < 1000 {
+		t.Errorf("expected all 1000 rects without a limit, got %d", n)
+	}
+}
+
+func TestOpenMaxObjectsNestedContent(t *testing.T) {
+	// The page's text, then a tiling pattern whose cell shows "Tile" on
+	// two tiles, then a stamp annotation's text
+	for n, text := range map[int]string{12: "Body textTil", 20: "Body textTileTileSta"} {
+		doc, err := Open("../../testdata/nested_text.pdf", WithMaxObjectsPerPage(n))
+		if err != nil {
+			t.Fatalf("failed to open PDF: %v", err)
+		}
+		page, _ := doc.GetPage(0)
+
+		var got strings.Builder
+		for _, char := range page.GetObjects().Chars {
+			got.WriteString(char.Text)
+		}
+		if got.String() != text || len(page.GetObjects().All()) != n {
+			t.Errorf("limit %d: expected %q, got %q", n, text, got.String())
+		}
+		if issues := doc.Validate(); len(issues) != 1 || issues[0].Message != fmt.Sprintf("content truncated at %d objects", n) {
+			t.Errorf("limit %d: expected a truncation warning, got %v", n, issues)
+		}
+		doc.Close()
+	}
+}
+
+func TestOpenMaxStreamSize(t *testing.T) {
+	// The page's content inflates to just over a megabyte
+	if _, err := Open("../../testdata/deflate_bomb.pdf", WithMaxStreamSize(64<<10)); !errors.Is(err, ErrStreamTooLarge) {
+		t.Errorf("expected ErrStreamTooLarge, got %v", err)
+	}
+
+	doc, err := Open("../../testdata/deflate_bomb.pdf", WithMaxStreamSize(2<<20))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+	if text := page.ExtractText(); text != "Deflated" {
+		t.Errorf("expected the text within the limit, got %q", text)
+	}
+}
+
+func TestOpenWithPageBBox(t *testing.T) {
+	// The MediaBox claims 200x100, but the text is drawn at (100, 700) and
+	// (400, 100) with a filled rect around the first
+	box := BoundingBox{X0: 50, Y0: 600, X1: 300, Y1: 750}
+	backends := map[string]struct {
+		open    func(string, ...OpenOption) (Document, error)
+		charTop float64 // Y0 of the first char in the backend's space
+	}{
+		"pdfcpu":     {Open, 100},
+		"ledongthuc": {OpenWithLedongthuc, 750 - (700 + 12*0.8)},
+		"dslipak":    {OpenWithDslipak, 100},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := backend.open("../../testdata/bogus_mediabox.pdf", WithPageBBox(box))
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, _ := doc.GetPage(0)
+			if page.GetWidth() != 250 || page.GetHeight() != 150 || page.GetBBox() != (BoundingBox{X1: 250, Y1: 150}) {
+				t.Errorf("expected a 250x150 page, got %vx%v with bbox %+v", page.GetWidth(), page.GetHeight(), page.GetBBox())
+			}
+
+			// Coordinates are relative to the box, and text outside it is dropped
+			chars := page.GetObjects().Chars
+			if len(chars) != 6 {
+				t.Fatalf("expected the 6 chars inside the box, got %d", len(chars))
+			}
+			if math.Abs(chars[0].X0-50) > 0.01 || math.Abs(chars[0].Y0-backend.charTop) > 0.01 {
+				t.Errorf("expected the first char at (50, %v), got (%v, %v)", backend.charTop, chars[0].X0, chars[0].Y0)
+			}
+			if text := strings.ReplaceAll(page.ExtractText(), " ", ""); text != "Inside" {
+				t.Errorf("expected only the text inside the box, got %q", text)
+			}
+		})
+	}
+
+	doc, err := Open("../../testdata/bogus_mediabox.pdf", WithPageBBox(box))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+	if rects := page.GetObjects().Rects; len(rects) != 1 || rects[0].X0 != 40 || rects[0].Y0 != 90 {
+		t.Errorf("expected the rect moved into the box, got %+v", rects)
+	}
+
+	if _, err := Open("../../testdata/bogus_mediabox.pdf", WithPageBBox(BoundingBox{X0: 10, Y0: 10, X1: 10, Y1: 50})); err == nil {
+		t.Error("expected an error for a page bbox without area")
+	}
+}
+
+func TestMergeAdjacentChars(t *testing.T) {
+	// "Hel" and "lo" are kerned 2.5pt apart, more than the width-based
+	// guess allows but within the x tolerance; the library emits the space
+	// before "World" as a text item of its own
+	file, reader, err := lpdf.Open("../../testdata/kerned_words.pdf")
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+	defer file.Close()
+	var items strings.Builder
+	for _, item := range reader.Page(1).Content().Text {
+		items.WriteString(item.S)
+	}
+	expected := strings.Fields(items.String())
+
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/kerned_words.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			var got []string
+			for _, word := range page.ExtractWords(WithMergeAdjacentChars(true)) {
+				got = append(got, word.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(expected, "|") {
+				t.Errorf("expected the library's words %q, got %q", expected, got)
+			}
+		})
+	}
+}
+
+func TestExtractWordsSeparateSuperscripts(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/superscript.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			// "12" is shown in a smaller font raised by a text rise
+			words := make(map[string]Word)
+			for _, word := range page.ExtractWords(WithSeparateSuperscripts(true)) {
+				words[word.Text] = word
+			}
+			if base, ok := words["text"]; !ok || base.Superscript || base.Subscript {
+				t.Errorf("expected the base word %q untagged, got %+v", "text", words)
+			}
+			if marker, ok := words["12"]; !ok || !marker.Superscript || marker.Subscript {
+				t.Errorf("expected a superscript %q, got %+v", "12", words)
+			}
+		})
+	}
+
+	doc, err := Open("../../testdata/superscript.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	var texts []string
+	for _, word := range page.ExtractWords(WithSeparateSuperscripts(true)) {
+		texts = append(texts, word.Text)
+		if word.Text == "2" && !word.Subscript {
+			t.Errorf("expected the 2 of H2O tagged as a subscript, got %+v", word)
+		}
+	}
+	if got := strings.Join(texts, " "); got != "text 12 H 2 O" {
+		t.Errorf("expected words %q, got %q", "text 12 H 2 O", got)
+	}
+
+	// Without the option the markers stay attached
+	texts = nil
+	for _, word := range page.ExtractWords() {
+		texts = append(texts, word.Text)
+	}
+	if got := strings.Join(texts, " "); got != "text12 H2O" {
+		t.Errorf("expected words %q, got %q", "text12 H2O", got)
+	}
+}
+
+func TestEmitSpaces(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			words := func(page Page) string {
+				var texts []string
+				for _, word := range page.ExtractWords() {
+					texts = append(texts, word.Text)
+				}
+				return strings.Join(texts, "|")
+			}
+
+			doc, err := open("../../testdata/two_pages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+			defaultWords := words(page)
+
+			doc, err = open("../../testdata/two_pages.pdf", WithEmitSpaces(true))
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ = doc.GetPage(0)
+
+			// The space of "First page" sits between the characters around it
+			chars := page.GetObjects().Chars
+			if len(chars) < 7 || chars[4].Text != "t" || chars[5].Text != " " || chars[6].Text != "p" {
+				t.Fatalf("expected a space char between %q and %q, got %+v", "t", "p", chars)
+			}
+			space := chars[5]
+			if space.Width <= 0 || abs(space.X0-chars[4].X1) > 0.001 || abs(space.X1-chars[6].X0) > 0.001 {
+				t.Errorf("expected the space from %.2f to %.2f, got %.2f to %.2f",
+					chars[4].X1, chars[6].X0, space.X0, space.X1)
+			}
+			if got := words(page); got != defaultWords {
+				t.Errorf("expected words %q as without spaces, got %q", defaultWords, got)
+			}
+		})
+	}
+
+	// The libraries' spaces are dropped by default
+	doc, err := OpenWithLedongthuc("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+	for _, char := range page.GetObjects().Chars {
+		if char.Text == " " {
+			t.Errorf("expected no space chars by default, got %+v", char)
+		}
+	}
+}
+
+func TestLibrarySpaceItems(t *testing.T) {
+	// The space before "World" is kerned down to 1.2pt, narrower than any
+	// gap words are split at; the library's space item still splits them
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/tight_space.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			var got []string
+			for _, word := range page.ExtractWords() {
+				got = append(got, word.Text)
+			}
+			if strings.Join(got, "|") != "Hello|World" {
+				t.Errorf("expected words Hello and World, got %q", got)
+			}
+			if spans := page.ExtractTextSpans(); len(spans) != 1 || spans[0].Text != "Hello World" {
+				t.Errorf("expected the span \"Hello World\", got %+v", spans)
+			}
+		})
+	}
+}
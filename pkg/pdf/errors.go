@@ -0,0 +1,82 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pyhub-apps/pdfplumber-golang/pkg/parser"
+)
+
+// Errors returned by all backends for the kinds of failure callers may want
+// to tell apart, wrapped with details; test for them with errors.Is. They
+// are the errors of the native parser.
+var (
+	ErrNotPDF            = parser.ErrNotPDF
+	ErrEncrypted         = parser.ErrEncrypted
+	ErrCorruptXRef       = parser.ErrCorruptXRef
+	ErrPageOutOfRange    = parser.ErrPageOutOfRange
+	ErrUnsupportedFilter = parser.ErrUnsupportedFilter
+	ErrStreamTooLarge    = parser.ErrStreamTooLarge
+	ErrTooManyPages      = parser.ErrTooManyPages
+	ErrPageTreeCycle     = parser.ErrPageTreeCycle
+)
+
+var (
+	// ErrImageNotFound is returned for image names missing from a page's
+	// resources
+	ErrImageNotFound = errors.New("image not found")
+	// ErrNotImplemented is returned by features a backend does not support
+	ErrNotImplemented = errors.New("not implemented")
+)
+
+// pdfcpuReadError classifies an error of pdfcpu reading a document
+func pdfcpuReadError(err error) error {
+	switch {
+	case errors.Is(err, pdfcpu.ErrWrongPassword):
+		return fmt.Errorf("%w: %w", ErrEncrypted, err)
+	case errors.Is(err, pdfcpu.ErrCorruptHeader):
+		return fmt.Errorf("%w: %w", ErrNotPDF, err)
+	}
+	return fmt.Errorf("%w: %w", ErrCorruptXRef, err)
+}
+
+// readLibraryFile opens a file and hands it to read, which creates the
+// reader of a PDF library. The file is read with the native parser and its
+// options first: the libraries follow object streams and page trees
+// without guarding against cycles, which overflows the stack rather than
+// panicking, and read the whole page tree whatever the page limit, so a
+// document the native parser rejects fails with its error. The libraries'
+// own errors are plain strings, and they may panic on malformed files, so
+// a document only the library cannot read fails with ErrNotImplemented.
+func readLibraryFile(path string, options []parser.Option, read func(f *os.File, size int64) error) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err == nil {
+		_, err = parser.NewPDFParser(f, info.Size(), options...).Parse()
+	}
+	if err == nil {
+		if err = readRecovered(f, info.Size(), read); err != nil {
+			err = fmt.Errorf("%w: %v", ErrNotImplemented, err)
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// readRecovered calls read, turning a panic into an error
+func readRecovered(f *os.File, size int64, read func(f *os.File, size int64) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	return read(f, size)
+}
@@ -0,0 +1,62 @@
+package pdf
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOpenErrors(t *testing.T) {
+	cases := map[string]error{
+		"../../testdata/not_a_pdf.pdf":    ErrNotPDF,
+		"../../testdata/corrupt_xref.pdf": ErrCorruptXRef,
+		"../../testdata/encrypted.pdf":    ErrEncrypted, // User password "secret"
+	}
+
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			for path, expected := range cases {
+				if _, err := open(path); !errors.Is(err, expected) {
+					t.Errorf("%s: expected %v, got %v", path, expected, err)
+				}
+			}
+
+			doc, err := open("../../testdata/two_pages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			if _, err := doc.GetPage(2); !errors.Is(err, ErrPageOutOfRange) {
+				t.Errorf("expected ErrPageOutOfRange, got %v", err)
+			}
+			page, _ := doc.GetPage(0)
+			if _, err := page.Image("Missing"); !errors.Is(err, ErrImageNotFound) {
+				t.Errorf("expected ErrImageNotFound, got %v", err)
+			}
+		})
+	}
+}
+
+func TestOpenErrorsLibraryOnly(t *testing.T) {
+	// The libraries only read PDF 1.x headers; the native parser reads the
+	// document, so the failure is the libraries' own rather than corruption
+	for name, open := range map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	} {
+		_, err := open("../../testdata/pdf20_header.pdf")
+		if !errors.Is(err, ErrNotImplemented) || errors.Is(err, ErrCorruptXRef) || errors.Is(err, ErrNotPDF) {
+			t.Errorf("%s: expected ErrNotImplemented, got %v", name, err)
+		}
+	}
+
+	doc, err := Open("../../testdata/pdf20_header.pdf")
+	if err != nil {
+		t.Fatalf("expected the pdfcpu backend to open a PDF 2.0 header, got %v", err)
+	}
+	doc.Close()
+}
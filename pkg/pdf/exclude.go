@@ -0,0 +1,99 @@
+package pdf
+
+// WithExcludeRegions drops every object whose bounding box center lies in
+// one of the regions before any extraction, to mask running headers and
+// footers of untagged documents by position. Regions are in PDF space, with
+// Y growing upwards from the bottom of the page (of the box given with
+// WithPageBBox, if any).
+func WithExcludeRegions(regions []BoundingBox) OpenOption {
+	return func(c *openConfig) {
+		c.ExcludeRegions = make([]BoundingBox, len(regions))
+		for i, region := range regions {
+			c.ExcludeRegions[i] = region.Normalize()
+		}
+	}
+}
+
+// flipRegions turns regions in PDF space into the top-down space of a page
+// of the given height
+func flipRegions(regions []BoundingBox, height float64) []BoundingBox {
+	flipped := make([]BoundingBox, len(regions))
+	for i, region := range regions {
+		flipped[i] = BoundingBox{X0: region.X0, Y0: height - region.Y1, X1: region.X1, Y1: height - region.Y0}
+	}
+	return flipped
+}
+
+// excludeRegions drops the objects whose bounding box center lies in one of
+// the regions
+func excludeRegions(objects Objects, regions []BoundingBox) Objects {
+	if len(regions) == 0 {
+		return objects
+	}
+	keep := func(obj Object) bool {
+		return !centerExcluded(obj.GetBBox(), regions)
+	}
+
+	kept := Objects{}
+	for _, char := range objects.Chars {
+		if keep(char) {
+			kept.Chars = append(kept.Chars, char)
+		}
+	}
+	for _, line := range objects.Lines {
+		if keep(line) {
+			kept.Lines = append(kept.Lines, line)
+		}
+	}
+	for _, rect := range objects.Rects {
+		if keep(rect) {
+			kept.Rects = append(kept.Rects, rect)
+		}
+	}
+	for _, curve := range objects.Curves {
+		if keep(curve) {
+			kept.Curves = append(kept.Curves, curve)
+		}
+	}
+	for _, image := range objects.Images {
+		if keep(image) {
+			kept.Images = append(kept.Images, image)
+		}
+	}
+	for _, anno := range objects.Annos {
+		if keep(anno) {
+			kept.Annos = append(kept.Annos, anno)
+		}
+	}
+	for _, shading := range objects.Shadings {
+		if keep(shading) {
+			kept.Shadings = append(kept.Shadings, shading)
+		}
+	}
+	return kept
+}
+
+// centerExcluded reports whether the center of a bounding box lies in one of
+// the regions
+func centerExcluded(bbox BoundingBox, regions []BoundingBox) bool {
+	x, y := (bbox.X0+bbox.X1)/2, (bbox.Y0+bbox.Y1)/2
+	for _, region := range regions {
+		if region.Contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// itemExcluded reports whether a text item of a backend library, placed in
+// PDF space, lies in one of the regions, which are relative to the page box
+// when one is set
+func itemExcluded(bbox BoundingBox, regions []BoundingBox, pageBox *BoundingBox) bool {
+	if len(regions) == 0 {
+		return false
+	}
+	if pageBox != nil {
+		bbox = BoundingBox{X0: bbox.X0 - pageBox.X0, Y0: bbox.Y0 - pageBox.Y0, X1: bbox.X1 - pageBox.X0, Y1: bbox.Y1 - pageBox.Y0}
+	}
+	return centerExcluded(bbox, regions)
+}
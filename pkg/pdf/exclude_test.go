@@ -0,0 +1,48 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExcludeRegions(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	footer := BoundingBox{X0: 0, Y0: 0, X1: 612, Y1: 50}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/footer.pdf", WithExcludeRegions([]BoundingBox{footer}))
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, _ := doc.GetPage(0)
+			// dslipak spaces out the letters of each text item
+			text := strings.ReplaceAll(page.ExtractText(), " ", "")
+			if strings.Contains(text, "Page7") {
+				t.Errorf("expected the page number in the footer to be dropped, got %q", text)
+			}
+			if !strings.Contains(text, "Bodytextstays.") || !strings.Contains(text, "ACMECorp") {
+				t.Errorf("expected the text outside the footer to be kept, got %q", text)
+			}
+			for _, line := range page.GetObjects().Lines {
+				t.Errorf("expected the footer rule to be dropped, got %+v", line)
+			}
+		})
+	}
+
+	// Without the option the footer is extracted
+	doc, err := Open("../../testdata/footer.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+	if text := page.ExtractText(); !strings.Contains(text, "Page 7") {
+		t.Errorf("expected the page number without excluded regions, got %q", text)
+	}
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// charRecord is the JSON Lines representation of a character
+type charRecord struct {
+	Text     string   `json:"text"`
+	FontName string   `json:"fontname"`
+	Size     float64  `json:"size"`
+	X0       float64  `json:"x0"`
+	Top      float64  `json:"top"`
+	X1       float64  `json:"x1"`
+	Bottom   float64  `json:"bottom"`
+	Upright  bool     `json:"upright"`
+	Color    [3]uint8 `json:"color"`
+	Page     int      `json:"page"`
+}
+
+// WriteCharsJSONL writes one JSON object per character to w, one per line.
+// top and bottom are measured from the top of the page; topDown tells
+// whether the chars already use that orientation or raw PDF space.
+func WriteCharsJSONL(w io.Writer, chars []CharObject, pageNumber int, pageHeight float64, topDown bool) error {
+	encoder := json.NewEncoder(w)
+	for _, char := range chars {
+		record := charRecord{
+			Text:     char.Text,
+			FontName: char.Font,
+			Size:     char.FontSize,
+			X0:       char.X0,
+			X1:       char.X1,
+			Top:      char.Y0,
+			Bottom:   char.Y1,
+			Upright:  char.Matrix.B == 0 && char.Matrix.C == 0,
+			Color:    [3]uint8{char.Color.R, char.Color.G, char.Color.B},
+			Page:     pageNumber,
+		}
+		if !topDown {
+			record.Top = pageHeight - char.Y1
+			record.Bottom = pageHeight - char.Y0
+		}
+
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write char: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package pdf
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// extractFontMetrics reads the FontBBox, Ascent, Descent, Flags and AvgWidth
+// of a font's descriptor, or of its descendant font's descriptor for Type0
+// fonts. Type3 glyphs are measured in their own glyph space and are left out.
+func (p *ContentStreamParser) extractFontMetrics(fontInfo *FontInfo, fontDict types.Dict) {
+	if subtype := fontDict.Subtype(); subtype != nil && *subtype == "Type3" {
+		return
+	}
+	descriptor := p.dereferenceDict(fontDict["FontDescriptor"])
+	if descriptor == nil {
+		if descendants, ok := p.resolveObject(fontDict["DescendantFonts"]).(types.Array); ok && len(descendants) > 0 {
+			if cidFont := p.dereferenceDict(descendants[0]); cidFont != nil {
+				descriptor = p.dereferenceDict(cidFont["FontDescriptor"])
+			}
+		}
+	}
+	if descriptor == nil {
+		return
+	}
+	
+	if bbox, ok := p.resolveObject(descriptor["FontBBox"]).(types.Array); ok && len(bbox) == 4 {
+		fontInfo.FontBBox = BoundingBox{
+			X0: numberValue(p.resolveObject(bbox[0])),
+			Y0: numberValue(p.resolveObject(bbox[1])),
+			X1: numberValue(p.resolveObject(bbox[2])),
+			Y1: numberValue(p.resolveObject(bbox[3])),
+		}.Normalize()
+	}
+	fontInfo.Ascent = numberValue(p.resolveObject(descriptor["Ascent"]))
+	fontInfo.Descent = numberValue(p.resolveObject(descriptor["Descent"]))
+	fontInfo.Flags = int(numberValue(p.resolveObject(descriptor["Flags"])))
+	fontInfo.AvgWidth = numberValue(p.resolveObject(descriptor["AvgWidth"]))
+	fontInfo.MissingWidth = numberValue(p.resolveObject(descriptor["MissingWidth"]))
+}
+
+// verticalExtent returns the bottom and top of the font's glyphs relative to
+// the baseline, as fractions of the font size. They come from the Ascent and
+// Descent of the font descriptor, or its FontBBox without them; fonts
+// without either span from the baseline up by the font size.
+func (f *FontInfo) verticalExtent() (float64, float64) {
+	if f == nil {
+		return 0, 1
+	}
+	if f.Ascent > 0 {
+		return min(f.Descent, 0) / 1000, f.Ascent / 1000
+	}
+	if f.FontBBox.Y1 > f.FontBBox.Y0 {
+		return f.FontBBox.Y0 / 1000, f.FontBBox.Y1 / 1000
+	}
+	return 0, 1
+}
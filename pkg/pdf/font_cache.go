@@ -0,0 +1,64 @@
+package pdf
+
+import (
+	"sync"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// fontCacheKey identifies a font loaded from an indirect font object
+type fontCacheKey struct {
+	object int  // Object number of the font dictionary
+	repair bool // ToUnicode CMaps repaired when parsed
+}
+
+// fontCache keeps the fonts of a document by their object, so fonts shared
+// by pages and forms have their dictionaries and ToUnicode CMaps parsed once
+type fontCache struct {
+	mu    sync.Mutex
+	fonts map[fontCacheKey]*FontInfo // nil for objects that are not fonts
+}
+
+// newFontCache creates an empty font cache
+func newFontCache() *fontCache {
+	return &fontCache{fonts: make(map[fontCacheKey]*FontInfo)}
+}
+
+// get returns the font loaded for a key, if any
+func (c *fontCache) get(key fontCacheKey) (*FontInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	font, ok := c.fonts[key]
+	return font, ok
+}
+
+// put stores the font loaded for a key
+func (c *fontCache) put(key fontCacheKey, font *FontInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fonts[key] = font
+}
+
+// loadFont reads the font dictionary of a font resource, nil if it is not
+// one. Fonts of indirect objects come from the document's font cache when
+// the parser has one. Each call returns its own FontInfo, as the resource
+// name and font overrides differ between the streams sharing a font.
+func (p *ContentStreamParser) loadFont(name string, fontRef types.Object) *FontInfo {
+	number := objectNumber(fontRef)
+	if p.fontCache == nil || number == 0 {
+		return p.parseFont(name, fontRef)
+	}
+
+	key := fontCacheKey{object: number, repair: p.repairUnicode}
+	font, ok := p.fontCache.get(key)
+	if !ok {
+		font = p.parseFont(name, fontRef)
+		p.fontCache.put(key, font)
+	}
+	if font == nil {
+		return nil
+	}
+	loaded := *font
+	loaded.Name = name
+	return &loaded
+}
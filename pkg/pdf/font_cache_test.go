@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFontCacheSharedFonts(t *testing.T) {
+	doc, err := Open("../../testdata/shared_fonts.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// All 50 pages use the same two font objects, which share a ToUnicode CMap
+	var cmaps []*ToUnicodeCMap
+	for i, page := range doc.GetPages() {
+		if text := page.ExtractText(); !strings.Contains(text, fmt.Sprintf("Page %d heading", i+1)) {
+			t.Fatalf("page %d: expected its heading, got %q", i+1, text)
+		}
+		for _, char := range page.GetObjects().Chars {
+			if char.Font != "F1" && char.Font != "F2" {
+				t.Fatalf("page %d: expected characters in F1 or F2, got %q", i+1, char.Font)
+			}
+		}
+		parser := page.(*PDFCPUPage).newParser()
+		cmaps = append(cmaps, parser.fonts["F1"].ToUnicodeCMap)
+	}
+
+	cache := doc.GetPages()[0].(*PDFCPUPage).fontCache
+	if len(cache.fonts) != 2 {
+		t.Errorf("expected the 2 shared fonts to be loaded once, got %d cached", len(cache.fonts))
+	}
+	for i, cmap := range cmaps {
+		if cmap == nil || cmap != cmaps[0] {
+			t.Errorf("page %d: expected the cached CMap to be reused", i+1)
+		}
+	}
+}
+
+func BenchmarkSharedFonts(b *testing.B) {
+	for _, cached := range []bool{true, false} {
+		b.Run(fmt.Sprintf("cached=%v", cached), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				doc, err := Open("../../testdata/shared_fonts.pdf")
+				if err != nil {
+					b.Fatalf("failed to open PDF: %v", err)
+				}
+				for _, page := range doc.GetPages() {
+					if !cached {
+						page.(*PDFCPUPage).fontCache = nil
+					}
+					page.GetObjects()
+				}
+				doc.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package pdf
+
+import (
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+)
+
+// defaultSpaceGlyphWidth is the width of the space glyph, as a fraction of
+// the font size, for fonts without known metrics
+const defaultSpaceGlyphWidth = 0.25
+
+// spaceGlyphWidth returns the width of the font's space glyph, code 32, as a
+// fraction of the font size, from the font's Widths or the AFM metrics of its
+// standard font. Fonts without either get a typical proportional width.
+func (f *FontInfo) spaceGlyphWidth() float64 {
+	if width, ok := f.codeWidth(' '); ok && width > 0 {
+		return width / 1000
+	}
+	return defaultSpaceGlyphWidth
+}
+
+// SpaceGlyphWidth returns the width of the space glyph of the character's
+// font as a fraction of the font size. Open reads it from the font's Widths
+// or the AFM metrics of its standard font; the libraries' characters, which
+// carry only the font name, get the metrics of the standard font it names.
+// Other fonts get a typical proportional width.
+func (c CharObject) SpaceGlyphWidth() float64 {
+	if c.spaceWidth > 0 {
+		return c.spaceWidth
+	}
+	if standard, ok := standardFontName(c.Font); ok {
+		if width := font.CharWidth(standard, ' '); width > 0 {
+			return float64(width) / 1000
+		}
+	}
+	return defaultSpaceGlyphWidth
+}
+
+// standardFontAliases maps common names of the metric-compatible substitutes
+// of the standard 14 fonts to the standard font
+var standardFontAliases = map[string]string{
+	"Arial":                        "Helvetica",
+	"Arial,Bold":                   "Helvetica-Bold",
+	"Arial,Italic":                 "Helvetica-Oblique",
+	"Arial,BoldItalic":             "Helvetica-BoldOblique",
+	"ArialMT":                      "Helvetica",
+	"Arial-BoldMT":                 "Helvetica-Bold",
+	"Arial-ItalicMT":               "Helvetica-Oblique",
+	"Arial-BoldItalicMT":           "Helvetica-BoldOblique",
+	"TimesNewRoman":                "Times-Roman",
+	"TimesNewRoman,Bold":           "Times-Bold",
+	"TimesNewRoman,Italic":         "Times-Italic",
+	"TimesNewRoman,BoldItalic":     "Times-BoldItalic",
+	"TimesNewRomanPSMT":            "Times-Roman",
+	"TimesNewRomanPS-BoldMT":       "Times-Bold",
+	"TimesNewRomanPS-ItalicMT":     "Times-Italic",
+	"TimesNewRomanPS-BoldItalicMT": "Times-BoldItalic",
+	"CourierNew":                   "Courier",
+	"CourierNew,Bold":              "Courier-Bold",
+	"CourierNew,Italic":            "Courier-Oblique",
+	"CourierNew,BoldItalic":        "Courier-BoldOblique",
+	"CourierNewPSMT":               "Courier",
+	"CourierNewPS-BoldMT":          "Courier-Bold",
+	"CourierNewPS-ItalicMT":        "Courier-Oblique",
+	"CourierNewPS-BoldItalicMT":    "Courier-BoldOblique",
+}
+
+// standardFontName returns the standard 14 font a base font name refers to,
+// directly or through a common alias
+func standardFontName(baseFont string) (string, bool) {
+	if i := strings.IndexByte(baseFont, '+'); i >= 0 {
+		baseFont = baseFont[i+1:]
+	}
+	if font.IsCoreFont(baseFont) {
+		return baseFont, true
+	}
+	name, ok := standardFontAliases[baseFont]
+	return name, ok
+}
+
+// codeWidth returns the advance of a single-byte code in glyph space units
+// (1/1000 em) from the font's Widths, with the descriptor's MissingWidth for
+// codes outside them, else from the AFM metrics of the font's standard font.
+// Those codes are looked up in WinAnsiEncoding, or the built-in encoding of
+// Symbol and ZapfDingbats. It reports false for fonts without either.
+func (f *FontInfo) codeWidth(code byte) (float64, bool) {
+	if f == nil {
+		return 0, false
+	}
+	if f.Widths != nil {
+		if i := int(code) - f.FirstChar; i >= 0 && i < len(f.Widths) {
+			return f.Widths[i], true
+		}
+		if f.MissingWidth > 0 {
+			return f.MissingWidth, true
+		}
+	}
+	if f.StandardFont == "" {
+		return 0, false
+	}
+	return float64(font.CharWidth(f.StandardFont, rune(code))), true
+}
+
+// Font descriptor flags that tell a font's class
+const (
+	fontFlagFixedPitch = 1 << 0
+	fontFlagSerif      = 1 << 1
+	fontFlagItalic     = 1 << 6
+)
+
+// Average glyph widths, as fractions of the font size, of typical fonts of
+// each class, italic faces being a little narrower, and the share of the
+// FontBBox width the average glyph takes
+const (
+	fixedPitchWidth       = 0.6
+	sansSerifWidth        = 0.5
+	serifWidth            = 0.45
+	italicWidthFactor     = 0.95
+	fixedPitchBBoxShare   = 0.8
+	proportionalBBoxShare = 0.43
+)
+
+// estimatedWidth returns the advance of a character as a fraction of the
+// font size for fonts without metrics. Every character of a fixed-pitch font
+// gets the average width; proportional fonts scale it for narrow, wide and
+// space characters. The average comes from the descriptor's AvgWidth, else a
+// share of the FontBBox width, else the typical width of the font's class.
+func (f *FontInfo) estimatedWidth(char string) float64 {
+	average := f.averageWidth()
+	if f != nil && f.Flags&fontFlagFixedPitch != 0 {
+		return average
+	}
+
+	switch char {
+	case " ":
+		return average * 0.5
+	case "i", "l", "I", "!", ".", ",", ";", ":", "'", "\"":
+		return average * 0.6
+	case "m", "M", "W", "w":
+		return average * 1.6
+	}
+	return average
+}
+
+// averageWidth returns the average glyph width of a font as a fraction of
+// the font size. Widths taken from a damaged descriptor are kept within
+// reasonable bounds.
+func (f *FontInfo) averageWidth() float64 {
+	if f == nil {
+		return sansSerifWidth
+	}
+	fixed := f.Flags&fontFlagFixedPitch != 0
+	if f.AvgWidth > 0 {
+		return clampWidth(f.AvgWidth / 1000)
+	}
+	if bboxWidth := f.FontBBox.Width(); bboxWidth > 0 {
+		share := proportionalBBoxShare
+		if fixed {
+			share = fixedPitchBBoxShare
+		}
+		return clampWidth(bboxWidth / 1000 * share)
+	}
+
+	width := sansSerifWidth
+	switch {
+	case fixed:
+		width = fixedPitchWidth
+	case f.Flags&fontFlagSerif != 0:
+		width = serifWidth
+	}
+	if f.Flags&fontFlagItalic != 0 {
+		width *= italicWidthFactor
+	}
+	return width
+}
+
+// clampWidth limits an average glyph width to between a quarter and one em
+func clampWidth(width float64) float64 {
+	return max(0.25, min(width, 1))
+}
+
+// winAnsiHigh maps the characters WinAnsiEncoding puts at codes 128 to 159
+// to their codes; the other codes below 256 match their character
+var winAnsiHigh = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// winAnsiCode returns the WinAnsiEncoding code of a character
+func winAnsiCode(r rune) (byte, bool) {
+	if r < 0x80 || r >= 0xA0 && r < 0x100 {
+		return byte(r), true
+	}
+	code, ok := winAnsiHigh[r]
+	return code, ok
+}
+
+// standardTextWidth returns the advance of decoded text in a standard font
+// as a fraction of the font size, from the font's AFM metrics. The text is
+// encoded with WinAnsiEncoding; characters outside it, and all of Symbol
+// and ZapfDingbats, whose built-in encodings it does not follow, get the
+// average width of a sans-serif font.
+func standardTextWidth(standard, text string) float64 {
+	symbolic := standard == "Symbol" || standard == "ZapfDingbats"
+	var width float64
+	for _, r := range text {
+		if code, ok := winAnsiCode(r); ok && !symbolic {
+			width += float64(font.CharWidth(standard, rune(code))) / 1000
+		} else {
+			width += sansSerifWidth
+		}
+	}
+	return width
+}
+
+// libraryTextWidth returns the advance of text the libraries read as
+// zero-width as a fraction of the font size: the AFM widths of the standard
+// font the font name refers to, else the estimated widths of a font without
+// metrics, since the libraries keep no descriptor
+func libraryTextWidth(fontName, text string) float64 {
+	if standard, ok := standardFontName(fontName); ok {
+		return standardTextWidth(standard, text)
+	}
+	var unknown *FontInfo
+	var width float64
+	for _, r := range text {
+		width += unknown.estimatedWidth(string(r))
+	}
+	return width
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestStandardFontWidths(t *testing.T) {
+	// Non-embedded Helvetica, Times-Bold and Arial, standing in for
+	// Helvetica, at 10pt without Widths arrays
+	doc, err := Open("../../testdata/standard_fonts.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Advances from the AFM files, in 1/1000 em
+	expected := map[string][]float64{
+		"F1": {722, 556, 222, 222, 556},
+		"F2": {778, 444, 278, 278, 500},
+		"F3": {944, 222},
+	}
+
+	page, _ := doc.GetPage(0)
+	got := map[string][]CharObject{}
+	for _, char := range page.GetObjects().Chars {
+		got[char.Font] = append(got[char.Font], char)
+	}
+
+	for font, widths := range expected {
+		chars := got[font]
+		if len(chars) != len(widths) {
+			t.Fatalf("font %s: expected %d chars, got %d", font, len(widths), len(chars))
+		}
+		x := 72.0
+		for i, width := range widths {
+			want := width / 1000 * 10
+			if abs(chars[i].Width-want) > 0.001 || abs(chars[i].X0-x) > 0.001 {
+				t.Errorf("font %s char %q: expected width %.2f at x %.2f, got %.2f at %.2f",
+					font, chars[i].Text, want, x, chars[i].Width, chars[i].X0)
+			}
+			x += want
+		}
+	}
+}
+
+func TestEstimatedFontWidths(t *testing.T) {
+	// Two fonts with descriptors but no Widths at 10pt: F2 fixed-pitch with
+	// a FontBBox 750 units wide, F3 proportional with one 1200 units wide
+	doc, err := Open("../../testdata/metricless_fonts.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	got := map[string][]CharObject{}
+	for _, char := range page.GetObjects().Chars {
+		got[char.Font] = append(got[char.Font], char)
+	}
+	if len(got["F2"]) != 5 || len(got["F3"]) != 5 {
+		t.Fatalf("expected 5 chars per font, got %d and %d", len(got["F2"]), len(got["F3"]))
+	}
+
+	// Fixed pitch: every character, the space too, is 0.8 of the FontBBox
+	// width
+	for i, char := range got["F2"] {
+		if x := 72 + 6*float64(i); abs(char.Width-6) > 0.001 || abs(char.X0-x) > 0.001 {
+			t.Errorf("fixed-pitch char %q: expected width 6.00 at x %.2f, got %.2f at %.2f",
+				char.Text, x, char.Width, char.X0)
+		}
+	}
+
+	// Proportional: narrow and wide characters around an average of 0.43
+	// of the FontBBox width
+	average := 1200 * proportionalBBoxShare / 1000 * 10
+	chars := got["F3"]
+	if abs(chars[0].Width-chars[1].Width) > 0.001 || abs(chars[3].Width-chars[4].Width) > 0.001 {
+		t.Errorf("expected i and l, then m and W to match, got widths %.2f %.2f %.2f %.2f",
+			chars[0].Width, chars[1].Width, chars[3].Width, chars[4].Width)
+	}
+	if chars[0].Width >= average || chars[3].Width <= average {
+		t.Errorf("expected narrow width below and wide width above %.2f, got %.2f and %.2f",
+			average, chars[0].Width, chars[3].Width)
+	}
+}
+
+func TestEstimatedWidthDefaults(t *testing.T) {
+	tests := []struct {
+		flags int
+		want  float64
+	}{
+		{0, sansSerifWidth},
+		{fontFlagFixedPitch, fixedPitchWidth},
+		{fontFlagSerif, serifWidth},
+		{fontFlagSerif | fontFlagItalic, serifWidth * italicWidthFactor},
+	}
+	for _, test := range tests {
+		font := &FontInfo{Flags: test.flags}
+		if got := font.estimatedWidth("a"); abs(got-test.want) > 0.0001 {
+			t.Errorf("flags %d: estimatedWidth = %.4f, want %.4f", test.flags, got, test.want)
+		}
+	}
+	if got := (&FontInfo{AvgWidth: 5000}).estimatedWidth("a"); got != 1 {
+		t.Errorf("damaged AvgWidth: estimatedWidth = %.2f, want 1", got)
+	}
+}
+
+func TestSpaceGlyphWidth(t *testing.T) {
+	// F1's name suggests Courier but its Widths give the space 400/1000 em;
+	// F2 has only its standard font's metrics and F3 neither
+	fonts := types.Dict{
+		"F1": types.Dict{
+			"Subtype":   types.Name("TrueType"),
+			"BaseFont":  types.Name("MonoSansCourierLike"),
+			"FirstChar": types.Integer(32),
+			"LastChar":  types.Integer(32),
+			"Widths":    types.Array{types.Integer(400)},
+		},
+		"F2": types.Dict{"Subtype": types.Name("Type1"), "BaseFont": types.Name("Times-Roman")},
+		"F3": types.Dict{"Subtype": types.Name("TrueType"), "BaseFont": types.Name("Garamond")},
+	}
+	parser := NewContentStreamParser(nil, types.Dict{"Resources": types.Dict{"Font": fonts}})
+	objects := parser.Parse([]byte(`BT /F1 10 Tf (a) Tj /F2 10 Tf (a) Tj /F3 10 Tf (a) Tj ET`))
+	if len(objects.Chars) != 3 {
+		t.Fatalf("expected 3 chars, got %d", len(objects.Chars))
+	}
+	for i, want := range []float64{0.4, 0.25, defaultSpaceGlyphWidth} {
+		if got := objects.Chars[i].SpaceGlyphWidth(); abs(got-want) > 1e-9 {
+			t.Errorf("font F%d: expected a space width of %v, got %v", i+1, want, got)
+		}
+	}
+
+	// The libraries' characters carry only the font name
+	for name, want := range map[string]float64{"ABCDEF+Arial": 0.278, "Courier": 0.6, "Garamond": defaultSpaceGlyphWidth} {
+		if got := (CharObject{Font: name}).SpaceGlyphWidth(); abs(got-want) > 1e-9 {
+			t.Errorf("font %s: expected a space width of %v, got %v", name, want, got)
+		}
+	}
+}
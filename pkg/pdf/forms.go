@@ -0,0 +1,50 @@
+package pdf
+
+import (
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// FormField is a terminal field of the document's interactive form (/AcroForm)
+type FormField struct {
+	Name  string      // Fully qualified name, the /T of the field and its ancestors joined by dots
+	Type  string      // Field type from /FT (Tx, Btn, Ch or Sig), inherited from ancestors
+	Value string      // From /V, or the text of the widget's appearance stream when /V is absent
+	BBox  BoundingBox // The widget's /Rect in PDF space
+}
+
+// maxFieldTreeDepth bounds the recursion into /Kids of the field tree so
+// that malformed documents with cyclic references terminate
+const maxFieldTreeDepth = 32
+
+// qualifiedFieldName appends a field's partial name to its parent's name
+func qualifiedFieldName(parent, partial string) string {
+	switch {
+	case partial == "":
+		return parent
+	case parent == "":
+		return partial
+	}
+	return parent + "." + partial
+}
+
+// appearanceText recovers the text a viewer displays for a field from the
+// content of its normal appearance stream, one line per text line. Without
+// font resources the parser falls back to default glyph widths. Font streams
+// decode to at most maxStreamSize bytes, 0 for no limit.
+func appearanceText(ctx *model.Context, resources types.Dict, content []byte, maxStreamSize int64) string {
+	parser := NewContentStreamParser(ctx, types.Dict{})
+	parser.maxStreamSize = maxStreamSize
+	parser.setResources(resources)
+	objects := parser.Parse(content)
+	lines := groupCharsIntoTextLines(objects.Chars, 3, false)
+	texts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if text := strings.TrimSpace(extractLineText(line, 3)); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,38 @@
+package pdf
+
+import "testing"
+
+func TestFormFields(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/form_fields.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			fields := doc.FormFields()
+			if len(fields) != 2 {
+				t.Fatalf("expected 2 form fields, got %d: %+v", len(fields), fields)
+			}
+
+			// A field with its value in /V, inheriting its type
+			if got := fields[0]; got.Name != "applicant.name" || got.Type != "Tx" || got.Value != "Jane Doe" {
+				t.Errorf("expected applicant.name of type Tx with value Jane Doe, got %+v", got)
+			}
+			if got := fields[0].BBox; got != (BoundingBox{X0: 72, Y0: 650, X1: 272, Y1: 670}) {
+				t.Errorf("expected the widget's rect as bbox, got %+v", got)
+			}
+
+			// A field whose value is only in its appearance stream
+			if got := fields[1]; got.Name != "city" || got.Value != "Only in appearance" {
+				t.Errorf("expected city with value from its appearance stream, got %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package pdf
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// sampleColorSpace describes how the samples of an image map to colors
+type sampleColorSpace struct {
+	name       string            // DeviceGray, DeviceRGB, DeviceCMYK or Indexed
+	components int               // Samples per pixel, 0 when it has to be inferred from the data
+	base       *sampleColorSpace // Base space of an Indexed space
+	hival      int               // Highest index of an Indexed space
+	lookup     []byte            // Palette of an Indexed space, components of base per entry
+}
+
+// DecodeImageSamples converts the samples of an image XObject into an image,
+// honoring /ColorSpace, /BitsPerComponent, /Decode and /ImageMask. data must
+// already have its stream filters removed, and the entries of dict must be
+// direct objects.
+func DecodeImageSamples(dict types.Dict, data []byte) (image.Image, error) {
+	width, height := dict.IntEntry("Width"), dict.IntEntry("Height")
+	if width == nil || height == nil || *width <= 0 || *height <= 0 {
+		return nil, fmt.Errorf("image has no valid size")
+	}
+	pixels, ok := multiplySizes(*width, *height)
+	if _, fits := multiplySizes(pixels, 4); !ok || !fits {
+		return nil, fmt.Errorf("image too large: %dx%d", *width, *height)
+	}
+
+	isMask := false
+	if mask := dict.BooleanEntry("ImageMask"); mask != nil {
+		isMask = *mask
+	}
+
+	bpc := 8
+	if isMask {
+		bpc = 1
+	} else if entry := dict.IntEntry("BitsPerComponent"); entry != nil {
+		bpc = *entry
+	}
+	switch bpc {
+	case 1, 2, 4, 8, 16:
+	default:
+		return nil, fmt.Errorf("unsupported BitsPerComponent %d", bpc)
+	}
+
+	cs := deviceColorSpace(1)
+	if !isMask {
+		obj, ok := dict.Find("ColorSpace")
+		if !ok {
+			return nil, fmt.Errorf("image has no ColorSpace")
+		}
+		var err error
+		if cs, err = parseSampleColorSpace(obj); err != nil {
+			return nil, err
+		}
+		if cs.components == 0 {
+			// An ICC profile stream that is not at hand; the data tells how
+			// many components each pixel has
+			bits, fits := multiplySizes(pixels, bpc)
+			if !fits {
+				return nil, fmt.Errorf("image too large: %dx%d", *width, *height)
+			}
+			if cs = deviceColorSpace(len(data) * 8 / bits); cs == nil {
+				return nil, fmt.Errorf("cannot infer the components of the ICCBased color space")
+			}
+		}
+	}
+
+	rowBits, ok := multiplySizes(*width, cs.components, bpc)
+	stride := (rowBits + 7) / 8
+	if size, fits := multiplySizes(stride, *height); !ok || !fits || len(data) < size {
+		return nil, fmt.Errorf("image data too short: %d bytes for %dx%d samples", len(data), *width, *height)
+	}
+
+	decode := imageDecodeArray(dict.ArrayEntry("Decode"), cs, bpc)
+	maxValue := float64(int(1)<<bpc - 1)
+
+	img := image.NewRGBA(image.Rect(0, 0, *width, *height))
+	values := make([]float64, cs.components)
+	for y := 0; y < *height; y++ {
+		row := data[y*stride : (y+1)*stride]
+		for x := 0; x < *width; x++ {
+			for c := range values {
+				sample := float64(readSample(row, x*cs.components+c, bpc))
+				values[c] = decode[2*c] + sample*(decode[2*c+1]-decode[2*c])/maxValue
+			}
+
+			if isMask {
+				// Samples decoding to 0 are painted, the others let the
+				// background show through
+				if values[0] < 0.5 {
+					img.Set(x, y, color.RGBA{A: 255})
+				}
+				continue
+			}
+			img.Set(x, y, cs.color(values))
+		}
+	}
+	return img, nil
+}
+
+// parseSampleColorSpace reads an image's /ColorSpace entry
+func parseSampleColorSpace(obj types.Object) (*sampleColorSpace, error) {
+	switch v := obj.(type) {
+	case types.Name:
+		switch v {
+		case "DeviceGray", "G", "CalGray":
+			return deviceColorSpace(1), nil
+		case "DeviceRGB", "RGB", "CalRGB":
+			return deviceColorSpace(3), nil
+		case "DeviceCMYK", "CMYK":
+			return deviceColorSpace(4), nil
+		}
+		return nil, fmt.Errorf("unsupported color space %s", v)
+	case types.Array:
+		if len(v) == 0 {
+			return nil, fmt.Errorf("empty color space array")
+		}
+		family, _ := v[0].(types.Name)
+		switch family {
+		case "CalGray", "CalRGB":
+			return parseSampleColorSpace(family)
+		case "ICCBased":
+			// The profile stream is usually indirect; without it the
+			// component count is inferred from the data
+			var n *int
+			if len(v) > 1 {
+				if stream, ok := v[1].(types.StreamDict); ok {
+					n = stream.IntEntry("N")
+				}
+			}
+			if n == nil {
+				return &sampleColorSpace{}, nil
+			}
+			if cs := deviceColorSpace(*n); cs != nil {
+				return cs, nil
+			}
+			return nil, fmt.Errorf("unsupported ICCBased component count %d", *n)
+		case "Indexed", "I":
+			return parseIndexedColorSpace(v)
+		}
+		return nil, fmt.Errorf("unsupported color space %s", family)
+	}
+	return nil, fmt.Errorf("unsupported color space object %T", obj)
+}
+
+// deviceColorSpace returns the device color space with n components, or nil
+func deviceColorSpace(n int) *sampleColorSpace {
+	switch n {
+	case 1:
+		return &sampleColorSpace{name: "DeviceGray", components: 1}
+	case 3:
+		return &sampleColorSpace{name: "DeviceRGB", components: 3}
+	case 4:
+		return &sampleColorSpace{name: "DeviceCMYK", components: 4}
+	}
+	return nil
+}
+
+// parseIndexedColorSpace reads an [/Indexed base hival lookup] color space
+func parseIndexedColorSpace(v types.Array) (*sampleColorSpace, error) {
+	if len(v) < 4 {
+		return nil, fmt.Errorf("invalid Indexed color space")
+	}
+	base, err := parseSampleColorSpace(v[1])
+	if err != nil {
+		return nil, err
+	}
+	if base.components == 0 {
+		return nil, fmt.Errorf("unsupported Indexed base color space")
+	}
+
+	var lookup []byte
+	switch l := v[3].(type) {
+	case types.StringLiteral:
+		lookup, err = types.Unescape(l.Value())
+	case types.HexLiteral:
+		lookup, err = l.Bytes()
+	case types.StreamDict:
+		lookup = l.Content
+	default:
+		return nil, fmt.Errorf("unsupported Indexed lookup table %T", v[3])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid Indexed lookup table: %w", err)
+	}
+
+	return &sampleColorSpace{
+		name:       "Indexed",
+		components: 1,
+		base:       base,
+		hival:      int(numberValue(v[2])),
+		lookup:     lookup,
+	}, nil
+}
+
+// imageDecodeArray returns the /Decode ranges of each component, falling
+// back to the defaults of the color space
+func imageDecodeArray(arr types.Array, cs *sampleColorSpace, bpc int) []float64 {
+	decode := make([]float64, 2*cs.components)
+	if len(arr) == len(decode) {
+		for i, obj := range arr {
+			decode[i] = numberValue(obj)
+		}
+		return decode
+	}
+
+	for c := 0; c < cs.components; c++ {
+		decode[2*c+1] = 1
+		if cs.name == "Indexed" {
+			decode[2*c+1] = float64(int(1)<<bpc - 1)
+		}
+	}
+	return decode
+}
+
+// readSample returns the index-th sample of a row packed at bpc bits
+func readSample(row []byte, index, bpc int) int {
+	switch bpc {
+	case 8:
+		return int(row[index])
+	case 16:
+		return int(row[2*index])<<8 | int(row[2*index+1])
+	}
+	bit := index * bpc
+	shift := 8 - bpc - bit%8
+	return int(row[bit/8]>>shift) & (1<<bpc - 1)
+}
+
+// color converts decoded component values in [0, 1], or a palette index
+// for Indexed spaces, to a color
+func (cs *sampleColorSpace) color(values []float64) color.RGBA {
+	switch cs.name {
+	case "Indexed":
+		index := int(max(0, min(math.Round(values[0]), float64(cs.hival))))
+		n := cs.base.components
+		entry := make([]float64, n)
+		for c := range entry {
+			if i := index*n + c; i < len(cs.lookup) {
+				entry[c] = float64(cs.lookup[i]) / 255
+			}
+		}
+		return cs.base.color(entry)
+	case "DeviceRGB":
+		return color.RGBA{R: sampleByte(values[0]), G: sampleByte(values[1]), B: sampleByte(values[2]), A: 255}
+	case "DeviceCMYK":
+		k := 1 - values[3]
+		return color.RGBA{
+			R: sampleByte((1 - values[0]) * k),
+			G: sampleByte((1 - values[1]) * k),
+			B: sampleByte((1 - values[2]) * k),
+			A: 255,
+		}
+	}
+	g := sampleByte(values[0])
+	return color.RGBA{R: g, G: g, B: g, A: 255}
+}
+
+// sampleByte scales a component value in [0, 1] to a byte
+func sampleByte(v float64) uint8 {
+	return uint8(math.Round(max(0, min(1, v)) * 255))
+}
+
+// multiplySizes multiplies positive sizes, reporting false when the product
+// does not fit an int
+func multiplySizes(sizes ...int) (int, bool) {
+	product := 1
+	for _, size := range sizes {
+		if size != 0 && product > math.MaxInt/size {
+			return 0, false
+		}
+		product *= size
+	}
+	return product, true
+}
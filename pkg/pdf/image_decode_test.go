@@ -0,0 +1,184 @@
+package pdf
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestDecodeImageSamplesMask(t *testing.T) {
+	// A 3x2 stencil mask: 0 bits are painted, 1 bits are transparent
+	dict := types.Dict{
+		"Width":     types.Integer(3),
+		"Height":    types.Integer(2),
+		"ImageMask": types.Boolean(true),
+	}
+	img, err := DecodeImageSamples(dict, []byte{0x40, 0xA0})
+	if err != nil {
+		t.Fatalf("DecodeImageSamples failed: %v", err)
+	}
+
+	painted := color.RGBA{A: 255}
+	expected := [][]bool{{true, false, true}, {false, true, false}}
+	for y, row := range expected {
+		for x, paint := range row {
+			got := img.At(x, y).(color.RGBA)
+			if (got == painted) != paint {
+				t.Errorf("pixel (%d, %d): expected painted=%v, got %v", x, y, paint, got)
+			}
+		}
+	}
+
+	// An inverted Decode array paints the 1 bits instead
+	dict["Decode"] = types.Array{types.Integer(1), types.Integer(0)}
+	img, _ = DecodeImageSamples(dict, []byte{0x40, 0xA0})
+	if img.At(1, 0).(color.RGBA) != painted {
+		t.Error("expected a 1 bit to be painted with Decode [1 0]")
+	}
+}
+
+func TestDecodeImageSamplesGray(t *testing.T) {
+	dict := types.Dict{
+		"Width":            types.Integer(2),
+		"Height":           types.Integer(1),
+		"ColorSpace":       types.Name("DeviceGray"),
+		"BitsPerComponent": types.Integer(8),
+	}
+	img, err := DecodeImageSamples(dict, []byte{0x00, 0x80})
+	if err != nil {
+		t.Fatalf("DecodeImageSamples failed: %v", err)
+	}
+	if got := img.At(1, 0).(color.RGBA); got != (color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 255}) {
+		t.Errorf("expected mid gray, got %v", got)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 2 || bounds.Dy() != 1 {
+		t.Errorf("expected a 2x1 image, got %v", bounds)
+	}
+
+	if _, err := DecodeImageSamples(dict, []byte{0x00}); err == nil {
+		t.Error("expected an error for truncated data")
+	}
+
+	// Sizes whose byte count overflows an int must not pass the length check
+	for _, size := range [][2]int64{{1 << 31, 1 << 31}, {1 << 62, 2}, {3, 1 << 61}} {
+		dict["Width"], dict["Height"] = types.Integer(size[0]), types.Integer(size[1])
+		if _, err := DecodeImageSamples(dict, []byte{0x00, 0x80}); err == nil {
+			t.Errorf("expected an error for a %dx%d image", size[0], size[1])
+		}
+	}
+	dict["ColorSpace"] = types.Array{types.Name("ICCBased")}
+	dict["Width"], dict["Height"] = types.Integer(1<<32), types.Integer(1<<30)
+	if _, err := DecodeImageSamples(dict, []byte{0x00, 0x80}); err == nil {
+		t.Error("expected an error for an ICCBased image too large to infer")
+	}
+}
+
+func TestDecodeImageSamplesIndexed(t *testing.T) {
+	// Two-entry RGB palette of red and blue, indexed by 4-bit samples
+	dict := types.Dict{
+		"Width":            types.Integer(2),
+		"Height":           types.Integer(1),
+		"BitsPerComponent": types.Integer(4),
+		"ColorSpace": types.Array{
+			types.Name("Indexed"),
+			types.Name("DeviceRGB"),
+			types.Integer(1),
+			types.NewHexLiteral([]byte{0xFF, 0x00, 0x00, 0x00, 0x00, 0xFF}),
+		},
+	}
+	img, err := DecodeImageSamples(dict, []byte{0x10})
+	if err != nil {
+		t.Fatalf("DecodeImageSamples failed: %v", err)
+	}
+	if got := img.At(0, 0).(color.RGBA); got != (color.RGBA{B: 255, A: 255}) {
+		t.Errorf("expected blue for index 1, got %v", got)
+	}
+	if got := img.At(1, 0).(color.RGBA); got != (color.RGBA{R: 255, A: 255}) {
+		t.Errorf("expected red for index 0, got %v", got)
+	}
+}
+
+func TestPageImage(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/named_image.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, err := doc.GetPage(0)
+			if err != nil {
+				t.Fatalf("failed to get page: %v", err)
+			}
+			img, err := page.Image("Logo")
+			if err != nil {
+				t.Fatalf("Image failed: %v", err)
+			}
+
+			// Matches the XObject dictionary: 2x3 DeviceRGB at 8 bits
+			if img.Name != "Logo" || img.Width != 2 || img.Height != 3 {
+				t.Errorf("expected Logo at 2x3, got %q at %dx%d", img.Name, img.Width, img.Height)
+			}
+			if img.ColorSpace != "DeviceRGB" || img.BitsPerComponent != 8 || img.ImageMask {
+				t.Errorf("expected 8-bit DeviceRGB, got %d-bit %s (mask %v)", img.BitsPerComponent, img.ColorSpace, img.ImageMask)
+			}
+			if len(img.Data) != 2*3*3 {
+				t.Errorf("expected 18 bytes of samples, got %d", len(img.Data))
+			}
+
+			if _, err := page.Image("Missing"); err == nil {
+				t.Error("expected an error for a missing image")
+			}
+		})
+	}
+}
+
+func TestPageImageJPEG(t *testing.T) {
+	file, err := os.ReadFile("../../testdata/scanned.pdf")
+	if err != nil {
+		t.Fatalf("failed to read PDF: %v", err)
+	}
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/scanned.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, err := doc.GetPage(0)
+			if err != nil {
+				t.Fatalf("failed to get page: %v", err)
+			}
+			img, err := page.Image("Im1")
+			if err != nil {
+				t.Fatalf("Image failed: %v", err)
+			}
+
+			// The JPEG comes back as stored, from its SOI to its EOI marker
+			if img.Filter != "DCTDecode" || img.Width != 16 || img.Height != 8 || img.ColorSpace != "DeviceGray" {
+				t.Errorf("expected a 16x8 DeviceGray DCTDecode image, got %s %dx%d %s", img.Filter, img.Width, img.Height, img.ColorSpace)
+			}
+			if !bytes.HasPrefix(img.Data, []byte{0xff, 0xd8}) || !bytes.HasSuffix(img.Data, []byte{0xff, 0xd9}) {
+				t.Fatalf("expected the JPEG file, got %d bytes", len(img.Data))
+			}
+			if !bytes.Contains(file, img.Data) {
+				t.Error("expected the stream bytes of the file")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ExtractedImage is an image XObject taken from a page's resources
+type ExtractedImage struct {
+	Name             string // XObject resource name
+	Width            int
+	Height           int
+	ColorSpace       string // Color space name, or the family of an array color space such as ICCBased
+	BitsPerComponent int
+	ImageMask        bool
+	Filter           string // Format of Data when stored as a standalone image, such as DCTDecode; empty for raw samples
+	Data             []byte // The image file for standalone formats, the decoded samples otherwise
+}
+
+// isStandaloneImageFilter tells whether a filter's output is an image file
+// format that is kept as stored rather than decoded to samples
+func isStandaloneImageFilter(filter string) bool {
+	switch filter {
+	case "DCTDecode", "JPXDecode", "JBIG2Decode", "CCITTFaxDecode":
+		return true
+	}
+	return false
+}
+
+// imageColorSpaceName returns the name of a color space, or the family of a
+// color space array
+func imageColorSpaceName(obj types.Object) string {
+	switch v := obj.(type) {
+	case types.Name:
+		return v.Value()
+	case types.Array:
+		if len(v) > 0 {
+			if family, ok := v[0].(types.Name); ok {
+				return family.Value()
+			}
+		}
+	}
+	return ""
+}
+
+// libraryImage reads an image XObject stream through the ledongthuc or
+// dslipak library. The libraries cannot decode the standalone formats, so
+// an image stored with just one of those filters is read as stored from
+// file. file is nil when the document's streams are encrypted, and such
+// images cannot be read then.
+func libraryImage[V libraryValue[V]](name string, xobject V, file io.ReaderAt, decode func(V) ([]byte, error)) (ExtractedImage, error) {
+	if xobject.Key("Subtype").Name() != "Image" {
+		return ExtractedImage{}, fmt.Errorf("XObject %s is not an image", name)
+	}
+
+	image := ExtractedImage{
+		Name:             name,
+		Width:            int(xobject.Key("Width").Int64()),
+		Height:           int(xobject.Key("Height").Int64()),
+		BitsPerComponent: int(xobject.Key("BitsPerComponent").Int64()),
+		ImageMask:        xobject.Key("ImageMask").Bool(),
+	}
+	if colorSpace := xobject.Key("ColorSpace"); colorSpace.Len() > 0 {
+		image.ColorSpace = colorSpace.Index(0).Name()
+	} else {
+		image.ColorSpace = colorSpace.Name()
+	}
+
+	filters := xobject.Key("Filter")
+	filter := filters.Name()
+	if n := filters.Len(); n > 0 {
+		filter = filters.Index(n - 1).Name()
+	}
+	if isStandaloneImageFilter(filter) {
+		if filters.Len() > 1 || file == nil {
+			return ExtractedImage{}, fmt.Errorf("%w: image %s is stored as %s, which this backend cannot read", ErrUnsupportedFilter, name, filter)
+		}
+		data, err := libraryRawStream(xobject, file)
+		if err != nil {
+			return ExtractedImage{}, fmt.Errorf("failed to read image %s: %w", name, err)
+		}
+		image.Filter = filter
+		image.Data = data
+		return image, nil
+	}
+	data, err := decode(xobject)
+	if err != nil {
+		return ExtractedImage{}, fmt.Errorf("failed to decode image %s: %w", name, err)
+	}
+	image.Data = data
+	return image, nil
+}
+
+// libraryRawStream reads the bytes of a library stream as stored in file.
+// The libraries only tell where a stream starts through its String, which
+// ends in "@offset"
+func libraryRawStream[V libraryValue[V]](stream V, file io.ReaderAt) ([]byte, error) {
+	s := stream.String()
+	at := strings.LastIndexByte(s, '@')
+	offset, err := strconv.ParseInt(s[at+1:], 10, 64)
+	if at < 0 || err != nil || offset < 0 {
+		return nil, fmt.Errorf("no stream offset in %.40q", s)
+	}
+	length := stream.Key("Length").Int64()
+	if length < 0 {
+		return nil, fmt.Errorf("invalid stream length %d", length)
+	}
+	// Reading to the end of the file at most keeps a bogus /Length from
+	// allocating more than the file holds
+	return io.ReadAll(io.NewSectionReader(file, offset, length))
+}
@@ -0,0 +1,97 @@
+package pdf
+
+import (
+	"sort"
+)
+
+// DocumentInfo is an overview of a document in one call, for triage,
+// dashboards and logging
+type DocumentInfo struct {
+	Metadata  Metadata
+	PageCount int
+	Pages     []PageInfo        // Object counts of each page
+	Encrypted bool              // The trailer has an /Encrypt dictionary
+	Tagged    bool              // The catalog's /MarkInfo marks the document as tagged
+	HasTables bool              // Some page has a table
+	Fonts     []string          // Base fonts of the pages' and their form XObjects' font resources, sorted
+	Issues    []ValidationIssue // Problems found by Validate
+}
+
+// PageInfo counts the objects of a page
+type PageInfo struct {
+	Chars  int
+	Lines  int
+	Rects  int
+	Images int // Images drawn, or image XObjects in the resources for backends that do not extract images
+	Tables int // Tables found with the default extraction options
+}
+
+// documentInfo builds the overview of a document from its pages, which
+// describe themselves as they do for validation
+func documentInfo(doc Document, encrypted, tagged bool) DocumentInfo {
+	info := DocumentInfo{
+		Metadata:  doc.GetMetadata(),
+		PageCount: doc.PageCount(),
+		Encrypted: encrypted,
+		Tagged:    tagged,
+		Issues:    doc.Validate(),
+	}
+
+	fonts := make(map[string]bool)
+	for _, page := range doc.GetPages() {
+		objects := page.GetObjects()
+		pageInfo := PageInfo{
+			Chars:  len(objects.Chars),
+			Lines:  len(objects.Lines),
+			Rects:  len(objects.Rects),
+			Images: len(objects.Images),
+			Tables: len(page.ExtractTables()),
+		}
+		if p, ok := page.(interface{ validationFacts() pageFacts }); ok {
+			facts := p.validationFacts()
+			pageInfo.Images = facts.images
+			for _, font := range facts.fonts {
+				if font.baseFont != "" {
+					fonts[font.baseFont] = true
+				}
+			}
+			for _, font := range facts.formFonts {
+				fonts[font] = true
+			}
+		}
+		info.HasTables = info.HasTables || pageInfo.Tables > 0
+		info.Pages = append(info.Pages, pageInfo)
+	}
+
+	for font := range fonts {
+		info.Fonts = append(info.Fonts, font)
+	}
+	sort.Strings(info.Fonts)
+	return info
+}
+
+// libraryFormFonts returns the base fonts of the form XObjects in resources
+// of the ledongthuc or dslipak library, and of the forms those hold in turn.
+// Forms are told apart by their String, which ends in the stream's offset,
+// so that each is looked at once.
+func libraryFormFonts[V libraryValue[V]](resources V, seen map[string]bool) []string {
+	var fonts []string
+	xobjects := resources.Key("XObject")
+	for _, name := range xobjects.Keys() {
+		form := xobjects.Key(name)
+		if form.Key("Subtype").Name() != "Form" || seen[form.String()] {
+			continue
+		}
+		seen[form.String()] = true
+
+		own := form.Key("Resources")
+		fontDict := own.Key("Font")
+		for _, fontName := range fontDict.Keys() {
+			if baseFont := fontDict.Key(fontName).Key("BaseFont").Name(); baseFont != "" {
+				fonts = append(fonts, baseFont)
+			}
+		}
+		fonts = append(fonts, libraryFormFonts(own, seen)...)
+	}
+	return fonts
+}
@@ -0,0 +1,61 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentInfo(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/grid_table.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			info := doc.Info()
+			if info.PageCount != 1 || len(info.Pages) != 1 || info.Pages[0].Chars == 0 {
+				t.Errorf("expected one page with chars, got %d pages %+v", info.PageCount, info.Pages)
+			}
+			if !reflect.DeepEqual(info.Fonts, []string{"Courier"}) {
+				t.Errorf("expected fonts [Courier], got %v", info.Fonts)
+			}
+			if info.Encrypted || info.Tagged || len(info.Issues) != 0 {
+				t.Errorf("expected a plain document without issues, got %+v", info)
+			}
+
+			// The libraries extract no rules, and find the table by its text
+			if !info.HasTables || info.Pages[0].Tables != 1 {
+				t.Errorf("expected a table, got %+v", info.Pages[0])
+			}
+			if name == "pdfcpu" && info.Pages[0].Lines == 0 {
+				t.Errorf("expected the table's rules, got %+v", info.Pages[0])
+			}
+
+			// Helvetica is only in the resources of a form XObject
+			formDoc, err := open("../../testdata/form_resources.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer formDoc.Close()
+			if fonts := formDoc.Info().Fonts; !reflect.DeepEqual(fonts, []string{"Courier", "Helvetica"}) {
+				t.Errorf("expected fonts [Courier Helvetica], got %v", fonts)
+			}
+		})
+	}
+
+	doc, err := Open("../../testdata/tagged_order.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	if info := doc.Info(); !info.Tagged {
+		t.Errorf("expected a tagged document, got %+v", info)
+	}
+}
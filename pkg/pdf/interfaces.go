@@ -1,7 +1,9 @@
 package pdf
 
 import (
+	"context"
 	"io"
+	"iter"
 )
 
 // Document represents a PDF document with methods similar to pdfplumber.PDF
@@ -12,12 +14,45 @@ type Document interface {
 	// GetPages returns all pages in the document
 	GetPages() []Page
 	
+	// Pages yields the pages one at a time without keeping the objects
+	// extracted from them, so large documents are read in bounded memory
+	Pages(ctx context.Context) iter.Seq2[int, Page]
+	
 	// GetPage returns a specific page by index (0-based)
 	GetPage(index int) (Page, error)
 	
 	// PageCount returns the total number of pages
 	PageCount() int
 	
+	// PageDimensions returns the displayed size of every page without
+	// parsing page content
+	PageDimensions() []PageDim
+	
+	// Validate reports structural problems that affect extraction
+	Validate() []ValidationIssue
+	
+	// Attachments returns the files embedded in the document
+	Attachments() []Attachment
+	
+	// FormFields returns the fields of the document's interactive form
+	FormFields() []FormField
+	
+	// Language returns the document's natural language, such as en-US
+	Language() string
+	
+	// Search finds a pattern in the text of all pages
+	Search(pattern string, opts ...SearchOption) []DocSearchMatch
+	
+	// ExtractBetween returns the text between two anchors in reading order
+	ExtractBetween(start, end string, opts ...SearchOption) (string, bool)
+	
+	// ExtractAllTables extracts the tables of all pages concurrently, keyed
+	// by page index
+	ExtractAllTables(workers int, opts ...TableExtractionOption) map[int][]Table
+	
+	// Info returns an overview of the document's metadata, pages and health
+	Info() DocumentInfo
+	
 	// Close releases resources associated with the document
 	Close() error
 }
@@ -36,6 +71,10 @@ type Page interface {
 	// GetRotation returns the page rotation in degrees
 	GetRotation() int
 	
+	// UserUnit returns the size of a default user space unit in 1/72 inch,
+	// read from the page's /UserUnit (1 by default)
+	UserUnit() float64
+	
 	// GetBBox returns the page bounding box
 	GetBBox() BoundingBox
 	
@@ -45,14 +84,35 @@ type Page interface {
 	// ExtractText extracts text from the page
 	ExtractText(opts ...TextExtractionOption) string
 	
+	// RecognizeText runs the OCR function set with WithOCRFunc on a page
+	// that has images but no characters, and returns the errors ExtractText
+	// falls back from. Pages with characters give an empty string.
+	// Backends that cannot pass a page's images on return ErrNotImplemented.
+	RecognizeText(opts ...TextExtractionOption) (string, error)
+	
+	// ExtractTextSpans extracts text lines along with their page and position
+	ExtractTextSpans(opts ...TextExtractionOption) []TextSpan
+	
+	// CharGrid returns the characters placed on the grid of layout-mode text
+	CharGrid(opts ...TextExtractionOption) [][]rune
+	
 	// ExtractWords extracts individual words from the page
 	ExtractWords(opts ...WordExtractionOption) []Word
 	
+	// ColorRuns returns the page's text, in reading order, split into runs
+	// of characters sharing a fill color
+	ColorRuns() []ColoredRun
+	
 	// ExtractTables extracts tables from the page
 	ExtractTables(opts ...TableExtractionOption) []Table
 	
-	// Crop returns a new page cropped to the specified bounding box
-	Crop(bbox BoundingBox) Page
+	// Crop returns a new page with only the objects intersecting the
+	// bounding box, characters clamped to it. Objects keep the page's
+	// coordinates unless WithRelativeCoordinates is given.
+	Crop(bbox BoundingBox, opts ...CropOption) Page
+	
+	// Rotate returns a new page rotated clockwise by a multiple of 90 degrees
+	Rotate(degrees int) Page
 	
 	// WithinBBox filters objects within a bounding box
 	WithinBBox(bbox BoundingBox) Objects
@@ -60,8 +120,34 @@ type Page interface {
 	// Filter filters objects based on a predicate function
 	Filter(predicate func(Object) bool) Objects
 	
+	// Image returns the image XObject with the given resource name
+	Image(name string) (ExtractedImage, error)
+	
+	// LayerNames returns the names of the optional content groups (layers)
+	// the page's content is marked with
+	LayerNames() []string
+	
+	// Comments returns the page's comment and text markup annotations, with
+	// the page text under highlights and other markup
+	Comments() []Comment
+	
+	// PotentialRedactions returns the filled boxes painted over text that
+	// can still be extracted. Backends that do not record the painting
+	// order return ErrNotImplemented.
+	PotentialRedactions() ([]RedactionWarning, error)
+	
+	// ExtractBetween returns the text between two anchors in reading order
+	ExtractBetween(start, end string, opts ...SearchOption) (string, bool)
+	
 	// ToImage renders the page to an image (for visual debugging)
 	ToImage(opts ...ImageOption) (io.Reader, error)
+	
+	// DrawDebug renders the page to a PNG with the objects selected by opts,
+	// and the cells of the tables found on it, outlined
+	DrawDebug(opts DebugOptions) (io.Reader, error)
+	
+	// WriteCharsJSONL writes each character as a line of JSON
+	WriteCharsJSONL(w io.Writer) error
 }
 
 // Object represents a PDF object (char, line, rect, curve, etc.)
@@ -0,0 +1,224 @@
+package pdf
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// maxStructTreeDepth bounds the recursion into the structure tree
+const maxStructTreeDepth = 64
+
+// structTreeLanguages maps the marked-content IDs of each page, keyed by the
+// page's object number, to the /Lang of their nearest structure element that
+// has one
+func (d *PDFDocument) structTreeLanguages() map[int]map[int]string {
+	langs := make(map[int]map[int]string)
+	if !d.walkStructTreeRoot(func(page, mcid int, lang string) {
+		recordMCIDLanguage(langs, page, mcid, lang)
+	}) {
+		return nil
+	}
+	return langs
+}
+
+// walkStructTreeRoot visits the marked content below the structure tree
+// root in tree order. It reports false when the document has no structure
+// tree.
+func (d *PDFDocument) walkStructTreeRoot(visit func(page, mcid int, lang string)) bool {
+	root, err := d.ctx.DereferenceDict(d.ctx.RootDict["StructTreeRoot"])
+	if err != nil || root == nil {
+		return false
+	}
+	d.walkStructTree(root["K"], "", 0, 0, visit)
+	return true
+}
+
+// walkStructTree visits the marked content below a structure tree node with
+// its page and language. lang and page are inherited from the enclosing
+// elements; page is 0 while unknown.
+func (d *PDFDocument) walkStructTree(obj types.Object, lang string, page, depth int, visit func(page, mcid int, lang string)) {
+	if depth > maxStructTreeDepth {
+		return
+	}
+	obj, err := d.ctx.Dereference(obj)
+	if err != nil {
+		return
+	}
+
+	switch v := obj.(type) {
+	case types.Integer:
+		// A marked-content ID on the page of the enclosing element
+		visit(page, int(v), lang)
+	case types.Array:
+		for _, kid := range v {
+			d.walkStructTree(kid, lang, page, depth+1, visit)
+		}
+	case types.Dict:
+		if ref, ok := v["Pg"].(types.IndirectRef); ok {
+			page = ref.ObjectNumber.Value()
+		}
+		if mcid := v.IntEntry("MCID"); mcid != nil {
+			// A marked-content reference
+			visit(page, *mcid, lang)
+			return
+		}
+		if elemLang := d.textString(v["Lang"]); elemLang != "" {
+			lang = elemLang
+		}
+		d.walkStructTree(v["K"], lang, page, depth+1, visit)
+	}
+}
+
+// libraryStructTreeLanguages maps the marked-content IDs of a page to the
+// /Lang of their nearest structure element that has one, through the
+// libraries
+func libraryStructTreeLanguages[V libraryValue[V]](catalog, page V) map[int]string {
+	langs := make(map[int]string)
+	libraryWalkStructTree(catalog, page, func(mcid int, lang string) {
+		if lang != "" {
+			langs[mcid] = lang
+		}
+	})
+	return langs
+}
+
+// libraryWalkStructTree visits the marked content of a page below the
+// structure tree root in tree order, with its language, through the
+// libraries. The libraries give no object numbers, so the pages of
+// structure elements are matched by their dictionaries.
+func libraryWalkStructTree[V libraryValue[V]](catalog, page V, visit func(mcid int, lang string)) {
+	root := catalog.Key("StructTreeRoot")
+	if len(root.Keys()) == 0 {
+		return
+	}
+	pageKey := page.String()
+	var walk func(node V, lang string, onPage bool, depth int)
+	walk = func(node V, lang string, onPage bool, depth int) {
+		if depth > maxStructTreeDepth || node.IsNull() {
+			return
+		}
+		if n := node.Len(); n > 0 {
+			for i := 0; i < n; i++ {
+				walk(node.Index(i), lang, onPage, depth+1)
+			}
+			return
+		}
+		keys := node.Keys()
+		if len(keys) == 0 {
+			// A marked-content ID on the page of the enclosing element
+			if onPage {
+				visit(int(node.Float64()), lang)
+			}
+			return
+		}
+		if pg := node.Key("Pg"); !pg.IsNull() {
+			onPage = pg.String() == pageKey
+		}
+		if slices.Contains(keys, "MCID") {
+			// A marked-content reference
+			if onPage {
+				visit(int(node.Key("MCID").Float64()), lang)
+			}
+			return
+		}
+		if elemLang := node.Key("Lang").Text(); elemLang != "" {
+			lang = elemLang
+		}
+		walk(node.Key("K"), lang, onPage, depth+1)
+	}
+	walk(root.Key("K"), "", false, 0)
+}
+
+// recordMCIDLanguage notes the language of a marked-content ID on a page
+func recordMCIDLanguage(langs map[int]map[int]string, page, mcid int, lang string) {
+	if page == 0 || lang == "" {
+		return
+	}
+	if langs[page] == nil {
+		langs[page] = make(map[int]string)
+	}
+	langs[page][mcid] = lang
+}
+
+// beginMarkedContent opens a marked-content sequence (BMC or BDC). A BDC
+// property list with a /Lang, or with an /MCID whose structure element has
+// one, sets the language of the text inside.
+func (p *ContentStreamParser) beginMarkedContent(operands []string, hasProperties bool) {
+	p.langStack = append(p.langStack, p.lang)
+	p.markedContentStack = append(p.markedContentStack, p.markedContentID)
+	p.optionalStack = append(p.optionalStack, p.openOptionalContent(operands, hasProperties))
+	if !hasProperties || len(operands) < 2 {
+		return
+	}
+
+	lang, mcid := "", -1
+	if strings.HasPrefix(operands[1], "/") {
+		// A property list from the Properties resources
+		properties := p.lookupResource("Properties", strings.TrimPrefix(operands[1], "/"))
+		if properties == nil {
+			return
+		}
+		if s, err := types.StringOrHexLiteral(p.resolveObject(properties["Lang"])); err == nil && s != nil {
+			lang = *s
+		}
+		if id := properties.IntEntry("MCID"); id != nil {
+			mcid = *id
+		}
+	} else {
+		// An inline dictionary, as tokens between << and >>
+		for i := 1; i+1 < len(operands); i++ {
+			switch operands[i] {
+			case "/Lang":
+				lang = operandText(operands[i+1])
+			case "/MCID":
+				if id, err := strconv.Atoi(operands[i+1]); err == nil {
+					mcid = id
+				}
+			}
+		}
+	}
+
+	if mcid >= 0 {
+		p.markedContentID = mcid + 1
+	}
+	if lang == "" && mcid >= 0 {
+		lang = p.mcidLangs[mcid]
+	}
+	if lang != "" {
+		p.lang = lang
+	}
+}
+
+// endMarkedContent closes the innermost marked-content sequence (EMC)
+func (p *ContentStreamParser) endMarkedContent() {
+	if len(p.langStack) == 0 {
+		return
+	}
+	p.lang = p.langStack[len(p.langStack)-1]
+	p.langStack = p.langStack[:len(p.langStack)-1]
+	p.markedContentID = p.markedContentStack[len(p.markedContentStack)-1]
+	p.markedContentStack = p.markedContentStack[:len(p.markedContentStack)-1]
+	p.closeOptionalContent(p.optionalStack[len(p.optionalStack)-1])
+	p.optionalStack = p.optionalStack[:len(p.optionalStack)-1]
+}
+
+// operandText decodes a string operand token, literal or hex, to text
+func operandText(token string) string {
+	var s string
+	var err error
+	switch {
+	case strings.HasPrefix(token, "(") && strings.HasSuffix(token, ")"):
+		s, err = types.StringLiteralToString(types.StringLiteral(token[1 : len(token)-1]))
+	case strings.HasPrefix(token, "<") && strings.HasSuffix(token, ">"):
+		s, err = types.HexLiteralToString(types.HexLiteral(token[1 : len(token)-1]))
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,242 @@
+package pdf
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// WithVisibleLayersOnly drops content in optional content groups (layers)
+// that the document's default configuration, /OCProperties /D, turns off.
+// Only documents opened with Open apply it; the ledongthuc and dslipak
+// backends fail to open with ErrNotImplemented.
+func WithVisibleLayersOnly(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.VisibleLayersOnly = enabled
+	}
+}
+
+// optionalContent is the layer state of an open marked-content sequence
+type optionalContent struct {
+	layer  string       // Layer name, empty for sequences that are not /OC
+	hidden bool         // The content of the sequence is dropped
+	start  objectCounts // Objects emitted before the sequence began
+}
+
+// objectCounts holds the lengths of the object slices the content parser fills
+type objectCounts struct {
+	chars, lines, rects, curves, images, shadings int
+}
+
+// countObjects returns how many objects of each type have been emitted
+func (p *ContentStreamParser) countObjects() objectCounts {
+	return objectCounts{
+		chars:    len(p.objects.Chars),
+		lines:    len(p.objects.Lines),
+		rects:    len(p.objects.Rects),
+		curves:   len(p.objects.Curves),
+		images:   len(p.objects.Images),
+		shadings: len(p.objects.Shadings),
+	}
+}
+
+// openOptionalContent returns the layer state of a marked-content sequence
+// being opened. Only BDC /OC sequences naming an entry of the Properties
+// resources belong to a layer.
+func (p *ContentStreamParser) openOptionalContent(operands []string, hasProperties bool) optionalContent {
+	oc := optionalContent{start: p.countObjects()}
+	if !hasProperties || len(operands) < 2 || operands[0] != "/OC" || len(operands[1]) < 2 || operands[1][0] != '/' {
+		return oc
+	}
+	entry := p.resourceEntry("Properties", operands[1][1:])
+	if entry == nil {
+		return oc
+	}
+	oc.layer, oc.hidden = p.resolveOptionalContent(entry)
+	if oc.layer != "" {
+		p.recordLayer(oc.layer)
+	}
+	return oc
+}
+
+// resolveOptionalContent returns the layer name of an optional content
+// group or membership dictionary, and whether its content is hidden. A
+// membership dictionary is named after its first group.
+func (p *ContentStreamParser) resolveOptionalContent(obj types.Object) (string, bool) {
+	dict := p.dereferenceDict(obj)
+	if dict == nil {
+		return "", false
+	}
+	if dict.Type() == nil || *dict.Type() != "OCMD" {
+		return p.layerName(dict), p.hiddenLayers[objectNumber(obj)]
+	}
+
+	groups := []types.Object{dict["OCGs"]}
+	if array, ok := p.resolveObject(dict["OCGs"]).(types.Array); ok {
+		groups = array
+	}
+	name, visible := "", 0
+	for _, group := range groups {
+		if name == "" {
+			name = p.layerName(p.dereferenceDict(group))
+		}
+		if !p.hiddenLayers[objectNumber(group)] {
+			visible++
+		}
+	}
+
+	// The visibility policy defaults to AnyOn
+	policy := "AnyOn"
+	if entry := dict.NameEntry("P"); entry != nil {
+		policy = *entry
+	}
+	switch policy {
+	case "AllOn":
+		return name, visible < len(groups)
+	case "AnyOff":
+		return name, visible == len(groups)
+	case "AllOff":
+		return name, visible > 0
+	}
+	return name, len(groups) > 0 && visible == 0
+}
+
+// layerName returns the /Name of an optional content group
+func (p *ContentStreamParser) layerName(group types.Dict) string {
+	if group == nil {
+		return ""
+	}
+	s, err := types.StringOrHexLiteral(p.resolveObject(group["Name"]))
+	if err != nil || s == nil {
+		return ""
+	}
+	return *s
+}
+
+// recordLayer notes a layer seen in the content, once
+func (p *ContentStreamParser) recordLayer(name string) {
+	for _, layer := range p.layers {
+		if layer == name {
+			return
+		}
+	}
+	p.layers = append(p.layers, name)
+}
+
+// closeOptionalContent drops the objects of a hidden sequence being closed,
+// or tags them with the sequence's layer unless an inner layer already has
+func (p *ContentStreamParser) closeOptionalContent(oc optionalContent) {
+	start := oc.start
+	if oc.hidden {
+		// The object limit may have cut the slices shorter than at the start
+		keep := func(start, length int) int {
+			if start < length {
+				return start
+			}
+			return length
+		}
+		p.objects.Chars = p.objects.Chars[:keep(start.chars, len(p.objects.Chars))]
+		p.objects.Lines = p.objects.Lines[:keep(start.lines, len(p.objects.Lines))]
+		p.objects.Rects = p.objects.Rects[:keep(start.rects, len(p.objects.Rects))]
+		p.objects.Curves = p.objects.Curves[:keep(start.curves, len(p.objects.Curves))]
+		p.objects.Images = p.objects.Images[:keep(start.images, len(p.objects.Images))]
+		p.objects.Shadings = p.objects.Shadings[:keep(start.shadings, len(p.objects.Shadings))]
+		return
+	}
+	if oc.layer == "" {
+		return
+	}
+	for i := start.chars; i < len(p.objects.Chars); i++ {
+		if p.objects.Chars[i].Layer == "" {
+			p.objects.Chars[i].Layer = oc.layer
+		}
+	}
+	for i := start.lines; i < len(p.objects.Lines); i++ {
+		if p.objects.Lines[i].Layer == "" {
+			p.objects.Lines[i].Layer = oc.layer
+		}
+	}
+	for i := start.rects; i < len(p.objects.Rects); i++ {
+		if p.objects.Rects[i].Layer == "" {
+			p.objects.Rects[i].Layer = oc.layer
+		}
+	}
+	for i := start.curves; i < len(p.objects.Curves); i++ {
+		if p.objects.Curves[i].Layer == "" {
+			p.objects.Curves[i].Layer = oc.layer
+		}
+	}
+	for i := start.images; i < len(p.objects.Images); i++ {
+		if p.objects.Images[i].Layer == "" {
+			p.objects.Images[i].Layer = oc.layer
+		}
+	}
+	for i := start.shadings; i < len(p.objects.Shadings); i++ {
+		if p.objects.Shadings[i].Layer == "" {
+			p.objects.Shadings[i].Layer = oc.layer
+		}
+	}
+}
+
+// closeAllOptionalContent closes the sequences left open at the end of the
+// content, so hidden content is dropped even when EMC is missing
+func (p *ContentStreamParser) closeAllOptionalContent() {
+	for len(p.optionalStack) > 0 {
+		p.closeOptionalContent(p.optionalStack[len(p.optionalStack)-1])
+		p.optionalStack = p.optionalStack[:len(p.optionalStack)-1]
+	}
+}
+
+// objectNumber returns the object number of an indirect reference, 0 for
+// direct objects
+func objectNumber(obj types.Object) int {
+	switch ref := obj.(type) {
+	case types.IndirectRef:
+		return ref.ObjectNumber.Value()
+	case *types.IndirectRef:
+		if ref != nil {
+			return ref.ObjectNumber.Value()
+		}
+	}
+	return 0
+}
+
+// hiddenLayers returns the object numbers of the optional content groups
+// the default configuration turns off
+func (d *PDFDocument) hiddenLayers() map[int]bool {
+	properties, err := d.ctx.DereferenceDict(d.ctx.RootDict["OCProperties"])
+	if err != nil || properties == nil {
+		return nil
+	}
+	config, err := d.ctx.DereferenceDict(properties["D"])
+	if err != nil || config == nil {
+		return nil
+	}
+
+	hidden := make(map[int]bool)
+	if base := config.NameEntry("BaseState"); base != nil && *base == "OFF" {
+		for _, n := range d.refNumbers(properties["OCGs"]) {
+			hidden[n] = true
+		}
+		for _, n := range d.refNumbers(config["ON"]) {
+			delete(hidden, n)
+		}
+	}
+	for _, n := range d.refNumbers(config["OFF"]) {
+		hidden[n] = true
+	}
+	return hidden
+}
+
+// refNumbers returns the object numbers of the references in an array
+func (d *PDFDocument) refNumbers(obj types.Object) []int {
+	array, err := d.ctx.DereferenceArray(obj)
+	if err != nil {
+		return nil
+	}
+	var numbers []int
+	for _, item := range array {
+		if n := objectNumber(item); n != 0 {
+			numbers = append(numbers, n)
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVisibleLayersOnly(t *testing.T) {
+	doc, err := Open("../../testdata/layers.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// By default hidden layers are kept, their objects tagged with the layer
+	text := page.ExtractText()
+	if !strings.Contains(text, "Secret") {
+		t.Errorf("expected the hidden layer's text by default, got %q", text)
+	}
+	layers := make(map[string]string)
+	for _, char := range page.GetObjects().Chars {
+		layers[char.Text] = char.Layer
+	}
+	if layers["P"] != "" || layers["w"] != "Base" || layers["c"] != "Notes" {
+		t.Errorf("expected chars tagged none, Base and Notes, got %v", layers)
+	}
+	if lines := page.GetObjects().Lines; len(lines) != 1 || lines[0].Layer != "Notes" {
+		t.Errorf("expected one line in Notes, got %+v", lines)
+	}
+
+	doc, err = Open("../../testdata/layers.pdf", WithVisibleLayersOnly(true))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ = doc.GetPage(0)
+
+	text = page.ExtractText()
+	if strings.Contains(text, "Secret") || !strings.Contains(text, "Plain") || !strings.Contains(text, "Shown") {
+		t.Errorf("expected only the visible text, got %q", text)
+	}
+	if lines := page.GetObjects().Lines; len(lines) != 0 {
+		t.Errorf("expected the hidden line to be dropped, got %+v", lines)
+	}
+	if names := page.LayerNames(); !reflect.DeepEqual(names, []string{"Base", "Notes"}) {
+		t.Errorf("expected layers [Base Notes], got %v", names)
+	}
+}
+
+func TestVisibleLayersOnlyIndirect(t *testing.T) {
+	// The OCProperties, its default configuration and /OFF array, the
+	// Properties resources, the hidden group and its /Name are all indirect
+	// objects; /P2 is a membership dictionary over the hidden group
+	doc, err := Open("../../testdata/layers_indirect.pdf", WithVisibleLayersOnly(true))
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	text := page.ExtractText()
+	if strings.Contains(text, "Secret") || strings.Contains(text, "Member") || !strings.Contains(text, "Shown") {
+		t.Errorf("expected only the visible text, got %q", text)
+	}
+	if lines := page.GetObjects().Lines; len(lines) != 0 {
+		t.Errorf("expected the hidden line to be dropped, got %+v", lines)
+	}
+	want := []string{"Base", "Hidden Notes"}
+	if names := page.LayerNames(); !reflect.DeepEqual(names, want) {
+		t.Errorf("expected layers %v, got %v", want, names)
+	}
+
+	for name, open := range map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	} {
+		doc, err := open("../../testdata/layers_indirect.pdf")
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		page, _ := doc.GetPage(0)
+		if names := page.LayerNames(); !reflect.DeepEqual(names, want) {
+			t.Errorf("%s: expected layers %v, got %v", name, want, names)
+		}
+		doc.Close()
+	}
+}
@@ -0,0 +1,94 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Grid cell size of layout-mode text, matching pdfplumber's x_density and
+// y_density defaults
+const (
+	layoutXDensity = 7.25 // Points per grid column
+	layoutYDensity = 13.0 // Points per grid row
+)
+
+// charGrid places characters on a grid of runes by quantizing their top-left
+// corners, with spaces for the gaps. The grid covers bbox, one row per
+// layoutYDensity points and one column per layoutXDensity points; a row grows
+// past that width when characters collide and have to shift right.
+// topDown tells whether Y grows downwards (true) or upwards as in raw PDF space (false).
+func charGrid(chars []CharObject, bbox BoundingBox, topDown bool) [][]rune {
+	bbox = bbox.Normalize()
+	rows := int(math.Ceil((bbox.Y1 - bbox.Y0) / layoutYDensity))
+	cols := int(math.Ceil((bbox.X1 - bbox.X0) / layoutXDensity))
+
+	grid := make([][]rune, rows)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", cols))
+	}
+
+	// Place characters left to right, each row keeping the next free column
+	// so that characters closer than a column apart push later ones on
+	next := make([]int, rows)
+	sorted := append([]CharObject{}, chars...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return min(sorted[i].X0, sorted[i].X1) < min(sorted[j].X0, sorted[j].X1)
+	})
+
+	for _, char := range sorted {
+		top := bbox.Y1 - max(char.Y0, char.Y1)
+		if topDown {
+			top = min(char.Y0, char.Y1) - bbox.Y0
+		}
+		row := int(math.Round(top / layoutYDensity))
+		col := int(math.Round((min(char.X0, char.X1) - bbox.X0) / layoutXDensity))
+		// Tops in the bottom half of the last row round past the grid
+		if row == rows && top <= bbox.Y1-bbox.Y0 {
+			row = rows - 1
+		}
+		if row < 0 || row >= rows || col < 0 {
+			continue
+		}
+
+		if col < next[row] {
+			col = next[row]
+		}
+		for _, r := range char.Text {
+			for col >= len(grid[row]) {
+				grid[row] = append(grid[row], ' ')
+			}
+			grid[row][col] = r
+			col++
+		}
+		next[row] = col
+	}
+
+	return grid
+}
+
+// layoutText renders a char grid as text, dropping trailing spaces of rows
+// and trailing blank rows
+func layoutText(grid [][]rune) string {
+	lines := make([]string, len(grid))
+	for i, row := range grid {
+		lines[i] = strings.TrimRight(string(row), " ")
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pageCharGrid builds the char grid of a page's characters after applying the
+// text extraction options that select characters
+func pageCharGrid(chars []CharObject, bbox BoundingBox, topDown bool, opts ...TextExtractionOption) [][]rune {
+	config := &textExtractionConfig{RotatedTextTolerance: defaultRotatedTextTolerance}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, config.RotatedTextTolerance)
+	}
+	return charGrid(chars, bbox, topDown)
+}
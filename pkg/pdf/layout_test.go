@@ -0,0 +1,50 @@
+package pdf
+
+import "testing"
+
+func TestCharGrid(t *testing.T) {
+	doc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	grid := page.CharGrid()
+
+	// A 612x792 page is 85 columns of 7.25pt by 61 rows of 13pt
+	if len(grid) != 61 || len(grid[0]) != 85 {
+		t.Fatalf("expected a 61x85 grid, got %dx%d", len(grid), len(grid[0]))
+	}
+
+	// "First page" starts at x=72 with its top 60pt below the top of the page
+	if got := string(grid[5][10:20]); got != "First page" {
+		t.Errorf("expected %q at row 5, column 10, got %q", "First page", got)
+	}
+	if got := grid[6][10]; got != 'S' {
+		t.Errorf("expected 'S' at row 6, column 10, got %q", got)
+	}
+}
+
+func TestCharGridBottomRow(t *testing.T) {
+	// 792pt is 60.9 rows of 13pt. A top 785pt down rounds to the last row,
+	// 60, one 790pt down past it, and one 797pt down is below the page.
+	bbox := BoundingBox{X1: 612, Y1: 792}
+	chars := []CharObject{
+		{Text: "a", X0: 72, X1: 78, Y0: 2, Y1: 7},
+		{Text: "b", X0: 144, X1: 150, Y0: 0, Y1: 2},
+		{Text: "c", X0: 216, X1: 222, Y0: -10, Y1: -5},
+	}
+	grid := charGrid(chars, bbox, false)
+	if len(grid) != 61 {
+		t.Fatalf("expected 61 rows, got %d", len(grid))
+	}
+	if grid[60][10] != 'a' || grid[60][20] != 'b' {
+		t.Errorf("expected both chars on the last row, got %q", string(grid[60]))
+	}
+	for _, r := range grid[60][21:] {
+		if r != ' ' {
+			t.Errorf("expected the char below the page dropped, got %q", string(grid[60]))
+		}
+	}
+}
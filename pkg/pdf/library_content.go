@@ -0,0 +1,516 @@
+package pdf
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// libraryText is a glyph shown through the ledongthuc or dslipak library,
+// with the fields of the libraries' Text
+type libraryText struct {
+	Font     string
+	FontSize float64
+	X        float64
+	Y        float64
+	W        float64
+	S        string
+}
+
+// libraryFont is a font read through the libraries
+type libraryFont interface {
+	BaseFont() string
+	Width(code int) float64
+	decode(raw string) string
+}
+
+// libraryContent interprets content streams through the ledongthuc or
+// dslipak library, for the text of the form XObjects and annotation
+// appearances the libraries' page content leaves out
+type libraryContent[V libraryValue[V]] struct {
+	interpret func(stream V, do func(op string, args []V))
+	font      func(dict V) libraryFont
+	tjBreak   bool // The library ends the text of each TJ with a newline glyph
+}
+
+// glyphMark is what the content stream tells of a glyph that the
+// libraries' Text leaves out
+type glyphMark struct {
+	render int    // Text render mode (Tr)
+	mcid   int    // MCID + 1 of the enclosing marked content, 0 outside any
+	lang   string // Language from the innermost /Lang, empty outside any
+	order  int    // Place in the page's painting order
+	color  Color  // Fill color the glyph is painted in
+}
+
+// formText is the text of a form, to go after the given number of glyphs
+// of the library's page text
+type formText struct {
+	at    int
+	text  []libraryText
+	marks []glyphMark
+}
+
+// libraryTextState is the part of the graphics state that places text
+type libraryTextState struct {
+	ctm       Matrix
+	font      libraryFont
+	size      float64
+	charSpace float64
+	wordSpace float64
+	scale     float64
+	leading   float64
+	rise      float64
+	render    int
+	fill      Color // Fill color, of filled paths and of text
+}
+
+// libraryWalk collects the text of forms while marking the glyphs the
+// library shows for the page itself
+type libraryWalk[V libraryValue[V]] struct {
+	libraryContent[V]
+	marks     []glyphMark    // Marks of the page text shown so far
+	mcid      int            // MCID + 1 of the page's marked content
+	mcids     []int          // Enclosing marked content of the page
+	lang      string         // Language of the page's marked content
+	langs     []string       // Languages outside each open marked content
+	mcidLangs map[int]string // Languages of the page's MCIDs from the structure tree
+	pending   formText       // Text of the form being painted from the page
+	forms     []formText
+	fills     []RectObject // Filled rectangles, in PDF space
+	painted   int          // Glyphs and fills painted so far
+}
+
+// formTexts returns the text of the forms a page's content paints with Do,
+// then of the normal appearances of its visible annotations, the marks of
+// the glyphs of the page's own text, and the rectangles filled by the page
+// and its forms, which the libraries do not read. Appearances without
+// resources use the document's default form resources. mcidLangs gives the
+// languages of the page's marked-content IDs from the structure tree.
+func (c libraryContent[V]) formTexts(page, resources, defaults V, mcidLangs map[int]string) (forms []formText, marks []glyphMark, fills []RectObject) {
+	w := &libraryWalk[V]{libraryContent: c, mcidLangs: mcidLangs}
+	defer func() {
+		// The libraries panic on malformed content; the forms, marks and
+		// fills read until then are kept
+		if recover() != nil {
+			forms, marks, fills = w.forms, w.marks, w.fills
+		}
+	}()
+	if contents := page.Key("Contents"); contents.Len() > 0 || len(contents.Keys()) > 0 {
+		w.walk(contents, resources, IdentityMatrix(), 0)
+	}
+
+	annots := page.Key("Annots")
+	for i := 0; i < annots.Len(); i++ {
+		annot := annots.Index(i)
+		if flags := int(annot.Key("F").Float64()); flags&(annotationHidden|annotationNoView) != 0 {
+			continue
+		}
+		appearance := annot.Key("AP").Key("N")
+		if state := annot.Key("AS").Name(); state != "" && appearance.Key("BBox").Len() != 4 {
+			appearance = appearance.Key(state)
+		}
+		b, rect := appearance.Key("BBox"), annot.Key("Rect")
+		if b.Len() != 4 || rect.Len() != 4 {
+			continue
+		}
+
+		// The form's bounding box, transformed by its matrix, is fitted
+		// to the rectangle
+		box := transformBox(libraryBox(b), libraryMatrix(appearance.Key("Matrix")))
+		target := libraryBox(rect)
+		if box.Width() == 0 || box.Height() == 0 {
+			continue
+		}
+		sx, sy := target.Width()/box.Width(), target.Height()/box.Height()
+		ctm := Matrix{A: sx, D: sy, E: target.X0 - box.X0*sx, F: target.Y0 - box.Y0*sy}
+		formResources := resources
+		if len(defaults.Keys()) > 0 {
+			formResources = defaults
+		}
+		w.paintForm(appearance, formResources, ctm, 0)
+	}
+	return w.forms, w.marks, w.fills
+}
+
+// paintForm walks the content of a form XObject with its matrix composed
+// with the CTM. Forms without resources use those of the enclosing stream.
+func (w *libraryWalk[V]) paintForm(form, resources V, ctm Matrix, depth int) {
+	if depth >= maxFormDepth {
+		return
+	}
+	if own := form.Key("Resources"); len(own.Keys()) > 0 {
+		resources = own
+	}
+	w.walk(form, resources, MultiplyMatrix(libraryMatrix(form.Key("Matrix")), ctm), depth+1)
+	if depth == 0 && len(w.pending.text) > 0 {
+		w.pending.at = len(w.marks)
+		w.forms = append(w.forms, w.pending)
+		w.pending = formText{}
+	}
+}
+
+// walk interprets a content stream. At depth 0, the page's own content,
+// glyphs are only marked; inside forms they are placed as the library
+// places them. Paths made only of rectangles are recorded when filled.
+func (w *libraryWalk[V]) walk(stream, resources V, ctm Matrix, depth int) {
+	state := libraryTextState{ctm: ctm, scale: 1, fill: Color{A: 255}}
+	var stack []libraryTextState
+	tm, tlm := IdentityMatrix(), IdentityMatrix()
+	var path []BoundingBox
+	onlyRects := true
+
+	show := func(raw string) {
+		if state.font == nil {
+			return
+		}
+		decoded := state.font.decode(raw)
+		mark := glyphMark{render: state.render, mcid: w.mcid, lang: w.lang, color: state.fill}
+		if depth == 0 {
+			for range utf8.RuneCountInString(decoded) {
+				w.painted++
+				mark.order = w.painted
+				w.marks = append(w.marks, mark)
+			}
+			return
+		}
+		name := state.font.BaseFont()
+		if i := strings.Index(name, "+"); i >= 0 {
+			name = name[i+1:]
+		}
+		n := 0
+		for _, ch := range decoded {
+			var w0 float64
+			if n < len(raw) {
+				w0 = state.font.Width(int(raw[n]))
+			}
+			n++
+			trm := MultiplyMatrix(MultiplyMatrix(Matrix{A: state.size * state.scale, D: state.size, F: state.rise}, tm), state.ctm)
+			w.pending.text = append(w.pending.text, libraryText{
+				Font:     name,
+				FontSize: trm.A,
+				X:        trm.E,
+				Y:        trm.F,
+				W:        w0 / 1000 * trm.A,
+				S:        string(ch),
+			})
+			w.painted++
+			mark.order = w.painted
+			w.pending.marks = append(w.pending.marks, mark)
+			tx := w0/1000*state.size + state.charSpace
+			if ch == ' ' && len(raw) == len(decoded) {
+				tx += state.wordSpace
+			}
+			tm = MultiplyMatrix(TranslationMatrix(tx*state.scale, 0), tm)
+		}
+	}
+	nextLine := func() {
+		tlm = MultiplyMatrix(TranslationMatrix(0, -state.leading), tlm)
+		tm = tlm
+	}
+
+	w.interpret(stream, func(op string, args []V) {
+		switch op {
+		case "q":
+			stack = append(stack, state)
+		case "Q":
+			if len(stack) > 0 {
+				state, stack = stack[len(stack)-1], stack[:len(stack)-1]
+			}
+		case "cm":
+			if len(args) == 6 {
+				state.ctm = MultiplyMatrix(libraryMatrixOf(args), state.ctm)
+			}
+		case "g", "rg", "k", "sc", "scn":
+			state.fill = libraryFillColor(args)
+		case "cs":
+			state.fill = Color{A: 255}
+		case "re":
+			if len(args) == 4 {
+				x, y := args[0].Float64(), args[1].Float64()
+				box := BoundingBox{X0: x, Y0: y, X1: x + args[2].Float64(), Y1: y + args[3].Float64()}
+				path = append(path, transformBox(box.Normalize(), state.ctm))
+			}
+		case "m", "l", "c", "v", "y", "h":
+			onlyRects = false
+		case "f", "F", "f*", "B", "B*", "b", "b*":
+			if onlyRects {
+				for _, box := range path {
+					w.fill(box, state.fill, op != "f" && op != "F" && op != "f*")
+				}
+			}
+			path, onlyRects = nil, true
+		case "n", "S", "s":
+			path, onlyRects = nil, true
+		case "BT":
+			tm, tlm = IdentityMatrix(), IdentityMatrix()
+		case "Tf":
+			if len(args) == 2 {
+				state.font = w.font(resources.Key("Font").Key(args[0].Name()))
+				state.size = args[1].Float64()
+			}
+		case "Tc":
+			if len(args) == 1 {
+				state.charSpace = args[0].Float64()
+			}
+		case "Tw":
+			if len(args) == 1 {
+				state.wordSpace = args[0].Float64()
+			}
+		case "Tz":
+			if len(args) == 1 {
+				state.scale = args[0].Float64() / 100
+			}
+		case "TL":
+			if len(args) == 1 {
+				state.leading = args[0].Float64()
+			}
+		case "Ts":
+			if len(args) == 1 {
+				state.rise = args[0].Float64()
+			}
+		case "Tr":
+			if len(args) == 1 {
+				state.render = int(args[0].Float64())
+			}
+		case "BMC", "BDC":
+			// Marked-content IDs of forms belong to the forms' own
+			// structure parents, so only the page's are read
+			if depth == 0 {
+				w.mcids = append(w.mcids, w.mcid)
+				w.langs = append(w.langs, w.lang)
+				if op == "BDC" && len(args) == 2 {
+					w.beginMarkedContent(args[1], resources)
+				}
+			}
+		case "EMC":
+			if depth == 0 && len(w.mcids) > 0 {
+				w.mcid, w.mcids = w.mcids[len(w.mcids)-1], w.mcids[:len(w.mcids)-1]
+				w.lang, w.langs = w.langs[len(w.langs)-1], w.langs[:len(w.langs)-1]
+			}
+		case "Td", "TD":
+			if len(args) == 2 {
+				if op == "TD" {
+					state.leading = -args[1].Float64()
+				}
+				tlm = MultiplyMatrix(TranslationMatrix(args[0].Float64(), args[1].Float64()), tlm)
+				tm = tlm
+			}
+		case "Tm":
+			if len(args) == 6 {
+				tm = libraryMatrixOf(args)
+				tlm = tm
+			}
+		case "T*":
+			nextLine()
+		case "Tj":
+			if len(args) == 1 {
+				show(args[0].RawString())
+			}
+		case "'":
+			if len(args) == 1 {
+				nextLine()
+				show(args[0].RawString())
+			}
+		case "\"":
+			if len(args) == 3 {
+				state.wordSpace, state.charSpace = args[0].Float64(), args[1].Float64()
+				nextLine()
+				show(args[2].RawString())
+			}
+		case "TJ":
+			if len(args) != 1 {
+				return
+			}
+			for i := 0; i < args[0].Len(); i++ {
+				item := args[0].Index(i)
+				if raw := item.RawString(); raw != "" {
+					show(raw)
+				} else {
+					tx := -item.Float64() / 1000 * state.size * state.scale
+					tm = MultiplyMatrix(TranslationMatrix(tx, 0), tm)
+				}
+			}
+			mark := glyphMark{render: state.render, mcid: w.mcid, lang: w.lang, color: state.fill}
+			if w.tjBreak && depth == 0 {
+				w.marks = append(w.marks, mark)
+			} else if w.tjBreak {
+				w.pending.text = append(w.pending.text, libraryText{S: "\n"})
+				w.pending.marks = append(w.pending.marks, mark)
+			}
+		case "Do":
+			if len(args) != 1 {
+				return
+			}
+			form := resources.Key("XObject").Key(args[0].Name())
+			if form.Key("Subtype").Name() == "Form" {
+				w.paintForm(form, resources, state.ctm, depth)
+			}
+		}
+	})
+}
+
+// fill records a filled rectangle, in PDF space
+func (w *libraryWalk[V]) fill(box BoundingBox, color Color, stroked bool) {
+	w.painted++
+	w.fills = append(w.fills, RectObject{
+		X0:          box.X0,
+		Y0:          box.Y0,
+		X1:          box.X1,
+		Y1:          box.Y1,
+		FillColor:   color,
+		NonStroking: true,
+		Filled:      true,
+		Stroked:     stroked,
+		order:       w.painted,
+	})
+}
+
+// libraryFillColor reads the operands of a fill color operator as a gray,
+// RGB or CMYK color, told apart by their count. Pattern fills and other
+// operands give an unset color, as the pdfcpu backend gives patterns
+// without a representative solid color.
+func libraryFillColor[V libraryValue[V]](args []V) Color {
+	v := make([]float64, len(args))
+	for i, arg := range args {
+		if arg.Name() != "" {
+			return Color{}
+		}
+		v[i] = arg.Float64()
+	}
+	color, ok := solidColorOf(v)
+	if !ok {
+		return Color{}
+	}
+	return Color{R: uint8(color.R * 255), G: uint8(color.G * 255), B: uint8(color.B * 255), A: 255}
+}
+
+// beginMarkedContent reads the MCID and language of a BDC property list,
+// given inline or as the name of a Properties resource. Without a /Lang of
+// its own, the language is that of the MCID's structure element, if any.
+func (w *libraryWalk[V]) beginMarkedContent(properties, resources V) {
+	if name := properties.Name(); name != "" {
+		properties = resources.Key("Properties").Key(name)
+	}
+	lang := properties.Key("Lang").Text()
+	if slices.Contains(properties.Keys(), "MCID") {
+		mcid := int(properties.Key("MCID").Float64())
+		w.mcid = mcid + 1
+		if lang == "" {
+			lang = w.mcidLangs[mcid]
+		}
+	}
+	if lang != "" {
+		w.lang = lang
+	}
+}
+
+// spliceFormText puts the text of forms into the library's page text, each
+// after the glyphs shown before the form was painted, and returns the marks
+// of the spliced text. Glyphs the walk did not reach are left unmarked.
+func spliceFormText[T any](text []T, marks []glyphMark, forms []formText, convert func(libraryText) T) ([]T, []glyphMark) {
+	if len(marks) > len(text) {
+		marks = marks[:len(text)]
+	}
+	marks = append(marks, make([]glyphMark, len(text)-len(marks))...)
+	if len(forms) == 0 {
+		return text, marks
+	}
+	spliced := make([]T, 0, len(text))
+	splicedMarks := make([]glyphMark, 0, len(text))
+	next := 0
+	for _, form := range forms {
+		at := form.at
+		if at < next {
+			at = next
+		}
+		if at > len(text) {
+			at = len(text)
+		}
+		spliced = append(spliced, text[next:at]...)
+		splicedMarks = append(splicedMarks, marks[next:at]...)
+		for _, glyph := range form.text {
+			spliced = append(spliced, convert(glyph))
+		}
+		splicedMarks = append(splicedMarks, form.marks...)
+		next = at
+	}
+	return append(spliced, text[next:]...), append(splicedMarks, marks[next:]...)
+}
+
+// libraryMatrix reads a matrix array, the identity if there is none
+func libraryMatrix[V libraryValue[V]](array V) Matrix {
+	if array.Len() != 6 {
+		return IdentityMatrix()
+	}
+	values := make([]V, 6)
+	for i := range values {
+		values[i] = array.Index(i)
+	}
+	return libraryMatrixOf(values)
+}
+
+// libraryMatrixOf makes a matrix of six operands
+func libraryMatrixOf[V libraryValue[V]](values []V) Matrix {
+	return Matrix{
+		A: values[0].Float64(),
+		B: values[1].Float64(),
+		C: values[2].Float64(),
+		D: values[3].Float64(),
+		E: values[4].Float64(),
+		F: values[5].Float64(),
+	}
+}
+
+// libraryBox reads a rectangle array, normalized
+func libraryBox[V libraryValue[V]](rect V) BoundingBox {
+	return BoundingBox{
+		X0: rect.Index(0).Float64(),
+		Y0: rect.Index(1).Float64(),
+		X1: rect.Index(2).Float64(),
+		Y1: rect.Index(3).Float64(),
+	}.Normalize()
+}
+
+// popOperands takes the operands of an operator off a library's stack, in
+// the order they were given
+func popOperands[V any](n int, pop func() V) []V {
+	args := make([]V, n)
+	for i := n - 1; i >= 0; i-- {
+		args[i] = pop()
+	}
+	return args
+}
+
+// fillLibraryWidths gives the glyphs of fonts without Widths, which the
+// libraries read as zero-width and all at the position of the first, the
+// AFM widths of the standard font they name, or the estimated widths of a
+// font without metrics if they name none. The glyphs after one in the
+// same run move along by the width it gained; a glyph on another baseline
+// or more than an em from where the library advanced to starts a new run.
+func fillLibraryWidths[T ~struct {
+	Font     string
+	FontSize float64
+	X        float64
+	Y        float64
+	W        float64
+	S        string
+}](text []T) {
+	var shift, end, baseline float64
+	for i := range text {
+		glyph := libraryText(text[i])
+		if glyph.S == "\n" || glyph.S == "\r" {
+			continue
+		}
+		if abs(glyph.Y-baseline) > 0.01 || abs(glyph.X-end) > abs(glyph.FontSize) {
+			shift = 0
+		}
+		end, baseline = glyph.X+glyph.W, glyph.Y
+		glyph.X += shift
+		if glyph.W == 0 {
+			glyph.W = libraryTextWidth(glyph.Font, glyph.S) * glyph.FontSize
+			shift += glyph.W
+		}
+		text[i] = T(glyph)
+	}
+}
@@ -0,0 +1,42 @@
+package pdf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MergeTables combines the tables extracted from several sources, such as
+// the documents of a batch, into one list in source order. Each table's
+// Source is set to the index of the result set it came from.
+func MergeTables(tables ...[]Table) []Table {
+	var merged []Table
+	for source, set := range tables {
+		for _, table := range set {
+			table.Rows = append([][]string{}, table.Rows...)
+			table.Source = source
+			merged = append(merged, table)
+		}
+	}
+	return merged
+}
+
+// ConcatText joins the text of several documents, in order, with sep
+// between documents. The pages of a document are joined with newlines.
+func ConcatText(sep string, docs ...Document) (string, error) {
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		if doc == nil {
+			return "", fmt.Errorf("document %d is nil", i)
+		}
+		pages := make([]string, doc.PageCount())
+		for j := range pages {
+			page, err := doc.GetPage(j)
+			if err != nil {
+				return "", fmt.Errorf("document %d: %w", i, err)
+			}
+			pages[j] = page.ExtractText()
+		}
+		texts[i] = strings.Join(pages, "\n")
+	}
+	return strings.Join(texts, sep), nil
+}
@@ -0,0 +1,40 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ocrText runs the configured OCR function on a page that has images but no
+// characters. It reports whether OCR was applied, and the error of the
+// image or of the OCR function that kept it from being applied.
+func ocrText(config *textExtractionConfig, objects Objects, render func() (io.Reader, error)) (string, bool, error) {
+	if config.OCRFunc == nil || len(objects.Chars) > 0 || len(objects.Images) == 0 {
+		return "", false, nil
+	}
+
+	img, err := render()
+	if err != nil {
+		return "", false, err
+	}
+
+	text, err := config.OCRFunc(img)
+	if err != nil {
+		return "", false, fmt.Errorf("OCR: %w", err)
+	}
+	return strings.TrimSpace(text), true, nil
+}
+
+// recognizeText runs the OCR function of opts on a page for RecognizeText
+func recognizeText(objects Objects, render func() (io.Reader, error), opts []TextExtractionOption) (string, error) {
+	config := &textExtractionConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.OCRFunc == nil {
+		return "", fmt.Errorf("no OCR function set with WithOCRFunc")
+	}
+	text, _, err := ocrText(config, objects, render)
+	return text, err
+}
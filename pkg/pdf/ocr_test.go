@@ -0,0 +1,109 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestExtractTextWithOCRFunc(t *testing.T) {
+	doc, err := Open("../../testdata/scanned.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("failed to get page: %v", err)
+	}
+
+	images := page.GetObjects().Images
+	if len(images) != 1 || images[0].Width != 16 || images[0].Height != 8 {
+		t.Fatalf("expected one 16x8 image, got %+v", images)
+	}
+	if images[0].X0 != 66 || images[0].Y0 != 276 || images[0].X1 != 546 || images[0].Y1 != 516 {
+		t.Errorf("unexpected image bbox %+v", images[0].GetBBox())
+	}
+
+	if text := page.ExtractText(); text != "" {
+		t.Errorf("expected no text without OCR, got %q", text)
+	}
+
+	calls := 0
+	stub := func(img io.Reader) (string, error) {
+		calls++
+		data, err := io.ReadAll(img)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+			t.Errorf("expected JPEG data, got %d bytes", len(data))
+		}
+		return "Scanned text\n", nil
+	}
+
+	if text := page.ExtractText(WithOCRFunc(stub)); text != "Scanned text" {
+		t.Errorf("expected OCR text, got %q", text)
+	}
+	if calls != 1 {
+		t.Errorf("expected OCR to run once, ran %d times", calls)
+	}
+
+	// Pages with extractable text never reach the OCR function
+	textDoc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer textDoc.Close()
+
+	textPage, _ := textDoc.GetPage(0)
+	textPage.ExtractText(WithOCRFunc(stub))
+	if calls != 1 {
+		t.Errorf("expected OCR to be skipped on a text page, ran %d times", calls)
+	}
+}
+
+func TestRecognizeText(t *testing.T) {
+	doc, err := Open("../../testdata/scanned.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	stub := func(img io.Reader) (string, error) {
+		return "Scanned text", nil
+	}
+	if text, err := page.RecognizeText(WithOCRFunc(stub)); err != nil || text != "Scanned text" {
+		t.Errorf("expected OCR text, got %q, %v", text, err)
+	}
+
+	failure := errors.New("engine unavailable")
+	failing := func(img io.Reader) (string, error) {
+		return "", failure
+	}
+	if _, err := page.RecognizeText(WithOCRFunc(failing)); !errors.Is(err, failure) {
+		t.Errorf("expected the OCR function's error, got %v", err)
+	}
+	if _, err := page.RecognizeText(); err == nil {
+		t.Error("expected an error without an OCR function")
+	}
+
+	// The libraries cannot pass the scanned image on
+	for name, open := range map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	} {
+		libDoc, err := open("../../testdata/scanned.pdf")
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		libPage, _ := libDoc.GetPage(0)
+		if _, err := libPage.RecognizeText(WithOCRFunc(stub)); !errors.Is(err, ErrNotImplemented) {
+			t.Errorf("%s: expected ErrNotImplemented, got %v", name, err)
+		}
+		libDoc.Close()
+	}
+}
@@ -0,0 +1,49 @@
+package pdf
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// PageDim is the size of a page as displayed
+type PageDim struct {
+	Index    int     // Page index (0-based)
+	Width    float64 // Width after rotation
+	Height   float64 // Height after rotation
+	Rotation int     // Clockwise rotation in degrees: 0, 90, 180 or 270
+	UserUnit float64 // Size of a default user space unit in 1/72 inch
+}
+
+// letterPageBox is the page box assumed for pages without a MediaBox
+var letterPageBox = BoundingBox{X1: 612, Y1: 792}
+
+// pageDim returns the dimensions of a page from its boxes in PDF space and
+// its /Rotate. The CropBox is clipped to the MediaBox; an override from
+// WithPageBBox replaces both. Nil boxes are missing from the page.
+func pageDim(index int, mediaBox, cropBox, override *BoundingBox, rotate int) PageDim {
+	box := letterPageBox
+	switch {
+	case override != nil:
+		box = *override
+	case mediaBox != nil:
+		box = mediaBox.Normalize()
+		if cropBox != nil {
+			if clipped, ok := box.Intersection(*cropBox); ok {
+				box = clipped
+			}
+		}
+	}
+
+	dim := PageDim{Index: index, Width: box.Width(), Height: box.Height(), Rotation: normalizeRotation(rotate)}
+	if dim.Rotation == 90 || dim.Rotation == 270 {
+		dim.Width, dim.Height = dim.Height, dim.Width
+	}
+	return dim
+}
+
+// rectangleBox converts a pdfcpu rectangle to a bounding box, nil if missing
+func rectangleBox(rect *types.Rectangle) *BoundingBox {
+	if rect == nil {
+		return nil
+	}
+	return &BoundingBox{X0: rect.LL.X, Y0: rect.LL.Y, X1: rect.UR.X, Y1: rect.UR.Y}
+}
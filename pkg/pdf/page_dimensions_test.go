@@ -0,0 +1,87 @@
+package pdf
+
+import (
+	"testing"
+)
+
+func TestPageDimensions(t *testing.T) {
+	expected := []PageDim{
+		{Index: 0, Width: 612, Height: 792, UserUnit: 1},
+		{Index: 1, Width: 842, Height: 595, UserUnit: 1},
+		{Index: 2, Width: 720, Height: 540, Rotation: 90, UserUnit: 1}, // CropBox, turned sideways
+		{Index: 3, Width: 500, Height: 400, UserUnit: 1},               // MediaBox inherited from the Pages node
+	}
+
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/page_sizes.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			dims := doc.PageDimensions()
+			if len(dims) != len(expected) {
+				t.Fatalf("expected %d pages, got %d", len(expected), len(dims))
+			}
+			for i, dim := range dims {
+				if dim != expected[i] {
+					t.Errorf("page %d: expected %+v, got %+v", i, expected[i], dim)
+				}
+			}
+		})
+	}
+}
+
+func TestPageUserUnit(t *testing.T) {
+	// A 7200x3600 MediaBox turned sideways, in units of 2/72 inch
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/user_unit.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			physical, err := open("../../testdata/user_unit.pdf", WithPhysicalDimensions(true))
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer physical.Close()
+
+			page, _ := doc.GetPage(0)
+			scaled, _ := physical.GetPage(0)
+			if page.UserUnit() != 2 || scaled.UserUnit() != 2 {
+				t.Errorf("expected a user unit of 2, got %v and %v", page.UserUnit(), scaled.UserUnit())
+			}
+			if scaled.GetWidth() != 2*page.GetWidth() || scaled.GetHeight() != 2*page.GetHeight() {
+				t.Errorf("expected physical size %vx%v, got %vx%v",
+					2*page.GetWidth(), 2*page.GetHeight(), scaled.GetWidth(), scaled.GetHeight())
+			}
+
+			expected := PageDim{Width: 3600, Height: 7200, Rotation: 90, UserUnit: 2}
+			if dim := doc.PageDimensions()[0]; dim != expected {
+				t.Errorf("expected %+v, got %+v", expected, dim)
+			}
+			expected.Width, expected.Height = 7200, 14400
+			if dim := physical.PageDimensions()[0]; dim != expected {
+				t.Errorf("expected physical %+v, got %+v", expected, dim)
+			}
+
+			// Object coordinates stay in default user space
+			chars := scaled.GetObjects().Chars
+			if len(chars) == 0 || chars[0].X0 != 72 {
+				t.Errorf("expected the text to start at x=72 in user space, got %+v", chars)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"context"
+	"iter"
+)
+
+// iteratePages yields pages in order until ctx is done or the loop stops.
+// unretained returns a page whose extracted objects are kept by nothing but
+// the caller, so they can be reclaimed once the loop moves on.
+func iteratePages(ctx context.Context, count int, unretained func(index int) Page) iter.Seq2[int, Page] {
+	return func(yield func(int, Page) bool) {
+		for i := 0; i < count; i++ {
+			if ctx.Err() != nil {
+				return
+			}
+			if !yield(i, unretained(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Pages yields the pages of the document one at a time. Objects extracted
+// from a yielded page are not kept by the document, so memory is reclaimed
+// page by page; pages already extracted through GetPages or GetPage keep
+// their objects. Iteration stops once ctx is done.
+func (d *PDFDocument) Pages(ctx context.Context) iter.Seq2[int, Page] {
+	return iteratePages(ctx, len(d.pages), func(index int) Page {
+		page := *d.pages[index].(*PDFCPUPage)
+		return &page
+	})
+}
+
+// Pages yields the pages of the document one at a time, without keeping the
+// objects extracted from them. Iteration stops once ctx is done.
+func (d *LedongthucDocument) Pages(ctx context.Context) iter.Seq2[int, Page] {
+	return iteratePages(ctx, len(d.pages), func(index int) Page {
+		page := *d.pages[index].(*LedongthucPage)
+		return &page
+	})
+}
+
+// Pages yields the pages of the document one at a time, without keeping the
+// objects extracted from them. Iteration stops once ctx is done.
+func (d *DsliPakDocument) Pages(ctx context.Context) iter.Seq2[int, Page] {
+	return iteratePages(ctx, len(d.pages), func(index int) Page {
+		page := *d.pages[index].(*DsliPakPage)
+		return &page
+	})
+}
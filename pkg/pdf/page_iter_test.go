@@ -0,0 +1,90 @@
+package pdf
+
+import (
+	"context"
+	"runtime"
+	"testing"
+)
+
+// heapInUse returns the live heap after a collection
+func heapInUse() int64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return int64(stats.HeapAlloc)
+}
+
+func TestPagesReleasesObjects(t *testing.T) {
+	doc, err := Open("../../testdata/many_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Iterating keeps at most one page's objects alive
+	base := heapInUse()
+	var peak int64
+	count := 0
+	for i, page := range doc.Pages(context.Background()) {
+		if i != count {
+			t.Fatalf("expected page index %d, got %d", count, i)
+		}
+		if len(page.GetObjects().Chars) == 0 {
+			t.Fatalf("expected chars on page %d", i)
+		}
+		if heap := heapInUse(); heap > peak {
+			peak = heap
+		}
+		count++
+	}
+	if count != doc.PageCount() {
+		t.Fatalf("expected %d pages, got %d", doc.PageCount(), count)
+	}
+	for i, page := range doc.(*PDFDocument).pages {
+		if len(page.(*PDFCPUPage).objects.Chars) != 0 {
+			t.Fatalf("expected page %d to keep no objects after iterating", i)
+		}
+	}
+
+	// Extracting every page through GetPages keeps all of them
+	for _, page := range doc.GetPages() {
+		page.GetObjects()
+	}
+	retained := heapInUse() - base
+	if retained <= 0 || peak-base >= retained/4 {
+		t.Errorf("expected iterating to use a fraction of the %d bytes all pages keep, peaked at %d", retained, peak-base)
+	}
+}
+
+func TestPagesStopsOnContext(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/many_pages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var pages []int
+			for i, page := range doc.Pages(ctx) {
+				if page.GetPageNumber() != i+1 {
+					t.Errorf("expected page number %d, got %d", i+1, page.GetPageNumber())
+				}
+				pages = append(pages, i)
+				if i == 2 {
+					cancel()
+				}
+			}
+			if len(pages) != 3 {
+				t.Errorf("expected iteration to stop after 3 pages, got %v", pages)
+			}
+		})
+	}
+}
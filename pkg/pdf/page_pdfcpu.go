@@ -1,38 +1,75 @@
 package pdf
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/pyhub-apps/pdfplumber-golang/pkg/parser"
 )
 
 // PDFCPUPage implements the Page interface using pdfcpu
 type PDFCPUPage struct {
-	ctx        *model.Context
-	pageNumber int
-	pageDict   types.Dict
-	width      float64
-	height     float64
-	rotation   int
-	objects    Objects
-	content    []byte
+	ctx           *model.Context
+	pageNumber    int
+	pageDict      types.Dict
+	resources     types.Dict // Effective resources, possibly inherited from a Pages node
+	width         float64
+	height        float64
+	rotation      int
+	userUnit      float64 // Size of a default user space unit in 1/72 inch
+	physical      bool    // Report the size in physical points, scaled by userUnit
+	objects       Objects
+	content       []byte
+	words         wordCache
+	fontOverrides []fontUnicodeOverride
+	spaceGlyphs   bool           // Detect the fonts' designated space codes
+	repairUnicode bool           // Repair malformed UTF-16 in ToUnicode CMaps
+	fontCache     *fontCache     // Fonts loaded by the document's pages, shared between them
+	objectNumber  int            // Object number of the page dictionary, 0 if unknown
+	lang          string         // Document language, for text outside tagged content
+	mcidLangs     map[int]string // Languages of the page's marked-content IDs
+	mcidOrder     []int          // The page's marked-content IDs in structure tree order
+	fallbackFont  string         // Standard font of text without a usable font, empty for the default
+	tagged        bool           // The document is marked as tagged
+	maxObjects    int            // Objects parsed before the content is cut off, 0 for no limit
+	maxStreamSize int64          // Largest decoded stream in bytes, 0 for no limit
+	pageBox       *BoundingBox   // Overriding page box in PDF space, nil for the MediaBox
+	exclusions    []BoundingBox  // Areas whose objects are dropped
+	snapGrid      float64        // Grid object coordinates are rounded to, 0 for none
+	truncated     bool           // The content was cut off at maxObjects
+	hiddenLayers  map[int]bool   // Object numbers of the layers whose content is dropped
+	layers        []string       // Layers the content is marked with, set when parsed
+	cropBox       *BoundingBox   // Box of a cropped page in its object coordinates, nil if not cropped
+	view          pageView       // Crops and rotations applied since the page was read
 }
 
 // NewPDFCPUPage creates a new page using pdfcpu context
 func NewPDFCPUPage(ctx *model.Context, pageNumber int) (*PDFCPUPage, error) {
+	return newPDFCPUPage(ctx, pageNumber, 0)
+}
+
+// newPDFCPUPage creates a page whose streams decode to at most maxStreamSize
+// bytes, 0 for no limit
+func newPDFCPUPage(ctx *model.Context, pageNumber int, maxStreamSize int64) (*PDFCPUPage, error) {
 	if ctx == nil {
 		return nil, fmt.Errorf("context is nil")
 	}
 
 	if pageNumber < 1 || pageNumber > ctx.PageCount {
-		return nil, fmt.Errorf("page number %d out of range [1, %d]", pageNumber, ctx.PageCount)
+		return nil, fmt.Errorf("%w: number %d not in [1, %d]", ErrPageOutOfRange, pageNumber, ctx.PageCount)
 	}
 
 	// Get page dictionary and inherited attributes
-	pageDict, _, attrs, err := ctx.PageDict(pageNumber, false)
+	pageDict, pageRef, attrs, err := ctx.PageDict(pageNumber, false)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get page dict: %w", err)
 	}
@@ -49,18 +86,24 @@ func NewPDFCPUPage(ctx *model.Context, pageNumber int) (*PDFCPUPage, error) {
 	}
 
 	page := &PDFCPUPage{
-		ctx:        ctx,
-		pageNumber: pageNumber,
-		pageDict:   pageDict,
-		width:      width,
-		height:     height,
-		rotation:   0, // Will be extracted from attrs or page dict
-		objects:    Objects{},
+		ctx:           ctx,
+		pageNumber:    pageNumber,
+		pageDict:      pageDict,
+		width:         width,
+		height:        height,
+		rotation:      0, // Will be extracted from attrs or page dict
+		userUnit:      pdfcpuUserUnit(ctx, pageDict),
+		objects:       Objects{},
+		maxStreamSize: maxStreamSize,
+	}
+	if pageRef != nil {
+		page.objectNumber = pageRef.ObjectNumber.Value()
 	}
 	
-	// Extract rotation from inherited attributes first, then from page dict
+	// Extract rotation and resources from inherited attributes first, then from page dict
 	if attrs != nil {
 		page.rotation = attrs.Rotate
+		page.resources = attrs.Resources
 	} else if rot := pageDict["Rotate"]; rot != nil {
 		if rotInt, ok := rot.(types.Integer); ok {
 			page.rotation = int(rotInt)
@@ -97,7 +140,7 @@ func (p *PDFCPUPage) extractContent() error {
 		}
 		if found && stream != nil {
 			// fmt.Println("[DEBUG] Successfully got StreamDict via DereferenceStreamDict")
-			decoded, err := decodeStream(stream)
+			decoded, err := decodeStream(stream, p.maxStreamSize)
 			if err != nil {
 				return fmt.Errorf("failed to decode stream: %w", err)
 			}
@@ -118,11 +161,12 @@ func (p *PDFCPUPage) extractContent() error {
 		
 		if streamDict != nil {
 			// Decode the stream
-			if err := streamDict.Decode(); err != nil {
+			decoded, err := decodeStream(streamDict, p.maxStreamSize)
+			if err != nil {
 				return fmt.Errorf("failed to decode stream: %w", err)
 			}
 			// fmt.Printf("[DEBUG] Decoded %d bytes\n", len(streamDict.Content))
-			contentStreams = append(contentStreams, streamDict.Content)
+			contentStreams = append(contentStreams, decoded)
 		}
 
 	case types.Array:
@@ -141,12 +185,15 @@ func (p *PDFCPUPage) extractContent() error {
 				}
 				// fmt.Printf("[DEBUG]   DereferenceStreamDict: found=%v\n", found)
 				if streamDict != nil {
-					if err := streamDict.Decode(); err != nil {
+					decoded, err := decodeStream(streamDict, p.maxStreamSize)
+					if errors.Is(err, ErrStreamTooLarge) {
+						return fmt.Errorf("failed to decode stream: %w", err)
+					} else if err != nil {
 						// fmt.Printf("[DEBUG]   Failed to decode: %v\n", err)
 						continue
 					}
 					// fmt.Printf("[DEBUG]   Decoded %d bytes\n", len(streamDict.Content))
-					contentStreams = append(contentStreams, streamDict.Content)
+					contentStreams = append(contentStreams, decoded)
 				}
 			} else if indRef, ok := item.(types.IndirectRef); ok {
 				// Try value type
@@ -158,12 +205,15 @@ func (p *PDFCPUPage) extractContent() error {
 				}
 				// fmt.Printf("[DEBUG]   DereferenceStreamDict: found=%v\n", found)
 				if streamDict != nil {
-					if err := streamDict.Decode(); err != nil {
+					decoded, err := decodeStream(streamDict, p.maxStreamSize)
+					if errors.Is(err, ErrStreamTooLarge) {
+						return fmt.Errorf("failed to decode stream: %w", err)
+					} else if err != nil {
 						// fmt.Printf("[DEBUG]   Failed to decode: %v\n", err)
 						continue
 					}
 					// fmt.Printf("[DEBUG]   Decoded %d bytes\n", len(streamDict.Content))
-					contentStreams = append(contentStreams, streamDict.Content)
+					contentStreams = append(contentStreams, decoded)
 				}
 			}
 		}
@@ -172,7 +222,7 @@ func (p *PDFCPUPage) extractContent() error {
 	// Combine all content streams
 	// fmt.Printf("[DEBUG] Total content streams collected: %d\n", len(contentStreams))
 	if len(contentStreams) > 0 {
-		p.content = combineContentStreams(contentStreams)
+		p.content = parser.JoinContentStreams(contentStreams)
 		// fmt.Printf("[DEBUG] Combined content size: %d bytes\n", len(p.content))
 	} else {
 		// fmt.Println("[DEBUG] No content streams were extracted!")
@@ -181,13 +231,34 @@ func (p *PDFCPUPage) extractContent() error {
 	return nil
 }
 
-// decodeStream decodes a stream dictionary
-func decodeStream(stream *types.StreamDict) ([]byte, error) {
+// decodeStream decodes a stream dictionary, failing with
+// ErrStreamTooLarge once the data passes limit bytes (0 for no
+// limit). pdfcpu decodes without a bound, so limited streams are decoded
+// with the native parser's filters.
+func decodeStream(stream *types.StreamDict, limit int64) ([]byte, error) {
 	// If content is already available, return it
 	if len(stream.Content) > 0 {
 		return stream.Content, nil
 	}
 
+	if limit > 0 {
+		filters := make([]parser.Filter, len(stream.FilterPipeline))
+		for i, f := range stream.FilterPipeline {
+			filters[i] = parser.Filter{Name: f.Name, Parms: map[string]int{}}
+			for key, value := range f.DecodeParms {
+				if n, ok := value.(types.Integer); ok {
+					filters[i].Parms[key] = n.Value()
+				}
+			}
+		}
+		data, err := parser.DecodeFilters(stream.Raw, filters, limit)
+		if err != nil {
+			return nil, err
+		}
+		stream.Content = data
+		return data, nil
+	}
+
 	// Decode the stream
 	if err := stream.Decode(); err != nil {
 		return nil, err
@@ -196,16 +267,6 @@ func decodeStream(stream *types.StreamDict) ([]byte, error) {
 	return stream.Content, nil
 }
 
-// combineContentStreams combines multiple content streams
-func combineContentStreams(streams [][]byte) []byte {
-	var combined []byte
-	for _, stream := range streams {
-		combined = append(combined, stream...)
-		combined = append(combined, '\n')
-	}
-	return combined
-}
-
 // GetPageNumber returns the page number (1-based)
 func (p *PDFCPUPage) GetPageNumber() int {
 	return p.pageNumber
@@ -213,12 +274,12 @@ func (p *PDFCPUPage) GetPageNumber() int {
 
 // GetWidth returns the page width
 func (p *PDFCPUPage) GetWidth() float64 {
-	return p.width
+	return p.width * sizeScale(p.userUnit, p.physical)
 }
 
 // GetHeight returns the page height
 func (p *PDFCPUPage) GetHeight() float64 {
-	return p.height
+	return p.height * sizeScale(p.userUnit, p.physical)
 }
 
 // GetRotation returns the page rotation in degrees
@@ -226,8 +287,16 @@ func (p *PDFCPUPage) GetRotation() int {
 	return p.rotation
 }
 
+// UserUnit returns the size of a default user space unit in 1/72 inch
+func (p *PDFCPUPage) UserUnit() float64 {
+	return p.userUnit
+}
+
 // GetBBox returns the page bounding box
 func (p *PDFCPUPage) GetBBox() BoundingBox {
+	if p.cropBox != nil {
+		return *p.cropBox
+	}
 	return BoundingBox{
 		X0: 0,
 		Y0: 0,
@@ -236,14 +305,47 @@ func (p *PDFCPUPage) GetBBox() BoundingBox {
 	}
 }
 
+// newParser creates a content stream parser for the page with the
+// document's font overrides applied
+func (p *PDFCPUPage) newParser() *ContentStreamParser {
+	parser := newContentStreamParser(p.ctx, p.pageDict)
+	parser.detectSpaceGlyphs = p.spaceGlyphs
+	parser.repairUnicode = p.repairUnicode
+	parser.fontCache = p.fontCache
+	parser.maxStreamSize = p.maxStreamSize
+	resources := p.resources
+	if resources == nil {
+		resources, _ = p.pageDict["Resources"].(types.Dict)
+	}
+	if resources != nil {
+		parser.setResources(resources)
+	}
+	parser.applyFontOverrides(p.fontOverrides)
+	parser.lang = p.lang
+	parser.mcidLangs = p.mcidLangs
+	parser.maxObjects = p.maxObjects
+	parser.hiddenLayers = p.hiddenLayers
+	if p.fallbackFont != "" {
+		parser.fallbackFont = newFallbackFont(p.fallbackFont)
+	}
+	return parser
+}
+
 // GetObjects returns all objects on the page
 func (p *PDFCPUPage) GetObjects() Objects {
 	// Parse content stream if not already done
 	// Check if we have parsed content by checking if we have any objects at all
-	if len(p.objects.Chars) == 0 && len(p.objects.Lines) == 0 && len(p.objects.Rects) == 0 && len(p.content) > 0 {
+	if len(p.objects.Chars) == 0 && len(p.objects.Lines) == 0 && len(p.objects.Rects) == 0 && len(p.objects.Images) == 0 && len(p.content) > 0 {
 		// fmt.Println("[DEBUG] Parsing content stream...")
-		parser := NewContentStreamParser(p.ctx, p.pageDict)
+		parser := p.newParser()
 		p.objects = parser.Parse(p.content)
+		p.truncated = parser.truncated
+		p.layers = parser.layers
+		if p.pageBox != nil {
+			p.objects = clipToPageBox(p.objects, -p.pageBox.X0, -p.pageBox.Y0, p.width, p.height)
+		}
+		p.objects = excludeRegions(p.objects, p.exclusions)
+		p.objects = snapToGrid(p.objects, p.snapGrid)
 		// fmt.Printf("[DEBUG] After parsing: %d chars, %d lines, %d rects\n", 
 		//	len(p.objects.Chars), len(p.objects.Lines), len(p.objects.Rects))
 	}
@@ -256,8 +358,9 @@ func (p *PDFCPUPage) ExtractText(opts ...TextExtractionOption) string {
 	
 	// Default options
 	options := &textExtractionConfig{
-		XTolerance: 3,
-		YTolerance: 3,
+		XTolerance:           3,
+		YTolerance:           3,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
 	}
 	
 	// Apply custom options
@@ -265,14 +368,52 @@ func (p *PDFCPUPage) ExtractText(opts ...TextExtractionOption) string {
 		opt(options)
 	}
 	
-	// Extract text from character objects
+	if text, ok, _ := ocrText(options, objects, p.ocrImage); ok {
+		return formatLines(text, options)
+	}
+	
+	chars := objects.Chars
+	if options.IgnoreRotatedText {
+		chars = filterRotatedChars(chars, options.RotatedTextTolerance)
+	}
+	if options.ExcludeOutlined {
+		chars = filterOutlinedChars(chars)
+	}
+	
+	if options.Layout {
+		return formatLines(layoutText(charGrid(chars, p.GetBBox(), false)), options)
+	}
+	
+	if options.ColumnDetection {
+		return formatLines(extractColumnText(chars, options, false), options)
+	}
+	
+	if options.ParagraphBreaks {
+		return formatLines(extractParagraphText(chars, options, false), options)
+	}
+	
+	var lines []string
+	if options.usesStructTree(p.mcidOrder, p.tagged) {
+		lines = structOrderLines(chars, p.mcidOrder, options, false)
+	} else {
+		lines = textLines(chars, options, false)
+	}
+	
+	return formatLines(strings.Join(lines, "\n"), options)
+}
+
+// textLines groups characters into lines, starting a new line whenever the
+// baseline moves by more than the Y tolerance
+func textLines(chars []CharObject, options *textExtractionConfig, topDown bool) []string {
 	var lines []string
 	var currentLine []CharObject
 	var lastY float64
 	
-	for _, char := range objects.Chars {
-		// Check if we're on a new line
-		if len(currentLine) > 0 && abs(char.Y0-lastY) > options.YTolerance {
+	for _, char := range chars {
+		// Check if we're on a new line, by the baseline so that characters
+		// of different sizes stay on theirs
+		y := charBaseline(char, topDown)
+		if len(currentLine) > 0 && abs(y-lastY) > options.YTolerance {
 			// Process current line
 			lineText := extractLineText(currentLine, options.XTolerance)
 			if lineText != "" {
@@ -282,7 +423,7 @@ func (p *PDFCPUPage) ExtractText(opts ...TextExtractionOption) string {
 		} else {
 			currentLine = append(currentLine, char)
 		}
-		lastY = char.Y0
+		lastY = y
 	}
 	
 	// Process last line
@@ -292,8 +433,7 @@ func (p *PDFCPUPage) ExtractText(opts ...TextExtractionOption) string {
 			lines = append(lines, lineText)
 		}
 	}
-	
-	return strings.Join(lines, "\n")
+	return lines
 }
 
 // extractLineText extracts text from a line of characters
@@ -302,17 +442,20 @@ func extractLineText(chars []CharObject, xTolerance float64) string {
 		return ""
 	}
 	
-	// Sort characters by X position
+	// Sort characters by X position; the line's characters may sit on
+	// baselines a little apart, or on boxes of different depths
 	sortedChars := make([]CharObject, len(chars))
 	copy(sortedChars, chars)
-	sortCharsByPosition(sortedChars)
+	sort.SliceStable(sortedChars, func(i, j int) bool {
+		return sortedChars[i].X0 < sortedChars[j].X0
+	})
 	
 	var words []string
 	var currentWord []string
 	var lastX1 float64
 	
 	for i, char := range sortedChars {
-		if i > 0 && char.X0-lastX1 > xTolerance {
+		if i > 0 && (char.X0-lastX1 > xTolerance || char.followsSpace) {
 			// Space between words
 			if len(currentWord) > 0 {
 				words = append(words, strings.Join(currentWord, ""))
@@ -331,14 +474,15 @@ func extractLineText(chars []CharObject, xTolerance float64) string {
 	return strings.Join(words, " ")
 }
 
-// sortCharsByPosition sorts characters by their position (top-to-bottom, left-to-right)
+// sortCharsByPosition sorts characters by their position (top-to-bottom by
+// baseline, left-to-right)
 func sortCharsByPosition(chars []CharObject) {
 	// Simple bubble sort for now
 	n := len(chars)
 	for i := 0; i < n-1; i++ {
 		for j := 0; j < n-i-1; j++ {
-			if chars[j].Y0 < chars[j+1].Y0 || 
-			   (abs(chars[j].Y0-chars[j+1].Y0) < 1 && chars[j].X0 > chars[j+1].X0) {
+			y, next := charBaseline(chars[j], false), charBaseline(chars[j+1], false)
+			if y < next || (abs(y-next) < 1 && chars[j].X0 > chars[j+1].X0) {
 				chars[j], chars[j+1] = chars[j+1], chars[j]
 			}
 		}
@@ -347,12 +491,101 @@ func sortCharsByPosition(chars []CharObject) {
 
 // abs function is already defined in types.go
 
+// LayerNames returns the names of the layers the page's content is marked
+// with, in order of first use. Layers hidden by WithVisibleLayersOnly are
+// listed too.
+func (p *PDFCPUPage) LayerNames() []string {
+	p.GetObjects()
+	return p.layers
+}
+
+// Comments returns the page's comment and text markup annotations
+func (p *PDFCPUPage) Comments() []Comment {
+	annots, err := p.ctx.DereferenceArray(p.pageDict["Annots"])
+	if err != nil {
+		return nil
+	}
+	var comments []Comment
+	for _, annot := range annots {
+		dict, err := p.ctx.DereferenceDict(annot)
+		if err != nil || dict == nil {
+			continue
+		}
+		subtype := dict.NameEntry("Subtype")
+		if subtype == nil {
+			continue
+		}
+		if _, ok := commentTypes[*subtype]; !ok {
+			continue
+		}
+	
+		comment := Comment{
+			Type:     *subtype,
+			Contents: contextTextString(p.ctx, dict["Contents"]),
+			Author:   contextTextString(p.ctx, dict["T"]),
+		}
+		if parent, err := p.ctx.DereferenceDict(dict["Parent"]); err == nil && parent != nil {
+			if comment.Contents == "" {
+				comment.Contents = contextTextString(p.ctx, parent["Contents"])
+			}
+			if comment.Author == "" {
+				comment.Author = contextTextString(p.ctx, parent["T"])
+			}
+		}
+		if rect := p.numbers(dict["Rect"]); len(rect) == 4 {
+			comment.BBox = BoundingBox{X0: rect[0], Y0: rect[1], X1: rect[2], Y1: rect[3]}
+		}
+		comment.Areas = quadPointAreas(p.numbers(dict["QuadPoints"]))
+		comments = append(comments, comment)
+	}
+	return placeComments(comments, p.GetObjects().Chars, p.pageBox, p.view, p.height, false)
+}
+
+// numbers resolves an array of numbers
+func (p *PDFCPUPage) numbers(obj types.Object) []float64 {
+	array, err := p.ctx.DereferenceArray(obj)
+	if err != nil {
+		return nil
+	}
+	values := make([]float64, len(array))
+	for i, value := range array {
+		values[i] = numberValue(value)
+	}
+	return values
+}
+
+// ExtractBetween returns the text of the page between the start and end
+// anchors, reporting false if the start anchor is not found. A missing end
+// anchor runs to the end of the page.
+func (p *PDFCPUPage) ExtractBetween(start, end string, opts ...SearchOption) (string, bool) {
+	return extractBetween([]Page{p}, false, start, end, opts)
+}
+
+// RecognizeText runs the OCR function set with WithOCRFunc on the page's
+// largest image if the page has images but no characters
+func (p *PDFCPUPage) RecognizeText(opts ...TextExtractionOption) (string, error) {
+	return recognizeText(p.GetObjects(), p.ocrImage, opts)
+}
+
+// PotentialRedactions returns the filled boxes painted over text, which is
+// hidden on the page but still extractable
+func (p *PDFCPUPage) PotentialRedactions() ([]RedactionWarning, error) {
+	return potentialRedactions(p.GetObjects(), false), nil
+}
+
+// ColorRuns returns the page's text split into runs of one fill color
+func (p *PDFCPUPage) ColorRuns() []ColoredRun {
+	return colorRuns(p.ExtractWords())
+}
+
 // ExtractWords extracts individual words from the page
 func (p *PDFCPUPage) ExtractWords(opts ...WordExtractionOption) []Word {
 	// Default configuration
 	config := &wordExtractionConfig{
-		XTolerance: 3.0,
-		YTolerance: 3.0,
+		XTolerance:           3.0,
+		YTolerance:           3.0,
+		RotatedTextTolerance: defaultRotatedTextTolerance,
+		TextAngleThreshold:   defaultTextAngleThreshold,
 	}
 	
 	// Apply options
@@ -360,15 +593,22 @@ func (p *PDFCPUPage) ExtractWords(opts ...WordExtractionOption) []Word {
 		opt(config)
 	}
 	
+	if words, ok := p.words.get(config); ok {
+		return words
+	}
+	
 	// Get all character objects
-	objects := p.GetObjects()
-	if len(objects.Chars) == 0 {
+	pageChars := p.GetObjects().Chars
+	if config.IgnoreRotatedText {
+		pageChars = filterRotatedChars(pageChars, config.RotatedTextTolerance)
+	}
+	if len(pageChars) == 0 {
 		return []Word{}
 	}
 	
 	// Sort characters by position (Y first, then X)
-	chars := make([]CharObject, len(objects.Chars))
-	copy(chars, objects.Chars)
+	chars := make([]CharObject, len(pageChars))
+	copy(chars, pageChars)
 	sortCharsByPosition(chars)
 	
 	// Group characters into words
@@ -388,8 +628,8 @@ func (p *PDFCPUPage) ExtractWords(opts ...WordExtractionOption) []Word {
 					words = append(words, createWord(currentWord))
 					currentWord = []CharObject{}
 				}
-			} else if char.X0-lastChar.X1 > config.XTolerance {
-				// Too far horizontally - new word
+			} else if splitsWord(*char, *lastChar, config) {
+				// Too far horizontally or differently rotated - new word
 				if len(currentWord) > 0 {
 					words = append(words, createWord(currentWord))
 					currentWord = []CharObject{}
@@ -406,9 +646,22 @@ func (p *PDFCPUPage) ExtractWords(opts ...WordExtractionOption) []Word {
 		words = append(words, createWord(currentWord))
 	}
 	
+	if config.SeparateSuperscripts {
+		words = separateScripts(words, false)
+	}
+	words = scaleWords(words, config.Scale)
+	p.words.set(config, words)
 	return words
 }
 
+// splitsWord tells whether char starts a new word after last on the same
+// line: past a gap wider than the x tolerance, after a space that was not
+// kept as a character, or at a change of direction
+func splitsWord(char, last CharObject, config *wordExtractionConfig) bool {
+	return char.X0-last.X1 > config.XTolerance || char.followsSpace ||
+		!sameOrientation(char, last, config.TextAngleThreshold)
+}
+
 // createWord creates a Word from a group of characters
 func createWord(chars []CharObject) Word {
 	if len(chars) == 0 {
@@ -416,36 +669,95 @@ func createWord(chars []CharObject) Word {
 	}
 	
 	// Calculate bounding box
-	minX := chars[0].X0
-	minY := chars[0].Y0
-	maxX := chars[0].X1
-	maxY := chars[0].Y1
+	bbox := chars[0].GetBBox().Normalize()
 	
 	text := ""
 	for _, char := range chars {
 		text += char.Text
-		if char.X0 < minX {
-			minX = char.X0
-		}
-		if char.Y0 < minY {
-			minY = char.Y0
-		}
-		if char.X1 > maxX {
-			maxX = char.X1
-		}
-		if char.Y1 > maxY {
-			maxY = char.Y1
-		}
+		bbox = bbox.Union(char.GetBBox())
 	}
 	
 	return Word{
 		Text:       text,
-		X0:         minX,
-		Y0:         minY,
-		X1:         maxX,
-		Y1:         maxY,
+		X0:         bbox.X0,
+		Y0:         bbox.Y0,
+		X1:         bbox.X1,
+		Y1:         bbox.Y1,
 		Characters: chars,
+		Confidence: wordConfidence(chars),
+	}
+}
+
+// wordConfidence returns the share of characters that were decoded through a
+// font mapping to printable text. Raw code bytes, control characters and
+// U+FFFD replacement characters count against it.
+func wordConfidence(chars []CharObject) float64 {
+	if len(chars) == 0 {
+		return 0
+	}
+	good := 0
+	for _, char := range chars {
+		if !char.unmapped && isPrintableText(char.Text) {
+			good++
+		}
 	}
+	return float64(good) / float64(len(chars))
+}
+
+// isPrintableText reports whether text is non-empty and free of control and
+// replacement characters
+func isPrintableText(text string) bool {
+	if text == "" || !utf8.ValidString(text) {
+		return false
+	}
+	for _, r := range text {
+		if r == utf8.RuneError || (unicode.IsControl(r) && !unicode.IsSpace(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ocrImage returns the image handed to OCR. Rendered pages show images only
+// as placeholders, so the image with the most pixels is passed on as stored
+// when it is a JPEG or JPEG 2000 stream.
+func (p *PDFCPUPage) ocrImage() (io.Reader, error) {
+	images := p.GetObjects().Images
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no image to recognize")
+	}
+	largest := images[0]
+	for _, image := range images[1:] {
+		if image.Width*image.Height > largest.Width*largest.Height {
+			largest = image
+		}
+	}
+	
+	parser := p.newParser()
+	xobjects := parser.dereferenceDict(parser.resources["XObject"])
+	if xobjects == nil {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, largest.Name)
+	}
+	stream, _, err := p.ctx.DereferenceStreamDict(xobjects[largest.Name])
+	if err != nil || stream == nil {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, largest.Name)
+	}
+	
+	filters := stream.FilterPipeline
+	if len(filters) == 0 || (filters[len(filters)-1].Name != "DCTDecode" && filters[len(filters)-1].Name != "JPXDecode") {
+		return nil, fmt.Errorf("image %s is not stored in a standalone format", largest.Name)
+	}
+	return bytes.NewReader(stream.Raw), nil
+}
+
+// ExtractTextSpans extracts text lines along with their page and position
+func (p *PDFCPUPage) ExtractTextSpans(opts ...TextExtractionOption) []TextSpan {
+	return extractTextSpans(p.GetObjects().Chars, p.pageNumber-1, false, opts...)
+}
+
+// CharGrid returns the characters placed on the grid of layout-mode text
+func (p *PDFCPUPage) CharGrid(opts ...TextExtractionOption) [][]rune {
+	return pageCharGrid(p.GetObjects().Chars, p.GetBBox(), false, opts...)
 }
 
 // ExtractTables extracts tables from the page
@@ -455,9 +767,36 @@ func (p *PDFCPUPage) ExtractTables(opts ...TableExtractionOption) []Table {
 }
 
 // Crop returns a new page cropped to the specified bounding box
-func (p *PDFCPUPage) Crop(bbox BoundingBox) Page {
-	// TODO: Implement page cropping
-	return p
+func (p *PDFCPUPage) Crop(bbox BoundingBox, opts ...CropOption) Page {
+	objects, box := CropObjects(p.GetObjects(), bbox, opts...)
+	
+	cropped := *p
+	cropped.objects = objects
+	cropped.content = nil // Objects are already parsed and must not be parsed again
+	cropped.width, cropped.height = box.Width(), box.Height()
+	cropped.cropBox = &box
+	cropped.view = p.view.cropped(bbox, p.height, opts...)
+	cropped.words = wordCache{}
+	
+	return &cropped
+}
+
+// Rotate returns a new page rotated clockwise by a multiple of 90 degrees
+func (p *PDFCPUPage) Rotate(degrees int) Page {
+	objects, box := RotateObjectsInBox(p.GetObjects(), degrees, p.GetBBox(), false)
+	
+	rotated := *p
+	rotated.objects = objects
+	rotated.content = nil // Objects are already parsed and must not be parsed again
+	rotated.width, rotated.height = rotatedSize(degrees, p.width, p.height)
+	if p.cropBox != nil {
+		rotated.cropBox = &box
+	}
+	rotated.view = p.view.rotated(degrees, p.GetBBox(), p.height)
+	rotated.rotation = normalizeRotation(p.rotation + degrees)
+	rotated.words = wordCache{}
+	
+	return &rotated
 }
 
 // WithinBBox filters objects within a bounding box
@@ -489,6 +828,12 @@ func (p *PDFCPUPage) WithinBBox(bbox BoundingBox) Objects {
 		}
 	}
 	
+	for _, shading := range objects.Shadings {
+		if shading.GetBBox().Intersects(bbox) {
+			filtered.Shadings = append(filtered.Shadings, shading)
+		}
+	}
+	
 	return filtered
 }
 
@@ -521,11 +866,140 @@ func (p *PDFCPUPage) Filter(predicate func(Object) bool) Objects {
 		}
 	}
 	
+	for _, shading := range objects.Shadings {
+		if predicate(shading) {
+			filtered.Shadings = append(filtered.Shadings, shading)
+		}
+	}
+	
 	return filtered
 }
 
-// ToImage renders the page to an image (for visual debugging)
+// WriteCharsJSONL writes each character as a line of JSON
+func (p *PDFCPUPage) WriteCharsJSONL(w io.Writer) error {
+	return WriteCharsJSONL(w, p.GetObjects().Chars, p.pageNumber, p.height, false)
+}
+
+// Image returns the image XObject with the given resource name. Images
+// stored in a standalone format with a single filter, such as JPEG, are
+// returned as stored; others have their filters removed.
+func (p *PDFCPUPage) Image(name string) (ExtractedImage, error) {
+	parser := p.newParser()
+	xobjects := parser.dereferenceDict(parser.resources["XObject"])
+	if xobjects == nil || xobjects[name] == nil {
+		return ExtractedImage{}, fmt.Errorf("%w: %s", ErrImageNotFound, name)
+	}
+	stream, _, err := p.ctx.DereferenceStreamDict(xobjects[name])
+	if err != nil || stream == nil {
+		return ExtractedImage{}, fmt.Errorf("%w: %s", ErrImageNotFound, name)
+	}
+	if subtype := stream.Subtype(); subtype == nil || *subtype != "Image" {
+		return ExtractedImage{}, fmt.Errorf("XObject %s is not an image", name)
+	}
+	
+	image := ExtractedImage{Name: name}
+	if width := stream.IntEntry("Width"); width != nil {
+		image.Width = *width
+	}
+	if height := stream.IntEntry("Height"); height != nil {
+		image.Height = *height
+	}
+	if bpc := stream.IntEntry("BitsPerComponent"); bpc != nil {
+		image.BitsPerComponent = *bpc
+	}
+	if mask := stream.BooleanEntry("ImageMask"); mask != nil {
+		image.ImageMask = *mask
+	}
+	image.ColorSpace = imageColorSpaceName(parser.resolveObject(stream.Dict["ColorSpace"]))
+	
+	if filters := stream.FilterPipeline; len(filters) == 1 && isStandaloneImageFilter(filters[0].Name) {
+		image.Filter = filters[0].Name
+		image.Data = stream.Raw
+		return image, nil
+	}
+	data, err := decodeStream(stream, p.maxStreamSize)
+	if err != nil {
+		if errors.Is(err, filter.ErrUnsupportedFilter) {
+			err = fmt.Errorf("%w: %w", ErrUnsupportedFilter, err)
+		}
+		return ExtractedImage{}, fmt.Errorf("failed to decode image %s: %w", name, err)
+	}
+	image.Data = data
+	return image, nil
+}
+
+// ToImage renders the page's objects to a PNG (for visual debugging), with
+// glyphs drawn as boxes and images as placeholders
 func (p *PDFCPUPage) ToImage(opts ...ImageOption) (io.Reader, error) {
-	// TODO: Implement page rendering
-	return nil, fmt.Errorf("not implemented")
-}
\ No newline at end of file
+	return renderPage(p.GetObjects(), p.GetBBox(), false, opts)
+}
+
+// DrawDebug renders the page with the objects selected by opts outlined
+func (p *PDFCPUPage) DrawDebug(opts DebugOptions) (io.Reader, error) {
+	return drawDebug(p, p.GetBBox(), false, opts)
+}
+
+// validationFacts collects what validation checks about the page
+func (p *PDFCPUPage) validationFacts() pageFacts {
+	objects := p.GetObjects()
+	facts := pageFacts{
+		index:  p.pageNumber - 1,
+		width:  p.width,
+		height: p.height,
+		chars:  len(objects.Chars),
+		images: len(objects.Images),
+	}
+	if p.truncated {
+		facts.objectLimit = p.maxObjects
+	}
+	
+	if _, _, attrs, err := p.ctx.PageDict(p.pageNumber, false); err == nil && attrs != nil && attrs.MediaBox != nil {
+		facts.hasMediaBox = true
+	}
+	
+	parser := p.newParser()
+	for name, font := range parser.fonts {
+		facts.fonts = append(facts.fonts, fontFacts{
+			name:      name,
+			baseFont:  font.BaseFont,
+			encoding:  font.Encoding,
+			toUnicode: font.ToUnicodeCMap != nil,
+		})
+	}
+	facts.formFonts = p.formFonts(parser)
+	
+	return facts
+}
+
+// formFonts returns the base fonts of the form XObjects in the page
+// resources, and of the forms those hold in turn, each form looked at once
+func (p *PDFCPUPage) formFonts(parser *ContentStreamParser) []string {
+	var fonts []string
+	seen := make(map[int]bool)
+	var walk func(resources types.Dict)
+	walk = func(resources types.Dict) {
+		for _, entry := range parser.dereferenceDict(resources["XObject"]) {
+			number := objectNumber(entry)
+			if number == 0 || seen[number] {
+				continue
+			}
+			seen[number] = true
+			stream, _, err := p.ctx.DereferenceStreamDict(entry)
+			if err != nil || stream == nil {
+				continue
+			}
+			if subtype := stream.Dict.NameEntry("Subtype"); subtype == nil || *subtype != "Form" {
+				continue
+			}
+			own := parser.dereferenceDict(stream.Dict["Resources"])
+			for _, font := range parser.dereferenceDict(own["Font"]) {
+				if baseFont := parser.dereferenceDict(font).NameEntry("BaseFont"); baseFont != nil {
+					fonts = append(fonts, *baseFont)
+				}
+			}
+			walk(own)
+		}
+	}
+	walk(parser.resources)
+	return fonts
+}
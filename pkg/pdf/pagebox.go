@@ -0,0 +1,92 @@
+package pdf
+
+// translateChar moves a character by (dx, dy)
+func translateChar(char CharObject, dx, dy float64) CharObject {
+	char.X0 += dx
+	char.Y0 += dy
+	char.X1 += dx
+	char.Y1 += dy
+	char.Matrix.E += dx
+	char.Matrix.F += dy
+	return char
+}
+
+// clipToPageBox moves objects by (dx, dy) into the space of an overriding
+// page box of the given size, and drops those that lie entirely outside it.
+// Objects partly inside the box are kept whole.
+func clipToPageBox(objects Objects, dx, dy, width, height float64) Objects {
+	box := BoundingBox{X1: width, Y1: height}
+	inside := func(obj Object) bool {
+		return box.Intersects(obj.GetBBox().Normalize())
+	}
+	clipped := Objects{}
+
+	for _, char := range objects.Chars {
+		if char = translateChar(char, dx, dy); inside(char) {
+			clipped.Chars = append(clipped.Chars, char)
+		}
+	}
+
+	for _, line := range objects.Lines {
+		line.X0 += dx
+		line.Y0 += dy
+		line.X1 += dx
+		line.Y1 += dy
+		if inside(line) {
+			clipped.Lines = append(clipped.Lines, line)
+		}
+	}
+
+	for _, rect := range objects.Rects {
+		rect.X0 += dx
+		rect.Y0 += dy
+		rect.X1 += dx
+		rect.Y1 += dy
+		if inside(rect) {
+			clipped.Rects = append(clipped.Rects, rect)
+		}
+	}
+
+	for _, curve := range objects.Curves {
+		points := make([]Point, len(curve.Points))
+		for i, pt := range curve.Points {
+			points[i] = Point{X: pt.X + dx, Y: pt.Y + dy}
+		}
+		curve.Points = points
+		if inside(curve) {
+			clipped.Curves = append(clipped.Curves, curve)
+		}
+	}
+
+	for _, image := range objects.Images {
+		image.X0 += dx
+		image.Y0 += dy
+		image.X1 += dx
+		image.Y1 += dy
+		if inside(image) {
+			clipped.Images = append(clipped.Images, image)
+		}
+	}
+
+	for _, anno := range objects.Annos {
+		anno.X0 += dx
+		anno.Y0 += dy
+		anno.X1 += dx
+		anno.Y1 += dy
+		if inside(anno) {
+			clipped.Annos = append(clipped.Annos, anno)
+		}
+	}
+
+	for _, shading := range objects.Shadings {
+		shading.X0 += dx
+		shading.Y0 += dy
+		shading.X1 += dx
+		shading.Y1 += dy
+		if inside(shading) {
+			clipped.Shadings = append(clipped.Shadings, shading)
+		}
+	}
+
+	return clipped
+}
@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"sort"
+	"strings"
+)
+
+// paragraphGapFactor is how many times the typical line pitch the distance
+// between two lines must exceed for a paragraph break
+const paragraphGapFactor = 1.5
+
+// extractParagraphText extracts text line by line, with a blank line between
+// lines set further apart than the typical line pitch. The typical pitch is
+// the median distance between consecutive lines. topDown tells whether Y
+// grows downwards (true) or upwards as in raw PDF space (false).
+func extractParagraphText(chars []CharObject, config *textExtractionConfig, topDown bool) string {
+	var texts []string
+	var positions []float64
+	for _, line := range groupCharsIntoTextLines(chars, config.YTolerance, topDown) {
+		if text := extractLineText(line, config.XTolerance); text != "" {
+			texts = append(texts, text)
+			positions = append(positions, line[0].Y0)
+		}
+	}
+	if len(texts) == 0 {
+		return ""
+	}
+
+	pitches := make([]float64, 0, len(positions)-1)
+	for i := 1; i < len(positions); i++ {
+		pitches = append(pitches, abs(positions[i]-positions[i-1]))
+	}
+	typical := medianPitch(pitches)
+
+	var result strings.Builder
+	result.WriteString(texts[0])
+	for i, pitch := range pitches {
+		result.WriteString("\n")
+		if typical > 0 && pitch > typical*paragraphGapFactor {
+			result.WriteString("\n")
+		}
+		result.WriteString(texts[i+1])
+	}
+	return result.String()
+}
+
+// medianPitch returns the median of the line pitches, 0 if there are none
+func medianPitch(pitches []float64) float64 {
+	if len(pitches) == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, pitches...)
+	sort.Float64s(sorted)
+	return sorted[len(sorted)/2]
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// ReadingOrder selects how ExtractText orders the text of a page
+type ReadingOrder int
+
+const (
+	// ReadingOrderGeometric lays text out in lines by the position of its
+	// characters (the default)
+	ReadingOrderGeometric ReadingOrder = iota
+	// ReadingOrderAuto follows the structure tree of tagged documents, marked
+	// with /MarkInfo /Marked true, and the geometric order otherwise
+	ReadingOrderAuto
+	// ReadingOrderStructTree follows the structure tree whenever the
+	// document has one
+	ReadingOrderStructTree
+)
+
+// WithReadingOrder sets how text is ordered. Text in structure tree order
+// comes element by element, each laid out in lines; text outside the tree,
+// such as artifacts, follows in geometric order. Layout, column and
+// paragraph modes keep their own order.
+func WithReadingOrder(order ReadingOrder) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.ReadingOrder = order
+	}
+}
+
+// structTreeOrder lists the marked-content IDs of each page, keyed by the
+// page's object number, in structure tree order
+func (d *PDFDocument) structTreeOrder() map[int][]int {
+	order := make(map[int][]int)
+	if !d.walkStructTreeRoot(func(page, mcid int, _ string) {
+		if page != 0 {
+			order[page] = append(order[page], mcid)
+		}
+	}) {
+		return nil
+	}
+	return order
+}
+
+// isTagged reports whether the catalog's /MarkInfo marks the document as tagged
+func (d *PDFDocument) isTagged() bool {
+	markInfo, err := d.ctx.DereferenceDict(d.ctx.RootDict["MarkInfo"])
+	if err != nil || markInfo == nil {
+		return false
+	}
+	marked, ok := markInfo["Marked"].(types.Boolean)
+	return ok && marked.Value()
+}
+
+// libraryStructTreeOrder lists the marked-content IDs of a page in
+// structure tree order through the libraries, and whether the catalog marks
+// the document as tagged
+func libraryStructTreeOrder[V libraryValue[V]](catalog, page V) (order []int, tagged bool) {
+	tagged = catalog.Key("MarkInfo").Key("Marked").Bool()
+	libraryWalkStructTree(catalog, page, func(mcid int, _ string) {
+		order = append(order, mcid)
+	})
+	return order, tagged
+}
+
+// usesStructTree tells whether text is ordered by the structure tree for a
+// page whose marked content has the given tree order
+func (c *textExtractionConfig) usesStructTree(order []int, tagged bool) bool {
+	switch c.ReadingOrder {
+	case ReadingOrderAuto:
+		return tagged && len(order) > 0
+	case ReadingOrderStructTree:
+		return len(order) > 0
+	}
+	return false
+}
+
+// structOrderLines lays out the characters of each marked-content ID in
+// tree order, then the characters outside the tree
+func structOrderLines(chars []CharObject, order []int, options *textExtractionConfig, topDown bool) []string {
+	groups := make(map[int][]CharObject)
+	var rest []CharObject
+	inTree := make(map[int]bool, len(order))
+	for _, mcid := range order {
+		inTree[mcid] = true
+	}
+	for _, char := range chars {
+		if mcid := char.mcid - 1; char.mcid > 0 && inTree[mcid] {
+			groups[mcid] = append(groups[mcid], char)
+		} else {
+			rest = append(rest, char)
+		}
+	}
+
+	var lines []string
+	for _, mcid := range order {
+		lines = append(lines, textLines(groups[mcid], options, topDown)...)
+		delete(groups, mcid)
+	}
+	return append(lines, textLines(rest, options, topDown)...)
+}
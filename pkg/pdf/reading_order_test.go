@@ -0,0 +1,68 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadingOrder(t *testing.T) {
+	// Both documents draw the body before the title above it and have a
+	// structure tree listing the title first; only the first is marked as
+	// tagged. The page number is an artifact outside the tree.
+	tests := []struct {
+		path     string
+		order    ReadingOrder
+		expected string
+	}{
+		{"tagged_order.pdf", ReadingOrderAuto, "Title\nBody text\nPage 1"},
+		{"tagged_order.pdf", ReadingOrderStructTree, "Title\nBody text\nPage 1"},
+		{"tagged_order.pdf", ReadingOrderGeometric, "Body text\nTitle\nPage 1"},
+		{"untagged_order.pdf", ReadingOrderAuto, "Body text\nTitle\nPage 1"},
+		{"untagged_order.pdf", ReadingOrderStructTree, "Title\nBody text\nPage 1"},
+	}
+
+	for _, test := range tests {
+		doc, err := Open("../../testdata/" + test.path)
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", test.path, err)
+		}
+		page, _ := doc.GetPage(0)
+		if got := page.ExtractText(WithReadingOrder(test.order)); got != test.expected {
+			t.Errorf("%s with order %d: expected %q, got %q", test.path, test.order, test.expected, got)
+		}
+		doc.Close()
+	}
+}
+
+func TestReadingOrderLibraries(t *testing.T) {
+	// The libraries' pages find the structure tree's page by its
+	// dictionary, and their marked content through the content stream
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	tests := []struct {
+		path  string
+		order ReadingOrder
+		first string
+	}{
+		{"tagged_order.pdf", ReadingOrderAuto, "Title"},
+		{"tagged_order.pdf", ReadingOrderStructTree, "Title"},
+		{"untagged_order.pdf", ReadingOrderStructTree, "Title"},
+	}
+
+	for name, open := range backends {
+		for _, test := range tests {
+			doc, err := open("../../testdata/" + test.path)
+			if err != nil {
+				t.Fatalf("%s: failed to open %s: %v", name, test.path, err)
+			}
+			page, _ := doc.GetPage(0)
+			lines := strings.Split(page.ExtractText(WithReadingOrder(test.order)), "\n")
+			if len(lines) != 3 || lines[0] != test.first {
+				t.Errorf("%s: %s with order %d: expected 3 lines starting with %q, got %q", name, test.path, test.order, test.first, lines)
+			}
+			doc.Close()
+		}
+	}
+}
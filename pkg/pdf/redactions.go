@@ -0,0 +1,53 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// RedactionWarning is a filled box drawn where text is still present in the
+// content, which can be recovered by extracting it
+type RedactionWarning struct {
+	Text  string      // Covered text, its lines separated by newlines
+	BBox  BoundingBox // Bounds of the box
+	Color Color       // Fill color of the box, unset for pattern fills
+}
+
+// potentialRedactions finds filled rectangles painted over the centers of
+// characters drawn before them. Whatever its color, such a box hides the text
+// on the page while it stays in the content. Objects must carry their
+// painting order, which only the content stream parser records. topDown
+// tells whether Y grows downwards (true) or upwards as in raw PDF space
+// (false).
+func potentialRedactions(objects Objects, topDown bool) []RedactionWarning {
+	var warnings []RedactionWarning
+	for _, rect := range objects.Rects {
+		if !(rect.NonStroking || rect.Filled) || rect.order == 0 {
+			continue
+		}
+		box := rect.GetBBox().Normalize()
+		var covered []CharObject
+		for _, char := range objects.Chars {
+			if strings.TrimSpace(char.Text) == "" || char.order == 0 || char.order > rect.order {
+				continue
+			}
+			bbox := char.GetBBox()
+			if box.Contains((bbox.X0+bbox.X1)/2, (bbox.Y0+bbox.Y1)/2) {
+				covered = append(covered, char)
+			}
+		}
+		if len(covered) == 0 {
+			continue
+		}
+
+		var lines []string
+		for _, line := range groupCharsIntoTextLines(covered, 3, topDown) {
+			lines = append(lines, extractLineText(line, 3))
+		}
+		warnings = append(warnings, RedactionWarning{
+			Text:  strings.Join(lines, "\n"),
+			BBox:  box,
+			Color: rect.FillColor,
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,83 @@
+package pdf
+
+import (
+	"testing"
+)
+
+func TestPotentialRedactions(t *testing.T) {
+	// Boxes in PDF space; the ledongthuc backend has Y grow downwards
+	backends := map[string]struct {
+		open    func(string, ...OpenOption) (Document, error)
+		topDown bool
+	}{
+		"pdfcpu":     {Open, false},
+		"ledongthuc": {OpenWithLedongthuc, true},
+		"dslipak":    {OpenWithDslipak, false},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := backend.open("../../testdata/redactions.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, err := doc.GetPage(0)
+			if err != nil {
+				t.Fatalf("failed to get page: %v", err)
+			}
+
+			// White text on the black header and black text on the gray box
+			// stay readable; the black box, placed for estimated glyph widths,
+			// hides the colon and the first digits of the account number in
+			// Helvetica's
+			warnings, err := page.PotentialRedactions()
+			if err != nil {
+				t.Fatalf("failed to find redactions: %v", err)
+			}
+			if len(warnings) != 1 {
+				t.Fatalf("expected 1 potential redaction, got %d: %+v", len(warnings), warnings)
+			}
+			warning := warnings[0]
+			if warning.Text != ": 1234-" {
+				t.Errorf("expected covered text %q, got %q", ": 1234-", warning.Text)
+			}
+			expected := BoundingBox{X0: 119, Y0: 696, X1: 176, Y1: 710}
+			if backend.topDown {
+				expected.Y0, expected.Y1 = page.GetHeight()-710, page.GetHeight()-696
+			}
+			if warning.BBox != expected {
+				t.Errorf("expected bbox %+v, got %+v", expected, warning.BBox)
+			}
+			if warning.Color != (Color{A: 255}) {
+				t.Errorf("expected black fill, got %+v", warning.Color)
+			}
+		})
+	}
+}
+
+func TestPotentialRedactionsPaintOrder(t *testing.T) {
+	// A white box is painted over black text, and white text over a black
+	// box painted before it
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		doc, err := open("../../testdata/redactions_order.pdf")
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		page, _ := doc.GetPage(0)
+
+		warnings, err := page.PotentialRedactions()
+		if err != nil {
+			t.Fatalf("%s: failed to find redactions: %v", name, err)
+		}
+		if len(warnings) != 1 || warnings[0].Text != "Hidden" || warnings[0].Color != (Color{R: 255, G: 255, B: 255, A: 255}) {
+			t.Errorf("%s: expected only the white box over %q, got %+v", name, "Hidden", warnings)
+		}
+		doc.Close()
+	}
+}
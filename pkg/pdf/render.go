@@ -0,0 +1,286 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+// defaultResolution is the resolution of rendered pages unless set with
+// WithResolution, one pixel per point
+const defaultResolution = 72
+
+// maxRenderPixels bounds the size of rendered pages
+const maxRenderPixels = 1 << 26
+
+// maxResolution bounds the resolution of rendered pages in dots per inch
+const maxResolution = 4800
+
+// renderNote is stored in rendered PNGs to say what they leave out
+const renderNote = "Wireframe rendering: glyphs are drawn as boxes and images as placeholders"
+
+// Colors of objects that carry no color of their own
+var (
+	renderBackground  = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	renderDefaultInk  = color.RGBA{A: 255}
+	renderImageFill   = color.RGBA{R: 220, G: 220, B: 220, A: 255}
+	renderImageStroke = color.RGBA{R: 150, G: 150, B: 150, A: 255}
+)
+
+// charBoxAlpha is the opacity of the boxes standing in for glyphs, light
+// enough to keep overlapping objects visible
+const charBoxAlpha = 96
+
+// renderPage draws a page's objects on a white page the size of bbox and
+// encodes it as PNG. Rects, lines and curves are drawn in their colors;
+// characters become translucent boxes in their fill color and images gray
+// placeholders with a cross, since glyphs and image samples are not
+// rendered. topDown tells whether Y grows downwards.
+func renderPage(objects Objects, bbox BoundingBox, topDown bool, opts []ImageOption) (io.Reader, error) {
+	canvas, err := newRenderCanvas(bbox, topDown, opts)
+	if err != nil {
+		return nil, err
+	}
+	canvas.drawObjects(objects)
+	return canvas.encode()
+}
+
+// renderCanvas is a page being rendered, mapping page coordinates to pixels
+type renderCanvas struct {
+	img     *image.RGBA
+	bbox    BoundingBox
+	scale   float64
+	topDown bool
+}
+
+// newRenderCanvas creates a white canvas for bbox at the resolution of opts
+func newRenderCanvas(bbox BoundingBox, topDown bool, opts []ImageOption) (*renderCanvas, error) {
+	config := &imageConfig{Resolution: defaultResolution, Format: "png"}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.Resolution <= 0 || config.Resolution > maxResolution {
+		return nil, fmt.Errorf("invalid resolution %d, expected 1 to %d dpi", config.Resolution, maxResolution)
+	}
+	if config.Format != "png" {
+		return nil, fmt.Errorf("image format %q: %w", config.Format, ErrNotImplemented)
+	}
+
+	bbox = bbox.Normalize()
+	scale := float64(config.Resolution) / 72
+	// Sized in float64 first, so huge pages cannot overflow the check
+	fwidth := math.Ceil((bbox.X1 - bbox.X0) * scale)
+	fheight := math.Ceil((bbox.Y1 - bbox.Y0) * scale)
+	if !(fwidth > 0 && fheight > 0) {
+		return nil, fmt.Errorf("page has no area to render")
+	}
+	if fwidth*fheight > maxRenderPixels {
+		return nil, fmt.Errorf("rendering at %d dpi takes %.0fx%.0f pixels, more than %d", config.Resolution, fwidth, fheight, maxRenderPixels)
+	}
+	width, height := int(fwidth), int(fheight)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(renderBackground), image.Point{}, draw.Src)
+	return &renderCanvas{img: img, bbox: bbox, scale: scale, topDown: topDown}, nil
+}
+
+// drawObjects draws graphics first and characters over them
+func (c *renderCanvas) drawObjects(objects Objects) {
+	for _, img := range objects.Images {
+		c.fillBox(img.GetBBox(), renderImageFill)
+		c.strokeBox(img.GetBBox(), renderImageStroke, 1)
+		c.line(img.X0, img.Y0, img.X1, img.Y1, renderImageStroke, 1)
+		c.line(img.X0, img.Y1, img.X1, img.Y0, renderImageStroke, 1)
+	}
+	for _, rect := range objects.Rects {
+		// Rects painted one way are told apart by NonStroking; merged
+		// duplicates may be both filled and stroked
+		if (rect.NonStroking || rect.Filled) && colorSet(rect.FillColor) {
+			c.fillBox(rect.GetBBox(), renderColor(rect.FillColor, 255))
+		}
+		if !rect.NonStroking || rect.Stroked {
+			ink := renderDefaultInk
+			if colorSet(rect.StrokeColor) {
+				ink = renderColor(rect.StrokeColor, 255)
+			}
+			c.strokeBox(rect.GetBBox(), ink, rect.Width)
+		}
+	}
+	for _, curve := range objects.Curves {
+		ink := renderDefaultInk
+		if colorSet(curve.StrokeColor) {
+			ink = renderColor(curve.StrokeColor, 255)
+		} else if colorSet(curve.FillColor) {
+			ink = renderColor(curve.FillColor, 255)
+		}
+		for i := 1; i < len(curve.Points); i++ {
+			from, to := curve.Points[i-1], curve.Points[i]
+			c.line(from.X, from.Y, to.X, to.Y, ink, curve.Width)
+		}
+	}
+	for _, line := range objects.Lines {
+		ink := renderDefaultInk
+		if colorSet(line.StrokeColor) {
+			ink = renderColor(line.StrokeColor, 255)
+		}
+		c.line(line.X0, line.Y0, line.X1, line.Y1, ink, line.Width)
+	}
+	for _, char := range objects.Chars {
+		if char.RenderMode == 3 || char.RenderMode == 7 {
+			continue // Invisible text
+		}
+		fill := Color{A: 255}
+		if colorSet(char.Color) {
+			fill = char.Color
+		}
+		c.fillBox(BoundingBox{X0: char.X0, Y0: char.Y0, X1: char.X1, Y1: char.Y1}, renderColor(fill, charBoxAlpha))
+	}
+}
+
+// renderColor converts a color, giving it an opacity
+func renderColor(c Color, alpha uint8) color.RGBA {
+	// color.RGBA holds alpha-premultiplied values
+	premultiply := func(v uint8) uint8 { return uint8(uint16(v) * uint16(alpha) / 255) }
+	return color.RGBA{R: premultiply(c.R), G: premultiply(c.G), B: premultiply(c.B), A: alpha}
+}
+
+// point converts page coordinates to pixels
+func (c *renderCanvas) point(x, y float64) (float64, float64) {
+	px := (x - c.bbox.X0) * c.scale
+	if c.topDown {
+		return px, (y - c.bbox.Y0) * c.scale
+	}
+	return px, (c.bbox.Y1 - y) * c.scale
+}
+
+// rect converts a box in page coordinates to the pixels it covers, at least
+// one pixel in each direction
+func (c *renderCanvas) rect(box BoundingBox) image.Rectangle {
+	x0, y0 := c.point(box.X0, box.Y0)
+	x1, y1 := c.point(box.X1, box.Y1)
+	r := image.Rect(int(math.Floor(math.Min(x0, x1))), int(math.Floor(math.Min(y0, y1))),
+		int(math.Ceil(math.Max(x0, x1))), int(math.Ceil(math.Max(y0, y1))))
+	if r.Dx() == 0 {
+		r.Max.X++
+	}
+	if r.Dy() == 0 {
+		r.Max.Y++
+	}
+	return r
+}
+
+// fillBox fills a box, blending its color over what is drawn
+func (c *renderCanvas) fillBox(box BoundingBox, ink color.RGBA) {
+	draw.Draw(c.img, c.rect(box), image.NewUniform(ink), image.Point{}, draw.Over)
+}
+
+// strokeBox draws the outline of a box
+func (c *renderCanvas) strokeBox(box BoundingBox, ink color.RGBA, width float64) {
+	box = box.Normalize()
+	c.line(box.X0, box.Y0, box.X1, box.Y0, ink, width)
+	c.line(box.X1, box.Y0, box.X1, box.Y1, ink, width)
+	c.line(box.X1, box.Y1, box.X0, box.Y1, ink, width)
+	c.line(box.X0, box.Y1, box.X0, box.Y0, ink, width)
+}
+
+// line draws a line of a width in points, at least one pixel wide
+func (c *renderCanvas) line(x0, y0, x1, y1 float64, ink color.RGBA, width float64) {
+	bounds := c.img.Bounds()
+	thickness := int(math.Round(math.Min(width*c.scale, float64(bounds.Dx()+bounds.Dy()))))
+	if thickness < 1 {
+		thickness = 1
+	}
+
+	// Only the part of the line within a thickness of the canvas is
+	// stepped along
+	margin := float64(thickness)
+	px0, py0 := c.point(x0, y0)
+	px1, py1 := c.point(x1, y1)
+	px0, py0, px1, py1, ok := clipSegment(px0, py0, px1, py1,
+		-margin, -margin, float64(bounds.Dx())+margin, float64(bounds.Dy())+margin)
+	if !ok {
+		return
+	}
+
+	// Stamp a square of the line's thickness at every pixel along it
+	steps := int(math.Ceil(math.Max(math.Abs(px1-px0), math.Abs(py1-py0))))
+	src := image.NewUniform(ink)
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		x := int(math.Round(px0+(px1-px0)*t)) - thickness/2
+		y := int(math.Round(py0+(py1-py0)*t)) - thickness/2
+		draw.Draw(c.img, image.Rect(x, y, x+thickness, y+thickness), src, image.Point{}, draw.Src)
+	}
+}
+
+// clipSegment clips a segment to a rectangle with the Liang-Barsky
+// algorithm, reporting false if no part of it is inside
+func clipSegment(x0, y0, x1, y1, minX, minY, maxX, maxY float64) (float64, float64, float64, float64, bool) {
+	dx, dy := x1-x0, y1-y0
+	t0, t1 := 0.0, 1.0
+	for _, edge := range [4][2]float64{
+		{-dx, x0 - minX},
+		{dx, maxX - x0},
+		{-dy, y0 - minY},
+		{dy, maxY - y0},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		if t0 > t1 {
+			return 0, 0, 0, 0, false
+		}
+	}
+	if math.IsNaN(t0) || math.IsNaN(t1) {
+		return 0, 0, 0, 0, false
+	}
+	return x0 + t0*dx, y0 + t0*dy, x0 + t1*dx, y0 + t1*dy, true
+}
+
+// encode returns the canvas as PNG, noting what the rendering leaves out
+func (c *renderCanvas) encode() (io.Reader, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return bytes.NewReader(pngWithText(buf.Bytes(), "Comment", renderNote)), nil
+}
+
+// pngWithText inserts a tEXt chunk after the IHDR chunk of a PNG
+func pngWithText(data []byte, keyword, text string) []byte {
+	// 8-byte signature, then IHDR: length, type, 13 bytes of data and CRC
+	const ihdrEnd = 8 + 4 + 4 + 13 + 4
+	if len(data) < ihdrEnd {
+		return data
+	}
+
+	payload := append([]byte("tEXt"+keyword+"\x00"), text...)
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(payload)-4))
+	chunk = append(chunk, payload...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(payload))
+
+	out := make([]byte, 0, len(data)+len(chunk))
+	out = append(out, data[:ihdrEnd]...)
+	out = append(out, chunk...)
+	return append(out, data[ihdrEnd:]...)
+}
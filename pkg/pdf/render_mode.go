@@ -0,0 +1,29 @@
+package pdf
+
+// WithIncludeOutlined sets whether text drawn as outlines, with a render
+// mode that strokes the glyphs (1, 2, 5 or 6), is extracted (the default)
+func WithIncludeOutlined(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.ExcludeOutlined = !enabled
+	}
+}
+
+// strokesGlyphs reports whether a text render mode strokes the glyph outlines
+func strokesGlyphs(mode int) bool {
+	switch mode {
+	case 1, 2, 5, 6:
+		return true
+	}
+	return false
+}
+
+// filterOutlinedChars drops the characters drawn as outlines
+func filterOutlinedChars(chars []CharObject) []CharObject {
+	var filled []CharObject
+	for _, char := range chars {
+		if !char.Outlined {
+			filled = append(filled, char)
+		}
+	}
+	return filled
+}
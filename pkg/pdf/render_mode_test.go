@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOutlinedText(t *testing.T) {
+	doc, err := Open("../../testdata/outlined_text.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// The title is stroked in red with render mode 1, the body filled
+	for _, char := range page.GetObjects().Chars {
+		title := char.FontSize == 24
+		if char.Outlined != title || (title && char.RenderMode != 1) {
+			t.Errorf("%q: expected outlined %v, got render mode %d, outlined %v", char.Text, title, char.RenderMode, char.Outlined)
+		}
+		if title && char.Color != (Color{R: 255, A: 255}) {
+			t.Errorf("%q: expected the stroke color of outlined text, got %+v", char.Text, char.Color)
+		}
+	}
+
+	if text := page.ExtractText(); !strings.Contains(text, "ANNUAL REPORT") {
+		t.Errorf("expected outlined text by default, got %q", text)
+	}
+	text := page.ExtractText(WithIncludeOutlined(false))
+	if strings.Contains(text, "ANNUAL") || !strings.Contains(text, "Revenue grew this year.") {
+		t.Errorf("expected only the filled text, got %q", text)
+	}
+}
+
+func TestOutlinedTextLibraries(t *testing.T) {
+	// The libraries leave out the render mode, which is read from the
+	// content stream alongside them
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/outlined_text.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			for _, char := range page.GetObjects().Chars {
+				title := char.FontSize == 24
+				if char.Outlined != title || (title && char.RenderMode != 1) {
+					t.Errorf("%q: expected outlined %v, got render mode %d, outlined %v", char.Text, title, char.RenderMode, char.Outlined)
+				}
+			}
+			for _, opts := range [][]TextExtractionOption{
+				{WithIncludeOutlined(false)},
+				{WithIncludeOutlined(false), WithIgnoreRotatedText(true)},
+			} {
+				text := strings.ReplaceAll(page.ExtractText(opts...), " ", "")
+				if strings.Contains(text, "ANNUAL") || !strings.Contains(text, "Revenuegrew") {
+					t.Errorf("expected only the filled text, got %q", text)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+)
+
+// renderedPNG renders a page and decodes the PNG, returning its bytes too
+func renderedPNG(t *testing.T, page Page, opts ...ImageOption) (image.Image, []byte) {
+	t.Helper()
+	r, err := page.ToImage(opts...)
+	if err != nil {
+		t.Fatalf("failed to render page: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode PNG: %v", err)
+	}
+	return img, data
+}
+
+// inkedPixels counts the pixels of a region that are not white
+func inkedPixels(img image.Image, region image.Rectangle) int {
+	count := 0
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestToImage(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/two_pages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			// "First page" at 12pt from (72, 720), near the top whichever
+			// way the backend measures Y
+			img, data := renderedPNG(t, page)
+			if img.Bounds() != image.Rect(0, 0, 612, 792) {
+				t.Errorf("expected 612x792 pixels at 72 dpi, got %v", img.Bounds())
+			}
+			if inkedPixels(img, image.Rect(72, 60, 130, 76)) == 0 {
+				t.Error("expected character boxes at the top of the page")
+			}
+			if n := inkedPixels(img, image.Rect(72, 716, 130, 732)); n != 0 {
+				t.Errorf("expected nothing at the bottom of the page, got %d pixels", n)
+			}
+			if !bytes.Contains(data, []byte(renderNote)) {
+				t.Error("expected the PNG to note that glyphs are drawn as boxes")
+			}
+
+			if img, _ := renderedPNG(t, page, WithResolution(144)); img.Bounds() != image.Rect(0, 0, 1224, 1584) {
+				t.Errorf("expected 1224x1584 pixels at 144 dpi, got %v", img.Bounds())
+			}
+			if _, err := page.ToImage(WithResolution(0)); err == nil {
+				t.Error("expected an error for a zero resolution")
+			}
+		})
+	}
+}
+
+func TestToImageGraphics(t *testing.T) {
+	// A black bar from (72, 600) to (272, 620) with white text from x 80,
+	// and a light gray one from (72, 500) to (272, 520)
+	doc, err := Open("../../testdata/redactions.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	img, _ := renderedPNG(t, page)
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{250, 182, color.RGBA{A: 255}},                         // Black bar past the text
+		{250, 282, color.RGBA{R: 229, G: 229, B: 229, A: 255}}, // Gray bar
+		{500, 400, color.RGBA{R: 255, G: 255, B: 255, A: 255}}, // Blank page
+	}
+	for _, test := range tests {
+		if got := color.RGBAModel.Convert(img.At(test.x, test.y)); got != test.want {
+			t.Errorf("pixel (%d, %d): expected %v, got %v", test.x, test.y, test.want, got)
+		}
+	}
+}
+
+func TestDrawDebug(t *testing.T) {
+	// Ruled cells 128pt wide from x 72, rows 15pt and 20pt high from y 696
+	doc, err := Open("../../testdata/grid_table.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	draw := func(opts DebugOptions) image.Image {
+		t.Helper()
+		r, err := page.DrawDebug(opts)
+		if err != nil {
+			t.Fatalf("failed to draw page: %v", err)
+		}
+		img, err := png.Decode(r)
+		if err != nil {
+			t.Fatalf("failed to decode PNG: %v", err)
+		}
+		return img
+	}
+	pixel := func(img image.Image, x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+
+	// The cell border at x 200 is drawn black on the page and outlined in
+	// the default table color, since no color is set
+	cells := DebugOptions{Tables: true, TableOptions: []TableExtractionOption{WithTableStrategy("lines", "text")}}
+	if got := pixel(draw(DebugOptions{}), 200, 100); got != (color.RGBA{A: 255}) {
+		t.Errorf("expected the page's black rule without overlays, got %v", got)
+	}
+	if got := pixel(draw(cells), 200, 100); got != (color.RGBA{R: 255, B: 255, A: 255}) {
+		t.Errorf("expected the cell outlined in magenta, got %v", got)
+	}
+
+	// "Name" starts with a box from (76, 686) to (82, 696)
+	chars := DebugOptions{Chars: true, CharColor: Color{G: 200, A: 255}}
+	if got := pixel(draw(chars), 76, 100); got != (color.RGBA{G: 200, A: 255}) {
+		t.Errorf("expected the character outlined in its color, got %v", got)
+	}
+
+	all := DefaultDebugOptions()
+	all.Resolution = 144
+	if img := draw(all); img.Bounds() != image.Rect(0, 0, 1224, 1584) {
+		t.Errorf("expected 1224x1584 pixels at 144 dpi, got %v", img.Bounds())
+	}
+}
+
+func TestRenderLimits(t *testing.T) {
+	doc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// Pixel counts past the range of int must not slip through the check
+	for _, dpi := range []int{1 << 40, maxResolution + 1, 4000} {
+		if _, err := page.ToImage(WithResolution(dpi)); err == nil {
+			t.Errorf("expected an error rendering at %d dpi", dpi)
+		}
+	}
+
+	// A line reaching far off the page is drawn only where it is visible
+	canvas, err := newRenderCanvas(BoundingBox{X1: 100, Y1: 100}, false, nil)
+	if err != nil {
+		t.Fatalf("failed to create canvas: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		canvas.line(-1e10, 50, 1e10, 50, renderDefaultInk, 1)
+		canvas.line(-1e12, -1e12, 1e12, 1e12, renderDefaultInk, 1)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("drawing a huge line did not finish")
+	}
+	if got := color.RGBAModel.Convert(canvas.img.At(10, 50)); got != (color.RGBA{A: 255}) {
+		t.Errorf("expected the visible part of the line drawn, got %v", got)
+	}
+	if got := color.RGBAModel.Convert(canvas.img.At(30, 70)); got != (color.RGBA{A: 255}) {
+		t.Errorf("expected the visible part of the diagonal drawn, got %v", got)
+	}
+}
@@ -0,0 +1,270 @@
+package pdf
+
+import "math"
+
+// normalizeRotation reduces an angle in degrees to 0, 90, 180 or 270.
+// Angles that are not multiples of 90 are rounded to the nearest quarter-turn.
+func normalizeRotation(degrees int) int {
+	r := degrees % 360
+	if r < 0 {
+		r += 360
+	}
+	return (r + 45) / 90 * 90 % 360
+}
+
+// rotatePoint rotates a point clockwise on a page of the given size.
+// topDown tells whether Y grows downwards (true) or upwards as in raw PDF space (false).
+func rotatePoint(x, y float64, degrees int, width, height float64, topDown bool) (float64, float64) {
+	switch normalizeRotation(degrees) {
+	case 90:
+		if topDown {
+			return height - y, x
+		}
+		return y, width - x
+	case 180:
+		return width - x, height - y
+	case 270:
+		if topDown {
+			return y, width - x
+		}
+		return height - y, x
+	}
+	return x, y
+}
+
+// rotatedSize returns the page size after rotation
+func rotatedSize(degrees int, width, height float64) (float64, float64) {
+	if normalizeRotation(degrees)%180 == 90 {
+		return height, width
+	}
+	return width, height
+}
+
+// RotateObjects rotates all objects clockwise on a page of the given size.
+// topDown tells whether Y grows downwards (true) or upwards as in raw PDF space (false).
+func RotateObjects(objects Objects, degrees int, width, height float64, topDown bool) Objects {
+	rotated, _ := RotateObjectsInBox(objects, degrees, BoundingBox{X1: width, Y1: height}, topDown)
+	return rotated
+}
+
+// rotateInBox rotates a point clockwise within box, whose corner (X0, Y0)
+// stays in place
+func rotateInBox(x, y float64, degrees int, box BoundingBox, topDown bool) (float64, float64) {
+	x, y = rotatePoint(x-box.X0, y-box.Y0, degrees, box.Width(), box.Height(), topDown)
+	return x + box.X0, y + box.Y0
+}
+
+// rotateBoxInBox rotates a box clockwise within box and returns it normalized
+func rotateBoxInBox(b BoundingBox, degrees int, box BoundingBox, topDown bool) BoundingBox {
+	ax, ay := rotateInBox(b.X0, b.Y0, degrees, box, topDown)
+	bx, by := rotateInBox(b.X1, b.Y1, degrees, box, topDown)
+	return BoundingBox{X0: min(ax, bx), Y0: min(ay, by), X1: max(ax, bx), Y1: max(ay, by)}
+}
+
+// RotateObjectsInBox rotates objects clockwise within the box of a page,
+// possibly cropped, whose corner (X0, Y0) stays in place, and returns them
+// with the rotated box.
+func RotateObjectsInBox(objects Objects, degrees int, box BoundingBox, topDown bool) (Objects, BoundingBox) {
+	box = box.Normalize()
+	rect := func(x0, y0, x1, y1 float64) (float64, float64, float64, float64) {
+		b := rotateBoxInBox(BoundingBox{X0: x0, Y0: y0, X1: x1, Y1: y1}, degrees, box, topDown)
+		return b.X0, b.Y0, b.X1, b.Y1
+	}
+	rotated := Objects{}
+
+	for _, char := range objects.Chars {
+		char.X0, char.Y0, char.X1, char.Y1 = rect(char.X0, char.Y0, char.X1, char.Y1)
+		char.Width = char.X1 - char.X0
+		char.Height = char.Y1 - char.Y0
+		rotated.Chars = append(rotated.Chars, char)
+	}
+
+	for _, line := range objects.Lines {
+		line.X0, line.Y0 = rotateInBox(line.X0, line.Y0, degrees, box, topDown)
+		line.X1, line.Y1 = rotateInBox(line.X1, line.Y1, degrees, box, topDown)
+		rotated.Lines = append(rotated.Lines, line)
+	}
+
+	for _, r := range objects.Rects {
+		r.X0, r.Y0, r.X1, r.Y1 = rect(r.X0, r.Y0, r.X1, r.Y1)
+		rotated.Rects = append(rotated.Rects, r)
+	}
+
+	for _, curve := range objects.Curves {
+		points := make([]Point, len(curve.Points))
+		for i, pt := range curve.Points {
+			points[i].X, points[i].Y = rotateInBox(pt.X, pt.Y, degrees, box, topDown)
+		}
+		curve.Points = points
+		rotated.Curves = append(rotated.Curves, curve)
+	}
+
+	for _, image := range objects.Images {
+		image.X0, image.Y0, image.X1, image.Y1 = rect(image.X0, image.Y0, image.X1, image.Y1)
+		rotated.Images = append(rotated.Images, image)
+	}
+
+	for _, anno := range objects.Annos {
+		anno.X0, anno.Y0, anno.X1, anno.Y1 = rect(anno.X0, anno.Y0, anno.X1, anno.Y1)
+		rotated.Annos = append(rotated.Annos, anno)
+	}
+
+	for _, shading := range objects.Shadings {
+		shading.X0, shading.Y0, shading.X1, shading.Y1 = rect(shading.X0, shading.Y0, shading.X1, shading.Y1)
+		rotated.Shadings = append(rotated.Shadings, shading)
+	}
+
+	width, height := rotatedSize(degrees, box.Width(), box.Height())
+	return rotated, BoundingBox{X0: box.X0, Y0: box.Y0, X1: box.X0 + width, Y1: box.Y0 + height}
+}
+
+// charDirection returns the reading direction of a character in degrees
+// counter-clockwise: 0 for upright, 90 for bottom-to-top, 180 for upside
+// down and 270 for top-to-bottom text
+func charDirection(char CharObject) int {
+	m := char.Matrix
+	switch {
+	case math.Abs(m.B) > math.Abs(m.A) && m.B > 0:
+		return 90
+	case math.Abs(m.B) > math.Abs(m.A) && m.B < 0:
+		return 270
+	case m.A < 0:
+		return 180
+	}
+	return 0
+}
+
+// dominantTextDirection returns the most common direction among characters
+func dominantTextDirection(chars []CharObject) int {
+	counts := make(map[int]int)
+	best := 0
+	for _, char := range chars {
+		direction := charDirection(char)
+		counts[direction]++
+		if counts[direction] > counts[best] {
+			best = direction
+		}
+	}
+	return best
+}
+
+// textAxisPosition returns a character's position across lines and along its
+// line for text read in the given direction, both increasing in reading order.
+// Positions are in raw PDF space with Y growing upwards.
+func textAxisPosition(char CharObject, direction int) (float64, float64) {
+	switch direction {
+	case 90:
+		return char.X0, char.Y0
+	case 180:
+		return char.Y0, -char.X0
+	case 270:
+		return -char.X0, -char.Y0
+	}
+	return -char.Y0, char.X0
+}
+
+// textAxisGap returns the whitespace between two consecutive characters of a
+// line read in the given direction
+func textAxisGap(prev, next CharObject, direction int) float64 {
+	switch direction {
+	case 90:
+		return next.Y0 - prev.Y1
+	case 180:
+		return prev.X0 - next.X1
+	case 270:
+		return prev.Y0 - next.Y1
+	}
+	return next.X0 - prev.X1
+}
+
+// defaultRotatedTextTolerance is the largest angle in degrees at which text
+// still counts as upright when rotated text is ignored
+const defaultRotatedTextTolerance = 10.0
+
+// charAngle returns the angle of a character's baseline in degrees, in the
+// range (-180, 180]. Characters without a matrix count as upright.
+func charAngle(char CharObject) float64 {
+	m := char.Matrix
+	if m.A == 0 && m.B == 0 {
+		return 0
+	}
+	return math.Atan2(m.B, m.A) * 180 / math.Pi
+}
+
+// defaultTextAngleThreshold is the largest difference in degrees between
+// the baselines of characters grouped into one word
+const defaultTextAngleThreshold = 10.0
+
+// sameOrientation reports whether the baselines of two characters differ by
+// at most threshold degrees
+func sameOrientation(a, b CharObject, threshold float64) bool {
+	diff := math.Abs(charAngle(a) - charAngle(b))
+	if diff > 180 {
+		diff = 360 - diff
+	}
+	return diff <= threshold
+}
+
+// filterRotatedChars keeps the characters rotated by at most tolerance degrees
+func filterRotatedChars(chars []CharObject, tolerance float64) []CharObject {
+	var upright []CharObject
+	for _, char := range chars {
+		if math.Abs(charAngle(char)) <= tolerance {
+			upright = append(upright, char)
+		}
+	}
+	return upright
+}
+
+// glyphPosition is where the libraries place a text item: its origin,
+// the horizontal part of its advance and of its font size
+type glyphPosition struct {
+	x, y, w, size float64
+}
+
+// glyphAngles infers the baseline angle in degrees of the libraries' text
+// items, which come without a text matrix. A glyph followed by another at
+// the horizontal part of its advance steps towards it along its baseline;
+// two such steps in a row at one angle make a line at that angle, which
+// the raised glyph after a superscript or subscript does not. Any other
+// glyph is upright, unless its font size has no horizontal part at all, as
+// on vertical lines.
+func glyphAngles(glyphs []glyphPosition) []float64 {
+	steps := make([]float64, len(glyphs))
+	stepped := make([]bool, len(glyphs))
+	for i := 0; i+1 < len(glyphs); i++ {
+		g, next := glyphs[i], glyphs[i+1]
+		dx, dy := next.x-g.x, next.y-g.y
+		if math.Abs(dx-g.w) <= 0.01*max(1, math.Abs(g.w)) && (dx != 0 || dy != 0) {
+			steps[i], stepped[i] = math.Atan2(dy, dx)*180/math.Pi, true
+		}
+	}
+	line := func(i int) bool {
+		return i >= 0 && i+1 < len(glyphs) && stepped[i] && stepped[i+1] && math.Abs(steps[i]-steps[i+1]) < 1
+	}
+
+	angles := make([]float64, len(glyphs))
+	for i, g := range glyphs {
+		switch {
+		case line(i) || line(i-1):
+			angles[i] = steps[i]
+		case line(i - 2):
+			// The last glyph of a line
+			angles[i] = steps[i-1]
+		case g.size == 0:
+			angles[i] = 90
+		}
+	}
+	return angles
+}
+
+// baselineMatrix returns the matrix of a library glyph at (x, y) whose
+// baseline is at angle degrees, and the zero matrix the libraries leave
+// upright glyphs with
+func baselineMatrix(angle, x, y float64) TransformMatrix {
+	if angle == 0 {
+		return TransformMatrix{}
+	}
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	return TransformMatrix{A: cos, B: sin, C: -sin, D: cos, E: x, F: y}
+}
@@ -0,0 +1,125 @@
+package pdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIgnoreRotatedText(t *testing.T) {
+	doc, err := Open("../../testdata/watermark.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// A "CONFIDENTIAL" watermark runs bottom to top across the body text
+	page, _ := doc.GetPage(0)
+	if text := page.ExtractText(); !strings.Contains(text, "C\nO\nN") {
+		t.Fatalf("expected the watermark in the text by default, got %q", text)
+	}
+
+	if text := page.ExtractText(WithIgnoreRotatedText(true)); text != "Quarterly report\nRevenue grew" {
+		t.Errorf("expected only the body text, got %q", text)
+	}
+
+	for _, word := range page.ExtractWords(WithWordIgnoreRotatedText(true)) {
+		if strings.ContainsAny(word.Text, "CDFNT") {
+			t.Errorf("expected no watermark characters in words, got %q", word.Text)
+		}
+	}
+
+	// A tolerance above 90 degrees keeps the vertical watermark
+	text := page.ExtractText(WithIgnoreRotatedText(true), WithRotatedTextTolerance(90))
+	if !strings.Contains(text, "C\nO\nN") {
+		t.Errorf("expected the watermark within the tolerance, got %q", text)
+	}
+}
+
+func TestIgnoreRotatedTextLibraries(t *testing.T) {
+	// The libraries give no text matrix; the watermark's baseline is told
+	// from the steps between its glyphs
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/watermark.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+			page, _ := doc.GetPage(0)
+
+			if text := page.ExtractText(WithIgnoreRotatedText(true)); text != "Quarterly report\nRevenue grew" {
+				t.Errorf("expected only the body text, got %q", text)
+			}
+			for _, word := range page.ExtractWords(WithWordIgnoreRotatedText(true)) {
+				if strings.ContainsAny(word.Text, "CDFNT") {
+					t.Errorf("expected no watermark characters in words, got %q", word.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestWordsSplitAtRotation(t *testing.T) {
+	doc, err := Open("../../testdata/mixed_angle.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// The vertical "CD" starts right after the horizontal "AB", on its line
+	var texts []string
+	for _, word := range page.ExtractWords() {
+		texts = append(texts, word.Text)
+	}
+	for _, text := range texts {
+		if strings.Contains(text, "B") && strings.Contains(text, "C") {
+			t.Errorf("expected horizontal and vertical characters in separate words, got %q", texts)
+		}
+	}
+
+	// A threshold covering the rotation groups them by position alone
+	merged := false
+	for _, word := range page.ExtractWords(WithTextAngleThreshold(180)) {
+		if word.Text == "ABC" {
+			merged = true
+		}
+	}
+	if !merged {
+		t.Error("expected ABC as one word with a 180 degree threshold")
+	}
+}
+
+func TestPageRotation(t *testing.T) {
+	// The first page inherits /Rotate 90 from the page tree, the second
+	// sets /Rotate 180 itself
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/inherited_rotate.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			for i, want := range []int{90, 180} {
+				page, _ := doc.GetPage(i)
+				if got := page.GetRotation(); got != want {
+					t.Errorf("page %d: expected rotation %d, got %d", i, want, got)
+				}
+				// Page.Rotate turns the page further
+				if got := page.Rotate(90).GetRotation(); got != (want+90)%360 {
+					t.Errorf("page %d: expected rotation %d after Rotate(90), got %d", i, (want+90)%360, got)
+				}
+			}
+		})
+	}
+}
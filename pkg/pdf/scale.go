@@ -0,0 +1,102 @@
+package pdf
+
+// scaleChar multiplies a character's coordinates and dimensions by a factor
+func scaleChar(char CharObject, factor float64) CharObject {
+	char.X0 *= factor
+	char.Y0 *= factor
+	char.X1 *= factor
+	char.Y1 *= factor
+	char.Width *= factor
+	char.Height *= factor
+	char.Matrix.E *= factor
+	char.Matrix.F *= factor
+	return char
+}
+
+// scaleWords multiplies the coordinates of words and their characters by a factor
+func scaleWords(words []Word, factor float64) []Word {
+	if factor == 0 || factor == 1 {
+		return words
+	}
+
+	scaled := make([]Word, len(words))
+	for i, word := range words {
+		word.X0 *= factor
+		word.Y0 *= factor
+		word.X1 *= factor
+		word.Y1 *= factor
+
+		chars := make([]CharObject, len(word.Characters))
+		for j, char := range word.Characters {
+			chars[j] = scaleChar(char, factor)
+		}
+		word.Characters = chars
+
+		scaled[i] = word
+	}
+	return scaled
+}
+
+// Scale returns the objects with all coordinates and dimensions multiplied
+// by a factor, such as 150/72 to go from points to pixels at 150 DPI
+func (o Objects) Scale(factor float64) Objects {
+	scaled := Objects{}
+
+	for _, char := range o.Chars {
+		scaled.Chars = append(scaled.Chars, scaleChar(char, factor))
+	}
+
+	for _, line := range o.Lines {
+		line.X0 *= factor
+		line.Y0 *= factor
+		line.X1 *= factor
+		line.Y1 *= factor
+		line.Width *= factor
+		scaled.Lines = append(scaled.Lines, line)
+	}
+
+	for _, rect := range o.Rects {
+		rect.X0 *= factor
+		rect.Y0 *= factor
+		rect.X1 *= factor
+		rect.Y1 *= factor
+		rect.Width *= factor
+		scaled.Rects = append(scaled.Rects, rect)
+	}
+
+	for _, curve := range o.Curves {
+		points := make([]Point, len(curve.Points))
+		for i, pt := range curve.Points {
+			points[i] = Point{X: pt.X * factor, Y: pt.Y * factor}
+		}
+		curve.Points = points
+		curve.Width *= factor
+		scaled.Curves = append(scaled.Curves, curve)
+	}
+
+	for _, image := range o.Images {
+		image.X0 *= factor
+		image.Y0 *= factor
+		image.X1 *= factor
+		image.Y1 *= factor
+		scaled.Images = append(scaled.Images, image)
+	}
+
+	for _, anno := range o.Annos {
+		anno.X0 *= factor
+		anno.Y0 *= factor
+		anno.X1 *= factor
+		anno.Y1 *= factor
+		scaled.Annos = append(scaled.Annos, anno)
+	}
+
+	for _, shading := range o.Shadings {
+		shading.X0 *= factor
+		shading.Y0 *= factor
+		shading.X1 *= factor
+		shading.Y1 *= factor
+		scaled.Shadings = append(scaled.Shadings, shading)
+	}
+
+	return scaled
+}
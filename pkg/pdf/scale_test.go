@@ -0,0 +1,63 @@
+package pdf
+
+import "testing"
+
+func TestExtractWordsWithScale(t *testing.T) {
+	page := openGridTablePage(t)
+
+	factor := 150.0 / 72.0
+	words := page.ExtractWords()
+	scaled := page.ExtractWords(WithScale(factor))
+	if len(words) == 0 || len(scaled) != len(words) {
+		t.Fatalf("expected %d scaled words, got %d", len(words), len(scaled))
+	}
+
+	for i, word := range words {
+		got := scaled[i]
+		if got.Text != word.Text {
+			t.Fatalf("word %d: expected %q, got %q", i, word.Text, got.Text)
+		}
+		if abs(got.X0-word.X0*factor) > 1e-9 || abs(got.Y0-word.Y0*factor) > 1e-9 ||
+			abs(got.X1-word.X1*factor) > 1e-9 || abs(got.Y1-word.Y1*factor) > 1e-9 {
+			t.Errorf("word %q: expected (%.2f, %.2f, %.2f, %.2f) scaled, got (%.2f, %.2f, %.2f, %.2f)",
+				word.Text, word.X0, word.Y0, word.X1, word.Y1, got.X0, got.Y0, got.X1, got.Y1)
+		}
+
+		// Characters still fit inside their word
+		bbox := BoundingBox{X0: got.X0, Y0: got.Y0, X1: got.X1, Y1: got.Y1}
+		for _, char := range got.Characters {
+			if union := bbox.Union(char.GetBBox()); union != bbox {
+				t.Errorf("word %q: char %q at %+v falls outside %+v", got.Text, char.Text, char.GetBBox(), bbox)
+			}
+		}
+	}
+
+	// Unscaled words are not affected by the scaled extraction
+	if again := page.ExtractWords(); again[0].X0 != words[0].X0 {
+		t.Errorf("expected unscaled X0 %.2f, got %.2f", words[0].X0, again[0].X0)
+	}
+}
+
+func TestObjectsScale(t *testing.T) {
+	objects := Objects{
+		Chars: []CharObject{{Text: "A", X0: 10, Y0: 20, X1: 16, Y1: 32, Width: 6, Height: 12}},
+		Lines: []LineObject{{X0: 0, Y0: 5, X1: 100, Y1: 5, Width: 1}},
+		Rects: []RectObject{{X0: 10, Y0: 10, X1: 50, Y1: 30}},
+	}
+
+	scaled := objects.Scale(2)
+
+	char := scaled.Chars[0]
+	if char.X0 != 20 || char.Y0 != 40 || char.X1 != 32 || char.Y1 != 64 || char.Width != 12 || char.Height != 24 {
+		t.Errorf("unexpected scaled char %+v", char)
+	}
+	if line := scaled.Lines[0]; line.X1 != 200 || line.Y0 != 10 || line.Width != 2 {
+		t.Errorf("unexpected scaled line %+v", line)
+	}
+	if rect := scaled.Rects[0]; rect.GetBBox() != (BoundingBox{X0: 20, Y0: 20, X1: 100, Y1: 60}) {
+		t.Errorf("unexpected scaled rect %+v", rect)
+	}
+	if objects.Chars[0].X0 != 10 {
+		t.Errorf("expected the original objects to be unchanged")
+	}
+}
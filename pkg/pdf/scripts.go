@@ -0,0 +1,96 @@
+package pdf
+
+const (
+	// scriptSizeRatio is the least ratio between the font sizes of
+	// neighboring characters kept in one word by WithSeparateSuperscripts
+	scriptSizeRatio = 0.85
+	// scriptShiftRatio is the least baseline shift, as a fraction of the
+	// larger font size, that starts a new word or tags a superscript or
+	// subscript
+	scriptShiftRatio = 0.1
+)
+
+// WithSeparateSuperscripts splits words where the font size or baseline of
+// a character jumps from the one before it, so a footnote marker such as
+// the "1" of "income¹" becomes a word of its own. A fragment raised above
+// the largest text of its word is tagged Superscript, one lowered below it
+// Subscript.
+func WithSeparateSuperscripts(enabled bool) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		c.SeparateSuperscripts = enabled
+	}
+}
+
+// separateScripts splits words into runs of one font size and baseline and
+// tags the runs shifted from the word's main text. topDown tells whether Y
+// grows downwards (true) or upwards as in raw PDF space (false).
+func separateScripts(words []Word, topDown bool) []Word {
+	var result []Word
+	for _, word := range words {
+		runs := scriptRuns(word.Characters, topDown)
+		if len(runs) < 2 {
+			result = append(result, word)
+			continue
+		}
+
+		// The run in the largest font is the word's main text
+		main := 0
+		for i, run := range runs {
+			if run[0].FontSize > runs[main][0].FontSize {
+				main = i
+			}
+		}
+		size := runs[main][0].FontSize
+		for i, run := range runs {
+			fragment := createWord(run)
+			if i != main {
+				shift := baselineShift(run[0], runs[main][0], topDown)
+				fragment.Superscript = shift > scriptShiftRatio*size
+				fragment.Subscript = shift < -scriptShiftRatio*size
+			}
+			result = append(result, fragment)
+		}
+	}
+	return result
+}
+
+// scriptRuns splits characters where the font size or baseline changes
+func scriptRuns(chars []CharObject, topDown bool) [][]CharObject {
+	if len(chars) == 0 {
+		return nil
+	}
+	var runs [][]CharObject
+	start := 0
+	for i := 1; i < len(chars); i++ {
+		prev, char := chars[i-1], chars[i]
+		larger := max(prev.FontSize, char.FontSize)
+		if larger <= 0 {
+			continue
+		}
+		if min(prev.FontSize, char.FontSize)/larger < scriptSizeRatio ||
+			abs(baselineShift(char, prev, topDown)) > scriptShiftRatio*larger {
+			runs = append(runs, chars[start:i])
+			start = i
+		}
+	}
+	return append(runs, chars[start:])
+}
+
+// baselineShift returns how far the baseline of a character, raised by its
+// text rise, is above that of another
+func baselineShift(char, other CharObject, topDown bool) float64 {
+	shift := charBaseline(char, topDown) - charBaseline(other, topDown)
+	if topDown {
+		shift = -shift
+	}
+	return shift + char.rise - other.rise
+}
+
+// charBaseline returns the Y of a character's baseline, which lies above
+// the bottom of its box by the depth of the font's descenders
+func charBaseline(char CharObject, topDown bool) float64 {
+	if topDown {
+		return char.Y1 + char.descent
+	}
+	return char.Y0 - char.descent
+}
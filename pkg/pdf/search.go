@@ -0,0 +1,150 @@
+package pdf
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DocSearchMatch is a match of a text search within a document
+type DocSearchMatch struct {
+	PageIndex int         // Page the match is on (0-based)
+	Text      string      // The matched text
+	BBox      BoundingBox // Bounds of the matched characters
+}
+
+// SearchOption configures text search
+type SearchOption func(*searchConfig)
+
+type searchConfig struct {
+	Context       context.Context
+	Regex         bool
+	CaseSensitive bool
+	XTolerance    float64
+	YTolerance    float64
+}
+
+// WithSearchContext stops a search once ctx is done. The matches found on
+// the pages searched so far are returned.
+func WithSearchContext(ctx context.Context) SearchOption {
+	return func(c *searchConfig) {
+		c.Context = ctx
+	}
+}
+
+// WithSearchRegex sets whether the pattern is a regular expression (the
+// default) or literal text
+func WithSearchRegex(enabled bool) SearchOption {
+	return func(c *searchConfig) {
+		c.Regex = enabled
+	}
+}
+
+// WithSearchCaseSensitive sets whether matching is case-sensitive (the default)
+func WithSearchCaseSensitive(enabled bool) SearchOption {
+	return func(c *searchConfig) {
+		c.CaseSensitive = enabled
+	}
+}
+
+// newSearchConfig returns the default search configuration with opts applied
+func newSearchConfig(opts []SearchOption) *searchConfig {
+	config := &searchConfig{
+		Context:       context.Background(),
+		Regex:         true,
+		CaseSensitive: true,
+		XTolerance:    3,
+		YTolerance:    3,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// searchDocument searches the text of pages in order. topDown tells whether
+// Y grows downwards (true) or upwards as in raw PDF space (false). An
+// invalid regular expression matches nothing.
+func searchDocument(pages []Page, topDown bool, pattern string, opts []SearchOption) []DocSearchMatch {
+	config := newSearchConfig(opts)
+	if !config.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !config.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+
+	var matches []DocSearchMatch
+	for i, page := range pages {
+		if config.Context.Err() != nil {
+			break
+		}
+		matches = append(matches, searchChars(page.GetObjects().Chars, i, topDown, re, config)...)
+	}
+	return matches
+}
+
+// searchChars matches re against the text of a page's characters, mapping
+// matches back to the characters they cover
+func searchChars(chars []CharObject, pageIndex int, topDown bool, re *regexp.Regexp, config *searchConfig) []DocSearchMatch {
+	s, owners := searchText(chars, topDown, config)
+	var matches []DocSearchMatch
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		var bbox BoundingBox
+		found := false
+		for i, owner := range owners[loc[0]:loc[1]] {
+			// Count each character once, at its first byte
+			if owner == nil || (i > 0 && owners[loc[0]+i-1] == owner) {
+				continue
+			}
+			if !found {
+				bbox = owner.GetBBox().Normalize()
+				found = true
+			} else {
+				bbox = bbox.Union(owner.GetBBox())
+			}
+		}
+		if !found {
+			continue
+		}
+		matches = append(matches, DocSearchMatch{PageIndex: pageIndex, Text: s[loc[0]:loc[1]], BBox: bbox})
+	}
+	return matches
+}
+
+// searchText lays out the text of a page's characters as lines joined by
+// newlines, with spaces between words. It returns the character each byte
+// of the text comes from, nil for separators.
+func searchText(chars []CharObject, topDown bool, config *searchConfig) (string, []*CharObject) {
+	var text strings.Builder
+	var owners []*CharObject // Character each byte of text comes from, nil for separators
+	separator := func(s string) {
+		text.WriteString(s)
+		for range s {
+			owners = append(owners, nil)
+		}
+	}
+
+	for i, line := range groupCharsIntoTextLines(chars, config.YTolerance, topDown) {
+		if i > 0 {
+			separator("\n")
+		}
+		sort.SliceStable(line, func(a, b int) bool { return line[a].X0 < line[b].X0 })
+		for j := range line {
+			char := &line[j]
+			if j > 0 && (char.X0-line[j-1].X1 > config.XTolerance || char.followsSpace) {
+				separator(" ")
+			}
+			text.WriteString(char.Text)
+			for k := 0; k < len(char.Text); k++ {
+				owners = append(owners, char)
+			}
+		}
+	}
+	return text.String(), owners
+}
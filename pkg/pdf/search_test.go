@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDocumentSearch(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/two_pages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			// "First page" on page 1 and "Page two" on page 2
+			matches := doc.Search("page", WithSearchCaseSensitive(false))
+			if len(matches) != 2 {
+				t.Fatalf("expected 2 matches, got %+v", matches)
+			}
+			for i, want := range []string{"page", "Page"} {
+				match := matches[i]
+				if match.PageIndex != i || match.Text != want {
+					t.Errorf("match %d: expected %q on page %d, got %q on page %d", i, want, i, match.Text, match.PageIndex)
+				}
+				if match.BBox.X1-match.BBox.X0 <= 0 || match.BBox.Y1-match.BBox.Y0 <= 0 {
+					t.Errorf("match %d: expected a non-empty bbox, got %+v", i, match.BBox)
+				}
+			}
+
+			if matches := doc.Search(`Second\s+line`); len(matches) != 1 || matches[0].Text != "Second line" {
+				t.Errorf("expected the phrase across the word gap, got %+v", matches)
+			}
+			// Matches follow the reading order, top line first
+			var words []string
+			for _, match := range doc.Search(`\w+`) {
+				if match.PageIndex == 0 {
+					words = append(words, match.Text)
+				}
+			}
+			if strings.Join(words, " ") != "First page Second line" {
+				t.Errorf("expected the first page's words in reading order, got %q", words)
+			}
+			if matches := doc.Search("page"); len(matches) != 1 || matches[0].PageIndex != 0 {
+				t.Errorf("expected a case-sensitive match on the first page only, got %+v", matches)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if matches := doc.Search("page", WithSearchContext(ctx)); len(matches) != 0 {
+				t.Errorf("expected no matches once the context is cancelled, got %+v", matches)
+			}
+		})
+	}
+}
+
+func TestExtractBetween(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/between.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			page, err := doc.GetPage(0)
+			if err != nil {
+				t.Fatalf("failed to get page: %v", err)
+			}
+
+			// The "Summary of Results" heading is broken over two lines
+			tests := []struct {
+				start, end string
+				opts       []SearchOption
+				want       string
+			}{
+				{"Summary of Results", "Appendix", nil, "Sales grew in every region.\nCosts were flat."},
+				{"summary OF results", "appendix", []SearchOption{WithSearchCaseSensitive(false)}, "Sales grew in every region.\nCosts were flat."},
+				{"Costs were", "Glossary", nil, "flat.\nAppendix\nRaw tables follow."}, // No end anchor, to the end of the page
+			}
+			for _, tt := range tests {
+				text, ok := page.ExtractBetween(tt.start, tt.end, tt.opts...)
+				if !ok || text != tt.want {
+					t.Errorf("between %q and %q: expected %q, got %q (%v)", tt.start, tt.end, tt.want, text, ok)
+				}
+			}
+			if text, ok := page.ExtractBetween("Glossary", "Appendix"); ok || text != "" {
+				t.Errorf("expected a missing start anchor to report false, got %q (%v)", text, ok)
+			}
+
+			// The document-level version spans pages
+			text, ok := doc.ExtractBetween("Raw tables follow.", "Appendix B")
+			if !ok || text != "Notes\nMore body text." {
+				t.Errorf("expected the text across the page break, got %q (%v)", text, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+)
+
+// WithSnapGrid rounds the coordinates of every object to the nearest
+// multiple of size when a page is parsed, so positions that floating-point
+// error left apart, such as 100.0001 and 99.9998, become equal before lines
+// are consolidated, tables detected or objects deduplicated. Character
+// widths and heights follow the snapped coordinates; line widths and font
+// sizes are kept. A size of 0 disables snapping.
+func WithSnapGrid(size float64) OpenOption {
+	return func(c *openConfig) {
+		if size < 0 {
+			if c.err == nil {
+				c.err = fmt.Errorf("snap grid size %v is negative", size)
+			}
+			return
+		}
+		c.SnapGrid = size
+	}
+}
+
+// snapToGrid rounds the coordinates of the objects to multiples of size
+func snapToGrid(objects Objects, size float64) Objects {
+	if size <= 0 {
+		return objects
+	}
+	snap := func(v float64) float64 {
+		return math.Round(v/size) * size
+	}
+
+	for i := range objects.Chars {
+		char := &objects.Chars[i]
+		char.X0, char.Y0, char.X1, char.Y1 = snap(char.X0), snap(char.Y0), snap(char.X1), snap(char.Y1)
+		char.Width, char.Height = char.X1-char.X0, char.Y1-char.Y0
+	}
+	for i := range objects.Lines {
+		line := &objects.Lines[i]
+		line.X0, line.Y0, line.X1, line.Y1 = snap(line.X0), snap(line.Y0), snap(line.X1), snap(line.Y1)
+	}
+	for i := range objects.Rects {
+		rect := &objects.Rects[i]
+		rect.X0, rect.Y0, rect.X1, rect.Y1 = snap(rect.X0), snap(rect.Y0), snap(rect.X1), snap(rect.Y1)
+	}
+	for i := range objects.Curves {
+		points := make([]Point, len(objects.Curves[i].Points))
+		for j, point := range objects.Curves[i].Points {
+			points[j] = Point{X: snap(point.X), Y: snap(point.Y)}
+		}
+		objects.Curves[i].Points = points
+	}
+	for i := range objects.Images {
+		image := &objects.Images[i]
+		image.X0, image.Y0, image.X1, image.Y1 = snap(image.X0), snap(image.Y0), snap(image.X1), snap(image.Y1)
+	}
+	for i := range objects.Annos {
+		anno := &objects.Annos[i]
+		anno.X0, anno.Y0, anno.X1, anno.Y1 = snap(anno.X0), snap(anno.Y0), snap(anno.X1), snap(anno.Y1)
+	}
+	for i := range objects.Shadings {
+		shading := &objects.Shadings[i]
+		shading.X0, shading.Y0, shading.X1, shading.Y1 = snap(shading.X0), snap(shading.Y0), snap(shading.X1), snap(shading.Y1)
+	}
+	return objects
+}
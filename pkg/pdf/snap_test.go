@@ -0,0 +1,55 @@
+package pdf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSnapGrid(t *testing.T) {
+	// Two touching horizontal segments at y=100.3 and y=99.8, and two
+	// vertical ones at x=72.4 and x=71.8
+	open := func(opts ...OpenOption) Objects {
+		t.Helper()
+		doc, err := Open("../../testdata/near_aligned.pdf", opts...)
+		if err != nil {
+			t.Fatalf("failed to open PDF: %v", err)
+		}
+		t.Cleanup(func() { doc.Close() })
+		page, _ := doc.GetPage(0)
+		return page.GetObjects()
+	}
+
+	if lines := open().Deduped().Lines; len(lines) != 4 {
+		t.Errorf("expected the unsnapped segments to stay apart, got %d lines", len(lines))
+	}
+
+	objects := open(WithSnapGrid(1))
+	lines := objects.Deduped().Lines
+	if len(lines) != 2 {
+		t.Fatalf("expected the snapped segments consolidated into 2 lines, got %+v", lines)
+	}
+	for _, line := range lines {
+		horizontal := line.Y0 == line.Y1
+		if horizontal && (line.Y0 != 100 || line.X0 != 72 || line.X1 != 300) {
+			t.Errorf("expected a horizontal line from 72 to 300 at y=100, got %+v", line)
+		}
+		if !horizontal && (line.X0 != 72 || line.Y0 != 500 || line.Y1 != 700) {
+			t.Errorf("expected a vertical line from 500 to 700 at x=72, got %+v", line)
+		}
+	}
+
+	for _, char := range objects.Chars {
+		for _, v := range []float64{char.X0, char.Y0, char.X1, char.Y1} {
+			if v != math.Round(v) {
+				t.Errorf("expected %q snapped to whole units, got %+v", char.Text, char)
+			}
+		}
+		if char.Width != char.X1-char.X0 {
+			t.Errorf("expected the width of %q to follow its snapped box, got %+v", char.Text, char)
+		}
+	}
+
+	if _, err := Open("../../testdata/near_aligned.pdf", WithSnapGrid(-1)); err == nil {
+		t.Error("expected an error for a negative grid size")
+	}
+}
@@ -0,0 +1,64 @@
+package pdf
+
+// TextSpan is a line of extracted text together with where it came from
+type TextSpan struct {
+	Text      string      // The line text
+	PageIndex int         // Page the line is on (0-based)
+	LineIndex int         // Line number within the page (0-based), top to bottom
+	BBox      BoundingBox // Bounds of the line's characters
+	Lang      string      // Language from the nearest enclosing /Lang, empty if unknown
+}
+
+// ExtractTextSpans extracts text from all pages of a document as lines that
+// can be mapped back to their page and position. Spans without a language of
+// their own get the document's.
+func ExtractTextSpans(doc Document, opts ...TextExtractionOption) []TextSpan {
+	var spans []TextSpan
+	for _, page := range doc.GetPages() {
+		spans = append(spans, page.ExtractTextSpans(opts...)...)
+	}
+	
+	if lang := doc.Language(); lang != "" {
+		for i := range spans {
+			if spans[i].Lang == "" {
+				spans[i].Lang = lang
+			}
+		}
+	}
+	return spans
+}
+
+// extractTextSpans groups characters into lines and returns a span for each
+// non-empty line. topDown tells whether Y grows downwards (true) or upwards
+// as in raw PDF space (false).
+func extractTextSpans(chars []CharObject, pageIndex int, topDown bool, opts ...TextExtractionOption) []TextSpan {
+	config := &textExtractionConfig{
+		XTolerance: 3,
+		YTolerance: 3,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var spans []TextSpan
+	for _, line := range groupCharsIntoTextLines(chars, config.YTolerance, topDown) {
+		text := extractLineText(line, config.XTolerance)
+		if text == "" {
+			continue
+		}
+
+		bbox := line[0].GetBBox().Normalize()
+		for _, char := range line[1:] {
+			bbox = bbox.Union(char.GetBBox())
+		}
+
+		spans = append(spans, TextSpan{
+			Text:      text,
+			PageIndex: pageIndex,
+			LineIndex: len(spans),
+			BBox:      bbox,
+			Lang:      line[0].lang,
+		})
+	}
+	return spans
+}
@@ -0,0 +1,103 @@
+package pdf
+
+import "testing"
+
+func TestExtractTextSpans(t *testing.T) {
+	doc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	spans := ExtractTextSpans(doc)
+
+	// 12pt text with Y0 at the baseline; the right edge depends on glyph widths
+	expected := []TextSpan{
+		{Text: "First page", PageIndex: 0, LineIndex: 0, BBox: BoundingBox{X0: 72, Y0: 720, Y1: 732}},
+		{Text: "Second line", PageIndex: 0, LineIndex: 1, BBox: BoundingBox{X0: 72, Y0: 700, Y1: 712}},
+		{Text: "Page two", PageIndex: 1, LineIndex: 0, BBox: BoundingBox{X0: 72, Y0: 720, Y1: 732}},
+	}
+	if len(spans) != len(expected) {
+		t.Fatalf("expected %d spans, got %d: %+v", len(expected), len(spans), spans)
+	}
+
+	for i, want := range expected {
+		got := spans[i]
+		if got.Text != want.Text || got.PageIndex != want.PageIndex || got.LineIndex != want.LineIndex {
+			t.Errorf("span %d: expected %q on page %d line %d, got %q on page %d line %d",
+				i, want.Text, want.PageIndex, want.LineIndex, got.Text, got.PageIndex, got.LineIndex)
+		}
+		if abs(got.BBox.X0-want.BBox.X0) > 0.01 || abs(got.BBox.Y0-want.BBox.Y0) > 0.01 ||
+			abs(got.BBox.Y1-want.BBox.Y1) > 0.01 || got.BBox.X1 <= got.BBox.X0+40 {
+			t.Errorf("span %d (%q): expected bbox %+v, got %+v", i, want.Text, want.BBox, got.BBox)
+		}
+	}
+}
+
+func TestExtractTextSpansLanguage(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			doc, err := open("../../testdata/tagged_languages.pdf")
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer doc.Close()
+
+			if lang := doc.Language(); lang != "en-US" {
+				t.Errorf("expected document language en-US, got %q", lang)
+			}
+
+			// The French line takes its /Lang from its structure element
+			// through its MCID, the German one from its marked-content
+			// properties
+			expected := []TextSpan{
+				{Text: "Hello world", Lang: "en-US"},
+				{Text: "Bonjour le monde", Lang: "fr-FR"},
+				{Text: "Guten Tag", Lang: "de-DE"},
+			}
+			spans := ExtractTextSpans(doc)
+			if len(spans) != len(expected) {
+				t.Fatalf("expected %d spans, got %d: %+v", len(expected), len(spans), spans)
+			}
+			for i, want := range expected {
+				if spans[i].Text != want.Text || spans[i].Lang != want.Lang {
+					t.Errorf("span %d: expected %q in %q, got %q in %q", i, want.Text, want.Lang, spans[i].Text, spans[i].Lang)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractTextSpansLibraryWidths(t *testing.T) {
+	// Fonts without Widths, one a standard font and two naming none, which
+	// the libraries read as zero-width
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			for _, file := range []string{"standard_fonts.pdf", "metricless_fonts.pdf"} {
+				doc, err := open("../../testdata/" + file)
+				if err != nil {
+					t.Fatalf("failed to open %s: %v", file, err)
+				}
+				spans := ExtractTextSpans(doc)
+				if len(spans) == 0 {
+					t.Errorf("%s: expected spans", file)
+				}
+				for _, span := range spans {
+					if span.BBox.X1 <= span.BBox.X0 {
+						t.Errorf("%s: span %q has no width: %+v", file, span.Text, span.BBox)
+					}
+				}
+				doc.Close()
+			}
+		})
+	}
+}
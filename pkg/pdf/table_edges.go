@@ -0,0 +1,174 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+)
+
+// Table strategies for deriving cell borders in one direction
+const (
+	tableStrategyLines    = "lines"
+	tableStrategyText     = "text"
+	tableStrategyExplicit = "explicit"
+)
+
+// usesEdgeStrategies checks if the strategies need edge-based extraction
+// rather than the combined lines or text detection
+func (te *tableExtractor) usesEdgeStrategies() bool {
+	return te.verticalStrategy != te.horizontalStrategy ||
+		te.verticalStrategy == tableStrategyExplicit ||
+		len(te.explicitVertical) > 0 ||
+		len(te.explicitHorizontal) > 0
+}
+
+// extractEdgeBasedTables builds a table grid from vertical and horizontal
+// edges that are derived independently, each by its own strategy
+func (te *tableExtractor) extractEdgeBasedTables(objects Objects) []Table {
+	var vEdges, hEdges []float64
+
+	// Rules and explicit edges are found first so they can bound the
+	// area in which text edges are looked for
+	if te.verticalStrategy != tableStrategyText {
+		vEdges = te.ruleEdges(objects, false)
+	}
+	if te.horizontalStrategy != tableStrategyText {
+		hEdges = te.ruleEdges(objects, true)
+	}
+
+	if te.verticalStrategy == tableStrategyText || te.horizontalStrategy == tableStrategyText {
+		words := te.wordsWithinEdges(te.page.ExtractWords(), vEdges, hEdges)
+		if te.verticalStrategy == tableStrategyText {
+			vEdges = te.mergePositions(append(te.textVerticalEdges(words), te.explicitVertical...))
+		}
+		if te.horizontalStrategy == tableStrategyText {
+			hEdges = te.mergePositions(append(te.textHorizontalEdges(words), te.explicitHorizontal...))
+		}
+	}
+
+	if len(vEdges) < 2 || len(hEdges) < 2 {
+		return []Table{}
+	}
+
+	table := te.extractTableFromRegion(*newTableRegion(hEdges, vEdges), objects)
+	if len(table.Rows) < te.minTableSize {
+		return []Table{}
+	}
+
+	return []Table{table}
+}
+
+// ruleEdges returns border positions for the "lines" or "explicit" strategy.
+// Horizontal edges are Y positions, vertical edges are X positions.
+func (te *tableExtractor) ruleEdges(objects Objects, horizontal bool) []float64 {
+	strategy := te.verticalStrategy
+	positions := append([]float64{}, te.explicitVertical...)
+	if horizontal {
+		strategy = te.horizontalStrategy
+		positions = append([]float64{}, te.explicitHorizontal...)
+	}
+
+	if strategy == tableStrategyLines {
+		hLines, vLines := te.collectTableLines(objects)
+		lines := vLines
+		if horizontal {
+			lines = hLines
+		}
+		for _, line := range lines {
+			if horizontal {
+				positions = append(positions, line.Y0)
+			} else {
+				positions = append(positions, line.X0)
+			}
+		}
+
+		for _, rect := range objects.Rects {
+			if horizontal {
+				positions = append(positions, rect.Y0, rect.Y1)
+			} else {
+				positions = append(positions, rect.X0, rect.X1)
+			}
+		}
+	}
+
+	return te.mergePositions(positions)
+}
+
+// textVerticalEdges returns column borders from left-aligned words,
+// closed by the right edge of the text
+func (te *tableExtractor) textVerticalEdges(words []Word) []float64 {
+	if len(words) == 0 {
+		return nil
+	}
+
+	columns := te.findAlignedColumnsFromWords(te.groupWordsIntoLines(words))
+	if len(columns) == 0 {
+		return nil
+	}
+
+	// Column positions are cluster centers, so move each border left by half
+	// the snap tolerance to keep the words' first chars inside
+	edges := make([]float64, 0, len(columns)+1)
+	for _, x := range columns {
+		edges = append(edges, x-te.snapTolerance/2)
+	}
+
+	right := words[0].X1
+	for _, word := range words {
+		right = max(right, word.X1)
+	}
+
+	return append(edges, right)
+}
+
+// textHorizontalEdges returns row borders between lines of text
+func (te *tableExtractor) textHorizontalEdges(words []Word) []float64 {
+	lines := te.groupWordsIntoLines(words)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	edges := []float64{lines[0].BBox.Y0}
+	for i := 1; i < len(lines); i++ {
+		// Halfway between the previous line and this one
+		edges = append(edges, (lines[i-1].BBox.Y1+lines[i].BBox.Y0)/2)
+	}
+
+	return append(edges, lines[len(lines)-1].BBox.Y1)
+}
+
+// wordsWithinEdges keeps the words whose centers fall within the span of
+// the given edges. Empty edges do not restrict that direction.
+func (te *tableExtractor) wordsWithinEdges(words []Word, vEdges, hEdges []float64) []Word {
+	var result []Word
+	for _, word := range words {
+		centerX := (word.X0 + word.X1) / 2
+		centerY := (word.Y0 + word.Y1) / 2
+
+		if len(vEdges) >= 2 && (centerX < vEdges[0] || centerX > vEdges[len(vEdges)-1]) {
+			continue
+		}
+		if len(hEdges) >= 2 && (centerY < hEdges[0] || centerY > hEdges[len(hEdges)-1]) {
+			continue
+		}
+		result = append(result, word)
+	}
+	return result
+}
+
+// mergePositions sorts positions and merges those within the snap tolerance
+func (te *tableExtractor) mergePositions(positions []float64) []float64 {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	sorted := append([]float64{}, positions...)
+	sort.Float64s(sorted)
+
+	merged := []float64{sorted[0]}
+	for _, pos := range sorted[1:] {
+		if math.Abs(pos-merged[len(merged)-1]) > te.snapTolerance {
+			merged = append(merged, pos)
+		}
+	}
+	return merged
+}
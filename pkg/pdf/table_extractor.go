@@ -6,6 +6,10 @@ import (
 	"strings"
 )
 
+// defaultMinColumnGapRatio is the least distance between table columns, as
+// a fraction of the median character width
+const defaultMinColumnGapRatio = 0.75
+
 // TableExtractor handles table extraction from PDF pages
 type tableExtractor struct {
 	page              Page
@@ -16,9 +20,16 @@ type tableExtractor struct {
 	snapTolerance     float64
 	joinTolerance     float64
 	edgeTolerance     float64
+	explicitVertical   []float64
+	explicitHorizontal []float64
+	consistentColumns  bool
+	minColumnGap       float64
+	excludeDashedLines bool
 }
 
-// newTableExtractor creates a new table extractor with default settings
+// newTableExtractor creates a new table extractor with default settings.
+// It works in PDF space, where rows are read from the highest Y down;
+// pages with top-down objects go through extractTopDownTables.
 func newTableExtractor(page Page, opts ...TableExtractionOption) *tableExtractor {
 	// Default configuration
 	config := &tableExtractionConfig{
@@ -42,18 +53,121 @@ func newTableExtractor(page Page, opts ...TableExtractionOption) *tableExtractor
 		snapTolerance:      3.0,
 		joinTolerance:      3.0,
 		edgeTolerance:      10.0,
+		explicitVertical:   config.ExplicitVertical,
+		explicitHorizontal: config.ExplicitHorizontal,
+		consistentColumns:  config.ConsistentColumns,
+		minColumnGap:       config.MinColumnGap,
+		excludeDashedLines: config.ExcludeDashedLines,
 	}
 }
 
 // ExtractTables extracts tables from the page
 func (te *tableExtractor) ExtractTables() []Table {
+	tables := te.findTables()
+	objects := te.objects()
+	for i := range tables {
+		tables[i].Rules = te.tableRules(objects, tables[i])
+	}
+	if te.consistentColumns {
+		for i := range tables {
+			tables[i].Rows = consistentColumns(tables[i].Rows)
+		}
+	}
+	return tables
+}
+
+// uprightPage presents the characters and words of a top-down page in PDF
+// space
+type uprightPage struct {
+	Page
+	objects Objects
+	words   []Word
+}
+
+// GetObjects returns the page's characters in PDF space
+func (p uprightPage) GetObjects() Objects {
+	return p.objects
+}
+
+// ExtractWords returns the page's words in PDF space
+func (p uprightPage) ExtractWords(opts ...WordExtractionOption) []Word {
+	return p.words
+}
+
+// extractTopDownTables extracts the tables of a page of the given height
+// whose objects are top-down, as those of the ledongthuc backend. Its
+// characters and words are flipped into PDF space to be read, and the
+// tables found flipped back.
+func extractTopDownTables(page Page, height float64, opts ...TableExtractionOption) []Table {
+	flip := func(box BoundingBox) BoundingBox {
+		return flipRegions([]BoundingBox{box}, height)[0]
+	}
+	flipChars := func(chars []CharObject) []CharObject {
+		flipped := make([]CharObject, len(chars))
+		for i, char := range chars {
+			box := flip(char.GetBBox())
+			char.Y0, char.Y1 = box.Y0, box.Y1
+			flipped[i] = char
+		}
+		return flipped
+	}
+	
+	upright := uprightPage{Page: page, objects: Objects{Chars: flipChars(page.GetObjects().Chars)}}
+	for _, word := range page.ExtractWords() {
+		box := flip(BoundingBox{X0: word.X0, Y0: word.Y0, X1: word.X1, Y1: word.Y1})
+		word.Y0, word.Y1 = box.Y0, box.Y1
+		word.Characters = flipChars(word.Characters)
+		upright.words = append(upright.words, word)
+	}
+	
+	tables := newTableExtractor(upright, opts...).ExtractTables()
+	for i := range tables {
+		tables[i].BBox = flip(tables[i].BBox)
+		for _, row := range tables[i].Cells {
+			for j := range row {
+				row[j] = flip(row[j])
+			}
+		}
+		rules := tables[i].Rules
+		for j := range rules {
+			rules[j].Y = height - rules[j].Y
+		}
+		sort.SliceStable(rules, func(a, b int) bool { return rules[a].Y < rules[b].Y })
+	}
+	return tables
+}
+
+// objects returns the page objects tables are detected from
+func (te *tableExtractor) objects() Objects {
+	objects := te.page.GetObjects()
+	if !te.excludeDashedLines {
+		return objects
+	}
+	solid := make([]LineObject, 0, len(objects.Lines))
+	for _, line := range objects.Lines {
+		if !line.Dashed() {
+			solid = append(solid, line)
+		}
+	}
+	objects.Lines = solid
+	return objects
+}
+
+// findTables detects the tables of the page and extracts their text
+func (te *tableExtractor) findTables() []Table {
 	tables := []Table{}
 	
 	// Get all objects from the page
-	objects := te.page.GetObjects()
+	objects := te.objects()
 	// fmt.Printf("[DEBUG-TABLE] ExtractTables: Found %d lines, %d rects, %d chars\n",
 	//	len(objects.Lines), len(objects.Rects), len(objects.Chars))
 	
+	// Mixed and explicit strategies build the grid from independently
+	// derived vertical and horizontal edges
+	if te.usesEdgeStrategies() {
+		return te.extractEdgeBasedTables(objects)
+	}
+	
 	// Try line-based table extraction first
 	if te.verticalStrategy == "lines" || te.horizontalStrategy == "lines" {
 		// fmt.Println("[DEBUG-TABLE] Using line-based extraction strategy")
@@ -240,6 +354,11 @@ func (te *tableExtractor) createTableRegion(hLines, vLines []LineObject) *tableR
 		return nil
 	}
 	
+	return newTableRegion(hPositions, vPositions)
+}
+
+// newTableRegion creates a table region from horizontal and vertical border positions
+func newTableRegion(hPositions, vPositions []float64) *tableRegion {
 	// Sort positions
 	sort.Float64s(hPositions)
 	sort.Float64s(vPositions)
@@ -301,9 +420,14 @@ func (te *tableExtractor) getUniquePositions(lines []LineObject, horizontal bool
 // extractTableFromRegion extracts table data from a region
 func (te *tableExtractor) extractTableFromRegion(region tableRegion, objects Objects) Table {
 	rows := make([][]string, len(region.Cells))
+	cells := make([][]BoundingBox, len(region.Cells))
 	
-	for i, row := range region.Cells {
+	// Cells are ordered by ascending Y, which is bottom to top in PDF
+	// coordinates, so fill rows from the end to read top to bottom
+	for k, row := range region.Cells {
+		i := len(region.Cells) - 1 - k
 		rows[i] = make([]string, len(row))
+		cells[i] = row
 		for j, cell := range row {
 			// Get text within this cell
 			cellText := te.extractCellText(cell, objects.Chars)
@@ -312,8 +436,9 @@ func (te *tableExtractor) extractTableFromRegion(region tableRegion, objects Obj
 	}
 	
 	return Table{
-		Rows: rows,
-		BBox: region.BBox,
+		Rows:  rows,
+		BBox:  region.BBox,
+		Cells: cells,
 	}
 }
 
@@ -334,33 +459,37 @@ func (te *tableExtractor) extractCellText(cell BoundingBox, chars []CharObject)
 		}
 	}
 	
-	// Sort characters by position
-	sort.Slice(cellChars, func(i, j int) bool {
-		// Sort by Y first (top to bottom), then by X (left to right)
-		if math.Abs(cellChars[i].Y0-cellChars[j].Y0) > te.textTolerance {
-			return cellChars[i].Y0 < cellChars[j].Y0
+	// Rotated text, such as vertical column headers, is read along its own axis
+	direction := dominantTextDirection(cellChars)
+	
+	// Sort characters by line, then along the line
+	sort.SliceStable(cellChars, func(i, j int) bool {
+		lineI, alongI := textAxisPosition(cellChars[i], direction)
+		lineJ, alongJ := textAxisPosition(cellChars[j], direction)
+		if math.Abs(lineI-lineJ) > te.textTolerance {
+			return lineI < lineJ
 		}
-		return cellChars[i].X0 < cellChars[j].X0
+		return alongI < alongJ
 	})
 	
 	// Build text from characters
 	text := ""
-	lastY := -1000.0
-	lastX := -1000.0
-	
-	for _, char := range cellChars {
-		// Check if we need a newline
-		if lastY > 0 && math.Abs(char.Y0-lastY) > te.textTolerance {
-			text += "\n"
-			lastX = -1000.0
-		} else if lastX > 0 && char.X0-lastX > te.textTolerance {
-			// Add space between words
-			text += " "
+	for i, char := range cellChars {
+		if i > 0 {
+			last := cellChars[i-1]
+			lastLine, _ := textAxisPosition(last, direction)
+			line, _ := textAxisPosition(char, direction)
+			
+			// Check if we need a newline
+			if math.Abs(line-lastLine) > te.textTolerance {
+				text += "\n"
+			} else if textAxisGap(last, char, direction) > te.textTolerance {
+				// Add space between words
+				text += " "
+			}
 		}
 		
 		text += char.Text
-		lastY = char.Y0
-		lastX = char.X1
 	}
 	
 	return text
@@ -379,7 +508,23 @@ func (te *tableExtractor) extractTextBasedTables(objects Objects) []Table {
 	// Group words into lines
 	lines := te.groupWordsIntoLines(words)
 	
-	// Find aligned columns based on word positions
+	// Lines come out by ascending Y; higher Y is further up the page
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	
+	// Columns of running text are not tables, however well they align
+	borders := te.findWhitespaceRivers(lines)
+	if readsAsProse(lines, borders) {
+		return tables
+	}
+	
+	// Whitespace gutters and row spacing give the grid of borderless tables
+	if table, ok := te.extractWhitespaceTable(lines, borders); ok {
+		return append(tables, table)
+	}
+	
+	// Otherwise find aligned columns based on word positions
 	columns := te.findAlignedColumnsFromWords(lines)
 	
 	// If we have consistent columns, create a table
@@ -455,21 +600,9 @@ func (te *tableExtractor) finalizeLine(line textLine) textLine {
 	})
 	
 	// Calculate bounding box
-	minX := line.Chars[0].X0
-	maxX := line.Chars[len(line.Chars)-1].X1
-	minY := line.Chars[0].Y0
-	maxY := line.Chars[0].Y1
-	
-	for _, char := range line.Chars {
-		minY = min(minY, char.Y0)
-		maxY = max(maxY, char.Y1)
-	}
-	
-	line.BBox = BoundingBox{
-		X0: minX,
-		Y0: minY,
-		X1: maxX,
-		Y1: maxY,
+	line.BBox = line.Chars[0].GetBBox().Normalize()
+	for _, char := range line.Chars[1:] {
+		line.BBox = line.BBox.Union(char.GetBBox())
 	}
 	
 	return line
@@ -481,29 +614,20 @@ func (te *tableExtractor) findAlignedColumns(lines []textLine) []float64 {
 		return []float64{}
 	}
 	
-	// Collect all unique X positions
-	xPositions := make(map[float64]int)
-	
+	// Collect all X positions
+	var xPositions []float64
 	for _, line := range lines {
 		for _, char := range line.Chars {
-			// Round to snap tolerance
-			x := math.Round(char.X0/te.snapTolerance) * te.snapTolerance
-			xPositions[x]++
+			xPositions = append(xPositions, char.X0)
 		}
 	}
 	
 	// Find positions that appear in multiple lines
-	columns := []float64{}
-	minCount := len(lines) / 2 // At least half the lines
-	
-	for x, count := range xPositions {
-		if count >= minCount {
-			columns = append(columns, x)
-		}
+	var chars []CharObject
+	for _, line := range lines {
+		chars = append(chars, line.Chars...)
 	}
-	
-	sort.Float64s(columns)
-	return columns
+	return te.mergeCloseColumns(te.frequentPositions(xPositions, len(lines)/2), chars) // At least half the lines
 }
 
 // extractTableFromRowRectangles extracts table when rectangles represent rows
@@ -588,28 +712,15 @@ func (te *tableExtractor) extractTableFromRowRectangles(objects Objects) *Table
 // findTextColumns finds column positions based on text alignment
 func (te *tableExtractor) findTextColumns(chars []CharObject, minX, maxX float64) []float64 {
 	// Group characters by X position
-	xPositions := make(map[float64]int)
-	
+	var xPositions []float64
 	for _, char := range chars {
 		if char.X0 >= minX && char.X1 <= maxX {
-			// Round to snap tolerance
-			x := math.Round(char.X0/te.snapTolerance) * te.snapTolerance
-			xPositions[x]++
+			xPositions = append(xPositions, char.X0)
 		}
 	}
 	
 	// Find positions that appear frequently
-	columns := []float64{}
-	minCount := 3 // At least 3 occurrences to be a column
-	
-	for x, count := range xPositions {
-		if count >= minCount {
-			columns = append(columns, x)
-		}
-	}
-	
-	sort.Float64s(columns)
-	return columns
+	return te.mergeCloseColumns(te.frequentPositions(xPositions, 3), chars) // At least 3 occurrences to be a column
 }
 
 // extractRowFromRectangle extracts text for each column in a row rectangle
@@ -697,12 +808,9 @@ func (te *tableExtractor) createTableFromTextLines(lines []textLine, columns []f
 	// Calculate table bounding box
 	var bbox BoundingBox
 	if len(lines) > 0 {
-		bbox = lines[0].BBox
+		bbox = lines[0].BBox.Normalize()
 		for _, line := range lines[1:] {
-			bbox.X0 = min(bbox.X0, line.BBox.X0)
-			bbox.Y0 = min(bbox.Y0, line.BBox.Y0)
-			bbox.X1 = max(bbox.X1, line.BBox.X1)
-			bbox.Y1 = max(bbox.Y1, line.BBox.Y1)
+			bbox = bbox.Union(line.BBox)
 		}
 	}
 	
@@ -776,21 +884,9 @@ func (te *tableExtractor) finalizeWordLine(line wordLine) wordLine {
 	
 	// Calculate bounding box
 	if len(line.Words) > 0 {
-		minX := line.Words[0].X0
-		maxX := line.Words[len(line.Words)-1].X1
-		minY := line.Words[0].Y0
-		maxY := line.Words[0].Y1
-		
-		for _, word := range line.Words {
-			minY = min(minY, word.Y0)
-			maxY = max(maxY, word.Y1)
-		}
-		
-		line.BBox = BoundingBox{
-			X0: minX,
-			Y0: minY,
-			X1: maxX,
-			Y1: maxY,
+		line.BBox = BoundingBox{X0: line.Words[0].X0, Y0: line.Words[0].Y0, X1: line.Words[0].X1, Y1: line.Words[0].Y1}.Normalize()
+		for _, word := range line.Words[1:] {
+			line.BBox = line.BBox.Union(BoundingBox{X0: word.X0, Y0: word.Y0, X1: word.X1, Y1: word.Y1})
 		}
 	}
 	
@@ -803,31 +899,97 @@ func (te *tableExtractor) findAlignedColumnsFromWords(lines []wordLine) []float6
 		return []float64{}
 	}
 	
-	// Collect all unique X positions of word starts
-	xPositions := make(map[float64]int)
-	
+	// Collect all X positions of word starts
+	var xPositions []float64
+	var chars []CharObject
 	for _, line := range lines {
 		for _, word := range line.Words {
-			// Round to snap tolerance
-			x := math.Round(word.X0/te.snapTolerance) * te.snapTolerance
-			xPositions[x]++
+			xPositions = append(xPositions, word.X0)
+			chars = append(chars, word.Characters...)
 		}
 	}
 	
 	// Find positions that appear in multiple lines (at least 30% of lines)
-	columns := []float64{}
-	minCount := max(2.0, float64(len(lines)*3/10)) // At least 2 or 30% of lines
+	minCount := len(lines) * 3 / 10
+	if minCount < 2 {
+		minCount = 2
+	}
+	return te.mergeCloseColumns(te.frequentPositions(xPositions, minCount), chars)
+}
+
+// positionCluster is a group of nearby positions
+type positionCluster struct {
+	Center float64 // Mean of the positions
+	Count  int
+}
+
+// clusterPositions groups positions lying within the snap tolerance of a
+// cluster's center. Unlike rounding to a fixed grid, positions that straddle
+// a grid boundary, as in justified text, still end up together.
+func (te *tableExtractor) clusterPositions(positions []float64) []positionCluster {
+	if len(positions) == 0 {
+		return nil
+	}
 	
-	for x, count := range xPositions {
-		if float64(count) >= minCount {
-			columns = append(columns, x)
+	sorted := append([]float64{}, positions...)
+	sort.Float64s(sorted)
+	
+	var clusters []positionCluster
+	sum := sorted[0]
+	current := positionCluster{Center: sorted[0], Count: 1}
+	for _, x := range sorted[1:] {
+		if x-current.Center > te.snapTolerance {
+			clusters = append(clusters, current)
+			sum = x
+			current = positionCluster{Center: x, Count: 1}
+			continue
+		}
+		sum += x
+		current.Count++
+		current.Center = sum / float64(current.Count)
+	}
+	return append(clusters, current)
+}
+
+// frequentPositions returns the centers of position clusters with at least
+// minCount members, in ascending order
+func (te *tableExtractor) frequentPositions(positions []float64, minCount int) []float64 {
+	columns := []float64{}
+	for _, cluster := range te.clusterPositions(positions) {
+		if cluster.Count >= minCount {
+			columns = append(columns, cluster.Center)
 		}
 	}
-	
-	sort.Float64s(columns)
 	return columns
 }
 
+// mergeCloseColumns merges ascending column positions closer than the
+// minimum column gap into the leftmost of them. Without a gap set, the gap
+// is a fraction of the median width of chars.
+func (te *tableExtractor) mergeCloseColumns(columns []float64, chars []CharObject) []float64 {
+	gap := te.minColumnGap
+	if gap <= 0 {
+		widths := make([]float64, 0, len(chars))
+		for _, char := range chars {
+			if w := char.X1 - char.X0; w > 0 {
+				widths = append(widths, w)
+			}
+		}
+		gap = defaultMinColumnGapRatio * medianPitch(widths)
+	}
+	if gap <= 0 || len(columns) < 2 {
+		return columns
+	}
+
+	merged := []float64{columns[0]}
+	for _, x := range columns[1:] {
+		if x-merged[len(merged)-1] >= gap {
+			merged = append(merged, x)
+		}
+	}
+	return merged
+}
+
 // createTableFromWordLines creates a table from aligned word lines
 func (te *tableExtractor) createTableFromWordLines(lines []wordLine, columns []float64) Table {
 	rows := make([][]string, len(lines))
@@ -835,12 +997,9 @@ func (te *tableExtractor) createTableFromWordLines(lines []wordLine, columns []f
 	// Calculate table bounding box
 	var bbox BoundingBox
 	if len(lines) > 0 {
-		bbox = lines[0].BBox
+		bbox = lines[0].BBox.Normalize()
 		for _, line := range lines[1:] {
-			bbox.X0 = min(bbox.X0, line.BBox.X0)
-			bbox.Y0 = min(bbox.Y0, line.BBox.Y0)
-			bbox.X1 = max(bbox.X1, line.BBox.X1)
-			bbox.Y1 = max(bbox.Y1, line.BBox.Y1)
+			bbox = bbox.Union(line.BBox)
 		}
 	}
 	
@@ -883,4 +1042,39 @@ func (te *tableExtractor) findWordColumn(wordX float64, columns []float64) int {
 	}
 	
 	return bestCol
-}
\ No newline at end of file
+}
+
+// consistentColumns gives every row the most common number of cells among
+// the rows that have any, the larger on ties. Short rows are padded with
+// empty cells; the cells past the count are joined into the last cell,
+// separated by spaces.
+func consistentColumns(rows [][]string) [][]string {
+	counts := make(map[int]int)
+	columns := 0
+	for _, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		counts[len(row)]++
+		if n := counts[len(row)]; n > counts[columns] || (n == counts[columns] && len(row) > columns) {
+			columns = len(row)
+		}
+	}
+
+	result := make([][]string, len(rows))
+	for i, row := range rows {
+		cells := make([]string, columns)
+		copy(cells, row)
+		if len(row) > columns && columns > 0 {
+			var overflow []string
+			for _, cell := range row[columns-1:] {
+				if cell != "" {
+					overflow = append(overflow, cell)
+				}
+			}
+			cells[columns-1] = strings.Join(overflow, " ")
+		}
+		result[i] = cells
+	}
+	return result
+}
@@ -0,0 +1,526 @@
+package pdf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// gridTableRows is the content of testdata/grid_table.pdf, a ruled
+// table whose text is also aligned in columns
+var gridTableRows = [][]string{
+	{"Name", "Qty", "Price"},
+	{"Apple", "3", "1.20"},
+	{"Banana", "12", "0.50"},
+	{"Cherry", "7", "3.75"},
+}
+
+func openGridTablePage(t *testing.T) Page {
+	t.Helper()
+
+	doc, err := Open("../../testdata/grid_table.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	t.Cleanup(func() { doc.Close() })
+
+	page, err := doc.GetPage(0)
+	if err != nil {
+		t.Fatalf("failed to get page: %v", err)
+	}
+	return page
+}
+
+func TestExtractTablesStrategyCombinations(t *testing.T) {
+	page := openGridTablePage(t)
+
+	tests := []struct {
+		vertical   string
+		horizontal string
+	}{
+		{"lines", "lines"},
+		{"lines", "text"},
+		{"text", "lines"},
+		{"text", "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vertical+"/"+tt.horizontal, func(t *testing.T) {
+			tables := page.ExtractTables(WithTableStrategy(tt.vertical, tt.horizontal))
+			if len(tables) != 1 {
+				t.Fatalf("expected 1 table, got %d", len(tables))
+			}
+			if !reflect.DeepEqual(tables[0].Rows, gridTableRows) {
+				t.Errorf("unexpected rows:\n got: %q\nwant: %q", tables[0].Rows, gridTableRows)
+			}
+		})
+	}
+}
+
+func TestExtractTablesLibraries(t *testing.T) {
+	openers := map[string]func(string, ...OpenOption) (Document, error){
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	strategies := map[string][]TableExtractionOption{
+		"text": {WithTableStrategy("text", "text")},
+		"explicit": {
+			WithTableStrategy("explicit", "text"),
+			WithExplicitVerticalLines(72, 200, 300, 456),
+		},
+	}
+
+	for name, open := range openers {
+		for strategy, opts := range strategies {
+			t.Run(name+"/"+strategy, func(t *testing.T) {
+				doc, err := open("../../testdata/grid_table.pdf")
+				if err != nil {
+					t.Fatalf("failed to open PDF: %v", err)
+				}
+				defer doc.Close()
+
+				page, err := doc.GetPage(0)
+				if err != nil {
+					t.Fatalf("failed to get page: %v", err)
+				}
+				tables := page.ExtractTables(opts...)
+				if len(tables) != 1 {
+					t.Fatalf("expected 1 table, got %d", len(tables))
+				}
+				// Rows come top row first whichever way the backend's
+				// coordinates run
+				if !reflect.DeepEqual(tables[0].Rows, gridTableRows) {
+					t.Errorf("unexpected rows:\n got: %q\nwant: %q", tables[0].Rows, gridTableRows)
+				}
+			})
+		}
+	}
+}
+
+func TestExtractTablesExplicitStrategy(t *testing.T) {
+	page := openGridTablePage(t)
+
+	// Only split off the first column and the header row
+	tables := page.ExtractTables(
+		WithTableStrategy("explicit", "explicit"),
+		WithExplicitVerticalLines(72, 200, 456),
+		WithExplicitHorizontalLines(620, 680, 700),
+		WithMinTableSize(2),
+	)
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	expected := [][]string{
+		{"Name", "Qty Price"},
+		{"Apple\nBanana\nCherry", "3 1.20\n12 0.50\n7 3.75"},
+	}
+	if !reflect.DeepEqual(tables[0].Rows, expected) {
+		t.Errorf("unexpected rows:\n got: %q\nwant: %q", tables[0].Rows, expected)
+	}
+
+	// Explicit vertical borders combined with text rows
+	tables = page.ExtractTables(
+		WithTableStrategy("explicit", "text"),
+		WithExplicitVerticalLines(72, 200, 456),
+	)
+	if len(tables) != 1 || len(tables[0].Rows) != 4 || len(tables[0].Rows[0]) != 2 {
+		t.Fatalf("expected a 4x2 table, got %v", tables)
+	}
+	if tables[0].Rows[2][0] != "Banana" {
+		t.Errorf("expected Banana in row 3, got %q", tables[0].Rows[2][0])
+	}
+}
+
+func TestExtractCellTextRotated(t *testing.T) {
+	// Two lines of bottom-to-top text, as in a vertical column header
+	content := []byte(`
+		BT 0 1 -1 0 110 505 Tm (Unit) Tj ET
+		BT 0 1 -1 0 122 505 Tm (Price) Tj ET
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Chars) != 9 {
+		t.Fatalf("expected 9 chars, got %d", len(objects.Chars))
+	}
+
+	te := &tableExtractor{textTolerance: 3}
+	cell := BoundingBox{X0: 90, Y0: 500, X1: 130, Y1: 560}
+	if text := te.extractCellText(cell, objects.Chars); text != "Unit\nPrice" {
+		t.Errorf("expected %q, got %q", "Unit\nPrice", text)
+	}
+}
+
+func TestFindAlignedColumnsJustified(t *testing.T) {
+	te := &tableExtractor{snapTolerance: 3}
+
+	// Justified text shifts the second column around x=101, straddling the
+	// 99/102 grid lines that rounding to the snap tolerance would use
+	var lines []wordLine
+	for i, x := range []float64{100.4, 100.2, 101.6, 101.8} {
+		y := 100 + float64(i)*15
+		lines = append(lines, wordLine{
+			Words: []Word{
+				{Text: "Item", X0: 72, Y0: y, X1: 95, Y1: y + 10},
+				{Text: "100", X0: x, Y0: y, X1: x + 15, Y1: y + 10},
+			},
+			Y: y,
+		})
+	}
+
+	columns := te.findAlignedColumnsFromWords(lines)
+	if len(columns) != 2 {
+		t.Fatalf("expected 2 columns, got %v", columns)
+	}
+	if columns[0] != 72 || columns[1] < 100.9 || columns[1] > 101.1 {
+		t.Errorf("expected columns at 72 and 101, got %v", columns)
+	}
+}
+
+func TestFindAlignedColumnsMinColumnGap(t *testing.T) {
+	// Kerning starts the second column at x=100 in some rows and x=105 in
+	// others, further apart than the snap tolerance
+	word := func(text string, x, y float64) Word {
+		var chars []CharObject
+		for i, r := range text {
+			x0 := x + float64(i)*6
+			chars = append(chars, CharObject{Text: string(r), X0: x0, Y0: y, X1: x0 + 6, Y1: y + 10})
+		}
+		return Word{Text: text, X0: x, Y0: y, X1: x + float64(len(text))*6, Y1: y + 10, Characters: chars}
+	}
+	var lines []wordLine
+	for i, x := range []float64{100, 105, 100, 105} {
+		y := 100 + float64(i)*15
+		lines = append(lines, wordLine{Words: []Word{word("Item", 72, y), word("100", x, y)}, Y: y})
+	}
+
+	// The default gap, a fraction of the 6pt character width, keeps both
+	te := &tableExtractor{snapTolerance: 3}
+	if columns := te.findAlignedColumnsFromWords(lines); len(columns) != 3 {
+		t.Fatalf("expected 3 columns with the default gap, got %v", columns)
+	}
+
+	te.minColumnGap = 8
+	columns := te.findAlignedColumnsFromWords(lines)
+	if !reflect.DeepEqual(columns, []float64{72, 100}) {
+		t.Errorf("expected columns at 72 and 100, got %v", columns)
+	}
+}
+
+func TestExtractTablesBorderless(t *testing.T) {
+	doc, err := Open("../../testdata/borderless_table.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Amounts are right-aligned, the first description wraps onto a second
+	// line and rows are spaced unevenly
+	page, _ := doc.GetPage(0)
+	tables := page.ExtractTables(WithTableStrategy("text", "text"))
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+
+	expected := [][]string{
+		{"Item", "Description", "Amount"},
+		{"A-1", "Blue widget with chrome trim", "12.50"},
+		{"B-22", "Gear", "1,240.00"},
+		{"C-3", "Spring, steel", "3.75"},
+	}
+	if !reflect.DeepEqual(tables[0].Rows, expected) {
+		t.Errorf("expected rows %q, got %q", expected, tables[0].Rows)
+	}
+}
+
+func TestColumnsAligned(t *testing.T) {
+	// The second column's cells share neither an edge nor a center
+	lines := []wordLine{
+		{Words: []Word{{Text: "A", X0: 72, X1: 78}, {Text: "red", X0: 120, X1: 138}}},
+		{Words: []Word{{Text: "B", X0: 72, X1: 78}, {Text: "green", X0: 130, X1: 160}}},
+		{Words: []Word{{Text: "C", X0: 72, X1: 78}, {Text: "blue", X0: 145, X1: 169}}},
+	}
+	te := &tableExtractor{minTableSize: 3, snapTolerance: 3}
+	if te.columnsAligned(lines, []float64{100}) {
+		t.Errorf("expected the ragged second column to be rejected")
+	}
+
+	// Right-aligned at x=170
+	lines[0].Words[1].X0, lines[0].Words[1].X1 = 152, 170
+	lines[1].Words[1].X0, lines[1].Words[1].X1 = 140, 170
+	lines[2].Words[1].X0, lines[2].Words[1].X1 = 146, 170
+	if !te.columnsAligned(lines, []float64{100}) {
+		t.Errorf("expected right-aligned cells to line up")
+	}
+}
+
+func TestExtractTablesTwoColumnProse(t *testing.T) {
+	// Two columns of running text, aligned on their left edges with a wide
+	// gutter between them
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		doc, err := open("../../testdata/two_column_prose.pdf")
+		if err != nil {
+			t.Fatalf("%s: failed to open PDF: %v", name, err)
+		}
+		page, _ := doc.GetPage(0)
+		if tables := page.ExtractTables(WithTableStrategy("text", "text")); len(tables) != 0 {
+			t.Errorf("%s: expected no tables, got %q", name, tables[0].Rows)
+		}
+		doc.Close()
+	}
+}
+
+func TestExtractTablesAcrossPagesRepeatedHeader(t *testing.T) {
+	doc, err := Open("../../testdata/stitched_table.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	// Both pages start with the header row
+	tables := ExtractTablesAcrossPages(doc, WithRemoveRepeatedHeaders(true))
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 stitched table, got %d", len(tables))
+	}
+	expected := append(append([][]string{}, gridTableRows...),
+		[]string{"Date", "5", "2.10"},
+		[]string{"Fig", "9", "4.00"},
+		[]string{"Grape", "20", "0.80"},
+	)
+	if !reflect.DeepEqual(tables[0].Rows, expected) {
+		t.Errorf("expected rows %q, got %q", expected, tables[0].Rows)
+	}
+
+	tables = ExtractTablesAcrossPages(doc)
+	if len(tables) != 1 || len(tables[0].Rows) != len(expected)+1 {
+		t.Errorf("expected the header kept without WithRemoveRepeatedHeaders, got %v", tables)
+	}
+}
+
+func TestIsRepeatedHeader(t *testing.T) {
+	header := []string{"Item", "Unit Price", "Quantity"}
+	tests := []struct {
+		row      []string
+		repeated bool
+	}{
+		{[]string{"Item", "Unit Price", "Quantity"}, true},
+		{[]string{" Item", "Unit  Price", "Quantity\n"}, true},
+		{[]string{"ITEM", "Unit Price", "Quantity"}, true},
+		{[]string{"Item", "Unit Price", "Quantty"}, true},
+		{[]string{"Apple", "1.20", "3"}, false},
+		{[]string{"Item", "Unit Price"}, false},
+	}
+	for _, test := range tests {
+		if got := isRepeatedHeader(header, test.row); got != test.repeated {
+			t.Errorf("isRepeatedHeader(%q) = %v, want %v", test.row, got, test.repeated)
+		}
+	}
+}
+
+// Benchmark extracting text and then text-based tables from the same page,
+// where the words grouped for the first table extraction are reused
+func BenchmarkExtractTextAndTables(b *testing.B) {
+	doc, err := Open("../../testdata/grid_table.pdf")
+	if err != nil {
+		b.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	page.GetObjects()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		page.ExtractText()
+		page.ExtractTables(WithTableStrategy("text", "text"))
+		page.ExtractTables(WithTableStrategy("lines", "text"))
+	}
+}
+
+// Benchmark the same work with the word cache cleared before every call
+func BenchmarkExtractTextAndTablesUncached(b *testing.B) {
+	doc, err := Open("../../testdata/grid_table.pdf")
+	if err != nil {
+		b.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	page.GetObjects()
+	pdfcpuPage := page.(*PDFCPUPage)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pdfcpuPage.words = wordCache{}
+		page.ExtractText()
+		page.ExtractTables(WithTableStrategy("text", "text"))
+		pdfcpuPage.words = wordCache{}
+		page.ExtractTables(WithTableStrategy("lines", "text"))
+	}
+}
+
+func TestConsistentColumns(t *testing.T) {
+	ragged := [][]string{
+		{"Name", "Qty", "Price"},
+		{"Apple", "3"},
+		{"Banana", "12", "0.50"},
+		{"Cherry", "7", "3.75", "each", "", "(sale)"},
+		{"Date", "8", "2.10"},
+	}
+	expected := [][]string{
+		{"Name", "Qty", "Price"},
+		{"Apple", "3", ""},
+		{"Banana", "12", "0.50"},
+		{"Cherry", "7", "3.75 each (sale)"},
+		{"Date", "8", "2.10"},
+	}
+	if rows := consistentColumns(ragged); !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %q, got %q", expected, rows)
+	}
+	if len(ragged[1]) != 2 {
+		t.Errorf("expected the input rows to be left as they are, got %q", ragged[1])
+	}
+
+	// The larger count wins a tie
+	rows := consistentColumns([][]string{{"a"}, {"b", "c"}})
+	if !reflect.DeepEqual(rows, [][]string{{"a", ""}, {"b", "c"}}) {
+		t.Errorf("expected rows padded to 2 cells, got %q", rows)
+	}
+
+	// Empty rows outnumbering the others do not set the count
+	rows = consistentColumns([][]string{{}, {}, {"a", "b"}, {}})
+	if !reflect.DeepEqual(rows, [][]string{{"", ""}, {"", ""}, {"a", "b"}, {"", ""}}) {
+		t.Errorf("expected every row padded to 2 cells, got %q", rows)
+	}
+}
+
+func TestExtractTablesRules(t *testing.T) {
+	doc, err := Open("../../testdata/table_rules.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	tables := page.ExtractTables()
+	if len(tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(tables))
+	}
+	if !reflect.DeepEqual(tables[0].Rows, gridTableRows) {
+		t.Errorf("expected rows %q, got %q", gridTableRows, tables[0].Rows)
+	}
+
+	// Rules come bottom to top in PDF space; the rule under the header is
+	// the thickest
+	expected := []TableRule{
+		{Y: 620, X0: 72, X1: 456, Width: 1},
+		{Y: 640, X0: 72, X1: 456, Width: 0.5},
+		{Y: 660, X0: 72, X1: 456, Width: 0.5},
+		{Y: 680, X0: 72, X1: 456, Width: 2.5},
+		{Y: 700, X0: 72, X1: 456, Width: 1},
+	}
+	if !reflect.DeepEqual(tables[0].Rules, expected) {
+		t.Errorf("expected rules %+v, got %+v", expected, tables[0].Rules)
+	}
+}
+
+func TestMergeTables(t *testing.T) {
+	var results [][]Table
+	var docs []Document
+	for _, path := range []string{"../../testdata/grid_table.pdf", "../../testdata/borderless_table.pdf"} {
+		doc, err := Open(path)
+		if err != nil {
+			t.Fatalf("failed to open PDF: %v", err)
+		}
+		defer doc.Close()
+		docs = append(docs, doc)
+		results = append(results, ExtractTablesAcrossPages(doc, WithTableStrategy("text", "text")))
+	}
+	if len(results[0]) == 0 || len(results[1]) == 0 {
+		t.Fatalf("expected tables in both documents, got %d and %d", len(results[0]), len(results[1]))
+	}
+
+	merged := MergeTables(results...)
+	if len(merged) != len(results[0])+len(results[1]) {
+		t.Fatalf("expected %d tables, got %d", len(results[0])+len(results[1]), len(merged))
+	}
+	for i, table := range merged {
+		source, index := 0, i
+		if i >= len(results[0]) {
+			source, index = 1, i-len(results[0])
+		}
+		if table.Source != source {
+			t.Errorf("table %d: expected source %d, got %d", i, source, table.Source)
+		}
+		if !reflect.DeepEqual(table.Rows, results[source][index].Rows) {
+			t.Errorf("table %d: expected rows %q, got %q", i, results[source][index].Rows, table.Rows)
+		}
+	}
+	if !reflect.DeepEqual(merged[0].Rows, gridTableRows) {
+		t.Errorf("expected the first table from the first document, got %q", merged[0].Rows)
+	}
+
+	text, err := ConcatText("\f", docs...)
+	if err != nil {
+		t.Fatalf("ConcatText failed: %v", err)
+	}
+	parts := strings.Split(text, "\f")
+	if len(parts) != 2 || !strings.Contains(parts[0], "Banana") || strings.Contains(parts[1], "Banana") {
+		t.Errorf("expected the text of each document in order, got %q", text)
+	}
+	if _, err := ConcatText("\n", docs[0], nil); err == nil {
+		t.Error("expected an error for a nil document")
+	}
+}
+
+func TestExtractTablesExcludeDashedLines(t *testing.T) {
+	doc, err := Open("../../testdata/toc_leaders.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// Each entry of the ruled contents table has a dotted leader drawn with
+	// [1 3] 0 d and round caps
+	leaders := 0
+	for _, line := range page.GetObjects().Lines {
+		if line.Dashed() {
+			leaders++
+			if !reflect.DeepEqual(line.DashPattern, []float64{1, 3}) || line.LineCap != 1 {
+				t.Errorf("expected a round-capped [1 3] leader, got %+v", line)
+			}
+		} else if line.LineCap != 0 {
+			t.Errorf("expected butt caps on solid rules, got %+v", line)
+		}
+	}
+	if leaders != 3 {
+		t.Fatalf("expected 3 dotted leaders, got %d", leaders)
+	}
+
+	expected := [][]string{{"Section", "Page"}, {"Introduction", "1"}, {"Methods", "4"}, {"Results", "9"}}
+	tables := page.ExtractTables(WithExcludeDashedLines(true))
+	if len(tables) != 1 || !reflect.DeepEqual(tables[0].Rows, expected) {
+		t.Fatalf("expected the contents table %q, got %v", expected, tables)
+	}
+	var ys []float64
+	for _, rule := range tables[0].Rules {
+		ys = append(ys, rule.Y)
+	}
+	if want := []float64{620, 640, 660, 680, 700}; !reflect.DeepEqual(ys, want) {
+		t.Errorf("expected only the solid rules at %v, got %v", want, ys)
+	}
+
+	// Without the option the leaders count as rules
+	tables = page.ExtractTables()
+	if len(tables) != 1 || len(tables[0].Rules) != 8 {
+		t.Errorf("expected the leaders among the rules without the option, got %v", tables)
+	}
+}
@@ -0,0 +1,43 @@
+package pdf
+
+import (
+	"runtime"
+	"sync"
+)
+
+// extractAllTables extracts the tables of every page with a pool of workers,
+// keyed by page index. A page is handed to one worker only, so the objects it
+// parses lazily are never shared; the result of each page is the same as
+// extracting it alone. Fewer than one worker means one per CPU.
+func extractAllTables(pages []Page, workers int, opts []TableExtractionOption) map[int][]Table {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(pages) {
+		workers = len(pages)
+	}
+
+	results := make([][]Table, len(pages))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = pages[i].ExtractTables(opts...)
+			}
+		}()
+	}
+	for i := range pages {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	tables := make(map[int][]Table, len(pages))
+	for i, pageTables := range results {
+		tables[i] = pageTables
+	}
+	return tables
+}
@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testdata/many_tables.pdf has 24 pages, each with a ruled table of 12 rows
+// and 4 columns whose cells read "P<page> R<row> C<column>"
+const manyTablesPDF = "../../testdata/many_tables.pdf"
+
+func TestExtractAllTables(t *testing.T) {
+	backends := map[string]func(string, ...OpenOption) (Document, error){
+		"pdfcpu":     Open,
+		"ledongthuc": OpenWithLedongthuc,
+		"dslipak":    OpenWithDslipak,
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			serialDoc, err := open(manyTablesPDF)
+			if err != nil {
+				t.Fatalf("failed to open PDF: %v", err)
+			}
+			defer serialDoc.Close()
+			var serial [][]Table
+			for _, page := range serialDoc.GetPages() {
+				serial = append(serial, page.ExtractTables())
+			}
+
+			for _, workers := range []int{0, 1, 4, 100} {
+				doc, err := open(manyTablesPDF)
+				if err != nil {
+					t.Fatalf("failed to open PDF: %v", err)
+				}
+				tables := doc.ExtractAllTables(workers)
+				doc.Close()
+
+				if len(tables) != len(serial) {
+					t.Fatalf("workers %d: got tables of %d pages, want %d", workers, len(tables), len(serial))
+				}
+				for i, want := range serial {
+					if !reflect.DeepEqual(tables[i], want) {
+						t.Errorf("workers %d: page %d tables differ from serial extraction", workers, i)
+					}
+				}
+			}
+
+			// The library backends extract no rules to find the tables by
+			if name != "pdfcpu" {
+				return
+			}
+			tables := serial[23]
+			if len(tables) != 1 || len(tables[0].Rows) != 12 {
+				t.Fatalf("last page tables = %v, want one table of 12 rows", tables)
+			}
+			if got := tables[0].Rows[11][3]; got != "P24 R12 C4" {
+				t.Errorf("last cell = %q, want %q", got, "P24 R12 C4")
+			}
+		})
+	}
+}
+
+// Benchmark extracting the tables of a freshly opened document, so pages are
+// parsed as well, with one worker and with one per CPU
+func BenchmarkExtractAllTables(b *testing.B) {
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				doc, err := Open(manyTablesPDF)
+				if err != nil {
+					b.Fatalf("failed to open PDF: %v", err)
+				}
+				if tables := doc.ExtractAllTables(workers); len(tables) != 24 {
+					b.Fatalf("got tables of %d pages, want 24", len(tables))
+				}
+				doc.Close()
+			}
+		})
+	}
+}
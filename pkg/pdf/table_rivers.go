@@ -0,0 +1,173 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// minRiverWidth is the narrowest whitespace gutter separating table columns.
+	// It is wider than the space between words of a cell.
+	minRiverWidth = 6.0
+	// continuationSpacing is the largest line pitch, relative to the line
+	// height, at which a line can continue a cell of the row above
+	continuationSpacing = 1.5
+	// maxProseWords is the most words the lines of a column average before
+	// the column reads as running text rather than table entries
+	maxProseWords = 4.0
+)
+
+// extractWhitespaceTable builds a table grid purely from whitespace, like
+// pdfplumber's text strategy on borderless tables. Vertical gutters that no
+// word crosses separate the columns, whose cells must line up over several
+// rows. A line starts a new row unless it sits at normal line spacing below
+// the previous one with an empty first column, in which case it continues
+// the cells of the row above. lines are ordered top to bottom, and borders
+// are the gutters found by findWhitespaceRivers.
+func (te *tableExtractor) extractWhitespaceTable(lines []wordLine, borders []float64) (Table, bool) {
+	if len(lines) < te.minTableSize {
+		return Table{}, false
+	}
+
+	if len(borders) == 0 || !te.columnsAligned(lines, borders) {
+		return Table{}, false
+	}
+
+	var rows [][]string
+	var bbox BoundingBox
+	for i, line := range lines {
+		cells := make([]string, len(borders)+1)
+		for _, word := range line.Words {
+			col := sort.SearchFloat64s(borders, (word.X0+word.X1)/2)
+			if cells[col] != "" {
+				cells[col] += " "
+			}
+			cells[col] += word.Text
+		}
+
+		if i == 0 {
+			bbox = line.BBox.Normalize()
+		} else {
+			bbox = bbox.Union(line.BBox)
+		}
+
+		if i > 0 && cells[0] == "" && te.continuesRow(lines[i-1], line) {
+			last := rows[len(rows)-1]
+			for col, text := range cells {
+				if text == "" {
+					continue
+				}
+				if last[col] != "" {
+					last[col] += " "
+				}
+				last[col] += text
+			}
+			continue
+		}
+		rows = append(rows, cells)
+	}
+
+	if len(rows) < te.minTableSize {
+		return Table{}, false
+	}
+	return Table{Rows: rows, BBox: bbox}, true
+}
+
+// findWhitespaceRivers returns the X positions of vertical whitespace gutters
+// at least minRiverWidth wide that run through all lines, between the
+// leftmost and rightmost words
+func (te *tableExtractor) findWhitespaceRivers(lines []wordLine) []float64 {
+	var spans []BoundingBox
+	for _, line := range lines {
+		for _, word := range line.Words {
+			spans = append(spans, BoundingBox{X0: min(word.X0, word.X1), X1: max(word.X0, word.X1)})
+		}
+	}
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].X0 < spans[j].X0
+	})
+
+	// Merge overlapping word spans; what remains between them is whitespace
+	var borders []float64
+	right := spans[0].X1
+	for _, span := range spans[1:] {
+		if span.X0-right >= minRiverWidth {
+			borders = append(borders, (right+span.X0)/2)
+		}
+		right = max(right, span.X1)
+	}
+	return borders
+}
+
+// columnsAligned checks that the cells of every column line up on their left
+// edges, right edges or centers in at least minTableSize lines, as the
+// columns of a table do and gutters running through text by chance do not
+func (te *tableExtractor) columnsAligned(lines []wordLine, borders []float64) bool {
+	for col := 0; col <= len(borders); col++ {
+		var lefts, rights, centers []float64
+		for _, line := range lines {
+			x0, x1 := math.Inf(1), math.Inf(-1)
+			for _, word := range line.Words {
+				if sort.SearchFloat64s(borders, (word.X0+word.X1)/2) == col {
+					x0, x1 = min(x0, min(word.X0, word.X1)), max(x1, max(word.X0, word.X1))
+				}
+			}
+			if x0 <= x1 {
+				lefts = append(lefts, x0)
+				rights = append(rights, x1)
+				centers = append(centers, (x0+x1)/2)
+			}
+		}
+
+		aligned := 0
+		for _, edges := range [][]float64{lefts, rights, centers} {
+			for _, cluster := range te.clusterPositions(edges) {
+				if cluster.Count > aligned {
+					aligned = cluster.Count
+				}
+			}
+		}
+		if aligned < te.minTableSize {
+			return false
+		}
+	}
+	return true
+}
+
+// readsAsProse checks if the text of every column between the gutters
+// averages more than maxProseWords words a line, as columns of running text
+// do. A table has at least one column of short entries, such as labels or
+// amounts.
+func readsAsProse(lines []wordLine, borders []float64) bool {
+	words := make([]int, len(borders)+1)
+	cells := make([]int, len(borders)+1)
+	for _, line := range lines {
+		counts := make([]int, len(borders)+1)
+		for _, word := range line.Words {
+			counts[sort.SearchFloat64s(borders, (word.X0+word.X1)/2)] += len(strings.Fields(word.Text))
+		}
+		for col, n := range counts {
+			if n > 0 {
+				words[col] += n
+				cells[col]++
+			}
+		}
+	}
+	for col := range words {
+		if cells[col] > 0 && float64(words[col]) <= maxProseWords*float64(cells[col]) {
+			return false
+		}
+	}
+	return true
+}
+
+// continuesRow checks if a line sits at normal line spacing below the
+// previous one, as the wrapped lines of a cell do
+func (te *tableExtractor) continuesRow(prev, line wordLine) bool {
+	height := max(abs(prev.BBox.Y1-prev.BBox.Y0), abs(line.BBox.Y1-line.BBox.Y0))
+	return abs(line.Y-prev.Y) <= height*continuationSpacing
+}
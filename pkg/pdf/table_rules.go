@@ -0,0 +1,63 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+)
+
+// tableRules returns the horizontal rules across a table: horizontal lines
+// and rects flatter than the snap tolerance. The bounding box of a table
+// found from its text ends at the text, so rules up to a row height beyond
+// it count as the table's borders. Segments at the same height, as drawn
+// cell by cell, are joined into one rule with the largest of their widths.
+func (te *tableExtractor) tableRules(objects Objects, table Table) []TableRule {
+	bbox := table.BBox.Normalize()
+	margin := te.snapTolerance
+	if len(table.Rows) > 0 {
+		margin = max(margin, bbox.Height()/float64(len(table.Rows)))
+	}
+	var segments []TableRule
+	add := func(rule TableRule) {
+		if rule.Y < bbox.Y0-margin || rule.Y > bbox.Y1+margin {
+			return
+		}
+		if min(rule.X1, bbox.X1) <= max(rule.X0, bbox.X0) {
+			return
+		}
+		segments = append(segments, rule)
+	}
+	for _, line := range objects.Lines {
+		if math.Abs(line.Y1-line.Y0) < te.snapTolerance {
+			add(TableRule{Y: (line.Y0 + line.Y1) / 2, X0: min(line.X0, line.X1), X1: max(line.X0, line.X1), Width: line.Width})
+		}
+	}
+	for _, rect := range objects.Rects {
+		box := rect.GetBBox().Normalize()
+		if box.Height() >= te.snapTolerance {
+			continue
+		}
+		// A flat rect drawn by stroking is as thick as its line
+		width := box.Height()
+		if width == 0 {
+			width = rect.Width
+		}
+		add(TableRule{Y: (box.Y0 + box.Y1) / 2, X0: box.X0, X1: box.X1, Width: width})
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].Y < segments[j].Y })
+	rules := []TableRule{segments[0]}
+	for _, segment := range segments[1:] {
+		last := &rules[len(rules)-1]
+		if segment.Y-last.Y > te.snapTolerance {
+			rules = append(rules, segment)
+			continue
+		}
+		last.X0 = min(last.X0, segment.X0)
+		last.X1 = max(last.X1, segment.X1)
+		last.Width = max(last.Width, segment.Width)
+	}
+	return rules
+}
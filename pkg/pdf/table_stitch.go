@@ -0,0 +1,115 @@
+package pdf
+
+import (
+	"strings"
+)
+
+// repeatedHeaderSimilarity is the least text similarity, from 0 to 1, at
+// which a continuation page's first row counts as a repeated header
+const repeatedHeaderSimilarity = 0.9
+
+// ExtractTablesAcrossPages extracts the tables of all pages of a document.
+// The last table of a page and the first table of the next page are joined
+// into one when they have the same number of columns; the joined table keeps
+// the bounding box of its first part.
+func ExtractTablesAcrossPages(doc Document, opts ...TableExtractionOption) []Table {
+	config := &tableExtractionConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var pageTables [][]Table
+	for _, page := range doc.GetPages() {
+		pageTables = append(pageTables, page.ExtractTables(opts...))
+	}
+	return stitchTables(pageTables, config.RemoveRepeatedHeaders)
+}
+
+// stitchTables joins tables that continue from one page onto the next.
+// removeHeaders drops the first row of a continuation that repeats the
+// header of the table it continues.
+func stitchTables(pageTables [][]Table, removeHeaders bool) []Table {
+	var tables []Table
+	continues := false // Whether the last table ends its page
+	for _, pageTable := range pageTables {
+		for i, table := range pageTable {
+			if i == 0 && continues {
+				last := &tables[len(tables)-1]
+				if tableColumns(*last) == tableColumns(table) && tableColumns(table) > 0 {
+					rows := table.Rows
+					if removeHeaders && len(last.Rows) > 0 && len(rows) > 0 && isRepeatedHeader(last.Rows[0], rows[0]) {
+						rows = rows[1:]
+					}
+					last.Rows = append(last.Rows, rows...)
+					continue
+				}
+			}
+			table.Rows = append([][]string{}, table.Rows...)
+			tables = append(tables, table)
+		}
+		continues = len(pageTable) > 0
+	}
+	return tables
+}
+
+// tableColumns returns the number of cells of the widest row of a table
+func tableColumns(table Table) int {
+	columns := 0
+	for _, row := range table.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	return columns
+}
+
+// isRepeatedHeader reports whether row repeats header, ignoring case and
+// differences in whitespace
+func isRepeatedHeader(header, row []string) bool {
+	if len(header) != len(row) {
+		return false
+	}
+	a, b := normalizeRow(header), normalizeRow(row)
+	if a == b {
+		return true
+	}
+	longest := max(float64(len([]rune(a))), float64(len([]rune(b))))
+	return 1-float64(editDistance(a, b))/longest >= repeatedHeaderSimilarity
+}
+
+// normalizeRow joins the cells of a row with their whitespace collapsed
+func normalizeRow(row []string) string {
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = strings.ToLower(strings.Join(strings.Fields(cell), " "))
+	}
+	return strings.Join(cells, "\t")
+}
+
+// editDistance returns the Levenshtein distance between two strings in runes
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = prev[j-1] + cost
+			if prev[j]+1 < curr[j] {
+				curr[j] = prev[j] + 1
+			}
+			if curr[j-1]+1 < curr[j] {
+				curr[j] = curr[j-1] + 1
+			}
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,37 @@
+package pdf
+
+import "strings"
+
+// formatLines applies the line trimming, whitespace collapsing and line
+// ending options to extracted text. Without any of them the text is returned
+// unchanged.
+func formatLines(text string, config *textExtractionConfig) string {
+	if !config.TrimLines && !config.CollapseWhitespace && config.LineEnding == "" {
+		return text
+	}
+	
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	lines := strings.Split(text, "\n")
+	if config.TrimLines {
+		for i, line := range lines {
+			lines[i] = strings.Trim(line, " \t")
+		}
+	}
+	if config.CollapseWhitespace {
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.FieldsFunc(line, isSpaceOrTab), " ")
+		}
+	}
+	
+	lineEnding := config.LineEnding
+	if lineEnding == "" {
+		lineEnding = "\n"
+	}
+	return strings.Join(lines, lineEnding)
+}
+
+// isSpaceOrTab reports whether r is a space or a tab
+func isSpaceOrTab(r rune) bool {
+	return r == ' ' || r == '\t'
+}
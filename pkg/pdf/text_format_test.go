@@ -0,0 +1,54 @@
+package pdf
+
+import "testing"
+
+func TestFormatLines(t *testing.T) {
+	text := "  Name   Qty \nWidget  3  \n"
+
+	if got := formatLines(text, &textExtractionConfig{}); got != text {
+		t.Errorf("expected text unchanged by default, got %q", got)
+	}
+
+	got := formatLines(text, &textExtractionConfig{TrimLines: true, LineEnding: "\r\n"})
+	if expected := "Name   Qty\r\nWidget  3\r\n"; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	got = formatLines(text+"\tTotal \t 3", &textExtractionConfig{CollapseWhitespace: true})
+	if expected := "Name Qty\nWidget 3\nTotal 3"; got != expected {
+		t.Errorf("expected collapsed whitespace %q, got %q", expected, got)
+	}
+}
+
+func TestExtractTextCollapseWhitespace(t *testing.T) {
+	doc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+	page, _ := doc.GetPage(0)
+
+	// Layout mode pads the lines with spaces; the last of the two options wins
+	if got := page.ExtractText(WithLayout(true), WithCollapseWhitespace(true)); got != "First page\nSecond line" {
+		t.Errorf("expected collapsed layout text, got %q", got)
+	}
+	if got := page.ExtractText(WithCollapseWhitespace(true), WithLayout(true)); got == "First page\nSecond line" {
+		t.Errorf("expected layout text when WithLayout comes last, got %q", got)
+	}
+}
+
+func TestExtractTextLineEnding(t *testing.T) {
+	doc, err := Open("../../testdata/two_pages.pdf")
+	if err != nil {
+		t.Fatalf("failed to open PDF: %v", err)
+	}
+	defer doc.Close()
+
+	page, _ := doc.GetPage(0)
+	if got := page.ExtractText(); got != "First page\nSecond line" {
+		t.Errorf("expected LF line endings by default, got %q", got)
+	}
+	if got := page.ExtractText(WithTrimLines(true), WithLineEnding("\r\n")); got != "First page\r\nSecond line" {
+		t.Errorf("expected CRLF line endings, got %q", got)
+	}
+}
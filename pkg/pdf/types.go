@@ -1,19 +1,25 @@
 package pdf
 
 import (
+	"fmt"
+	"io"
+	"regexp"
 	"time"
+
+	"github.com/pyhub-apps/pdfplumber-golang/pkg/parser"
 )
 
 // ObjectType represents the type of PDF object
 type ObjectType string
 
 const (
-	ObjectTypeChar  ObjectType = "char"
-	ObjectTypeLine  ObjectType = "line"
-	ObjectTypeRect  ObjectType = "rect"
-	ObjectTypeCurve ObjectType = "curve"
-	ObjectTypeImage ObjectType = "image"
-	ObjectTypeAnno  ObjectType = "annotation"
+	ObjectTypeChar    ObjectType = "char"
+	ObjectTypeLine    ObjectType = "line"
+	ObjectTypeRect    ObjectType = "rect"
+	ObjectTypeCurve   ObjectType = "curve"
+	ObjectTypeImage   ObjectType = "image"
+	ObjectTypeAnno    ObjectType = "annotation"
+	ObjectTypeShading ObjectType = "shading"
 )
 
 // BoundingBox represents a rectangular area with coordinates
@@ -44,6 +50,48 @@ func (b BoundingBox) Intersects(other BoundingBox) bool {
 	return !(b.X1 < other.X0 || b.X0 > other.X1 || b.Y1 < other.Y0 || b.Y0 > other.Y1)
 }
 
+// Normalize returns the bounding box with X0 <= X1 and Y0 <= Y1
+func (b BoundingBox) Normalize() BoundingBox {
+	return BoundingBox{
+		X0: min(b.X0, b.X1),
+		Y0: min(b.Y0, b.Y1),
+		X1: max(b.X0, b.X1),
+		Y1: max(b.Y0, b.Y1),
+	}
+}
+
+// Union returns the smallest bounding box containing both boxes
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	b, other = b.Normalize(), other.Normalize()
+	return BoundingBox{
+		X0: min(b.X0, other.X0),
+		Y0: min(b.Y0, other.Y0),
+		X1: max(b.X1, other.X1),
+		Y1: max(b.Y1, other.Y1),
+	}
+}
+
+// Intersection returns the overlap of two bounding boxes and whether they
+// intersect. Boxes that only touch give a zero-width or zero-height overlap.
+func (b BoundingBox) Intersection(other BoundingBox) (BoundingBox, bool) {
+	b, other = b.Normalize(), other.Normalize()
+	overlap := BoundingBox{
+		X0: max(b.X0, other.X0),
+		Y0: max(b.Y0, other.Y0),
+		X1: min(b.X1, other.X1),
+		Y1: min(b.Y1, other.Y1),
+	}
+	if overlap.X0 > overlap.X1 || overlap.Y0 > overlap.Y1 {
+		return BoundingBox{}, false
+	}
+	return overlap, true
+}
+
+// IsEmpty checks if the bounding box has no area
+func (b BoundingBox) IsEmpty() bool {
+	return b.Width() <= 0 || b.Height() <= 0
+}
+
 // Metadata represents PDF document metadata
 type Metadata struct {
 	Title        string
@@ -59,12 +107,69 @@ type Metadata struct {
 
 // Objects represents a collection of PDF objects
 type Objects struct {
-	Chars  []CharObject
-	Lines  []LineObject
-	Rects  []RectObject
-	Curves []CurveObject
-	Images []ImageObject
-	Annos  []AnnotationObject
+	Chars    []CharObject
+	Lines    []LineObject
+	Rects    []RectObject
+	Curves   []CurveObject
+	Images   []ImageObject
+	Annos    []AnnotationObject
+	Shadings []ShadingObject
+}
+
+// All returns every object boxed into the Object interface, ordered as the
+// typed slices are: chars, lines, rects, curves, images, annotations and
+// shadings, each in its own order
+func (o Objects) All() []Object {
+	all := make([]Object, 0, len(o.Chars)+len(o.Lines)+len(o.Rects)+len(o.Curves)+len(o.Images)+len(o.Annos)+len(o.Shadings))
+	for _, char := range o.Chars {
+		all = append(all, char)
+	}
+	for _, line := range o.Lines {
+		all = append(all, line)
+	}
+	for _, rect := range o.Rects {
+		all = append(all, rect)
+	}
+	for _, curve := range o.Curves {
+		all = append(all, curve)
+	}
+	for _, image := range o.Images {
+		all = append(all, image)
+	}
+	for _, anno := range o.Annos {
+		all = append(all, anno)
+	}
+	for _, shading := range o.Shadings {
+		all = append(all, shading)
+	}
+	return all
+}
+
+// limitObjects drops objects past the first n, taking them from the end of
+// the All order. It reports whether any were dropped; n <= 0 means no limit.
+func limitObjects(objects *Objects, n int) bool {
+	excess := len(objects.Chars) + len(objects.Lines) + len(objects.Rects) + len(objects.Curves) +
+		len(objects.Images) + len(objects.Annos) + len(objects.Shadings) - n
+	if n <= 0 || excess <= 0 {
+		return false
+	}
+	
+	keep := func(length int) int {
+		dropped := length
+		if dropped > excess {
+			dropped = excess
+		}
+		excess -= dropped
+		return length - dropped
+	}
+	objects.Shadings = objects.Shadings[:keep(len(objects.Shadings))]
+	objects.Annos = objects.Annos[:keep(len(objects.Annos))]
+	objects.Images = objects.Images[:keep(len(objects.Images))]
+	objects.Curves = objects.Curves[:keep(len(objects.Curves))]
+	objects.Rects = objects.Rects[:keep(len(objects.Rects))]
+	objects.Lines = objects.Lines[:keep(len(objects.Lines))]
+	objects.Chars = objects.Chars[:keep(len(objects.Chars))]
+	return true
 }
 
 // CharObject represents a character in the PDF
@@ -80,6 +185,20 @@ type CharObject struct {
 	Height   float64
 	Color    Color
 	Matrix   TransformMatrix
+	
+	FontFallback bool   // Drawn with the fallback font, the content setting no usable font
+	Layer        string // Optional content group (layer) the character is in, empty if none
+	RenderMode   int    // Text render mode (Tr): 0 fill, 1 stroke, 2 fill and stroke, 3 invisible, 4 to 7 also clip
+	Outlined     bool   // The glyph outlines are stroked, as in render modes 1, 2, 5 and 6
+	
+	followsSpace bool    // Preceded by a word-separating space not kept as a character
+	lang         string  // Language from the enclosing marked content or the document
+	unmapped     bool    // Decoded from raw code bytes, the font having no mapping for them
+	mcid         int     // MCID + 1 of the enclosing marked content, 0 outside any
+	rise         float64 // Text rise (Ts) in page units, not applied to the position
+	spaceWidth   float64 // Width of the font's space glyph as a fraction of the font size, 0 if unknown
+	descent      float64 // Depth of the box below the baseline, 0 or negative
+	order        int     // Place in the page's painting order, 0 if unknown
 }
 
 // GetType returns the object type
@@ -95,10 +214,13 @@ func (c CharObject) GetBBox() BoundingBox {
 // GetProperties returns character properties
 func (c CharObject) GetProperties() map[string]interface{} {
 	return map[string]interface{}{
-		"text":      c.Text,
-		"font":      c.Font,
-		"font_size": c.FontSize,
-		"color":     c.Color,
+		"text":          c.Text,
+		"font":          c.Font,
+		"font_size":     c.FontSize,
+		"color":         c.Color,
+		"font_fallback": c.FontFallback,
+		"render_mode":   c.RenderMode,
+		"outlined":      c.Outlined,
 	}
 }
 
@@ -111,6 +233,10 @@ type LineObject struct {
 	Width      float64
 	StrokeColor Color
 	NonStroking bool
+	Layer       string    // Optional content group (layer) the line is in, empty if none
+	DashPattern []float64 // Lengths of the alternating dashes and gaps, empty for a solid line
+	LineCap     int       // 0 for butt, 1 for round and 2 for projecting square caps
+	LineJoin    int       // 0 for miter, 1 for round and 2 for bevel joins
 }
 
 // GetType returns the object type
@@ -134,9 +260,18 @@ func (l LineObject) GetProperties() map[string]interface{} {
 		"width":        l.Width,
 		"stroke_color": l.StrokeColor,
 		"non_stroking": l.NonStroking,
+		"dash":         l.DashPattern,
+		"line_cap":     l.LineCap,
+		"line_join":    l.LineJoin,
 	}
 }
 
+// Dashed reports whether the line is stroked with a dash pattern, as dotted
+// leaders and dashed separators are
+func (l LineObject) Dashed() bool {
+	return len(l.DashPattern) > 0
+}
+
 // RectObject represents a rectangle in the PDF
 type RectObject struct {
 	X0          float64
@@ -146,9 +281,14 @@ type RectObject struct {
 	Width       float64
 	StrokeColor Color
 	FillColor   Color
+	FillPattern string // Pattern resource name when filled with a pattern
 	NonStroking bool
 	Filled      bool
 	Stroked     bool
+	Rounded     bool   // Drawn with rounded corners
+	Layer       string // Optional content group (layer) the rectangle is in, empty if none
+
+	order int // Place in the page's painting order, 0 if unknown
 }
 
 // GetType returns the object type
@@ -167,7 +307,9 @@ func (r RectObject) GetProperties() map[string]interface{} {
 		"width":        r.Width,
 		"stroke_color": r.StrokeColor,
 		"fill_color":   r.FillColor,
+		"fill_pattern": r.FillPattern,
 		"non_stroking": r.NonStroking,
+		"rounded":      r.Rounded,
 	}
 }
 
@@ -177,6 +319,7 @@ type CurveObject struct {
 	StrokeColor Color
 	FillColor   Color
 	Width       float64
+	Layer       string // Optional content group (layer) the curve is in, empty if none
 }
 
 // GetType returns the object type
@@ -223,6 +366,8 @@ type ImageObject struct {
 	Height     int
 	ColorSpace string
 	BitsPerComponent int
+	Name       string // XObject resource name
+	Layer      string // Optional content group (layer) the image is in, empty if none
 }
 
 // GetType returns the object type
@@ -242,6 +387,7 @@ func (i ImageObject) GetProperties() map[string]interface{} {
 		"height":             i.Height,
 		"color_space":        i.ColorSpace,
 		"bits_per_component": i.BitsPerComponent,
+		"name":               i.Name,
 	}
 }
 
@@ -275,6 +421,37 @@ func (a AnnotationObject) GetProperties() map[string]interface{} {
 	}
 }
 
+// ShadingObject represents a shading (gradient) fill in the PDF
+type ShadingObject struct {
+	X0          float64
+	Y0          float64
+	X1          float64
+	Y1          float64
+	ShadingType int    // 1-7 as defined by the PDF spec, 0 if unknown
+	Name        string // Resource name of the shading or pattern
+	Pattern     bool   // Painted through a shading pattern fill rather than sh
+	Layer       string // Optional content group (layer) the shading is in, empty if none
+}
+
+// GetType returns the object type
+func (s ShadingObject) GetType() ObjectType {
+	return ObjectTypeShading
+}
+
+// GetBBox returns the shading's bounding box
+func (s ShadingObject) GetBBox() BoundingBox {
+	return BoundingBox{X0: s.X0, Y0: s.Y0, X1: s.X1, Y1: s.Y1}
+}
+
+// GetProperties returns shading properties
+func (s ShadingObject) GetProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"shading_type": s.ShadingType,
+		"name":         s.Name,
+		"pattern":      s.Pattern,
+	}
+}
+
 // Color represents an RGB color
 type Color struct {
 	R, G, B uint8
@@ -293,34 +470,274 @@ type TransformMatrix struct {
 
 // Table represents an extracted table
 type Table struct {
-	Rows [][]string
-	BBox BoundingBox
+	Rows   [][]string      // Cell text, top row first, on every backend
+	BBox   BoundingBox
+	Rules  []TableRule     // Horizontal rules across the table, in order of Y
+	Source int             // Index of the result set the table came from, set by MergeTables
+	Cells  [][]BoundingBox // Grid cells the rows were read from, top row first, for tables found from edges
+}
+
+// TableRule is a horizontal rule drawn across a table, such as the border
+// under its header
+type TableRule struct {
+	Y      float64 // Position of the rule
+	X0, X1 float64 // Horizontal extent of the rule
+	Width  float64 // Line width, or the height of a rule drawn as a filled rect
 }
 
 // Word represents a word extracted from PDF
 type Word struct {
-	Text       string          // The word text
-	X0         float64         // Left boundary
-	Y0         float64         // Top boundary
-	X1         float64         // Right boundary
-	Y1         float64         // Bottom boundary
-	Characters []CharObject    // Characters that make up this word
+	Text        string       // The word text
+	X0          float64      // Left boundary
+	Y0          float64      // Top boundary
+	X1          float64      // Right boundary
+	Y1          float64      // Bottom boundary
+	Characters  []CharObject // Characters that make up this word
+	Confidence  float64      // Share of characters decoded to printable text through a font mapping, from 0 to 1
+	Superscript bool         // Raised above the text of the word it was split from, set by WithSeparateSuperscripts
+	Subscript   bool         // Lowered below the text of the word it was split from, set by WithSeparateSuperscripts
+}
+
+// OpenOption is a function that modifies how a document is opened
+type OpenOption func(*openConfig)
+
+type openConfig struct {
+	FontUnicodeOverrides []fontUnicodeOverride
+	MaxPages             int  // 0 for no limit
+	TruncatePages        bool // Drop pages past MaxPages instead of failing
+	SpaceGlyphDetection  bool
+	RepairUnicode        bool          // Repair malformed UTF-16 in ToUnicode CMaps
+	VisibleLayersOnly    bool          // Drop content in layers turned off by default
+	MaxObjectsPerPage    int           // 0 for no limit
+	MaxStreamSize        int64         // Largest decoded stream in bytes, 0 for no limit
+	PageBBox             *BoundingBox  // Overrides the box of every page, in PDF space
+	ExcludeRegions       []BoundingBox // Areas whose objects are dropped, in PDF space
+	FallbackFont         string        // Standard font of text shown without a usable font
+	PhysicalDimensions   bool          // Scale page sizes by the pages' /UserUnit
+	SnapGrid             float64       // Grid object coordinates are rounded to, 0 for none
+	EmitSpaces           bool          // Keep space glyphs as characters
+	err                  error         // First invalid option, reported by Open
+}
+
+// newOpenConfig applies options and reports the first invalid one
+func newOpenConfig(opts []OpenOption) (*openConfig, error) {
+	config := &openConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config, config.err
+}
+
+// newLibraryOpenConfig applies options for the ledongthuc and dslipak
+// backends, which return ErrNotImplemented for options only Open applies
+func newLibraryOpenConfig(opts []OpenOption) (*openConfig, error) {
+	config, err := newOpenConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	// The libraries decode text and run content streams themselves
+	switch {
+	case config.RepairUnicode:
+		return nil, fmt.Errorf("WithRepairUnicode: %w", ErrNotImplemented)
+	case len(config.FontUnicodeOverrides) > 0:
+		return nil, fmt.Errorf("WithFontUnicodeOverride: %w", ErrNotImplemented)
+	case config.SpaceGlyphDetection:
+		return nil, fmt.Errorf("WithSpaceGlyphDetection: %w", ErrNotImplemented)
+	case config.FallbackFont != "":
+		return nil, fmt.Errorf("WithFallbackFont: %w", ErrNotImplemented)
+	case config.VisibleLayersOnly:
+		return nil, fmt.Errorf("WithVisibleLayersOnly: %w", ErrNotImplemented)
+	case config.MaxStreamSize > 0:
+		return nil, fmt.Errorf("WithMaxStreamSize: %w", ErrNotImplemented)
+	}
+	return config, nil
+}
+
+// pageCount returns how many of a document's pages are opened
+func (c *openConfig) pageCount(total int) (int, error) {
+	if c.MaxPages <= 0 || total <= c.MaxPages {
+		return total, nil
+	}
+	if c.TruncatePages {
+		return c.MaxPages, nil
+	}
+	return 0, fmt.Errorf("%w: document has %d pages, more than the maximum of %d", ErrTooManyPages, total, c.MaxPages)
+}
+
+// parserOptions returns the options of the native parser's check of a
+// document before another backend reads it
+func (c *openConfig) parserOptions() []parser.Option {
+	return []parser.Option{
+		parser.WithMaxPages(c.MaxPages),
+		parser.WithTruncatePages(c.TruncatePages),
+	}
+}
+
+// WithMaxPages limits how many pages are opened, bounding the work done on
+// untrusted documents: the page tree is walked no further than the limit.
+// By default a document with more pages fails to open with ErrTooManyPages.
+func WithMaxPages(n int) OpenOption {
+	return func(c *openConfig) {
+		c.MaxPages = n
+	}
+}
+
+// WithSpaceGlyphDetection decodes each font's designated space code as a
+// space, found by the code its ToUnicode CMap maps to a space or a no-break
+// space. Word spacing applies to it, and zero-width space glyphs produce no
+// character but still separate words. Only documents opened with Open apply
+// it; the ledongthuc and dslipak backends fail to open with ErrNotImplemented.
+func WithSpaceGlyphDetection(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.SpaceGlyphDetection = enabled
+	}
+}
+
+// WithEmitSpaces keeps the spaces of shown strings as characters, positioned
+// and as wide as they advance, for rebuilding the exact text. By default the
+// ledongthuc and dslipak backends drop them, using them only to separate
+// words; kept spaces still separate words and are not part of any. Documents
+// opened with Open always keep spaces that advance.
+func WithEmitSpaces(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.EmitSpaces = enabled
+	}
+}
+
+// WithRepairUnicode repairs malformed UTF-16 in ToUnicode CMaps written by
+// buggy generators: values written byte-swapped, found by a byte order mark
+// or by which byte order decodes the CMap to valid text, values of an odd
+// number of bytes, and surrogates without their other half. Only documents
+// opened with Open apply it; the ledongthuc and dslipak backends, which
+// decode text in their libraries, fail to open with ErrNotImplemented.
+func WithRepairUnicode(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.RepairUnicode = enabled
+	}
+}
+
+// WithMaxObjectsPerPage stops parsing a page's content once it has emitted n
+// objects of all types combined, counting those of forms, pattern cells and
+// annotations, bounding the work and memory pathological content streams
+// can take. Truncated pages are reported by Validate. The ledongthuc and
+// dslipak libraries read a page's content whole, so on those backends only
+// the objects kept are bounded.
+func WithMaxObjectsPerPage(n int) OpenOption {
+	return func(c *openConfig) {
+		c.MaxObjectsPerPage = n
+	}
+}
+
+// WithMaxStreamSize bounds the size of every decoded stream, such as page
+// content, forms, fonts, images and attachments, guarding against
+// decompression bombs. A document whose page content decodes past n bytes
+// fails to open with ErrStreamTooLarge; other streams that do are skipped. Only
+// documents opened with Open apply it; the ledongthuc and dslipak backends,
+// which decode streams in their libraries, fail to open with
+// ErrNotImplemented.
+func WithMaxStreamSize(n int64) OpenOption {
+	return func(c *openConfig) {
+		c.MaxStreamSize = n
+	}
+}
+
+// WithFallbackFont sets the standard font whose metrics position text shown
+// without a usable font, such as text before any Tf or in a font that failed
+// to load. It defaults to Helvetica. Only documents opened with Open apply
+// it; the ledongthuc and dslipak backends fail to open with ErrNotImplemented.
+func WithFallbackFont(baseFont string) OpenOption {
+	return func(c *openConfig) {
+		if _, ok := standardFontName(baseFont); !ok {
+			if c.err == nil {
+				c.err = fmt.Errorf("fallback font %q is not a standard font", baseFont)
+			}
+			return
+		}
+		c.FallbackFont = baseFont
+	}
+}
+
+// WithPageBBox overrides the box of every page, given in PDF space, for
+// documents with a wrong MediaBox or CropBox. Page width and height become
+// those of the box, coordinates are taken relative to it, and objects
+// entirely outside it are dropped.
+func WithPageBBox(bbox BoundingBox) OpenOption {
+	return func(c *openConfig) {
+		bbox = bbox.Normalize()
+		if bbox.IsEmpty() {
+			if c.err == nil {
+				c.err = fmt.Errorf("page bbox %v has no area", bbox)
+			}
+			return
+		}
+		c.PageBBox = &bbox
+	}
+}
+
+// WithTruncatePages opens only the first pages of a document exceeding the
+// maximum page count instead of failing
+func WithTruncatePages(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.TruncatePages = enabled
+	}
+}
+
+// fontUnicodeOverride replaces the ToUnicode mapping of matching fonts
+type fontUnicodeOverride struct {
+	pattern *regexp.Regexp
+	cmap    *ToUnicodeCMap
+}
+
+// WithFontUnicodeOverride decodes text of fonts whose BaseFont matches the
+// regular expression with the given CMap. Codes the CMap does not map fall
+// back to the font's own ToUnicode CMap, if any. Only documents opened with
+// Open apply it; the ledongthuc and dslipak backends, which decode text in
+// their libraries, fail to open with ErrNotImplemented.
+func WithFontUnicodeOverride(fontNamePattern string, cmap *ToUnicodeCMap) OpenOption {
+	return func(c *openConfig) {
+		pattern, err := regexp.Compile(fontNamePattern)
+		if err != nil {
+			if c.err == nil {
+				c.err = fmt.Errorf("invalid font name pattern %q: %w", fontNamePattern, err)
+			}
+			return
+		}
+		c.FontUnicodeOverrides = append(c.FontUnicodeOverrides, fontUnicodeOverride{pattern: pattern, cmap: cmap})
+	}
 }
 
 // TextExtractionOption is a function that modifies text extraction behavior
 type TextExtractionOption func(*textExtractionConfig)
 
 type textExtractionConfig struct {
-	Layout      bool
-	XTolerance  float64
-	YTolerance  float64
-	UnicodeNorm string
+	Layout               bool
+	XTolerance           float64
+	YTolerance           float64
+	UnicodeNorm          string
+	ColumnDetection      bool
+	OCRFunc              OCRFunc
+	TrimLines            bool
+	CollapseWhitespace   bool   // Runs of spaces and tabs within a line become one space
+	LineEnding           string // Empty keeps the line endings as extracted
+	IgnoreRotatedText    bool
+	RotatedTextTolerance float64 // Largest angle in degrees of text kept upright
+	ColumnSeparator      string  // Joins the columns of each line when set
+	ParagraphBreaks      bool    // Blank line between lines set apart further than the line pitch
+	ReadingOrder         ReadingOrder
+	ExcludeOutlined      bool // Leave out text drawn as outlines
 }
 
-// WithLayout enables layout-aware text extraction
+// OCRFunc recognizes text in a rendered page image
+type OCRFunc func(img io.Reader) (string, error)
+
+// WithLayout enables layout-aware text extraction. Enabling it turns off
+// WithCollapseWhitespace given before it.
 func WithLayout(enabled bool) TextExtractionOption {
 	return func(c *textExtractionConfig) {
 		c.Layout = enabled
+		if enabled {
+			c.CollapseWhitespace = false
+		}
 	}
 }
 
@@ -338,12 +755,95 @@ func WithYTolerance(tolerance float64) TextExtractionOption {
 	}
 }
 
+// WithParagraphBreaks separates paragraphs with a blank line. Lines more than
+// one and a half times the typical line pitch apart start a new paragraph.
+func WithParagraphBreaks(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.ParagraphBreaks = enabled
+	}
+}
+
+// WithColumnDetection enables reading multi-column layouts column by column
+func WithColumnDetection(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.ColumnDetection = enabled
+	}
+}
+
+// WithOCRFunc sets a function used to recognize text on pages that have
+// images but no extractable characters, such as scanned pages. Only pages
+// of the pdfcpu backend, opened with Open, pass their images on; the other
+// backends return ErrNotImplemented from RecognizeText.
+func WithOCRFunc(fn OCRFunc) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.OCRFunc = fn
+	}
+}
+
+// WithTrimLines removes leading and trailing spaces from each extracted line
+func WithTrimLines(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.TrimLines = enabled
+	}
+}
+
+// WithCollapseWhitespace collapses each run of spaces and tabs within a line
+// to a single space and trims the line, keeping the line breaks, as search
+// indexing wants. Column separators made of spaces or tabs are collapsed
+// too. It and WithLayout exclude each other; the last one given wins.
+func WithCollapseWhitespace(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.CollapseWhitespace = enabled
+		if enabled {
+			c.Layout = false
+		}
+	}
+}
+
+// WithLineEnding sets the string joining extracted lines, such as "\r\n"
+func WithLineEnding(ending string) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.LineEnding = ending
+	}
+}
+
+// WithIgnoreRotatedText leaves out characters whose baseline is rotated,
+// such as diagonal watermarks and vertical side labels
+func WithIgnoreRotatedText(enabled bool) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.IgnoreRotatedText = enabled
+	}
+}
+
+// WithRotatedTextTolerance sets the largest angle in degrees at which text is
+// still kept when rotated text is ignored (default: 10)
+func WithRotatedTextTolerance(degrees float64) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.RotatedTextTolerance = degrees
+	}
+}
+
+// WithColumnSeparator joins the columns found by column detection with sep on
+// each line instead of reading them one after another, such as "\t" for
+// tab-separated output
+func WithColumnSeparator(sep string) TextExtractionOption {
+	return func(c *textExtractionConfig) {
+		c.ColumnSeparator = sep
+	}
+}
+
 // WordExtractionOption is a function that modifies word extraction behavior
 type WordExtractionOption func(*wordExtractionConfig)
 
 type wordExtractionConfig struct {
-	XTolerance float64 // Horizontal tolerance for word separation (default: 3.0)
-	YTolerance float64 // Vertical tolerance for line separation (default: 3.0)
+	XTolerance           float64 // Horizontal tolerance for word separation (default: 3.0)
+	YTolerance           float64 // Vertical tolerance for line separation (default: 3.0)
+	Scale                float64 // Factor applied to emitted coordinates (default: 1)
+	IgnoreRotatedText    bool
+	RotatedTextTolerance float64 // Largest angle in degrees of text kept (default: 10)
+	TextAngleThreshold   float64 // Largest baseline angle difference in degrees within a word (default: 10)
+	MergeAdjacentChars   bool    // Split words only at library spaces and gaps over XTolerance
+	SeparateSuperscripts bool    // Split words where the font size or baseline jumps
 }
 
 // WithWordXTolerance sets the horizontal tolerance for word separation
@@ -360,17 +860,72 @@ func WithWordYTolerance(tolerance float64) WordExtractionOption {
 	}
 }
 
+// WithScale multiplies all word and character coordinates by a factor, such
+// as 150/72 for overlays on a page rendered at 150 DPI
+func WithScale(factor float64) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		if factor > 0 {
+			c.Scale = factor
+		}
+	}
+}
+
+// WithWordIgnoreRotatedText leaves out characters whose baseline is rotated
+func WithWordIgnoreRotatedText(enabled bool) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		c.IgnoreRotatedText = enabled
+	}
+}
+
+// WithWordRotatedTextTolerance sets the largest angle in degrees at which
+// text is still kept when rotated text is ignored
+func WithWordRotatedTextTolerance(degrees float64) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		c.RotatedTextTolerance = degrees
+	}
+}
+
+// WithTextAngleThreshold sets the largest difference in degrees between the
+// baselines of characters grouped into one word. A character rotated further
+// from the one before it starts a new word.
+func WithTextAngleThreshold(degrees float64) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		c.TextAngleThreshold = degrees
+	}
+}
+
+// WithMergeAdjacentChars keeps the word boundaries of the ledongthuc and
+// dslipak libraries, which place each glyph at its own advance and emit
+// spaces as text items: words are split at those spaces and at gaps wider
+// than the x tolerance, rather than at gaps guessed from character widths.
+// A word's bounds are still those of its characters, which divide the X and
+// W of each text item evenly. Documents opened with Open split words this
+// way already.
+func WithMergeAdjacentChars(enabled bool) WordExtractionOption {
+	return func(c *wordExtractionConfig) {
+		c.MergeAdjacentChars = enabled
+	}
+}
+
 // TableExtractionOption is a function that modifies table extraction behavior
 type TableExtractionOption func(*tableExtractionConfig)
 
 type tableExtractionConfig struct {
-	VerticalStrategy   string
-	HorizontalStrategy string
-	MinTableSize       int
-	TextTolerance      float64
+	VerticalStrategy      string
+	HorizontalStrategy    string
+	MinTableSize          int
+	TextTolerance         float64
+	ExplicitVertical      []float64
+	ExplicitHorizontal    []float64
+	RemoveRepeatedHeaders bool    // Drop header rows repeated on continuation pages
+	ConsistentColumns     bool    // Give every row of a table the same number of cells
+	MinColumnGap          float64 // Least distance between text columns, 0 for a default from the character width
+	ExcludeDashedLines    bool    // Leave dashed and dotted lines out of table detection
 }
 
-// WithTableStrategy sets the table detection strategy
+// WithTableStrategy sets the table detection strategy for each direction.
+// Each strategy is one of "lines", "text" or "explicit", like pdfplumber's
+// vertical_strategy and horizontal_strategy.
 func WithTableStrategy(vertical, horizontal string) TableExtractionOption {
 	return func(c *tableExtractionConfig) {
 		c.VerticalStrategy = vertical
@@ -392,6 +947,59 @@ func WithTextTolerance(tolerance float64) TableExtractionOption {
 	}
 }
 
+// WithExplicitVerticalLines sets X positions of vertical cell borders.
+// They are used by the "explicit" strategy and added to the other strategies.
+func WithExplicitVerticalLines(xs ...float64) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.ExplicitVertical = xs
+	}
+}
+
+// WithExplicitHorizontalLines sets Y positions of horizontal cell borders.
+// They are used by the "explicit" strategy and added to the other strategies.
+func WithExplicitHorizontalLines(ys ...float64) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.ExplicitHorizontal = ys
+	}
+}
+
+// WithRemoveRepeatedHeaders drops the first row of a table continuing on a
+// later page when it repeats the header of the table, as ExtractTablesAcrossPages
+// joins them
+func WithRemoveRepeatedHeaders(enabled bool) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.RemoveRepeatedHeaders = enabled
+	}
+}
+
+// WithMinColumnGap sets the least distance between the left edges of two
+// columns of borderless tables; columns found closer together, as kerning
+// can make them, are merged into the leftmost. It defaults to a fraction of
+// the median character width.
+func WithMinColumnGap(gap float64) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.MinColumnGap = gap
+	}
+}
+
+// WithConsistentColumns gives every row of a table the column count most of
+// its rows have, as CSV export and data frames expect. Short rows are padded
+// with empty cells; the overflow of long rows is joined into their last cell.
+func WithConsistentColumns(enabled bool) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.ConsistentColumns = enabled
+	}
+}
+
+// WithExcludeDashedLines leaves lines stroked with a dash pattern, such as
+// the dotted leaders of a table of contents, out of table detection, so only
+// solid lines and rects act as table rules
+func WithExcludeDashedLines(enabled bool) TableExtractionOption {
+	return func(c *tableExtractionConfig) {
+		c.ExcludeDashedLines = enabled
+	}
+}
+
 // ImageOption is a function that modifies image rendering behavior
 type ImageOption func(*imageConfig)
 
@@ -400,6 +1008,67 @@ type imageConfig struct {
 	Format     string
 }
 
+// WithResolution sets the resolution of rendered pages in dots per inch,
+// 72 by default
+func WithResolution(dpi int) ImageOption {
+	return func(c *imageConfig) {
+		c.Resolution = dpi
+	}
+}
+
+// CropOption is a function that modifies page cropping behavior
+type CropOption func(*cropConfig)
+
+type cropConfig struct {
+	Relative bool
+}
+
+// WithRelativeCoordinates moves the objects of a cropped page so the crop
+// box's corner (X0, Y0) is the origin. By default they keep the
+// coordinates of the uncropped page.
+func WithRelativeCoordinates(relative bool) CropOption {
+	return func(c *cropConfig) {
+		c.Relative = relative
+	}
+}
+
+// DebugOptions selects the objects DrawDebug outlines over the rendered
+// page and their colors. Colors left unset use those of
+// DefaultDebugOptions.
+type DebugOptions struct {
+	Chars  bool // Character boxes
+	Lines  bool
+	Rects  bool
+	Curves bool
+	Tables bool // Cells of detected tables, or their bounds and rules
+
+	CharColor  Color
+	LineColor  Color
+	RectColor  Color
+	CurveColor Color
+	TableColor Color
+
+	Resolution   int // Dots per inch, 72 if zero
+	TableOptions []TableExtractionOption
+}
+
+// DefaultDebugOptions outlines every class of object at 72 dpi
+func DefaultDebugOptions() DebugOptions {
+	return DebugOptions{
+		Chars:      true,
+		Lines:      true,
+		Rects:      true,
+		Curves:     true,
+		Tables:     true,
+		CharColor:  Color{B: 255, A: 255},
+		LineColor:  Color{R: 255, A: 255},
+		RectColor:  Color{G: 160, A: 255},
+		CurveColor: Color{R: 255, G: 128, A: 255},
+		TableColor: Color{R: 255, B: 255, A: 255},
+		Resolution: defaultResolution,
+	}
+}
+
 // Helper functions
 func min(a, b float64) float64 {
 	if a < b {
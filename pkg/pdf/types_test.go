@@ -0,0 +1,82 @@
+package pdf
+
+import "testing"
+
+func TestBoundingBoxUnion(t *testing.T) {
+	a := BoundingBox{X0: 10, Y0: 10, X1: 20, Y1: 20}
+	b := BoundingBox{X0: 15, Y0: 5, X1: 30, Y1: 12}
+
+	expected := BoundingBox{X0: 10, Y0: 5, X1: 30, Y1: 20}
+	if got := a.Union(b); got != expected {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// Inverted coordinates are normalized first
+	inverted := BoundingBox{X0: 30, Y0: 12, X1: 15, Y1: 5}
+	if got := a.Union(inverted); got != expected {
+		t.Errorf("expected %+v for inverted box, got %+v", expected, got)
+	}
+}
+
+func TestBoundingBoxIntersection(t *testing.T) {
+	a := BoundingBox{X0: 0, Y0: 0, X1: 10, Y1: 10}
+
+	overlap, ok := a.Intersection(BoundingBox{X0: 5, Y0: -5, X1: 15, Y1: 5})
+	if !ok || overlap != (BoundingBox{X0: 5, Y0: 0, X1: 10, Y1: 5}) {
+		t.Errorf("expected overlap (5,0)-(10,5), got %+v (ok=%v)", overlap, ok)
+	}
+
+	if overlap, ok := a.Intersection(BoundingBox{X0: 20, Y0: 20, X1: 30, Y1: 30}); ok || overlap != (BoundingBox{}) {
+		t.Errorf("expected disjoint boxes not to intersect, got %+v (ok=%v)", overlap, ok)
+	}
+
+	// Touching boxes intersect along their shared edge, as with Intersects
+	overlap, ok = a.Intersection(BoundingBox{X0: 10, Y0: 0, X1: 20, Y1: 10})
+	if !ok || !overlap.IsEmpty() {
+		t.Errorf("expected an empty overlap for touching boxes, got %+v (ok=%v)", overlap, ok)
+	}
+}
+
+func TestBoundingBoxIsEmpty(t *testing.T) {
+	tests := []struct {
+		bbox  BoundingBox
+		empty bool
+	}{
+		{BoundingBox{}, true},
+		{BoundingBox{X0: 0, Y0: 0, X1: 10, Y1: 0}, true},
+		{BoundingBox{X0: 10, Y0: 0, X1: 0, Y1: 10}, true},
+		{BoundingBox{X0: 0, Y0: 0, X1: 10, Y1: 10}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.bbox.IsEmpty(); got != tt.empty {
+			t.Errorf("IsEmpty(%+v) = %v, want %v", tt.bbox, got, tt.empty)
+		}
+	}
+}
+
+func TestObjectsAll(t *testing.T) {
+	objects := Objects{
+		Chars:  []CharObject{{Text: "a"}, {Text: "b"}},
+		Lines:  []LineObject{{X0: 1}},
+		Rects:  []RectObject{{X0: 2}},
+		Curves: []CurveObject{{}},
+		Images: []ImageObject{{Name: "Im1"}},
+		Annos:  []AnnotationObject{{}},
+	}
+
+	all := objects.All()
+	if len(all) != 7 {
+		t.Fatalf("expected 7 objects, got %d", len(all))
+	}
+
+	expected := []ObjectType{ObjectTypeChar, ObjectTypeChar, ObjectTypeLine, ObjectTypeRect, ObjectTypeCurve, ObjectTypeImage, ObjectTypeAnno}
+	for i, obj := range all {
+		if obj.GetType() != expected[i] {
+			t.Errorf("object %d: expected type %v, got %v", i, expected[i], obj.GetType())
+		}
+	}
+	if char, ok := all[1].(CharObject); !ok || char.Text != "b" {
+		t.Errorf("expected the second char boxed unchanged, got %#v", all[1])
+	}
+}
@@ -0,0 +1,126 @@
+package pdf
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// bfcharDestinations returns the destination values of the bfchar mappings
+// of a CMap
+func bfcharDestinations(content string) [][]byte {
+	re := regexp.MustCompile(`beginbfchar\s*((?:<[0-9A-Fa-f]+>\s*<[0-9A-Fa-f]+>\s*)+)endbfchar`)
+	mappingRe := regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*<([0-9A-Fa-f]+)>`)
+	var values [][]byte
+	for _, section := range re.FindAllStringSubmatch(content, -1) {
+		for _, mapping := range mappingRe.FindAllStringSubmatch(section[1], -1) {
+			if value, err := hex.DecodeString(mapping[2]); err == nil {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// isByteSwapped reports whether destination values were written in the
+// wrong byte order: every unit ends in a zero byte after a printable ASCII
+// one, as swapped ASCII text does, or only the swapped order decodes to
+// valid text and more of it Latin. Values with a byte order mark or of an
+// odd length are left out.
+func isByteSwapped(values [][]byte) bool {
+	var data []byte
+	for _, value := range values {
+		if len(value)%2 == 0 && !hasByteOrderMark(value) {
+			data = append(data, value...)
+		}
+	}
+	units, swapped := utf16Units(data, false), utf16Units(data, true)
+	return looksSwapped(units) || (invalidUnits(units) > 0 && invalidUnits(swapped) == 0 && latinUnits(swapped) > latinUnits(units))
+}
+
+// hasByteOrderMark reports whether a value starts with a UTF-16 byte order mark
+func hasByteOrderMark(value []byte) bool {
+	return len(value) >= 2 && (value[0] == 0xFE && value[1] == 0xFF || value[0] == 0xFF && value[1] == 0xFE)
+}
+
+// repairUTF16 decodes a destination value written by a buggy generator. A
+// byte order mark overrides the byte order of the CMap. A value of an odd
+// number of bytes is taken to have lost the zero high byte of its first
+// unit. Surrogates without their other half are dropped.
+func repairUTF16(data []byte, littleEndian bool) string {
+	if hasByteOrderMark(data) {
+		littleEndian = data[0] == 0xFF
+		data = data[2:]
+	}
+	if len(data)%2 == 1 {
+		data = append([]byte{0}, data...)
+	}
+
+	units := utf16Units(data, littleEndian)
+	var text strings.Builder
+	for i := 0; i < len(units); i++ {
+		u := rune(units[i])
+		if !utf16.IsSurrogate(u) {
+			text.WriteRune(u)
+			continue
+		}
+		if i+1 < len(units) {
+			if r := utf16.DecodeRune(u, rune(units[i+1])); r != unicode.ReplacementChar {
+				text.WriteRune(r)
+				i++
+			}
+		}
+	}
+	return text.String()
+}
+
+// utf16Units splits data into 16-bit units of the given byte order
+func utf16Units(data []byte, littleEndian bool) []uint16 {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		if littleEndian {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		} else {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		}
+	}
+	return units
+}
+
+// looksSwapped reports whether every unit has a zero low byte after a
+// printable ASCII high byte, as ASCII text read in the wrong byte order has
+func looksSwapped(units []uint16) bool {
+	for _, u := range units {
+		if u&0xFF != 0 || u>>8 < 0x20 || u>>8 > 0x7E {
+			return false
+		}
+	}
+	return len(units) > 0
+}
+
+// invalidUnits counts the units that decode to no printable character:
+// controls, unassigned and private use code points. Surrogates are left
+// out, as those without their other half are dropped when decoding.
+func invalidUnits(units []uint16) int {
+	invalid := 0
+	for _, u := range units {
+		r := rune(u)
+		if !utf16.IsSurrogate(r) && !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			invalid++
+		}
+	}
+	return invalid
+}
+
+// latinUnits counts the printable units of Basic Latin and Latin-1
+func latinUnits(units []uint16) int {
+	latin := 0
+	for _, u := range units {
+		if u >= 0x20 && u <= 0xFF && unicode.IsPrint(rune(u)) {
+			latin++
+		}
+	}
+	return latin
+}
@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// WithPhysicalDimensions makes page sizes, from GetWidth, GetHeight and
+// PageDimensions, come in physical points: default user space units scaled
+// by the page's /UserUnit. Object coordinates stay in default user space,
+// where a unit is UserUnit/72 inch.
+func WithPhysicalDimensions(enabled bool) OpenOption {
+	return func(c *openConfig) {
+		c.PhysicalDimensions = enabled
+	}
+}
+
+// validUserUnit returns a page's user unit, 1 when it is missing or not positive
+func validUserUnit(unit float64) float64 {
+	if unit > 0 {
+		return unit
+	}
+	return 1
+}
+
+// sizeScale returns the factor from default user space to the reported size
+// of a page with the given user unit
+func sizeScale(unit float64, physical bool) float64 {
+	if physical {
+		return unit
+	}
+	return 1
+}
+
+// withUserUnit records the user unit of a page's dimensions, scaling its
+// size to physical points if asked to
+func (dim PageDim) withUserUnit(unit float64, physical bool) PageDim {
+	dim.UserUnit = unit
+	dim.Width *= sizeScale(unit, physical)
+	dim.Height *= sizeScale(unit, physical)
+	return dim
+}
+
+// pdfcpuUserUnit reads the /UserUnit of a page dictionary
+func pdfcpuUserUnit(ctx *model.Context, pageDict types.Dict) float64 {
+	unit, err := ctx.DereferenceNumber(pageDict["UserUnit"])
+	if err != nil {
+		return 1
+	}
+	return validUserUnit(unit)
+}
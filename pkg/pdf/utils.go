@@ -8,6 +8,9 @@ import (
 // Tolerance for floating point comparisons
 const FloatTolerance = 0.1
 
+// LineJoinTolerance is how far apart segment endpoints may be and still meet
+const LineJoinTolerance = 1.0
+
 // DeduplicateLines removes duplicate lines based on coordinates
 func DeduplicateLines(lines []LineObject) []LineObject {
 	if len(lines) == 0 {
@@ -45,16 +48,21 @@ func DeduplicateLines(lines []LineObject) []LineObject {
 
 // linesEqual checks if two lines are essentially the same
 func linesEqual(a, b LineObject) bool {
+	return linesWithin(a, b, FloatTolerance)
+}
+
+// linesWithin checks if the endpoints of two lines are less than tolerance apart
+func linesWithin(a, b LineObject, tolerance float64) bool {
 	// Check both directions (lines might be reversed)
-	sameDirection := math.Abs(a.X0-b.X0) < FloatTolerance &&
-		math.Abs(a.Y0-b.Y0) < FloatTolerance &&
-		math.Abs(a.X1-b.X1) < FloatTolerance &&
-		math.Abs(a.Y1-b.Y1) < FloatTolerance
+	sameDirection := math.Abs(a.X0-b.X0) < tolerance &&
+		math.Abs(a.Y0-b.Y0) < tolerance &&
+		math.Abs(a.X1-b.X1) < tolerance &&
+		math.Abs(a.Y1-b.Y1) < tolerance
 
-	reversedDirection := math.Abs(a.X0-b.X1) < FloatTolerance &&
-		math.Abs(a.Y0-b.Y1) < FloatTolerance &&
-		math.Abs(a.X1-b.X0) < FloatTolerance &&
-		math.Abs(a.Y1-b.Y0) < FloatTolerance
+	reversedDirection := math.Abs(a.X0-b.X1) < tolerance &&
+		math.Abs(a.Y0-b.Y1) < tolerance &&
+		math.Abs(a.X1-b.X0) < tolerance &&
+		math.Abs(a.Y1-b.Y0) < tolerance
 
 	return sameDirection || reversedDirection
 }
@@ -258,8 +266,163 @@ func DeduplicateRectangles(rects []RectObject) []RectObject {
 
 // rectsEqual checks if two rectangles are essentially the same
 func rectsEqual(a, b RectObject) bool {
-	return math.Abs(a.X0-b.X0) < FloatTolerance &&
-		math.Abs(a.Y0-b.Y0) < FloatTolerance &&
-		math.Abs(a.X1-b.X1) < FloatTolerance &&
-		math.Abs(a.Y1-b.Y1) < FloatTolerance
-}
\ No newline at end of file
+	return rectsWithin(a, b, FloatTolerance)
+}
+
+// rectsWithin checks if the corners of two rectangles are less than tolerance apart
+func rectsWithin(a, b RectObject, tolerance float64) bool {
+	return math.Abs(a.X0-b.X0) < tolerance &&
+		math.Abs(a.Y0-b.Y0) < tolerance &&
+		math.Abs(a.X1-b.X1) < tolerance &&
+		math.Abs(a.Y1-b.Y1) < tolerance
+}
+
+// DetectRectanglesFromLines finds closed axis-aligned loops formed by
+// separately drawn line segments and returns them as rectangles. Collinear
+// segments whose ends lie within tolerance are joined first, so a side may
+// be drawn in several pieces. Each loop is the smallest one at its corner:
+// a grid gives its cells rather than loops spanning several of them.
+func DetectRectanglesFromLines(lines []LineObject, tolerance float64) []RectObject {
+	var horizontal, vertical []LineObject
+	for _, line := range lines {
+		if math.Abs(line.Y0-line.Y1) < tolerance && math.Abs(line.X0-line.X1) >= tolerance {
+			horizontal = append(horizontal, line)
+		} else if math.Abs(line.X0-line.X1) < tolerance && math.Abs(line.Y0-line.Y1) >= tolerance {
+			// Join vertical segments as horizontal ones with the axes swapped
+			line.X0, line.Y0, line.X1, line.Y1 = line.Y0, line.X0, line.Y1, line.X1
+			vertical = append(vertical, line)
+		}
+	}
+	horizontal = joinCollinearSegments(horizontal, tolerance)
+	vertical = joinCollinearSegments(vertical, tolerance)
+	for i, line := range vertical {
+		vertical[i].X0, vertical[i].Y0, vertical[i].X1, vertical[i].Y1 = line.Y0, line.X0, line.Y1, line.X1
+	}
+
+	// Find where the segments cross or meet. Verticals are indexed by x, so
+	// that only those within a horizontal's span are looked at.
+	type crossing struct {
+		h, v int
+		x, y float64
+	}
+	byX := make([]int, len(vertical))
+	for i := range byX {
+		byX[i] = i
+	}
+	sort.Slice(byX, func(i, j int) bool { return vertical[byX[i]].X0 < vertical[byX[j]].X0 })
+
+	alongH := make([][]crossing, len(horizontal)) // Crossings of each horizontal by x
+	alongV := make([][]crossing, len(vertical))   // Crossings of each vertical by y
+	crosses := make(map[[2]int]bool)
+	for i, h := range horizontal {
+		k := sort.Search(len(byX), func(k int) bool { return vertical[byX[k]].X0 >= h.X0-tolerance })
+		for ; k < len(byX) && vertical[byX[k]].X0 <= h.X1+tolerance; k++ {
+			v := vertical[byX[k]]
+			if h.Y0 < v.Y0-tolerance || h.Y0 > v.Y1+tolerance {
+				continue
+			}
+			// Corners at a horizontal's ends keep its exact ends
+			x := v.X0
+			if math.Abs(x-h.X0) <= tolerance {
+				x = h.X0
+			} else if math.Abs(x-h.X1) <= tolerance {
+				x = h.X1
+			}
+			c := crossing{h: i, v: byX[k], x: x, y: h.Y0}
+			alongH[i] = append(alongH[i], c)
+			alongV[c.v] = append(alongV[c.v], c)
+			crosses[[2]int{c.h, c.v}] = true
+		}
+	}
+	for _, cs := range alongV {
+		sort.Slice(cs, func(i, j int) bool { return cs[i].y < cs[j].y })
+	}
+
+	// From each crossing as the bottom-left corner, take the nearest
+	// crossings above and to the right that close a loop
+	var rects []RectObject
+	seen := make(map[rectKey]bool)
+	for _, cs := range alongH {
+		for i, corner := range cs {
+			found := false
+			for _, above := range alongV[corner.v] {
+				if above.y-corner.y < tolerance {
+					continue
+				}
+				for _, right := range cs[i+1:] {
+					if right.x-corner.x < tolerance || !crosses[[2]int{above.h, right.v}] {
+						continue
+					}
+					bottom := horizontal[corner.h]
+					rect := RectObject{
+						X0:          corner.x,
+						Y0:          corner.y,
+						X1:          right.x,
+						Y1:          above.y,
+						Width:       bottom.Width,
+						StrokeColor: bottom.StrokeColor,
+						Stroked:     true,
+					}
+					if key := keyOfRect(rect); !seen[key] {
+						seen[key] = true
+						rects = append(rects, rect)
+					}
+					found = true
+					break
+				}
+				if found {
+					break
+				}
+			}
+		}
+	}
+
+	return rects
+}
+
+// joinCollinearSegments joins horizontal segments on the same line whose
+// ends lie within tolerance, returning them ordered by y and then x
+func joinCollinearSegments(lines []LineObject, tolerance float64) []LineObject {
+	for i := range lines {
+		if lines[i].X0 > lines[i].X1 {
+			lines[i].X0, lines[i].X1 = lines[i].X1, lines[i].X0
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Y0 < lines[j].Y0 })
+
+	var joined []LineObject
+	for start := 0; start < len(lines); {
+		// Segments within tolerance of the lowest one share its line
+		end := start + 1
+		for end < len(lines) && lines[end].Y0-lines[start].Y0 <= tolerance {
+			end++
+		}
+		y := lines[start].Y0
+		band := lines[start:end]
+		sort.SliceStable(band, func(i, j int) bool { return band[i].X0 < band[j].X0 })
+
+		current := band[0]
+		current.Y0, current.Y1 = y, y
+		for _, line := range band[1:] {
+			if line.X0 <= current.X1+tolerance {
+				current.X1 = math.Max(current.X1, line.X1)
+				continue
+			}
+			joined = append(joined, current)
+			current = line
+			current.Y0, current.Y1 = y, y
+		}
+		joined = append(joined, current)
+		start = end
+	}
+	return joined
+}
+
+// rectKey identifies a rectangle by its corners rounded to FloatTolerance
+type rectKey [4]int64
+
+// keyOfRect returns the key of a rectangle's corners
+func keyOfRect(rect RectObject) rectKey {
+	round := func(v float64) int64 { return int64(math.Round(v / FloatTolerance)) }
+	return rectKey{round(rect.X0), round(rect.Y0), round(rect.X1), round(rect.Y1)}
+}
@@ -0,0 +1,143 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestDetectRectanglesFromLines(t *testing.T) {
+	// Four edges drawn as separate segments, in mixed directions and
+	// with endpoints slightly off
+	lines := []LineObject{
+		{X0: 100, Y0: 100, X1: 200, Y1: 100, Width: 1},
+		{X0: 200.3, Y0: 100, X1: 200.3, Y1: 150, Width: 1},
+		{X0: 200, Y0: 150.2, X1: 100, Y1: 150.2, Width: 1},
+		{X0: 100, Y0: 150, X1: 100, Y1: 100, Width: 1},
+	}
+
+	rects := DetectRectanglesFromLines(lines, LineJoinTolerance)
+	if len(rects) != 1 {
+		t.Fatalf("expected 1 rectangle, got %d", len(rects))
+	}
+
+	rect := rects[0]
+	if rect.X0 != 100 || rect.Y0 != 100 || rect.X1 != 200 || rect.Y1 != 150.2 {
+		t.Errorf("unexpected rectangle bounds: %+v", rect)
+	}
+	if !rect.Stroked {
+		t.Error("expected rectangle to be stroked")
+	}
+}
+
+func TestDetectRectanglesFromLinesOpenPath(t *testing.T) {
+	// Only three edges - the loop is not closed
+	lines := []LineObject{
+		{X0: 100, Y0: 100, X1: 200, Y1: 100},
+		{X0: 200, Y0: 100, X1: 200, Y1: 150},
+		{X0: 200, Y0: 150, X1: 100, Y1: 150},
+	}
+
+	if rects := DetectRectanglesFromLines(lines, LineJoinTolerance); len(rects) != 0 {
+		t.Errorf("expected no rectangles, got %d", len(rects))
+	}
+}
+
+// gridEdges draws an n by n grid of 10pt cells, each edge of every cell as
+// a segment of its own
+func gridEdges(n int) []LineObject {
+	var lines []LineObject
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			x0, y0 := float64(col*10), float64(row*10)
+			x1, y1 := x0+10, y0+10
+			lines = append(lines,
+				LineObject{X0: x0, Y0: y0, X1: x1, Y1: y0},
+				LineObject{X0: x1, Y0: y0, X1: x1, Y1: y1},
+				LineObject{X0: x1, Y0: y1, X1: x0, Y1: y1},
+				LineObject{X0: x0, Y0: y1, X1: x0, Y1: y0},
+			)
+		}
+	}
+	return lines
+}
+
+func TestDetectRectanglesFromLinesGrid(t *testing.T) {
+	// Neighbouring cells draw their shared edges twice; each cell is found
+	// once, and no loop spans several cells
+	if rects := DetectRectanglesFromLines(gridEdges(12), LineJoinTolerance); len(rects) != 144 {
+		t.Errorf("expected 144 rectangles, got %d", len(rects))
+	}
+}
+
+func TestDetectRectanglesFromLinesJoinedSides(t *testing.T) {
+	// The bottom is drawn in two pieces and the right side in three, one
+	// of them overlapping another
+	lines := []LineObject{
+		{X0: 100, Y0: 100, X1: 150, Y1: 100},
+		{X0: 200, Y0: 100, X1: 150.5, Y1: 100},
+		{X0: 200, Y0: 100, X1: 200, Y1: 120},
+		{X0: 200, Y0: 110, X1: 200, Y1: 140},
+		{X0: 200, Y0: 140, X1: 200, Y1: 150},
+		{X0: 200, Y0: 150, X1: 100, Y1: 150},
+		{X0: 100, Y0: 150, X1: 100, Y1: 100},
+	}
+
+	rects := DetectRectanglesFromLines(lines, LineJoinTolerance)
+	if len(rects) != 1 {
+		t.Fatalf("expected 1 rectangle, got %+v", rects)
+	}
+	if rect := rects[0]; rect.X0 != 100 || rect.Y0 != 100 || rect.X1 != 200 || rect.Y1 != 150 {
+		t.Errorf("unexpected rectangle bounds: %+v", rect)
+	}
+}
+
+func TestDetectRectanglesFromLinesRuledTable(t *testing.T) {
+	// A table of 3 columns and 2 rows ruled with lines running its full
+	// width and height
+	var lines []LineObject
+	for _, y := range []float64{0, 20, 40} {
+		lines = append(lines, LineObject{X0: 0, Y0: y, X1: 90, Y1: y})
+	}
+	for _, x := range []float64{0, 30, 60, 90} {
+		lines = append(lines, LineObject{X0: x, Y0: 0, X1: x, Y1: 40})
+	}
+
+	rects := DetectRectanglesFromLines(lines, LineJoinTolerance)
+	if len(rects) != 6 {
+		t.Fatalf("expected the 6 cells, got %+v", rects)
+	}
+	for _, rect := range rects {
+		if rect.X1-rect.X0 != 30 || rect.Y1-rect.Y0 != 20 {
+			t.Errorf("expected a 30x20 cell, got %+v", rect)
+		}
+	}
+}
+
+func BenchmarkDetectRectanglesFromLines(b *testing.B) {
+	lines := gridEdges(30)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		DetectRectanglesFromLines(lines, LineJoinTolerance)
+	}
+}
+
+func TestParseSeparateEdgesAsRectangle(t *testing.T) {
+	content := []byte(`
+		100 100 m 200 100 l S
+		200 100 m 200 150 l S
+		200 150 m 100 150 l S
+		100 150 m 100 100 l S
+	`)
+
+	objects := NewContentStreamParser(nil, types.Dict{}).Parse(content)
+	if len(objects.Lines) != 4 {
+		t.Errorf("expected the 4 original lines to be kept, got %d", len(objects.Lines))
+	}
+	if len(objects.Rects) != 1 {
+		t.Fatalf("expected 1 rectangle, got %d", len(objects.Rects))
+	}
+	if rect := objects.Rects[0]; rect.X0 != 100 || rect.Y0 != 100 || rect.X1 != 200 || rect.Y1 != 150 {
+		t.Errorf("unexpected rectangle bounds: %+v", rect)
+	}
+}
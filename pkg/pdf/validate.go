@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity tells how much a validation issue affects extraction
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// ValidationIssue is a structural problem found in a document
+type ValidationIssue struct {
+	Severity  Severity
+	PageIndex int // Page the issue is on (0-based), -1 for the whole document
+	Message   string
+}
+
+// String formats the issue as "page N: message" with a 1-based page number
+func (i ValidationIssue) String() string {
+	if i.PageIndex < 0 {
+		return "document: " + i.Message
+	}
+	return fmt.Sprintf("page %d: %s", i.PageIndex+1, i.Message)
+}
+
+// pageFacts is what a backend knows about a page for validation
+type pageFacts struct {
+	index       int
+	hasMediaBox bool
+	width       float64
+	height      float64
+	fonts       []fontFacts
+	formFonts   []string // Base fonts of the form XObjects the resources hold, and the forms those hold
+	chars       int
+	images      int
+	objectLimit int // Limit the page's objects were cut off at, 0 if complete
+}
+
+// fontFacts describes a font resource for validation
+type fontFacts struct {
+	name      string
+	baseFont  string
+	encoding  string // Encoding name, "Differences" for an encoding dictionary
+	toUnicode bool
+}
+
+// standardEncodings are the encodings text can be decoded with without a ToUnicode CMap
+var standardEncodings = map[string]bool{
+	"StandardEncoding":  true,
+	"WinAnsiEncoding":   true,
+	"MacRomanEncoding":  true,
+	"MacExpertEncoding": true,
+	"PDFDocEncoding":    true,
+	"Differences":       true,
+}
+
+// standardFonts are the 14 fonts every reader provides, with built-in encodings
+var standardFonts = map[string]bool{
+	"Times-Roman": true, "Times-Bold": true, "Times-Italic": true, "Times-BoldItalic": true,
+	"Helvetica": true, "Helvetica-Bold": true, "Helvetica-Oblique": true, "Helvetica-BoldOblique": true,
+	"Courier": true, "Courier-Bold": true, "Courier-Oblique": true, "Courier-BoldOblique": true,
+	"Symbol": true, "ZapfDingbats": true,
+}
+
+// validatePage reports the issues found in a page's facts
+func validatePage(facts pageFacts) []ValidationIssue {
+	var issues []ValidationIssue
+	add := func(severity Severity, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			Severity:  severity,
+			PageIndex: facts.index,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	if !facts.hasMediaBox {
+		add(SeverityWarning, "no MediaBox, assuming %.0fx%.0f", facts.width, facts.height)
+	}
+	if facts.width <= 0 || facts.height <= 0 {
+		add(SeverityError, "page size %.0fx%.0f is empty", facts.width, facts.height)
+	}
+
+	fonts := append([]fontFacts{}, facts.fonts...)
+	sort.Slice(fonts, func(i, j int) bool { return fonts[i].name < fonts[j].name })
+	for _, font := range fonts {
+		if font.toUnicode {
+			continue
+		}
+		switch {
+		case font.encoding == "Identity-H" || font.encoding == "Identity-V":
+			add(SeverityError, "font %s has no ToUnicode and Identity encoding, text cannot be decoded", font.name)
+		case standardEncodings[font.encoding]:
+		case font.encoding == "" && standardFonts[font.baseFont]:
+		default:
+			add(SeverityWarning, "font %s has no ToUnicode and non-standard encoding", font.name)
+		}
+	}
+
+	if facts.objectLimit > 0 {
+		add(SeverityWarning, "content truncated at %d objects", facts.objectLimit)
+	}
+	
+	if facts.chars == 0 {
+		if facts.images > 0 {
+			add(SeverityWarning, "0 characters, %d image(s) - likely scanned", facts.images)
+		} else {
+			add(SeverityInfo, "no characters")
+		}
+	}
+
+	return issues
+}
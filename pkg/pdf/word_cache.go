@@ -0,0 +1,24 @@
+package pdf
+
+// wordCache keeps the words last extracted from a page together with the
+// config they were extracted with
+type wordCache struct {
+	config wordExtractionConfig
+	words  []Word
+	valid  bool
+}
+
+// get returns a copy of the cached words if they were extracted with the same config
+func (c *wordCache) get(config *wordExtractionConfig) ([]Word, bool) {
+	if !c.valid || c.config != *config {
+		return nil, false
+	}
+	return append([]Word(nil), c.words...), true
+}
+
+// set stores the words extracted with a config
+func (c *wordCache) set(config *wordExtractionConfig, words []Word) {
+	c.config = *config
+	c.words = append([]Word(nil), words...)
+	c.valid = true
+}